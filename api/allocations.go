@@ -210,8 +210,6 @@ func (a *Allocations) Exec(ctx context.Context,
 
 func (a *Allocations) execFrames(ctx context.Context, alloc *Allocation, task string, tty bool, command []string,
 	errCh chan<- error, q *QueryOptions) (sendFn func(*ExecStreamingInput) error, output <-chan *ExecStreamingOutput) {
-	nodeClient, _ := a.client.GetNodeClientWithTimeout(alloc.NodeID, ClientConnTimeout, q)
-
 	if q == nil {
 		q = &QueryOptions{}
 	}
@@ -231,22 +229,10 @@ func (a *Allocations) execFrames(ctx context.Context, alloc *Allocation, task st
 
 	reqPath := fmt.Sprintf("/v1/client/allocation/%s/exec", alloc.ID)
 
-	var conn *websocket.Conn
-
-	if nodeClient != nil {
-		conn, _, err = nodeClient.websocket(reqPath, q)
-		if _, ok := err.(net.Error); err != nil && !ok {
-			errCh <- err
-			return nil, nil
-		}
-	}
-
-	if conn == nil {
-		conn, _, err = a.client.websocket(reqPath, q)
-		if err != nil {
-			errCh <- err
-			return nil, nil
-		}
+	conn, err := a.allocWebsocket(alloc, reqPath, q)
+	if err != nil {
+		errCh <- err
+		return nil, nil
 	}
 
 	// Create the output channel
@@ -283,6 +269,113 @@ func (a *Allocations) execFrames(ctx context.Context, alloc *Allocation, task st
 
 }
 
+// allocWebsocket opens a websocket to the path, connecting to the node of the
+// allocation directly if possible.
+func (a *Allocations) allocWebsocket(alloc *Allocation, path string, q *QueryOptions) (*websocket.Conn, error) {
+	nodeClient, _ := a.client.GetNodeClientWithTimeout(alloc.NodeID, ClientConnTimeout, q)
+	if nodeClient != nil {
+		conn, _, err := nodeClient.websocket(path, q)
+		if _, ok := err.(net.Error); err != nil && !ok {
+			return nil, err
+		}
+		if conn != nil {
+			return conn, nil
+		}
+	}
+
+	conn, _, err := a.client.websocket(path, q)
+	return conn, err
+}
+
+// PortForward forwards a connection to a port of the allocation, or of its
+// task if one is given. The allocation's client connects to the port and
+// data is copied between it and conn until either side closes its
+// connection, which blocks the call. Closing conn is left to the caller.
+func (a *Allocations) PortForward(ctx context.Context, alloc *Allocation, task string, port int,
+	conn io.ReadWriter, q *QueryOptions) error {
+
+	ctx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+	q.Params["task"] = task
+	q.Params["port"] = strconv.Itoa(port)
+
+	reqPath := fmt.Sprintf("/v1/client/allocation/%s/port-forward", alloc.ID)
+	ws, err := a.allocWebsocket(alloc, reqPath, q)
+	if err != nil {
+		return err
+	}
+	defer ws.Close()
+
+	errCh := make(chan error, 2)
+
+	// Forward data from the connection to the allocation
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := conn.Read(buf)
+			if n != 0 {
+				if err := ws.WriteMessage(websocket.BinaryMessage, buf[:n]); err != nil {
+					errCh <- err
+					return
+				}
+			}
+
+			if err == io.EOF {
+				// An empty message signals the connection has no more data
+				// to send
+				if err := ws.WriteMessage(websocket.BinaryMessage, nil); err != nil {
+					errCh <- err
+				}
+				return
+			} else if err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	// Forward data from the allocation to the connection
+	go func() {
+		for {
+			_, data, err := ws.ReadMessage()
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				errCh <- nil
+				return
+			} else if err != nil {
+				// drop websocket code, not relevant to user
+				if wsErr, ok := err.(*websocket.CloseError); ok && wsErr.Text != "" {
+					err = errors.New(wsErr.Text)
+				}
+				errCh <- err
+				return
+			}
+
+			if _, err := conn.Write(data); err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	ws.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second))
+	return err
+}
+
 func (a *Allocations) Stats(alloc *Allocation, q *QueryOptions) (*AllocResourceUsage, error) {
 	var resp AllocResourceUsage
 	path := fmt.Sprintf("/v1/client/allocation/%s/stats", alloc.ID)
@@ -365,6 +458,7 @@ type Allocation struct {
 	TaskStates            map[string]*TaskState
 	DeploymentID          string
 	DeploymentStatus      *AllocDeploymentStatus
+	ArchiveStatus         *AllocArchiveStatus
 	FollowupEvalID        string
 	PreviousAllocation    string
 	NextAllocation        string
@@ -390,10 +484,12 @@ type AllocationMetric struct {
 	DimensionExhausted map[string]int
 	QuotaExhausted     []string
 	// Deprecated, replaced with ScoreMetaData
-	Scores            map[string]float64
-	AllocationTime    time.Duration
-	CoalescedFailures int
-	ScoreMetaData     []*NodeScoreMeta
+	Scores                map[string]float64
+	AllocationTime        time.Duration
+	CoalescedFailures     int
+	ScoreMetaData         []*NodeScoreMeta
+	PlacementTrace        []*NodePlacementTrace
+	PlacementTraceDropped int
 }
 
 // NodeScoreMeta is used to serialize node scoring metadata
@@ -404,6 +500,15 @@ type NodeScoreMeta struct {
 	NormScore float64
 }
 
+// NodePlacementTrace is used to serialize why a node was rejected for a
+// placement, displayed in the CLI when explaining placements
+type NodePlacementTrace struct {
+	NodeID   string
+	NodeName string
+	Checker  string
+	Reason   string
+}
+
 // AllocationListStub is used to return a subset of an allocation
 // during list operations.
 type AllocationListStub struct {
@@ -433,6 +538,15 @@ type AllocationListStub struct {
 	ModifyTime            int64
 }
 
+// AllocArchiveStatus captures where the allocation directory was archived to
+// before the allocation was garbage collected.
+type AllocArchiveStatus struct {
+	Volume    string
+	Path      string
+	Timestamp time.Time
+	Error     string
+}
+
 // AllocDeploymentStatus captures the status of the allocation as part of the
 // deployment. This can include things like if the allocation has been marked as
 // healthy.
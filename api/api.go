@@ -56,6 +56,21 @@ type QueryOptions struct {
 
 	// AuthToken is the secret ID of an ACL token
 	AuthToken string
+
+	// PerPage is the number of entries to be returned in queries that support
+	// paginated lists.
+	PerPage int32
+
+	// NextToken is the token used to indicate where to start paging
+	// for queries that support paginated lists. This token should be
+	// the ID of the next object after the last one seen in the
+	// previous response.
+	NextToken string
+
+	// Filter is a boolean expression over the fields of the listed objects,
+	// used to only return the objects matching it in queries that support
+	// filtered lists.
+	Filter string
 }
 
 // WriteOptions are used to parametrize a write
@@ -86,6 +101,10 @@ type QueryMeta struct {
 
 	// How long did the request take
 	RequestTime time.Duration
+
+	// NextToken is the token used to get the next page of a paginated list.
+	// It is empty when there are no more pages.
+	NextToken string
 }
 
 // WriteMeta is used to return meta data about a write
@@ -539,6 +558,15 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.Prefix != "" {
 		r.params.Set("prefix", q.Prefix)
 	}
+	if q.PerPage != 0 {
+		r.params.Set("per_page", strconv.Itoa(int(q.PerPage)))
+	}
+	if q.NextToken != "" {
+		r.params.Set("next_token", q.NextToken)
+	}
+	if q.Filter != "" {
+		r.params.Set("filter", q.Filter)
+	}
 	for k, v := range q.Params {
 		r.params.Set(k, v)
 	}
@@ -912,6 +940,9 @@ func parseQueryMeta(resp *http.Response, q *QueryMeta) error {
 	default:
 		q.KnownLeader = false
 	}
+
+	// Parse the X-Nomad-NextToken
+	q.NextToken = header.Get("X-Nomad-NextToken")
 	return nil
 }
 
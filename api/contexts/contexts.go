@@ -12,4 +12,10 @@ const (
 	Namespaces  Context = "namespaces"
 	Quotas      Context = "quotas"
 	All         Context = "all"
+
+	// The following contexts are only searched by fuzzy searches
+	Groups   Context = "groups"
+	Tasks    Context = "tasks"
+	Services Context = "services"
+	Images   Context = "images"
 )
@@ -177,6 +177,18 @@ type DeploymentState struct {
 	PlacedAllocs      int
 	HealthyAllocs     int
 	UnhealthyAllocs   int
+	RolloutSteps      []int
+	CurrentStep       int
+	HealthGate        *HealthGateResult
+}
+
+// HealthGateResult is the result of checking a task group's health gate
+type HealthGateResult struct {
+	Passed      bool
+	Canary      bool
+	Attempts    int
+	Description string
+	Timestamp   time.Time
 }
 
 // DeploymentIndexSort is a wrapper to sort deployments by CreateIndex. We
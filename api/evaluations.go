@@ -26,6 +26,19 @@ func (e *Evaluations) List(q *QueryOptions) ([]*Evaluation, *QueryMeta, error) {
 	return resp, qm, nil
 }
 
+// Delete is used to delete the given evaluations. The eval broker must be
+// paused in the scheduler configuration.
+func (e *Evaluations) Delete(evalIDs []string, q *WriteOptions) (*WriteMeta, error) {
+	req := &EvalDeleteRequest{
+		EvalIDs: evalIDs,
+	}
+	wm, err := e.client.write("/v1/evaluations/delete", req, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
 func (e *Evaluations) PrefixList(prefix string) ([]*Evaluation, *QueryMeta, error) {
 	return e.List(&QueryOptions{Prefix: prefix})
 }
@@ -54,34 +67,43 @@ func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*Allocation
 
 // Evaluation is used to serialize an evaluation.
 type Evaluation struct {
-	ID                   string
-	Priority             int
-	Type                 string
-	TriggeredBy          string
-	Namespace            string
-	JobID                string
-	JobModifyIndex       uint64
-	NodeID               string
-	NodeModifyIndex      uint64
-	DeploymentID         string
-	Status               string
-	StatusDescription    string
-	Wait                 time.Duration
-	WaitUntil            time.Time
-	NextEval             string
-	PreviousEval         string
-	BlockedEval          string
-	FailedTGAllocs       map[string]*AllocationMetric
-	ClassEligibility     map[string]bool
-	EscapedComputedClass bool
-	QuotaLimitReached    string
-	AnnotatePlan         bool
-	QueuedAllocations    map[string]int
-	SnapshotIndex        uint64
-	CreateIndex          uint64
-	ModifyIndex          uint64
-	CreateTime           int64
-	ModifyTime           int64
+	ID                     string
+	Priority               int
+	Type                   string
+	TriggeredBy            string
+	Namespace              string
+	JobID                  string
+	JobModifyIndex         uint64
+	NodeID                 string
+	NodeModifyIndex        uint64
+	DeploymentID           string
+	Status                 string
+	StatusDescription      string
+	Wait                   time.Duration
+	WaitUntil              time.Time
+	NextEval               string
+	PreviousEval           string
+	BlockedEval            string
+	CoalescedInto          string
+	FailedTGAllocs         map[string]*AllocationMetric
+	ClassEligibility       map[string]bool
+	UnmetDependencies      []string
+	UnmetDependencyReasons map[string]string
+	EscapedComputedClass   bool
+	QuotaLimitReached      string
+	AnnotatePlan           bool
+	QueuedAllocations      map[string]int
+	SnapshotIndex          uint64
+	CreateIndex            uint64
+	ModifyIndex            uint64
+	CreateTime             int64
+	ModifyTime             int64
+}
+
+// EvalDeleteRequest is used to delete evaluations.
+type EvalDeleteRequest struct {
+	EvalIDs []string
+	WriteRequest
 }
 
 // EvalIndexSort is a wrapper to sort evaluations by CreateIndex.
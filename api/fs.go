@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -89,6 +90,49 @@ func (a *AllocFS) Stat(alloc *Allocation, path string, q *QueryOptions) (*AllocF
 	return &resp, qm, nil
 }
 
+// Upload writes the content of r to a file at the given path of an
+// allocation directory, creating missing parent directories. The file is
+// created with the permissions of mode and an existing file is only replaced
+// if overwrite is set. The info of the uploaded file is returned.
+func (a *AllocFS) Upload(alloc *Allocation, path string, r io.Reader, mode os.FileMode, overwrite bool,
+	q *QueryOptions) (*AllocFileInfo, *QueryMeta, error) {
+
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+	q.Params["path"] = path
+	q.Params["mode"] = strconv.FormatUint(uint64(mode.Perm()), 8)
+	q.Params["overwrite"] = strconv.FormatBool(overwrite)
+
+	// The file is streamed so the request is made through the agent, as it
+	// can't be retried against the node once the file has been read
+	req, err := a.client.newRequest("PUT", fmt.Sprintf("/v1/client/fs/upload/%s", alloc.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.setQueryOptions(q)
+	req.body = r
+
+	rtt, resp, err := requireOK(a.client.doRequest(req))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var info AllocFileInfo
+	if err := decodeBody(resp, &info); err != nil {
+		return nil, nil, err
+	}
+	return &info, qm, nil
+}
+
 // ReadAt is used to read bytes at a given offset until limit at the given path
 // in an allocation directory. If limit is <= 0, there is no limit.
 func (a *AllocFS) ReadAt(alloc *Allocation, path string, offset int64, limit int64, q *QueryOptions) (io.ReadCloser, error) {
@@ -112,6 +156,18 @@ func (a *AllocFS) Cat(alloc *Allocation, path string, q *QueryOptions) (io.ReadC
 		})
 }
 
+// Archive is used to read a tar archive of the file or directory at the path
+// relative to the alloc dir. The archive is gzip compressed if compress is
+// set.
+func (a *AllocFS) Archive(alloc *Allocation, path string, compress bool, q *QueryOptions) (io.ReadCloser, error) {
+	reqPath := fmt.Sprintf("/v1/client/fs/archive/%s", alloc.ID)
+	return queryClientNode(a.client, alloc, reqPath, q,
+		func(q *QueryOptions) {
+			q.Params["path"] = path
+			q.Params["compress"] = strconv.FormatBool(compress)
+		})
+}
+
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 // * path: path to file to stream.
@@ -226,6 +282,31 @@ func queryClientNode(c *Client, alloc *Allocation, reqPath string, q *QueryOptio
 // Unexpected (non-EOF) errors will be sent on the error chan.
 func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin string,
 	offset int64, cancel <-chan struct{}, q *QueryOptions) (<-chan *StreamFrame, <-chan error) {
+	return a.LogsWithFilter(alloc, follow, task, logType, origin, offset, nil, cancel, q)
+}
+
+// LogFilter selects the log lines that are streamed. Lines are filtered by
+// the client so only the matching lines are transferred.
+type LogFilter struct {
+	// Filter only streams the lines containing it
+	Filter string
+
+	// Regex interprets Filter as a regular expression
+	Regex bool
+
+	// Since and Until only stream the lines written within the time range.
+	// Each is either an RFC3339 timestamp or a duration before now, and is
+	// parsed by the agent. An empty value leaves that end of the range open.
+	Since string
+	Until string
+}
+
+// LogsWithFilter is used to stream a task's logs like Logs, only streaming
+// the lines selected by the filter. A nil filter streams the logs unfiltered.
+// Once the logs have passed the end of the filter's time range the stream is
+// closed, even when following the logs.
+func (a *AllocFS) LogsWithFilter(alloc *Allocation, follow bool, task, logType, origin string,
+	offset int64, filter *LogFilter, cancel <-chan struct{}, q *QueryOptions) (<-chan *StreamFrame, <-chan error) {
 
 	errCh := make(chan error, 1)
 
@@ -237,6 +318,20 @@ func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin str
 			q.Params["type"] = logType
 			q.Params["origin"] = origin
 			q.Params["offset"] = strconv.FormatInt(offset, 10)
+
+			if filter == nil {
+				return
+			}
+			if filter.Filter != "" {
+				q.Params["filter"] = filter.Filter
+				q.Params["regex"] = strconv.FormatBool(filter.Regex)
+			}
+			if filter.Since != "" {
+				q.Params["since"] = filter.Since
+			}
+			if filter.Until != "" {
+				q.Params["until"] = filter.Until
+			}
 		})
 	if err != nil {
 		errCh <- err
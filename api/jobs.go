@@ -279,6 +279,7 @@ func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta,
 type PlanOptions struct {
 	Diff           bool
 	PolicyOverride bool
+	Explain        bool
 }
 
 func (j *Jobs) Plan(job *Job, diff bool, q *WriteOptions) (*JobPlanResponse, *WriteMeta, error) {
@@ -298,6 +299,7 @@ func (j *Jobs) PlanOpts(job *Job, opts *PlanOptions, q *WriteOptions) (*JobPlanR
 	if opts != nil {
 		req.Diff = opts.Diff
 		req.PolicyOverride = opts.PolicyOverride
+		req.Explain = opts.Explain
 	}
 
 	var resp JobPlanResponse
@@ -383,8 +385,11 @@ type UpdateStrategy struct {
 	HealthyDeadline  *time.Duration `mapstructure:"healthy_deadline"`
 	ProgressDeadline *time.Duration `mapstructure:"progress_deadline"`
 	Canary           *int           `mapstructure:"canary"`
+	CanaryPercent    *int           `mapstructure:"canary_percent"`
 	AutoRevert       *bool          `mapstructure:"auto_revert"`
 	AutoPromote      *bool          `mapstructure:"auto_promote"`
+	RolloutSteps     []int          `mapstructure:"rollout_steps"`
+	HealthGate       *HealthGate    `mapstructure:"health_gate"`
 }
 
 // DefaultUpdateStrategy provides a baseline that can be used to upgrade
@@ -442,10 +447,20 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.Canary = intToPtr(*u.Canary)
 	}
 
+	if u.CanaryPercent != nil {
+		copy.CanaryPercent = intToPtr(*u.CanaryPercent)
+	}
+
 	if u.AutoPromote != nil {
 		copy.AutoPromote = boolToPtr(*u.AutoPromote)
 	}
 
+	if u.RolloutSteps != nil {
+		copy.RolloutSteps = append([]int(nil), u.RolloutSteps...)
+	}
+
+	copy.HealthGate = u.HealthGate.Copy()
+
 	return copy
 }
 
@@ -482,13 +497,31 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 		u.AutoRevert = boolToPtr(*o.AutoRevert)
 	}
 
+	// Canary and CanaryPercent are exclusive so setting one overrides both
 	if o.Canary != nil {
 		u.Canary = intToPtr(*o.Canary)
+		u.CanaryPercent = nil
+	}
+
+	if o.CanaryPercent != nil {
+		u.CanaryPercent = intToPtr(*o.CanaryPercent)
+		if o.Canary == nil {
+			u.Canary = intToPtr(0)
+		}
 	}
 
 	if o.AutoPromote != nil {
 		u.AutoPromote = boolToPtr(*o.AutoPromote)
 	}
+
+	if o.RolloutSteps != nil {
+		u.RolloutSteps = make([]int, len(o.RolloutSteps))
+		copy(u.RolloutSteps, o.RolloutSteps)
+	}
+
+	if o.HealthGate != nil {
+		u.HealthGate = o.HealthGate.Copy()
+	}
 }
 
 func (u *UpdateStrategy) Canonicalize() {
@@ -529,6 +562,10 @@ func (u *UpdateStrategy) Canonicalize() {
 	if u.AutoPromote == nil {
 		u.AutoPromote = d.AutoPromote
 	}
+
+	if u.HealthGate != nil {
+		u.HealthGate.Canonicalize()
+	}
 }
 
 // Empty returns whether the UpdateStrategy is empty or has user defined values.
@@ -573,9 +610,67 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.CanaryPercent != nil && *u.CanaryPercent != 0 {
+		return false
+	}
+
+	if len(u.RolloutSteps) != 0 {
+		return false
+	}
+
+	if u.HealthGate != nil {
+		return false
+	}
+
 	return true
 }
 
+// HealthGate is an HTTP endpoint that must return a 2xx response before the
+// canaries of a task group are promoted or its deployment is completed.
+type HealthGate struct {
+	URL           *string        `mapstructure:"url"`
+	Timeout       *time.Duration `mapstructure:"timeout"`
+	Attempts      *int           `mapstructure:"attempts"`
+	RetryInterval *time.Duration `mapstructure:"retry_interval"`
+}
+
+func (h *HealthGate) Copy() *HealthGate {
+	if h == nil {
+		return nil
+	}
+
+	nh := new(HealthGate)
+	*nh = *h
+	if h.URL != nil {
+		nh.URL = stringToPtr(*h.URL)
+	}
+	if h.Timeout != nil {
+		nh.Timeout = timeToPtr(*h.Timeout)
+	}
+	if h.Attempts != nil {
+		nh.Attempts = intToPtr(*h.Attempts)
+	}
+	if h.RetryInterval != nil {
+		nh.RetryInterval = timeToPtr(*h.RetryInterval)
+	}
+	return nh
+}
+
+func (h *HealthGate) Canonicalize() {
+	if h.URL == nil {
+		h.URL = stringToPtr("")
+	}
+	if h.Timeout == nil {
+		h.Timeout = timeToPtr(10 * time.Second)
+	}
+	if h.Attempts == nil {
+		h.Attempts = intToPtr(3)
+	}
+	if h.RetryInterval == nil {
+		h.RetryInterval = timeToPtr(10 * time.Second)
+	}
+}
+
 // PeriodicConfig is for serializing periodic config for a job.
 type PeriodicConfig struct {
 	Enabled         *bool
@@ -646,6 +741,31 @@ type ParameterizedJobConfig struct {
 	MetaOptional []string `mapstructure:"meta_optional"`
 }
 
+// JobGCConfig is used to hint clients how to garbage collect the terminal
+// allocations of a job.
+type JobGCConfig struct {
+	MinRetention *time.Duration `mapstructure:"min_retention"`
+}
+
+func (g *JobGCConfig) Canonicalize() {
+	if g.MinRetention == nil {
+		g.MinRetention = timeToPtr(0)
+	}
+}
+
+// JobDependency is a job another job depends on, along with the status it
+// must reach before the allocations of the dependent job are placed.
+type JobDependency struct {
+	JobID  string `mapstructure:"job_id"`
+	Status *string
+}
+
+func (d *JobDependency) Canonicalize() {
+	if d.Status == nil {
+		d.Status = stringToPtr("running")
+	}
+}
+
 // Job is used to serialize a job.
 type Job struct {
 	Stop              *bool
@@ -663,8 +783,10 @@ type Job struct {
 	TaskGroups        []*TaskGroup
 	Update            *UpdateStrategy
 	Spreads           []*Spread
+	DependsOn         []*JobDependency `mapstructure:"depends_on"`
 	Periodic          *PeriodicConfig
 	ParameterizedJob  *ParameterizedJobConfig
+	GC                *JobGCConfig
 	Dispatched        bool
 	Payload           []byte
 	Reschedule        *ReschedulePolicy
@@ -749,6 +871,9 @@ func (j *Job) Canonicalize() {
 	if j.Periodic != nil {
 		j.Periodic.Canonicalize()
 	}
+	if j.GC != nil {
+		j.GC.Canonicalize()
+	}
 	if j.Update != nil {
 		j.Update.Canonicalize()
 	} else if *j.Type == JobTypeService {
@@ -765,6 +890,9 @@ func (j *Job) Canonicalize() {
 	for _, a := range j.Affinities {
 		a.Canonicalize()
 	}
+	for _, d := range j.DependsOn {
+		d.Canonicalize()
+	}
 }
 
 // LookupTaskGroup finds a task group by name
@@ -920,6 +1048,13 @@ func (j *Job) AddSpread(s *Spread) *Job {
 	return j
 }
 
+// AddDependency adds a dependency on another job which must reach the given
+// status before the job's allocations are placed.
+func (j *Job) AddDependency(jobID, status string) *Job {
+	j.DependsOn = append(j.DependsOn, &JobDependency{JobID: jobID, Status: stringToPtr(status)})
+	return j
+}
+
 type WriteRequest struct {
 	// The target region for this write
 	Region string
@@ -1021,6 +1156,7 @@ type JobPlanRequest struct {
 	Job            *Job
 	Diff           bool
 	PolicyOverride bool
+	Explain        bool
 	WriteRequest
 }
 
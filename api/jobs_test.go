@@ -1459,6 +1459,31 @@ func TestJobs_AddAffinity(t *testing.T) {
 	}
 }
 
+func TestJobs_AddDependency(t *testing.T) {
+	t.Parallel()
+	job := &Job{DependsOn: nil}
+
+	// Add a dependency and check that the job was returned
+	out := job.AddDependency("db", "healthy")
+	require.Equal(t, job, out)
+
+	// Adding another dependency preserves the original and the status
+	// defaults on canonicalization
+	job.DependsOn = append(job.DependsOn, &JobDependency{JobID: "cache"})
+	job.Canonicalize()
+	expect := []*JobDependency{
+		{
+			JobID:  "db",
+			Status: stringToPtr("healthy"),
+		},
+		{
+			JobID:  "cache",
+			Status: stringToPtr("running"),
+		},
+	}
+	require.Equal(t, expect, job.DependsOn)
+}
+
 func TestJobs_Sort(t *testing.T) {
 	t.Parallel()
 	jobs := []*JobListStub{
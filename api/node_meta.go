@@ -0,0 +1,65 @@
+package api
+
+import "net/url"
+
+// NodeMeta is used to query and update the metadata of a node at runtime.
+type NodeMeta struct {
+	client *Client
+}
+
+// Meta returns a handle on the node metadata endpoints.
+func (n *Nodes) Meta() *NodeMeta {
+	return &NodeMeta{client: n.client}
+}
+
+// NodeMetaApplyRequest is used to update the metadata of a node at runtime.
+type NodeMetaApplyRequest struct {
+	// NodeID is the node to update. If empty, the node of the agent handling
+	// the request is updated.
+	NodeID string
+
+	// Meta is the metadata to apply. A nil value unsets the key, removing it
+	// from the node even if it is set in the client configuration.
+	Meta map[string]*string
+}
+
+// NodeMetaResponse is the metadata of a node.
+type NodeMetaResponse struct {
+	// Meta is the effective metadata of the node.
+	Meta map[string]string
+
+	// Dynamic is the metadata applied at runtime, which takes precedence
+	// over Static.
+	Dynamic map[string]*string
+
+	// Static is the metadata from the client configuration.
+	Static map[string]string
+}
+
+// Apply updates the metadata of a node without restarting it. The metadata is
+// persisted by the client and the node is re-registered with the servers.
+func (n *NodeMeta) Apply(req *NodeMetaApplyRequest, q *WriteOptions) (*NodeMetaResponse, error) {
+	var resp NodeMetaResponse
+	path := "/v1/client/metadata"
+	if req.NodeID != "" {
+		path += "?node_id=" + url.QueryEscape(req.NodeID)
+	}
+	if _, err := n.client.write(path, req, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Read returns the metadata of a node. If nodeID is empty, the metadata of the
+// node of the agent handling the request is returned.
+func (n *NodeMeta) Read(nodeID string, q *QueryOptions) (*NodeMetaResponse, error) {
+	var resp NodeMetaResponse
+	path := "/v1/client/metadata"
+	if nodeID != "" {
+		path += "?node_id=" + url.QueryEscape(nodeID)
+	}
+	if _, err := n.client.query(path, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
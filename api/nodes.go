@@ -96,6 +96,65 @@ func (n *Nodes) UpdateDrain(nodeID string, spec *DrainSpec, markEligible bool, q
 	return &resp, nil
 }
 
+// NodeSelector is used to select a set of nodes by their properties. A node
+// is selected only if it matches every non-empty field of the selector.
+type NodeSelector struct {
+	// Datacenter restricts the selection to nodes in the given datacenter.
+	Datacenter string
+
+	// NodeClass restricts the selection to nodes of the given class.
+	NodeClass string
+
+	// Filters are constraints that the node must satisfy. They can be used to
+	// select nodes by attribute or meta.
+	Filters []*Constraint
+}
+
+// NodeBatchUpdateDrainRequest is used to update the drain specification of
+// all nodes matching a selector.
+type NodeBatchUpdateDrainRequest struct {
+	// Selector selects the nodes to update the drain specification for.
+	Selector *NodeSelector
+
+	// DrainSpec is the drain specification to set for the nodes. A nil
+	// DrainSpec will disable draining.
+	DrainSpec *DrainSpec
+
+	// MarkEligible marks the nodes as eligible for scheduling if removing
+	// the drain strategy.
+	MarkEligible bool
+}
+
+// NodeBatchDrainUpdateResponse is used to respond to a drain update of the
+// nodes matching a selector
+type NodeBatchDrainUpdateResponse struct {
+	NodeIDs         []string
+	NodeModifyIndex uint64
+	EvalIDs         []string
+	EvalCreateIndex uint64
+	WriteMeta
+}
+
+// BatchUpdateDrain is used to update the drain strategy of all nodes matching
+// the selector. The nodes are resolved by the servers and updated atomically.
+// If markEligible is true and the drain is being removed, the nodes will be
+// marked as having their scheduling being eligible.
+func (n *Nodes) BatchUpdateDrain(selector *NodeSelector, spec *DrainSpec, markEligible bool, q *WriteOptions) (*NodeBatchDrainUpdateResponse, error) {
+	req := &NodeBatchUpdateDrainRequest{
+		Selector:     selector,
+		DrainSpec:    spec,
+		MarkEligible: markEligible,
+	}
+
+	var resp NodeBatchDrainUpdateResponse
+	wm, err := n.client.write("/v1/nodes/drain", req, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
 // MonitorMsgLevels represents the severity log level of a MonitorMessage.
 type MonitorMsgLevel int
 
@@ -381,6 +440,47 @@ func (n *Nodes) ToggleEligibility(nodeID string, eligible bool, q *WriteOptions)
 	return &resp, nil
 }
 
+// NodeBatchUpdateEligibilityRequest is used to update the scheduling
+// eligibility of all nodes matching a selector.
+type NodeBatchUpdateEligibilityRequest struct {
+	// Selector selects the nodes to update the eligibility for.
+	Selector    *NodeSelector
+	Eligibility string
+}
+
+// NodeBatchEligibilityUpdateResponse is used to respond to an eligibility
+// update of the nodes matching a selector
+type NodeBatchEligibilityUpdateResponse struct {
+	NodeIDs         []string
+	NodeModifyIndex uint64
+	EvalIDs         []string
+	EvalCreateIndex uint64
+	WriteMeta
+}
+
+// BatchToggleEligibility is used to update the scheduling eligibility of all
+// nodes matching the selector. The nodes are resolved by the servers and
+// updated atomically.
+func (n *Nodes) BatchToggleEligibility(selector *NodeSelector, eligible bool, q *WriteOptions) (*NodeBatchEligibilityUpdateResponse, error) {
+	e := NodeSchedulingEligible
+	if !eligible {
+		e = NodeSchedulingIneligible
+	}
+
+	req := &NodeBatchUpdateEligibilityRequest{
+		Selector:    selector,
+		Eligibility: e,
+	}
+
+	var resp NodeBatchEligibilityUpdateResponse
+	wm, err := n.client.write("/v1/nodes/eligibility", req, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
 // Allocations is used to return the allocations associated with a node.
 func (n *Nodes) Allocations(nodeID string, q *QueryOptions) ([]*Allocation, *QueryMeta, error) {
 	var resp []*Allocation
@@ -419,6 +519,48 @@ func (n *Nodes) GC(nodeID string, q *QueryOptions) error {
 	return err
 }
 
+// GCStatus returns the allocations the node has marked for garbage collection.
+func (n *Nodes) GCStatus(nodeID string, q *QueryOptions) (*GCStatus, error) {
+	var resp GCStatus
+	path := fmt.Sprintf("/v1/client/gc/status?node_id=%s", nodeID)
+	if _, err := n.client.query(path, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GCStatus is the garbage collection status of a node.
+type GCStatus struct {
+	// Allocs are the terminal allocations in the order they will be
+	// garbage collected.
+	Allocs []*GCAllocStatus
+
+	// Reason is why the node would garbage collect allocations now, or empty
+	// if it is below all of its thresholds.
+	Reason string
+
+	NumAllocs           int
+	MaxAllocs           int
+	DiskUsedPercent     float64
+	DiskUsageThreshold  float64
+	InodesUsedPercent   float64
+	InodeUsageThreshold float64
+}
+
+// GCAllocStatus describes an allocation marked for garbage collection.
+type GCAllocStatus struct {
+	AllocID   string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	MarkedAt  time.Time
+
+	// RetainUntil is the time until which the allocation is only garbage
+	// collected to free disk space, and RetainReason is why.
+	RetainUntil  time.Time
+	RetainReason string
+}
+
 // TODO Add tests
 func (n *Nodes) GcAlloc(allocID string, q *QueryOptions) error {
 	var resp struct{}
@@ -114,6 +114,19 @@ type SchedulerConfiguration struct {
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig
 
+	// PlacementTraceEnabled specifies whether the schedulers record why each
+	// node was rejected in the metrics of the placements of all evaluations.
+	PlacementTraceEnabled bool
+
+	// NamespaceWeights specifies the share of the eval broker each namespace
+	// receives while several namespaces have evaluations of the same priority
+	// waiting. Namespaces without a weight have a weight of 1.
+	NamespaceWeights map[string]int
+
+	// PauseEvalBroker specifies whether the eval broker is paused. While it
+	// is paused, no evaluations are processed and evaluations may be deleted.
+	PauseEvalBroker bool
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -176,3 +189,81 @@ func (op *Operator) SchedulerCASConfiguration(conf *SchedulerConfiguration, q *W
 
 	return &out, wm, nil
 }
+
+// SchedulerSimulateRequest is used to replay hypothetical jobs, node
+// additions and node removals against a snapshot of the cluster state.
+type SchedulerSimulateRequest struct {
+	// Jobs are registered in order after the node changes are applied.
+	Jobs []*Job
+
+	// AddNodes are the nodes added to the cluster, as clones of existing
+	// nodes.
+	AddNodes []*SimulateAddNode
+
+	// RemoveNodes are the IDs of the nodes removed from the cluster.
+	RemoveNodes []string
+
+	WriteRequest
+}
+
+// SimulateAddNode adds Count clones of an existing node to a simulation.
+type SimulateAddNode struct {
+	NodeID string
+	Count  int
+}
+
+// SchedulerSimulateResponse is the result of a scheduler simulation.
+type SchedulerSimulateResponse struct {
+	Evals    []*SimulatedEval
+	Nodes    []*SimulatedNode
+	Warnings string
+}
+
+// SimulatedEval is the result of an evaluation processed by a simulation.
+type SimulatedEval struct {
+	Namespace      string
+	JobID          string
+	TriggeredBy    string
+	Placed         []*SimulatedAlloc
+	Stopped        []*SimulatedAlloc
+	Preempted      []*SimulatedAlloc
+	FailedTGAllocs map[string]*AllocationMetric
+}
+
+// SimulatedAlloc describes an allocation changed by a simulated evaluation.
+type SimulatedAlloc struct {
+	ID        string
+	Name      string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	NodeID    string
+}
+
+// SimulatedNode is the utilization of a node at the end of a simulation.
+type SimulatedNode struct {
+	ID               string
+	Name             string
+	Datacenter       string
+	NodeClass        string
+	Status           string
+	Simulated        bool
+	Allocs           int
+	CPU              int64
+	CPUCapacity      int64
+	MemoryMB         int64
+	MemoryCapacityMB int64
+	DiskMB           int64
+	DiskCapacityMB   int64
+}
+
+// SchedulerSimulate is used to simulate the scheduling of jobs and node
+// changes without committing any plan to the cluster.
+func (op *Operator) SchedulerSimulate(req *SchedulerSimulateRequest, q *WriteOptions) (*SchedulerSimulateResponse, *WriteMeta, error) {
+	var out SchedulerSimulateResponse
+	wm, err := op.c.write("/v1/operator/scheduler/simulate", req, &out, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &out, wm, nil
+}
@@ -26,6 +26,20 @@ func (s *Search) PrefixSearch(prefix string, context contexts.Context, q *QueryO
 	return &resp, qm, nil
 }
 
+// FuzzySearch returns the jobs, task groups, tasks, services, images and nodes
+// whose names contain the given text, ignoring case, for a particular context.
+func (s *Search) FuzzySearch(text string, context contexts.Context, q *QueryOptions) (*FuzzySearchResponse, *QueryMeta, error) {
+	var resp FuzzySearchResponse
+	req := &FuzzySearchRequest{Text: text, Context: context}
+
+	qm, err := s.client.putQuery("/v1/search/fuzzy", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &resp, qm, nil
+}
+
 type SearchRequest struct {
 	Prefix  string
 	Context contexts.Context
@@ -37,3 +51,22 @@ type SearchResponse struct {
 	Truncations map[contexts.Context]bool
 	QueryMeta
 }
+
+type FuzzySearchRequest struct {
+	Text    string
+	Context contexts.Context
+	QueryOptions
+}
+
+// FuzzyMatch is the name of a matching object along with its scope, the IDs
+// or names of the objects it belongs to.
+type FuzzyMatch struct {
+	ID    string
+	Scope []string `json:",omitempty"`
+}
+
+type FuzzySearchResponse struct {
+	Matches     map[contexts.Context][]FuzzyMatch
+	Truncations map[contexts.Context]bool
+	QueryMeta
+}
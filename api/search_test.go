@@ -30,3 +30,25 @@ func TestSearch_List(t *testing.T) {
 	require.Equal(1, len(jobMatches))
 	require.Equal(id, jobMatches[0])
 }
+
+func TestSearch_FuzzySearch(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	job := testJob()
+	_, _, err := c.Jobs().Register(job, nil)
+	require.Nil(err)
+
+	resp, qm, err := c.Search().FuzzySearch("ASK", contexts.All, nil)
+	require.Nil(err)
+	require.NotNil(qm)
+
+	taskMatches := resp.Matches[contexts.Tasks]
+	require.Equal(1, len(taskMatches))
+	require.Equal("task1", taskMatches[0].ID)
+	require.Equal([]string{"default", *job.ID, "group1"}, taskMatches[0].Scope)
+	require.Empty(resp.Matches[contexts.Jobs])
+}
@@ -370,6 +370,13 @@ type VolumeRequest struct {
 	ReadOnly bool `mapstructure:"read_only"`
 }
 
+// AllocArchive is used to archive paths of the allocation directory into a
+// host volume of the task group before the allocation is garbage collected.
+type AllocArchive struct {
+	Paths  []string
+	Volume string
+}
+
 const (
 	VolumeMountPropagationPrivate       = "private"
 	VolumeMountPropagationHostToTask    = "host-to-task"
@@ -412,6 +419,8 @@ type TaskGroup struct {
 	Meta             map[string]string
 	Services         []*Service
 	ShutdownDelay    *time.Duration `mapstructure:"shutdown_delay"`
+	MaxRunDuration   *time.Duration `mapstructure:"max_run_duration"`
+	Archive          *AllocArchive
 }
 
 // NewTaskGroup creates a new TaskGroup.
@@ -573,12 +582,29 @@ func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 type LogConfig struct {
 	MaxFiles      *int `mapstructure:"max_files"`
 	MaxFileSizeMB *int `mapstructure:"max_file_size"`
+
+	Compress       *bool          `mapstructure:"compress"`
+	RotateInterval *time.Duration `mapstructure:"rotate_interval"`
+	MaxAge         *time.Duration `mapstructure:"max_age"`
+
+	Sinks []*LogSink `mapstructure:"sink"`
+}
+
+// LogSink is a destination the logs of a task are shipped to alongside the
+// rotated log files
+type LogSink struct {
+	Type        string `mapstructure:"type"`
+	Address     string `mapstructure:"address"`
+	BufferLines int    `mapstructure:"buffer_lines"`
 }
 
 func DefaultLogConfig() *LogConfig {
 	return &LogConfig{
-		MaxFiles:      intToPtr(10),
-		MaxFileSizeMB: intToPtr(10),
+		MaxFiles:       intToPtr(10),
+		MaxFileSizeMB:  intToPtr(10),
+		Compress:       boolToPtr(false),
+		RotateInterval: timeToPtr(0),
+		MaxAge:         timeToPtr(0),
 	}
 }
 
@@ -589,6 +615,15 @@ func (l *LogConfig) Canonicalize() {
 	if l.MaxFileSizeMB == nil {
 		l.MaxFileSizeMB = intToPtr(10)
 	}
+	if l.Compress == nil {
+		l.Compress = boolToPtr(false)
+	}
+	if l.RotateInterval == nil {
+		l.RotateInterval = timeToPtr(0)
+	}
+	if l.MaxAge == nil {
+		l.MaxAge = timeToPtr(0)
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -617,6 +652,7 @@ type Task struct {
 	VolumeMounts    []*VolumeMount
 	Leader          bool
 	ShutdownDelay   time.Duration `mapstructure:"shutdown_delay"`
+	MaxRunDuration  time.Duration `mapstructure:"max_run_duration"`
 	KillSignal      string        `mapstructure:"kill_signal"`
 	Kind            string
 }
@@ -839,11 +875,14 @@ const (
 	TaskNotRestarting          = "Not Restarting"
 	TaskDownloadingArtifacts   = "Downloading Artifacts"
 	TaskArtifactDownloadFailed = "Failed Artifact Download"
+	TaskArtifactCacheHit       = "Artifact Cache Hit"
+	TaskArtifactCacheMiss      = "Artifact Cache Miss"
 	TaskSiblingFailed          = "Sibling Task Failed"
 	TaskSignaling              = "Signaling"
 	TaskRestartSignal          = "Restart Signaled"
 	TaskLeaderDead             = "Leader Task Dead"
 	TaskBuildingTaskDir        = "Building Task Directory"
+	TaskMaxRunDurationExceeded = "Max Run Duration Exceeded"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
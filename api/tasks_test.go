@@ -433,6 +433,59 @@ func TestTaskGroup_Merge_Update(t *testing.T) {
 	}, tg.Update)
 }
 
+// Verifies that a canary percent on the group overrides a job canary count
+func TestTaskGroup_Merge_Update_CanaryPercent(t *testing.T) {
+	job := &Job{
+		ID: stringToPtr("test"),
+		Update: &UpdateStrategy{
+			Canary:       intToPtr(2),
+			RolloutSteps: []int{50},
+		},
+	}
+	job.Canonicalize()
+
+	tg := &TaskGroup{
+		Name: stringToPtr("foo"),
+		Update: &UpdateStrategy{
+			CanaryPercent: intToPtr(20),
+		},
+	}
+
+	tg.Canonicalize(job)
+	require.Equal(t, 0, *tg.Update.Canary)
+	require.Equal(t, 20, *tg.Update.CanaryPercent)
+	require.Equal(t, []int{50}, tg.Update.RolloutSteps)
+}
+
+// Verifies that a health gate is inherited from the job and canonicalized
+func TestTaskGroup_Canonicalize_HealthGate(t *testing.T) {
+	job := &Job{
+		ID: stringToPtr("test"),
+		Update: &UpdateStrategy{
+			HealthGate: &HealthGate{
+				URL:      stringToPtr("http://127.0.0.1/gate"),
+				Attempts: intToPtr(5),
+			},
+		},
+	}
+	job.Canonicalize()
+
+	tg := &TaskGroup{
+		Name: stringToPtr("foo"),
+	}
+	tg.Canonicalize(job)
+	require.Equal(t, &HealthGate{
+		URL:           stringToPtr("http://127.0.0.1/gate"),
+		Timeout:       timeToPtr(10 * time.Second),
+		Attempts:      intToPtr(5),
+		RetryInterval: timeToPtr(10 * time.Second),
+	}, tg.Update.HealthGate)
+
+	// The group's copy is independent of the job's
+	*tg.Update.HealthGate.Attempts = 1
+	require.Equal(t, 5, *job.Update.HealthGate.Attempts)
+}
+
 // Verifies that migrate strategy is merged correctly
 func TestTaskGroup_Canonicalize_MigrateStrategy(t *testing.T) {
 	type testCase struct {
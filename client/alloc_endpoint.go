@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"strconv"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -19,6 +21,12 @@ import (
 	"github.com/ugorji/go/codec"
 )
 
+const (
+	// portForwardDialTimeout is the timeout for connecting to the port of an
+	// alloc when forwarding a connection to it
+	portForwardDialTimeout = 10 * time.Second
+)
+
 // Allocations endpoint is used for interacting with client allocations
 type Allocations struct {
 	c *Client
@@ -27,6 +35,7 @@ type Allocations struct {
 func NewAllocationsEndpoint(c *Client) *Allocations {
 	a := &Allocations{c: c}
 	a.c.streamingRpcs.Register("Allocations.Exec", a.exec)
+	a.c.streamingRpcs.Register("Allocations.PortForward", a.portForward)
 	return a
 }
 
@@ -45,6 +54,30 @@ func (a *Allocations) GarbageCollectAll(args *nstructs.NodeSpecificRequest, repl
 	return nil
 }
 
+// GCStatus is used to retrieve the allocations marked for garbage collection
+// on a client.
+func (a *Allocations) GCStatus(args *nstructs.NodeSpecificRequest, reply *cstructs.GCStatusResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "gc_status"}, time.Now())
+
+	// Check node read permissions
+	aclObj, err := a.c.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	*reply = *a.c.garbageCollector.Status()
+
+	// Only return the allocations of the namespaces the token can read jobs in
+	if aclObj != nil {
+		reply.FilterAllocs(func(namespace string) bool {
+			return aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
+		})
+	}
+	return nil
+}
+
 // GarbageCollect is used to garbage collect an allocation on a client.
 func (a *Allocations) GarbageCollect(args *nstructs.AllocSpecificRequest, reply *nstructs.GenericResponse) error {
 	defer metrics.MeasureSince([]string{"client", "allocations", "garbage_collect"}, time.Now())
@@ -309,3 +342,214 @@ func (s *execStream) Recv() (*drivers.ExecTaskStreamingRequestMsg, error) {
 	err := s.decoder.Decode(&req)
 	return &req, err
 }
+
+// portForward is used to forward a connection to a port of a running
+// allocation
+func (a *Allocations) portForward(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "allocations", "port_forward"}, time.Now())
+	defer conn.Close()
+
+	forwardID := uuid.Generate()
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	code, err := a.portForwardImpl(encoder, decoder, forwardID)
+	if err != nil {
+		a.c.logger.Info("port forward session ended with an error", "error", err, "code", code)
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	a.c.logger.Info("port forward session ended", "forward_id", forwardID)
+}
+
+func (a *Allocations) portForwardImpl(encoder *codec.Encoder, decoder *codec.Decoder, forwardID string) (code *int64, err error) {
+	// Decode the arguments
+	var req cstructs.AllocPortForwardRequest
+	if err := decoder.Decode(&req); err != nil {
+		return helper.Int64ToPtr(500), err
+	}
+
+	if req.AllocID == "" {
+		return helper.Int64ToPtr(400), allocIDNotPresentErr
+	}
+	ar, err := a.c.getAllocRunner(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if structs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+
+		return code, err
+	}
+	alloc := ar.Alloc()
+
+	aclObj, token, err := a.c.resolveTokenAndACL(req.QueryOptions.AuthToken)
+	{
+		// log access
+		tokenName, tokenID := "", ""
+		if token != nil {
+			tokenName, tokenID = token.Name, token.AccessorID
+		}
+
+		a.c.logger.Info("port forward session starting",
+			"forward_id", forwardID,
+			"alloc_id", req.AllocID,
+			"task", req.Task,
+			"port", req.Port,
+			"access_token_name", tokenName,
+			"access_token_id", tokenID,
+		)
+	}
+
+	// Check alloc-port-forward permission.
+	if err != nil {
+		return nil, err
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocPortForward) {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if req.Port <= 0 || req.Port > 65535 {
+		return helper.Int64ToPtr(400), fmt.Errorf("invalid port %d", req.Port)
+	}
+
+	allocState, err := a.c.GetAllocState(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if structs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+
+		return code, err
+	}
+
+	if allocState.ClientStatus != structs.AllocClientStatusRunning {
+		return helper.Int64ToPtr(404), fmt.Errorf("allocation %q is not running", req.AllocID)
+	}
+
+	if req.Task != "" {
+		taskState := allocState.TaskStates[req.Task]
+		if taskState == nil {
+			return helper.Int64ToPtr(400), fmt.Errorf("unknown task name %q", req.Task)
+		}
+		if taskState.State != structs.TaskStateRunning {
+			return helper.Int64ToPtr(404), fmt.Errorf("task %q is not running.", req.Task)
+		}
+	}
+
+	// Connections to allocs with their own network namespace are made from
+	// within the namespace
+	spec := ar.GetNetworkIsolation()
+	ip := "127.0.0.1"
+	if spec == nil || spec.Path == "" {
+		// Without a network namespace only ports allocated to the alloc may
+		// be reached
+		ip = portForwardIP(alloc, req.Task, req.Port)
+		if ip == "" {
+			return helper.Int64ToPtr(400), fmt.Errorf("port %d is not allocated to allocation %q", req.Port, req.AllocID)
+		}
+	}
+	addr := net.JoinHostPort(ip, strconv.Itoa(req.Port))
+
+	taskConn, err := dialPortForward(spec, addr, portForwardDialTimeout)
+	if err != nil {
+		return helper.Int64ToPtr(500), fmt.Errorf("failed to connect to %s: %v", addr, err)
+	}
+	defer taskConn.Close()
+
+	// Forward data from the stream to the task until the stream is closed
+	go func() {
+		defer taskConn.Close()
+		for {
+			var frame cstructs.PortForwardFrame
+			if err := decoder.Decode(&frame); err != nil {
+				return
+			}
+
+			if len(frame.Data) != 0 {
+				if _, err := taskConn.Write(frame.Data); err != nil {
+					return
+				}
+			}
+
+			if frame.Close {
+				// Let the task finish sending its response
+				if tcpConn, ok := taskConn.(*net.TCPConn); ok {
+					tcpConn.CloseWrite()
+				}
+			}
+		}
+	}()
+
+	// Forward data from the task to the stream until the task closes the
+	// connection
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := taskConn.Read(buf)
+		if n > 0 {
+			if err := encoder.Encode(cstructs.StreamErrWrapper{Payload: buf[:n]}); err != nil {
+				return nil, nil
+			}
+		}
+		if err != nil {
+			// The connection was closed by either side
+			return nil, nil
+		}
+	}
+}
+
+// portForwardIP returns the IP of the network of the task, or of the alloc if
+// no task is given, that has the port reserved or dynamically allocated. An
+// empty string is returned if the port isn't allocated.
+func portForwardIP(alloc *structs.Allocation, task string, port int) string {
+	resources := alloc.AllocatedResources
+	if resources == nil {
+		return ""
+	}
+
+	if tr, ok := resources.Tasks[task]; ok {
+		if ip := networksPortIP(tr.Networks, port); ip != "" {
+			return ip
+		}
+	}
+	if ip := networksPortIP(resources.Shared.Networks, port); ip != "" {
+		return ip
+	}
+	if task != "" {
+		return ""
+	}
+
+	// Use the first task with the port allocated
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil {
+		for _, t := range tg.Tasks {
+			if tr, ok := resources.Tasks[t.Name]; ok {
+				if ip := networksPortIP(tr.Networks, port); ip != "" {
+					return ip
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// networksPortIP returns the IP of the first network with the port reserved
+// or dynamically allocated.
+func networksPortIP(networks structs.Networks, port int) string {
+	for _, n := range networks {
+		if n.IP == "" {
+			continue
+		}
+		for _, p := range n.ReservedPorts {
+			if p.Value == port {
+				return n.IP
+			}
+		}
+		for _, p := range n.DynamicPorts {
+			if p.Value == port {
+				return n.IP
+			}
+		}
+	}
+	return ""
+}
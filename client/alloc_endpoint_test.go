@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/allocrunner"
 	"github.com/hashicorp/nomad/client/config"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/pluginutils/catalog"
@@ -206,6 +207,82 @@ func TestAllocations_GarbageCollectAll_ACL(t *testing.T) {
 	}
 }
 
+func TestAllocations_GCStatus(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.GCMaxAllocs = 20
+	})
+	defer cleanup()
+
+	req := &nstructs.NodeSpecificRequest{}
+	var resp cstructs.GCStatusResponse
+	require.Nil(client.ClientRPC("Allocations.GCStatus", &req, &resp))
+	require.Equal(20, resp.MaxAllocs)
+	require.Empty(resp.Allocs)
+}
+
+func TestAllocations_GCStatus_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	server, addr, root, cleanupS := testACLServer(t, nil)
+	defer cleanupS()
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer cleanupC()
+
+	// Try request without a token and expect failure
+	{
+		req := &nstructs.NodeSpecificRequest{}
+		var resp cstructs.GCStatusResponse
+		err := client.ClientRPC("Allocations.GCStatus", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Mark an allocation of the default namespace for collection
+	ar, cleanupAR := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanupAR()
+	client.garbageCollector.MarkForCollection(ar.Alloc().ID, ar)
+
+	// Try request with a valid token that can't read jobs and expect the
+	// allocation to be filtered
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "valid", mock.NodePolicy(acl.PolicyRead))
+		req := &nstructs.NodeSpecificRequest{}
+		req.AuthToken = token.SecretID
+		var resp cstructs.GCStatusResponse
+		require.Nil(client.ClientRPC("Allocations.GCStatus", &req, &resp))
+		require.Empty(resp.Allocs)
+	}
+
+	// Try request with a valid token that can read jobs
+	{
+		policy := mock.NodePolicy(acl.PolicyRead) +
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+		token := mock.CreatePolicyAndToken(t, server.State(), 1009, "valid-job", policy)
+		req := &nstructs.NodeSpecificRequest{}
+		req.AuthToken = token.SecretID
+		var resp cstructs.GCStatusResponse
+		require.Nil(client.ClientRPC("Allocations.GCStatus", &req, &resp))
+		require.Len(resp.Allocs, 1)
+		require.Equal(ar.Alloc().ID, resp.Allocs[0].AllocID)
+	}
+
+	// Try request with a management token
+	{
+		req := &nstructs.NodeSpecificRequest{}
+		req.AuthToken = root.SecretID
+		var resp cstructs.GCStatusResponse
+		require.Nil(client.ClientRPC("Allocations.GCStatus", &req, &resp))
+		require.Len(resp.Allocs, 1)
+	}
+}
+
 func TestAllocations_GarbageCollect(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -1304,3 +1381,457 @@ func decodeFrames(t *testing.T, p1 net.Conn, frames chan<- *drivers.ExecTaskStre
 		frames <- &frame
 	}
 }
+
+// decodePortForwardPayloads sends the payloads and errors received from a
+// port forward stream to the given channels.
+func decodePortForwardPayloads(t *testing.T, p1 net.Conn, payloads chan<- []byte, errCh chan<- error) {
+	decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+
+	for {
+		var msg cstructs.StreamErrWrapper
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF || strings.Contains(err.Error(), "closed") {
+				return
+			}
+			errCh <- fmt.Errorf("error decoding: %v", err)
+			return
+		}
+
+		if msg.Error != nil {
+			errCh <- msg.Error
+			continue
+		}
+		payloads <- msg.Payload
+	}
+}
+
+func TestAlloc_PortForward(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Start an echo server standing in for the task
+	ln, err := net.Listen("tcp", ":0")
+	require.NoError(err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+
+	// Run an alloc with the echo server's port allocated
+	port := ln.Addr().(*net.TCPAddr).Port
+	alloc := runPortForwardAlloc(t, s, c, port)
+
+	// Make the request
+	req := &cstructs.AllocPortForwardRequest{
+		AllocID:      alloc.ID,
+		Port:         port,
+		QueryOptions: nstructs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("Allocations.PortForward")
+	require.Nil(err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	errCh := make(chan error)
+	payloads := make(chan []byte)
+
+	// Start the handler
+	go handler(p2)
+	go decodePortForwardPayloads(t, p1, payloads, errCh)
+
+	// Send the request and data
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	require.Nil(encoder.Encode(req))
+	require.Nil(encoder.Encode(&cstructs.PortForwardFrame{Data: []byte("hello")}))
+	require.Nil(encoder.Encode(&cstructs.PortForwardFrame{Data: []byte(" world"), Close: true}))
+
+	timeout := time.After(3 * time.Second)
+	received := ""
+
+	for received != "hello world" {
+		select {
+		case <-timeout:
+			require.FailNow("timed out", "received %q", received)
+		case err := <-errCh:
+			require.NoError(err)
+		case p := <-payloads:
+			received += string(p)
+		}
+	}
+}
+
+func TestAlloc_PortForward_UnallocatedPort(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Start a server on the host which isn't allocated to the alloc
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte("secret"))
+		conn.Close()
+	}()
+
+	alloc := runPortForwardAlloc(t, s, c, ln.Addr().(*net.TCPAddr).Port+1)
+
+	// Make the request
+	req := &cstructs.AllocPortForwardRequest{
+		AllocID:      alloc.ID,
+		Port:         ln.Addr().(*net.TCPAddr).Port,
+		QueryOptions: nstructs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("Allocations.PortForward")
+	require.Nil(err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	errCh := make(chan error)
+	payloads := make(chan []byte)
+
+	// Start the handler
+	go handler(p2)
+	go decodePortForwardPayloads(t, p1, payloads, errCh)
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	require.Nil(encoder.Encode(req))
+
+	select {
+	case <-time.After(3 * time.Second):
+		require.FailNow("timed out")
+	case err := <-errCh:
+		require.Contains(err.Error(), "is not allocated")
+	case p := <-payloads:
+		require.Fail("received unexpected payload", "payload: %q", p)
+	}
+}
+
+// networkIsolationAllocRunner is an AllocRunner reporting the given network
+// isolation spec
+type networkIsolationAllocRunner struct {
+	AllocRunner
+	spec *drivers.NetworkIsolationSpec
+}
+
+func (ar *networkIsolationAllocRunner) GetNetworkIsolation() *drivers.NetworkIsolationSpec {
+	return ar.spec
+}
+
+func TestAlloc_PortForward_EmptyNetworkIsolationPath(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Start a server on the host which isn't allocated to the alloc
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte("secret"))
+		conn.Close()
+	}()
+
+	alloc := runPortForwardAlloc(t, s, c, ln.Addr().(*net.TCPAddr).Port+1)
+
+	// Report a network isolation spec without a namespace path, which is
+	// dialed from the host
+	c.allocLock.Lock()
+	c.allocs[alloc.ID] = &networkIsolationAllocRunner{
+		AllocRunner: c.allocs[alloc.ID],
+		spec:        &drivers.NetworkIsolationSpec{Mode: drivers.NetIsolationModeGroup},
+	}
+	c.allocLock.Unlock()
+
+	// Make the request
+	req := &cstructs.AllocPortForwardRequest{
+		AllocID:      alloc.ID,
+		Port:         ln.Addr().(*net.TCPAddr).Port,
+		QueryOptions: nstructs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("Allocations.PortForward")
+	require.Nil(err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	errCh := make(chan error)
+	payloads := make(chan []byte)
+
+	// Start the handler
+	go handler(p2)
+	go decodePortForwardPayloads(t, p1, payloads, errCh)
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	require.Nil(encoder.Encode(req))
+
+	select {
+	case <-time.After(3 * time.Second):
+		require.FailNow("timed out")
+	case err := <-errCh:
+		require.Contains(err.Error(), "is not allocated")
+	case p := <-payloads:
+		require.Fail("received unexpected payload", "payload: %q", p)
+	}
+}
+
+// runPortForwardAlloc runs a batch alloc on the client with the port reserved
+// on the loopback address and waits for it to be running.
+func runPortForwardAlloc(t *testing.T, s *nomad.Server, c *Client, port int) *nstructs.Allocation {
+	alloc := mock.BatchAlloc()
+	alloc.NodeID = c.NodeID()
+	alloc.Job.TaskGroups[0].Count = 1
+	alloc.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	for _, tr := range alloc.AllocatedResources.Tasks {
+		tr.Networks = []*nstructs.NetworkResource{{
+			IP:            "127.0.0.1",
+			ReservedPorts: []nstructs.Port{{Label: "echo", Value: port}},
+		}}
+	}
+
+	// Wait for the client to register
+	state := s.State()
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := state.NodeByID(nil, c.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if node == nil || node.Status != nstructs.NodeStatusReady {
+			return false, fmt.Errorf("client not ready")
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	require.NoError(t, state.UpsertJob(999, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(1003, []*nstructs.Allocation{alloc}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := state.AllocByID(nil, alloc.ID)
+		if err != nil {
+			return false, err
+		}
+		if out == nil {
+			return false, fmt.Errorf("unknown alloc")
+		}
+		if out.ClientStatus != nstructs.AllocClientStatusRunning {
+			return false, fmt.Errorf("alloc client status: %v", out.ClientStatus)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err, "alloc didn't start")
+	})
+	return alloc
+}
+
+func TestAlloc_PortForward_NoAllocation(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Make the request
+	req := &cstructs.AllocPortForwardRequest{
+		AllocID:      uuid.Generate(),
+		Port:         8080,
+		QueryOptions: nstructs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("Allocations.PortForward")
+	require.Nil(err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	errCh := make(chan error)
+	payloads := make(chan []byte)
+
+	// Start the handler
+	go handler(p2)
+	go decodePortForwardPayloads(t, p1, payloads, errCh)
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	require.Nil(encoder.Encode(req))
+
+	select {
+	case <-time.After(3 * time.Second):
+		require.FailNow("timed out")
+	case err := <-errCh:
+		require.True(nstructs.IsErrUnknownAllocation(err), "expected no allocation error but found: %v", err)
+	case p := <-payloads:
+		require.Fail("received unexpected payload", "payload: %q", p)
+	}
+}
+
+func TestAlloc_PortForward_ACL(t *testing.T) {
+	t.Parallel()
+
+	// Start a server and client
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy(structs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocExec})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocPortForward})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for client to be running job
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: "unknown task name",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: "unknown task name",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+
+			// Make the request
+			req := &cstructs.AllocPortForwardRequest{
+				AllocID: alloc.ID,
+				Task:    "testtask",
+				Port:    8080,
+				QueryOptions: nstructs.QueryOptions{
+					Region:    "global",
+					AuthToken: c.Token,
+					Namespace: nstructs.DefaultNamespace,
+				},
+			}
+
+			// Get the handler
+			handler, err := client.StreamingRpcHandler("Allocations.PortForward")
+			require.Nil(t, err)
+
+			// Create a pipe
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			errCh := make(chan error)
+			payloads := make(chan []byte)
+
+			// Start the handler
+			go handler(p2)
+			go decodePortForwardPayloads(t, p1, payloads, errCh)
+
+			// Send the request
+			encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+			require.Nil(t, encoder.Encode(req))
+
+			select {
+			case <-time.After(3 * time.Second):
+				require.FailNow(t, "timed out")
+			case err := <-errCh:
+				require.Contains(t, err.Error(), c.ExpectedError)
+			case p := <-payloads:
+				require.Fail(t, "received unexpected payload", "payload: %q", p)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"net"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// dialPortForward dials the address, from within the network namespace of the
+// spec if it isn't nil.
+func dialPortForward(spec *drivers.NetworkIsolationSpec, addr string, timeout time.Duration) (net.Conn, error) {
+	if spec == nil || spec.Path == "" {
+		return net.DialTimeout("tcp", addr, timeout)
+	}
+
+	netns, err := ns.GetNS(spec.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer netns.Close()
+
+	// Sockets belong to the namespace they are created in, so the connection
+	// stays in the alloc's namespace once the thread switches back.
+	var conn net.Conn
+	err = netns.Do(func(ns.NetNS) error {
+		var err error
+		conn, err = net.DialTimeout("tcp", addr, timeout)
+		return err
+	})
+	return conn, err
+}
@@ -0,0 +1,20 @@
+// +build !linux
+
+package client
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// dialPortForward dials the address. Network namespaces aren't supported on
+// this platform.
+func dialPortForward(spec *drivers.NetworkIsolationSpec, addr string, timeout time.Duration) (net.Conn, error) {
+	if spec != nil && spec.Path != "" {
+		return nil, errors.New("port forwarding into network namespaces is not supported on this platform")
+	}
+	return net.DialTimeout("tcp", addr, timeout)
+}
@@ -2,6 +2,7 @@ package allocdir
 
 import (
 	"archive/tar"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -93,6 +94,8 @@ type AllocDirFS interface {
 	List(path string) ([]*cstructs.AllocFileInfo, error)
 	Stat(path string) (*cstructs.AllocFileInfo, error)
 	ReadAt(path string, offset int64) (io.ReadCloser, error)
+	WriteFile(path string, r io.Reader, perm os.FileMode, overwrite bool) (*cstructs.AllocFileInfo, error)
+	Archive(path string, w io.Writer, compress bool) error
 	Snapshot(w io.Writer) error
 	BlockUntilExists(ctx context.Context, path string) (chan error, error)
 	ChangeEvents(ctx context.Context, path string, curOffset int64) (*watch.FileChanges, error)
@@ -464,6 +467,300 @@ func (d *AllocDir) ReadAt(path string, offset int64) (io.ReadCloser, error) {
 	return f, nil
 }
 
+// WriteFile writes the content of r to a file at the path relative to the
+// alloc dir, creating missing parent directories. The content is written to a
+// temporary file that replaces the file once complete, so a failed write never
+// leaves a partial file behind. An existing file is only replaced if overwrite
+// is set. The parent directories of the file may not be symlinks.
+func (d *AllocDir) WriteFile(path string, r io.Reader, perm os.FileMode, overwrite bool) (*cstructs.AllocFileInfo, error) {
+	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
+		return nil, fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return nil, fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	p := filepath.Join(d.AllocDir, path)
+	if p == d.AllocDir {
+		return nil, fmt.Errorf("Path is the alloc directory")
+	}
+
+	// Check if it is trying to write into a secret directory
+	d.mu.RLock()
+	for _, dir := range d.TaskDirs {
+		if filepath.HasPrefix(p, dir.SecretsDir) {
+			d.mu.RUnlock()
+			return nil, fmt.Errorf("Writing secret file prohibited: %s", path)
+		}
+	}
+	d.mu.RUnlock()
+
+	// Tasks may create symlinks in their directories, so check the parent
+	// directory doesn't resolve outside of the alloc dir
+	dir := filepath.Dir(p)
+	if err := d.checkResolvesWithin(dir); err != nil {
+		return nil, err
+	}
+
+	if fi, err := os.Lstat(p); err == nil {
+		if fi.IsDir() {
+			return nil, fmt.Errorf("Path %q is a directory", path)
+		}
+		if !overwrite {
+			return nil, fmt.Errorf("File %q already exists", path)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, fmt.Errorf("Failed to create parent directory: %v", err)
+	}
+
+	// The task may swap a parent directory for a symlink at any time, so
+	// the directories are checked again once the temporary file is opened
+	// and before it is renamed
+	if err := d.checkNoSymlinks(dir); err != nil {
+		return nil, err
+	}
+
+	tmp, err := ioutil.TempFile(dir, ".nomad-upload-")
+	if err != nil {
+		return nil, err
+	}
+	tmpInfo, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	if err := writeTempFile(tmp, r, perm); err != nil {
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+
+	if err := d.checkNoSymlinks(dir); err != nil {
+		return nil, err
+	}
+	if fi, err := os.Lstat(tmp.Name()); err != nil || !os.SameFile(fi, tmpInfo) {
+		return nil, fmt.Errorf("Parent directory of %q changed while writing", path)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+
+	return d.Stat(path)
+}
+
+// writeTempFile copies r into the file, sets its permissions and closes it
+func writeTempFile(f *os.File, r io.Reader, perm os.FileMode) error {
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Archive writes a tar archive of the file or directory at the path relative
+// to the alloc dir to w, gzip compressed if compress is set. Entries are named
+// relative to the alloc dir. Secret directories are skipped and symlinks are
+// archived as links without being followed. The path is validated before
+// anything is written, so an error with nothing written means the path can't
+// be archived.
+func (d *AllocDir) Archive(path string, w io.Writer, compress bool) error {
+	return d.ArchivePaths([]string{path}, w, compress)
+}
+
+// ArchivePaths writes a single tar archive of the files and directories at
+// the paths relative to the alloc dir to w. All paths are validated before
+// anything is written. See Archive for details.
+func (d *AllocDir) ArchivePaths(paths []string, w io.Writer, compress bool) error {
+	roots := make([]string, len(paths))
+	for i, path := range paths {
+		p, err := d.archiveRoot(path)
+		if err != nil {
+			return err
+		}
+		roots[i] = p
+	}
+
+	d.mu.RLock()
+	secretDirs := make([]string, 0, len(d.TaskDirs))
+	for _, dir := range d.TaskDirs {
+		secretDirs = append(secretDirs, dir.SecretsDir)
+	}
+	d.mu.RUnlock()
+
+	if compress {
+		gw := gzip.NewWriter(w)
+		defer gw.Close()
+		w = gw
+	}
+
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	walkFn := func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		for _, dir := range secretDirs {
+			if path == dir {
+				return filepath.SkipDir
+			}
+		}
+
+		relPath, err := filepath.Rel(d.AllocDir, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		// Only directories, symlinks and regular files can be archived
+		link := ""
+		switch mode := fileInfo.Mode(); {
+		case mode&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("error reading symlink: %v", err)
+			}
+			link = target
+		case mode.IsDir(), mode.IsRegular():
+		default:
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(fileInfo, link)
+		if err != nil {
+			return fmt.Errorf("error creating file header: %v", err)
+		}
+		hdr.Name = filepath.ToSlash(relPath)
+		if fileInfo.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		// Write the file into the archive
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		// Only copy the size written in the header as the file may still be
+		// written to by the task
+		_, err = io.CopyN(tw, file, hdr.Size)
+		return err
+	}
+
+	for _, root := range roots {
+		if err := filepath.Walk(root, walkFn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveRoot validates that the path relative to the alloc dir can be
+// archived and returns its absolute path.
+func (d *AllocDir) archiveRoot(path string) (string, error) {
+	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
+		return "", fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return "", fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	p := filepath.Join(d.AllocDir, path)
+
+	// Check if it is trying to read a secret directory
+	d.mu.RLock()
+	for _, dir := range d.TaskDirs {
+		if filepath.HasPrefix(p, dir.SecretsDir) {
+			d.mu.RUnlock()
+			return "", fmt.Errorf("Reading secret file prohibited: %s", path)
+		}
+	}
+	d.mu.RUnlock()
+
+	// Tasks may create symlinks in their directories, so check the parent
+	// directory doesn't resolve outside of the alloc dir
+	if p != d.AllocDir {
+		if err := d.checkResolvesWithin(filepath.Dir(p)); err != nil {
+			return "", err
+		}
+	}
+	if _, err := os.Lstat(p); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// checkResolvesWithin returns an error if the deepest existing ancestor of
+// the path resolves to a path outside of the alloc dir.
+func (d *AllocDir) checkResolvesWithin(path string) error {
+	root, err := filepath.EvalSymlinks(d.AllocDir)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resolved, err := filepath.EvalSymlinks(path)
+		if os.IsNotExist(err) {
+			path = filepath.Dir(path)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if rel, err := filepath.Rel(root, resolved); err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("Path escapes the alloc directory")
+		}
+		return nil
+	}
+}
+
+// checkNoSymlinks returns an error if a directory of the path below the alloc
+// dir is a symlink or isn't a directory.
+func (d *AllocDir) checkNoSymlinks(path string) error {
+	rel, err := filepath.Rel(d.AllocDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	cur := d.AllocDir
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		if name == "." {
+			continue
+		}
+		cur = filepath.Join(cur, name)
+
+		fi, err := os.Lstat(cur)
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("Path %q is a symlink", cur)
+		}
+		if !fi.IsDir() {
+			return fmt.Errorf("Path %q is not a directory", cur)
+		}
+	}
+	return nil
+}
+
 // BlockUntilExists blocks until the passed file relative the allocation
 // directory exists. The block can be cancelled with the passed context.
 func (d *AllocDir) BlockUntilExists(ctx context.Context, path string) (chan error, error) {
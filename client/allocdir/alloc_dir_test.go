@@ -3,6 +3,7 @@ package allocdir
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"io"
 	"io/ioutil"
@@ -13,6 +14,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"testing/iotest"
 
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -355,6 +357,222 @@ func TestAllocDir_ReadAt_SecretDir(t *testing.T) {
 	}
 }
 
+func TestAllocDir_WriteFile(t *testing.T) {
+	require := require.New(t)
+
+	tmp, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(err)
+	defer os.RemoveAll(tmp)
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp)
+	require.NoError(d.Build())
+	defer d.Destroy()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(td.Build(false, nil))
+
+	// Files are written with missing parent directories
+	path := filepath.Join(t1.Name, TaskLocal, "tools", "tool.sh")
+	info, err := d.WriteFile(path, strings.NewReader("echo hi"), 0700, false)
+	require.NoError(err)
+	require.Equal("tool.sh", info.Name)
+	require.EqualValues(7, info.Size)
+
+	fi, err := os.Stat(filepath.Join(td.LocalDir, "tools", "tool.sh"))
+	require.NoError(err)
+	require.Equal(os.FileMode(0700), fi.Mode().Perm())
+
+	// Existing files are only replaced if overwrite is set
+	_, err = d.WriteFile(path, strings.NewReader("echo bye"), 0700, false)
+	require.Error(err)
+	require.Contains(err.Error(), "already exists")
+
+	info, err = d.WriteFile(path, strings.NewReader("echo bye"), 0700, true)
+	require.NoError(err)
+	require.EqualValues(8, info.Size)
+
+	// Failed writes leave no file behind
+	failed := filepath.Join(SharedAllocName, "failed")
+	_, err = d.WriteFile(failed, iotest.TimeoutReader(strings.NewReader("data")), 0644, false)
+	require.Error(err)
+	fis, err := ioutil.ReadDir(d.SharedDir)
+	require.NoError(err)
+	for _, fi := range fis {
+		require.NotEqual("failed", fi.Name())
+		require.False(strings.HasPrefix(fi.Name(), ".nomad-upload-"), "found temporary file %q", fi.Name())
+	}
+
+	// Writes into the secret dir, directories or outside of the alloc dir fail
+	_, err = d.WriteFile(filepath.Join(t1.Name, TaskSecrets, "test_file"), strings.NewReader(""), 0644, false)
+	require.Error(err)
+	require.Contains(err.Error(), "secret file prohibited")
+
+	_, err = d.WriteFile(filepath.Join(t1.Name, TaskLocal), strings.NewReader(""), 0644, true)
+	require.Error(err)
+	require.Contains(err.Error(), "is a directory")
+
+	_, err = d.WriteFile("../foo", strings.NewReader(""), 0644, false)
+	require.Error(err)
+	require.Contains(err.Error(), "escapes")
+
+	// Symlinks resolving outside of the alloc dir are rejected
+	outside, err := ioutil.TempDir("", "AllocDirOutside")
+	require.NoError(err)
+	defer os.RemoveAll(outside)
+	require.NoError(os.Symlink(outside, filepath.Join(td.LocalDir, "link")))
+
+	_, err = d.WriteFile(filepath.Join(t1.Name, TaskLocal, "link", "foo"), strings.NewReader(""), 0644, false)
+	require.Error(err)
+	require.Contains(err.Error(), "escapes")
+	_, err = os.Stat(filepath.Join(outside, "foo"))
+	require.True(os.IsNotExist(err))
+
+	// Symlinks resolving within the alloc dir are rejected too
+	require.NoError(os.Symlink(td.LocalDir, filepath.Join(td.LocalDir, "self")))
+	_, err = d.WriteFile(filepath.Join(t1.Name, TaskLocal, "self", "foo"), strings.NewReader(""), 0644, false)
+	require.Error(err)
+	require.Contains(err.Error(), "is a symlink")
+}
+
+// swapReader swaps a directory for a symlink on its first read, as a task
+// racing a write would
+type swapReader struct {
+	r       io.Reader
+	dir     string
+	target  string
+	swapped bool
+	err     error
+}
+
+func (s *swapReader) Read(p []byte) (int, error) {
+	if !s.swapped {
+		s.swapped = true
+		if err := os.Rename(s.dir, s.dir+".orig"); err != nil {
+			s.err = err
+		} else {
+			s.err = os.Symlink(s.target, s.dir)
+		}
+	}
+	return s.r.Read(p)
+}
+
+func TestAllocDir_WriteFile_SymlinkSwap(t *testing.T) {
+	require := require.New(t)
+
+	tmp, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(err)
+	defer os.RemoveAll(tmp)
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp)
+	require.NoError(d.Build())
+	defer d.Destroy()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(td.Build(false, nil))
+
+	outside, err := ioutil.TempDir("", "AllocDirOutside")
+	require.NoError(err)
+	defer os.RemoveAll(outside)
+
+	// Swap the parent directory for a symlink outside of the alloc dir while
+	// the file is written
+	dir := filepath.Join(td.LocalDir, "tools")
+	require.NoError(os.MkdirAll(dir, 0777))
+	r := &swapReader{
+		r:      strings.NewReader("echo hi"),
+		dir:    dir,
+		target: outside,
+	}
+
+	_, err = d.WriteFile(filepath.Join(t1.Name, TaskLocal, "tools", "tool.sh"), r, 0700, false)
+	require.NoError(r.err)
+	require.Error(err)
+	require.Contains(err.Error(), "is a symlink")
+
+	fis, err := ioutil.ReadDir(outside)
+	require.NoError(err)
+	require.Empty(fis)
+}
+
+func TestAllocDir_Archive(t *testing.T) {
+	require := require.New(t)
+
+	tmp, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(err)
+	defer os.RemoveAll(tmp)
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp)
+	require.NoError(d.Build())
+	defer d.Destroy()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(td.Build(false, nil))
+
+	dataDir := filepath.Join(d.SharedDir, SharedDataDir)
+	require.NoError(os.MkdirAll(filepath.Join(dataDir, "results"), 0777))
+	require.NoError(ioutil.WriteFile(filepath.Join(dataDir, "results", "out.xml"), []byte("<ok/>"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(td.SecretsDir, "token"), []byte("secret"), 0600))
+
+	outside, err := ioutil.TempDir("", "AllocDirOutside")
+	require.NoError(err)
+	defer os.RemoveAll(outside)
+	require.NoError(ioutil.WriteFile(filepath.Join(outside, "foo"), []byte("foo"), 0644))
+	require.NoError(os.Symlink(outside, filepath.Join(td.LocalDir, "link")))
+
+	// readArchive returns the contents of the archive keyed by entry name
+	readArchive := func(r io.Reader) map[string]string {
+		entries := make(map[string]string)
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return entries
+			}
+			require.NoError(err)
+
+			data, err := ioutil.ReadAll(tr)
+			require.NoError(err)
+			entries[hdr.Name] = string(data)
+		}
+	}
+
+	// Archiving the alloc dir skips the secrets and doesn't follow symlinks
+	var buf bytes.Buffer
+	require.NoError(d.Archive("/", &buf, false))
+	entries := readArchive(&buf)
+	require.Equal("<ok/>", entries["alloc/data/results/out.xml"])
+	require.Contains(entries, filepath.Join(t1.Name, TaskLocal, "link"))
+	for name := range entries {
+		require.NotContains(name, TaskSecrets)
+	}
+
+	// Archives of sub directories are named relative to the alloc dir
+	buf.Reset()
+	require.NoError(d.Archive("alloc/data", &buf, true))
+	gr, err := gzip.NewReader(&buf)
+	require.NoError(err)
+	entries = readArchive(gr)
+	require.Len(entries, 3)
+	require.Contains(entries, "alloc/data/")
+	require.Contains(entries, "alloc/data/results/")
+	require.Equal("<ok/>", entries["alloc/data/results/out.xml"])
+
+	// Invalid paths fail before anything is written
+	cases := map[string]string{
+		filepath.Join(t1.Name, TaskSecrets): "secret file prohibited",
+		"../foo":                            "escapes",
+		filepath.Join(t1.Name, TaskLocal, "link", "foo"): "escapes",
+		"missing": "no such file",
+	}
+	for path, expected := range cases {
+		buf.Reset()
+		err := d.Archive(path, &buf, false)
+		require.Error(err)
+		require.Contains(err.Error(), expected)
+		require.Zero(buf.Len())
+	}
+}
+
 func TestAllocDir_SplitPath(t *testing.T) {
 	dir, err := ioutil.TempDir("", "tmpdirtest")
 	if err != nil {
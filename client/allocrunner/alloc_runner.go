@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/state"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/allocwatcher"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/consul"
@@ -117,6 +118,12 @@ type allocRunner struct {
 	// tasks are the set of task runners
 	tasks map[string]*taskrunner.TaskRunner
 
+	// networkIsolationSpec is the alloc's network namespace set by the
+	// network hook and is nil if the alloc uses host networking. It is
+	// guarded by networkIsolationLock.
+	networkIsolationSpec *drivers.NetworkIsolationSpec
+	networkIsolationLock sync.Mutex
+
 	// deviceStatsReporter is used to lookup resource usage for alloc devices
 	deviceStatsReporter cinterfaces.DeviceStatsReporter
 
@@ -142,6 +149,10 @@ type allocRunner struct {
 	// servers have been contacted for the first time in case of a failed
 	// restore.
 	serversContactedCh chan struct{}
+
+	// artifactCache is the client's artifact cache passed to TaskRunners.
+	// It is nil if artifacts aren't cached.
+	artifactCache *getter.Cache
 }
 
 // NewAllocRunner returns a new allocation runner.
@@ -174,6 +185,7 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		devicemanager:            config.DeviceManager,
 		driverManager:            config.DriverManager,
 		serversContactedCh:       config.ServersContactedCh,
+		artifactCache:            config.ArtifactCache,
 	}
 
 	// Create the logger based on the allocation ID
@@ -215,6 +227,7 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			DeviceManager:       ar.devicemanager,
 			DriverManager:       ar.driverManager,
 			ServersContactedCh:  ar.serversContactedCh,
+			ArtifactCache:       ar.artifactCache,
 		}
 
 		// Create, but do not Run, the task runner
@@ -567,6 +580,8 @@ func (ar *allocRunner) clientAlloc(taskStates map[string]*structs.TaskState) *st
 		a.DeploymentStatus = d.Copy()
 	}
 
+	a.ArchiveStatus = ar.state.ArchiveStatus.Copy()
+
 	// Compute the ClientStatus
 	if ar.state.ClientStatus != "" {
 		// The client status is being forced
@@ -1089,6 +1104,14 @@ func (ar *allocRunner) GetTaskExecHandler(taskName string) drivermanager.TaskExe
 	return tr.TaskExecHandler()
 }
 
+// GetNetworkIsolation returns the network namespace of the alloc or nil if it
+// uses host networking.
+func (ar *allocRunner) GetNetworkIsolation() *drivers.NetworkIsolationSpec {
+	ar.networkIsolationLock.Lock()
+	defer ar.networkIsolationLock.Unlock()
+	return ar.networkIsolationSpec
+}
+
 func (ar *allocRunner) GetTaskDriverCapabilities(taskName string) (*drivers.Capabilities, error) {
 	tr, ok := ar.tasks[taskName]
 	if !ok {
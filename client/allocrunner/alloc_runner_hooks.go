@@ -12,6 +12,30 @@ import (
 	"github.com/hashicorp/nomad/plugins/drivers"
 )
 
+// allocArchiveStatusSetter is a shim to allow the alloc archive hook to
+// record where the alloc dir was archived to without full access to the alloc
+// runner state
+type allocArchiveStatusSetter struct {
+	ar *allocRunner
+}
+
+// SetArchiveStatus sets the archive status of the alloc and updates the
+// server.
+func (a *allocArchiveStatusSetter) SetArchiveStatus(status *structs.AllocArchiveStatus) {
+	a.ar.stateLock.Lock()
+	a.ar.state.ArchiveStatus = status
+	a.ar.stateLock.Unlock()
+
+	// Gather the state of the tasks
+	states := make(map[string]*structs.TaskState, len(a.ar.tasks))
+	for name, tr := range a.ar.tasks {
+		states[name] = tr.TaskState()
+	}
+
+	// Update the server
+	a.ar.stateUpdater.AllocStateUpdated(a.ar.clientAlloc(states))
+}
+
 type networkIsolationSetter interface {
 	SetNetworkIsolation(*drivers.NetworkIsolationSpec)
 }
@@ -24,6 +48,10 @@ type allocNetworkIsolationSetter struct {
 }
 
 func (a *allocNetworkIsolationSetter) SetNetworkIsolation(n *drivers.NetworkIsolationSpec) {
+	a.ar.networkIsolationLock.Lock()
+	a.ar.networkIsolationSpec = n
+	a.ar.networkIsolationLock.Unlock()
+
 	for _, tr := range a.ar.tasks {
 		tr.SetNetworkIsolation(n)
 	}
@@ -118,9 +146,11 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 	}
 
 	// Create the alloc directory hook. This is run first to ensure the
-	// directory path exists for other hooks.
+	// directory path exists for other hooks. The archive hook precedes it as
+	// it must archive the directory before it is destroyed.
 	alloc := ar.Alloc()
 	ar.runnerHooks = []interfaces.RunnerHook{
+		newAllocArchiveHook(hookLogger, alloc, ar.allocDir, config.Node.HostVolumes, &allocArchiveStatusSetter{ar}),
 		newAllocDirHook(hookLogger, ar.allocDir),
 		newUpstreamAllocsHook(hookLogger, ar.prevAllocWatcher),
 		newDiskMigrationHook(hookLogger, ar.prevAllocMigrator, ar.allocDir),
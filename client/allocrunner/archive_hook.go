@@ -0,0 +1,144 @@
+package allocrunner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+type archiveStatusSetter interface {
+	SetArchiveStatus(*structs.AllocArchiveStatus)
+}
+
+// allocArchiveHook archives the paths of the alloc dir configured by the task
+// group's archive stanza into a host volume before the alloc dir is destroyed.
+type allocArchiveHook struct {
+	alloc     *structs.Allocation
+	allocLock sync.Mutex
+
+	allocDir    *allocdir.AllocDir
+	hostVolumes map[string]*structs.ClientHostVolumeConfig
+	setter      archiveStatusSetter
+	logger      log.Logger
+}
+
+func newAllocArchiveHook(logger log.Logger, alloc *structs.Allocation, allocDir *allocdir.AllocDir,
+	hostVolumes map[string]*structs.ClientHostVolumeConfig, setter archiveStatusSetter) *allocArchiveHook {
+
+	h := &allocArchiveHook{
+		alloc:       alloc,
+		allocDir:    allocDir,
+		hostVolumes: hostVolumes,
+		setter:      setter,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (h *allocArchiveHook) Name() string {
+	return "alloc_archive"
+}
+
+func (h *allocArchiveHook) Update(req *interfaces.RunnerUpdateRequest) error {
+	h.allocLock.Lock()
+	defer h.allocLock.Unlock()
+
+	h.alloc = req.Alloc
+	return nil
+}
+
+func (h *allocArchiveHook) Destroy() error {
+	h.allocLock.Lock()
+	alloc := h.alloc
+	h.allocLock.Unlock()
+
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil || tg.Archive == nil {
+		return nil
+	}
+
+	status, err := h.archive(alloc, tg)
+	if err != nil {
+		status = &structs.AllocArchiveStatus{
+			Error: err.Error(),
+		}
+	}
+	status.Timestamp = time.Now()
+	h.setter.SetArchiveStatus(status)
+
+	return err
+}
+
+// archive writes the archive into the host volume and returns its location.
+func (h *allocArchiveHook) archive(alloc *structs.Allocation, tg *structs.TaskGroup) (*structs.AllocArchiveStatus, error) {
+	req, ok := tg.Volumes[tg.Archive.Volume]
+	if !ok {
+		// Should never happen unless we misvalidated on job submission
+		return nil, fmt.Errorf("No group volume declaration found named: %s", tg.Archive.Volume)
+	}
+
+	hostVolume, ok := h.hostVolumes[req.Source]
+	if !ok {
+		return nil, fmt.Errorf("No host volume named: %s", req.Source)
+	}
+	if hostVolume.ReadOnly || req.ReadOnly {
+		return nil, fmt.Errorf("Host volume %s is read only", req.Source)
+	}
+
+	// Only archive the paths the tasks created
+	var paths []string
+	for _, path := range tg.Archive.Paths {
+		if _, err := os.Lstat(filepath.Join(h.allocDir.AllocDir, path)); err != nil {
+			h.logger.Debug("skipping missing archive path", "path", path)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("None of the archive paths exist")
+	}
+
+	// Job IDs may contain slashes, so ensure the archive stays in the volume
+	rel := filepath.Join(alloc.Namespace, alloc.JobID, alloc.ID+".tar.gz")
+	if strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("Archive path %q escapes the host volume", rel)
+	}
+
+	dst := filepath.Join(hostVolume.Path, rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, fmt.Errorf("Failed to create archive directory: %v", err)
+	}
+
+	// Write to a temporary file first so a failed archive never leaves a
+	// partial archive behind
+	tmp, err := ioutil.TempFile(filepath.Dir(dst), ".nomad-archive-")
+	if err != nil {
+		return nil, err
+	}
+	err = h.allocDir.ArchivePaths(paths, tmp, true)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), dst)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("Failed to archive allocation directory: %v", err)
+	}
+
+	h.logger.Info("archived allocation directory", "volume", req.Source, "path", rel)
+	return &structs.AllocArchiveStatus{
+		Volume: req.Source,
+		Path:   rel,
+	}, nil
+}
@@ -0,0 +1,182 @@
+package allocrunner
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// statically assert archive hook implements the expected interfaces
+var _ interfaces.RunnerDestroyHook = (*allocArchiveHook)(nil)
+var _ interfaces.RunnerUpdateHook = (*allocArchiveHook)(nil)
+
+type mockArchiveStatusSetter struct {
+	status *structs.AllocArchiveStatus
+}
+
+func (m *mockArchiveStatusSetter) SetArchiveStatus(status *structs.AllocArchiveStatus) {
+	m.status = status
+}
+
+// archiveAlloc returns a batch alloc archiving its data dir into the host
+// volume "results"
+func archiveAlloc() *structs.Allocation {
+	alloc := mock.BatchAlloc()
+	tg := alloc.Job.TaskGroups[0]
+	tg.Volumes = map[string]*structs.VolumeRequest{
+		"results": {
+			Name:   "results",
+			Type:   structs.VolumeTypeHost,
+			Source: "results",
+		},
+	}
+	tg.Archive = &structs.AllocArchive{
+		Paths:  []string{"alloc/data", "missing"},
+		Volume: "results",
+	}
+	return alloc
+}
+
+func TestAllocArchiveHook_Destroy(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	logger := testlog.HCLogger(t)
+	allocDir, cleanup := allocdir.TestAllocDir(t, logger, "AllocArchiveHook")
+	defer cleanup()
+
+	volume, err := ioutil.TempDir("", "AllocArchiveHookVolume")
+	require.NoError(err)
+	defer os.RemoveAll(volume)
+
+	dataDir := filepath.Join(allocDir.SharedDir, allocdir.SharedDataDir)
+	require.NoError(ioutil.WriteFile(filepath.Join(dataDir, "out.txt"), []byte("done"), 0644))
+
+	hostVolumes := map[string]*structs.ClientHostVolumeConfig{
+		"results": {Name: "results", Path: volume},
+	}
+
+	alloc := archiveAlloc()
+	setter := &mockArchiveStatusSetter{}
+	h := newAllocArchiveHook(logger, alloc, allocDir, hostVolumes, setter)
+	require.NoError(h.Destroy())
+
+	// The archive location is recorded
+	status := setter.status
+	require.NotNil(status)
+	require.Empty(status.Error)
+	require.Equal("results", status.Volume)
+	require.Equal(filepath.Join(alloc.Namespace, alloc.JobID, alloc.ID+".tar.gz"), status.Path)
+	require.False(status.Timestamp.IsZero())
+
+	// The archive contains the existing paths
+	f, err := os.Open(filepath.Join(volume, status.Path))
+	require.NoError(err)
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	require.NoError(err)
+	tr := tar.NewReader(gr)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(err)
+		files[hdr.Name] = string(data)
+	}
+	require.Equal("done", files["alloc/data/out.txt"])
+}
+
+func TestAllocArchiveHook_Destroy_Errors(t *testing.T) {
+	t.Parallel()
+
+	logger := testlog.HCLogger(t)
+	allocDir, cleanup := allocdir.TestAllocDir(t, logger, "AllocArchiveHook")
+	defer cleanup()
+
+	volume, err := ioutil.TempDir("", "AllocArchiveHookVolume")
+	require.NoError(t, err)
+	defer os.RemoveAll(volume)
+
+	cases := []struct {
+		Name          string
+		HostVolumes   map[string]*structs.ClientHostVolumeConfig
+		Paths         []string
+		ExpectedError string
+	}{
+		{
+			Name:          "missing host volume",
+			Paths:         []string{"alloc/data"},
+			ExpectedError: "No host volume named: results",
+		},
+		{
+			Name: "read only host volume",
+			HostVolumes: map[string]*structs.ClientHostVolumeConfig{
+				"results": {Name: "results", Path: volume, ReadOnly: true},
+			},
+			Paths:         []string{"alloc/data"},
+			ExpectedError: "is read only",
+		},
+		{
+			Name: "missing paths",
+			HostVolumes: map[string]*structs.ClientHostVolumeConfig{
+				"results": {Name: "results", Path: volume},
+			},
+			Paths:         []string{"missing"},
+			ExpectedError: "None of the archive paths exist",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			alloc := archiveAlloc()
+			alloc.Job.TaskGroups[0].Archive.Paths = c.Paths
+
+			setter := &mockArchiveStatusSetter{}
+			h := newAllocArchiveHook(logger, alloc, allocDir, c.HostVolumes, setter)
+			err := h.Destroy()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.ExpectedError)
+
+			// The error is recorded
+			require.NotNil(t, setter.status)
+			require.Contains(t, setter.status.Error, c.ExpectedError)
+		})
+	}
+}
+
+func TestAllocArchiveHook_Destroy_NoArchive(t *testing.T) {
+	t.Parallel()
+
+	logger := testlog.HCLogger(t)
+	allocDir, cleanup := allocdir.TestAllocDir(t, logger, "AllocArchiveHook")
+	defer cleanup()
+
+	// Allocs without an archive stanza are not archived
+	setter := &mockArchiveStatusSetter{}
+	h := newAllocArchiveHook(logger, mock.BatchAlloc(), allocDir, nil, setter)
+	require.NoError(t, h.Destroy())
+	require.Nil(t, setter.status)
+
+	// Updates to the alloc are archived
+	alloc := archiveAlloc()
+	require.NoError(t, h.Update(&interfaces.RunnerUpdateRequest{Alloc: alloc}))
+	require.Error(t, h.Destroy())
+	require.NotNil(t, setter.status)
+}
@@ -70,9 +70,11 @@ type allocHealthWatcherHook struct {
 func newAllocHealthWatcherHook(logger log.Logger, alloc *structs.Allocation, hs healthSetter,
 	listener *cstructs.AllocListener, consul consul.ConsulServiceAPI) interfaces.RunnerHook {
 
-	// Neither deployments nor migrations care about the health of
-	// non-service jobs so never watch their health
-	if alloc.Job.Type != structs.JobTypeService {
+	// Neither deployments nor migrations care about the health of batch
+	// jobs so never watch their health
+	switch alloc.Job.Type {
+	case structs.JobTypeService, structs.JobTypeSystem:
+	default:
 		return noopAllocHealthWatcherHook{}
 	}
 
@@ -115,6 +117,12 @@ func (h *allocHealthWatcherHook) init() error {
 
 	h.isDeploy = h.alloc.DeploymentID != ""
 
+	// System jobs are never migrated so only deployments care about their
+	// health
+	if !h.isDeploy && h.alloc.Job.Type == structs.JobTypeSystem {
+		return nil
+	}
+
 	// No need to watch allocs for deployments that rely on operators
 	// manually setting health
 	if h.isDeploy && (tg.Update.IsEmpty() || tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Manual) {
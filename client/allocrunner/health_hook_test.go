@@ -300,25 +300,48 @@ func TestHealthHook_SetHealth(t *testing.T) {
 	require.NoError(h.Postrun())
 }
 
-// TestHealthHook_SystemNoop asserts that system jobs return the noop tracker.
-func TestHealthHook_SystemNoop(t *testing.T) {
+// TestHealthHook_System asserts that system jobs only watch health while being
+// deployed.
+func TestHealthHook_System(t *testing.T) {
 	t.Parallel()
+	require := require.New(t)
 
-	h := newAllocHealthWatcherHook(testlog.HCLogger(t), mock.SystemAlloc(), nil, nil, nil)
+	logger := testlog.HCLogger(t)
+	b := cstructs.NewAllocBroadcaster(logger)
+	defer b.Close()
 
-	// Assert that it's the noop impl
-	_, ok := h.(noopAllocHealthWatcherHook)
-	require.True(t, ok)
+	consul := consul.NewMockConsulServiceClient(t, logger)
+	hs := &mockHealthSetter{}
 
-	// Assert the noop impl does not implement any hooks
-	_, ok = h.(interfaces.RunnerPrerunHook)
-	require.False(t, ok)
-	_, ok = h.(interfaces.RunnerUpdateHook)
-	require.False(t, ok)
-	_, ok = h.(interfaces.RunnerPostrunHook)
-	require.False(t, ok)
-	_, ok = h.(interfaces.ShutdownHook)
-	require.False(t, ok)
+	alloc := mock.SystemAlloc()
+	alloc.Job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+
+	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul).(*allocHealthWatcherHook)
+
+	// Prerun without a deployment does not watch health
+	require.NoError(h.Prerun())
+	h.hookLock.Lock()
+	require.False(h.isDeploy)
+	select {
+	case <-h.watchDone:
+	default:
+		t.Fatalf("expected health not to be watched")
+	}
+	h.hookLock.Unlock()
+
+	// Update with a deployment starts watching health
+	alloc.DeploymentID = uuid.Generate()
+	require.NoError(h.Update(&interfaces.RunnerUpdateRequest{Alloc: alloc}))
+	h.hookLock.Lock()
+	require.True(h.isDeploy)
+	select {
+	case <-h.watchDone:
+		t.Fatalf("expected health to be watched")
+	default:
+	}
+	h.hookLock.Unlock()
+
+	require.NoError(h.Postrun())
 }
 
 // TestHealthHook_BatchNoop asserts that batch jobs return the noop tracker.
@@ -20,6 +20,9 @@ type State struct {
 
 	// TaskStates is a snapshot of task states.
 	TaskStates map[string]*structs.TaskState
+
+	// ArchiveStatus captures where the alloc dir was archived to
+	ArchiveStatus *structs.AllocArchiveStatus
 }
 
 // SetDeploymentStatus is a helper for updating the client-controlled
@@ -57,6 +60,7 @@ func (s *State) Copy() *State {
 		ClientDescription: s.ClientDescription,
 		DeploymentStatus:  s.DeploymentStatus.Copy(),
 		TaskStates:        taskStates,
+		ArchiveStatus:     s.ArchiveStatus.Copy(),
 	}
 }
 
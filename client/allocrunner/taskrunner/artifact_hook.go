@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -15,16 +16,35 @@ import (
 type artifactHook struct {
 	eventEmitter ti.EventEmitter
 	logger       log.Logger
+
+	// cache is the client's artifact cache. It is nil if artifacts aren't
+	// cached.
+	cache *getter.Cache
+
+	// limits bounds the resources used to fetch each artifact
+	limits *getter.Limits
 }
 
-func newArtifactHook(e ti.EventEmitter, logger log.Logger) *artifactHook {
+func newArtifactHook(e ti.EventEmitter, cache *getter.Cache, limits *getter.Limits, logger log.Logger) *artifactHook {
 	h := &artifactHook{
 		eventEmitter: e,
+		cache:        cache,
+		limits:       limits,
 	}
 	h.logger = logger.Named(h.Name())
 	return h
 }
 
+// artifactLimits returns the client's artifact limits
+func artifactLimits(c *config.Config) *getter.Limits {
+	return &getter.Limits{
+		MaxBytes:              int64(c.ArtifactMaxSizeMB) * 1024 * 1024,
+		MaxFiles:              c.ArtifactMaxFiles,
+		MaxDecompressionRatio: c.ArtifactMaxDecompressionRatio,
+		Timeout:               c.ArtifactTimeout,
+	}
+}
+
 func (*artifactHook) Name() string {
 	// Copied in client/state when upgrading from <0.9 schemas, so if you
 	// change it here you also must change it there.
@@ -52,10 +72,11 @@ func (h *artifactHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 
 		h.logger.Debug("downloading artifact", "artifact", artifact.GetterSource)
 		//XXX add ctx to GetArtifact to allow cancelling long downloads
-		if err := getter.GetArtifact(req.TaskEnv, artifact, req.TaskDir.Dir); err != nil {
+		if err := h.getArtifact(req, artifact); err != nil {
+			// Artifacts breaching the client's limits won't succeed on retry
 			wrapped := structs.NewRecoverableError(
 				fmt.Errorf("failed to download artifact %q: %v", artifact.GetterSource, err),
-				true,
+				structs.IsRecoverable(err),
 			)
 			herr := NewHookError(wrapped, structs.NewTaskEvent(structs.TaskArtifactDownloadFailed).SetDownloadError(wrapped))
 
@@ -71,3 +92,27 @@ func (h *artifactHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 	resp.Done = true
 	return nil
 }
+
+// getArtifact fetches the artifact into the task directory, using the
+// artifact cache if the client has one.
+func (h *artifactHook) getArtifact(req *interfaces.TaskPrestartRequest, artifact *structs.TaskArtifact) error {
+	if h.cache == nil {
+		return getter.GetArtifact(req.TaskEnv, artifact, req.TaskDir.Dir, h.limits)
+	}
+
+	status, err := h.cache.GetArtifact(req.TaskEnv, artifact, req.TaskDir.Dir, h.limits)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case getter.CacheHit:
+		h.logger.Debug("copied artifact from cache", "artifact", artifact.GetterSource)
+		h.eventEmitter.EmitEvent(structs.NewTaskEvent(structs.TaskArtifactCacheHit).
+			SetMessage(fmt.Sprintf("Copied artifact %q from the client's cache", artifact.GetterSource)))
+	case getter.CacheMiss:
+		h.eventEmitter.EmitEvent(structs.NewTaskEvent(structs.TaskArtifactCacheMiss).
+			SetMessage(fmt.Sprintf("Downloaded artifact %q into the client's cache", artifact.GetterSource)))
+	}
+	return nil
+}
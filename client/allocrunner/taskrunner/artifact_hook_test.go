@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -36,7 +37,7 @@ func TestTaskRunner_ArtifactHook_Recoverable(t *testing.T) {
 	t.Parallel()
 
 	me := &mockEmitter{}
-	artifactHook := newArtifactHook(me, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, nil, nil, testlog.HCLogger(t))
 
 	req := &interfaces.TaskPrestartRequest{
 		TaskEnv: taskenv.NewEmptyTaskEnv(),
@@ -62,6 +63,49 @@ func TestTaskRunner_ArtifactHook_Recoverable(t *testing.T) {
 	require.Equal(t, structs.TaskDownloadingArtifacts, me.events[0].Type)
 }
 
+// TestTaskRunner_ArtifactHook_Limits asserts that artifacts breaching the
+// client's artifact limits are a non-recoverable error.
+func TestTaskRunner_ArtifactHook_Limits(t *testing.T) {
+	t.Parallel()
+
+	srcdir, err := ioutil.TempDir("", "nomadtest-src")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcdir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcdir, "foo.txt"), []byte("foo"), 0644))
+
+	ts := httptest.NewServer(http.FileServer(http.Dir(srcdir)))
+	defer ts.Close()
+
+	destdir, err := ioutil.TempDir("", "nomadtest-dest")
+	require.NoError(t, err)
+	defer os.RemoveAll(destdir)
+
+	me := &mockEmitter{}
+	artifactHook := newArtifactHook(me, nil, &getter.Limits{MaxBytes: 1}, testlog.HCLogger(t))
+
+	req := &interfaces.TaskPrestartRequest{
+		TaskEnv: taskenv.NewEmptyTaskEnv(),
+		TaskDir: &allocdir.TaskDir{Dir: destdir},
+		Task: &structs.Task{
+			Artifacts: []*structs.TaskArtifact{
+				{
+					GetterSource: ts.URL + "/foo.txt",
+					GetterMode:   structs.GetterModeAny,
+				},
+			},
+		},
+	}
+
+	resp := interfaces.TaskPrestartResponse{}
+
+	err = artifactHook.Prestart(context.Background(), req, &resp)
+
+	require.False(t, resp.Done)
+	require.NotNil(t, err)
+	require.False(t, structs.IsRecoverable(err))
+	require.Contains(t, err.Error(), "exceeds the maximum")
+}
+
 // TestTaskRunnerArtifactHook_PartialDone asserts that the artifact hook skips
 // already downloaded artifacts when subsequent artifacts fail and cause a
 // restart.
@@ -69,7 +113,7 @@ func TestTaskRunner_ArtifactHook_PartialDone(t *testing.T) {
 	t.Parallel()
 
 	me := &mockEmitter{}
-	artifactHook := newArtifactHook(me, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, nil, nil, testlog.HCLogger(t))
 
 	// Create a source directory with 1 of the 2 artifacts
 	srcdir, err := ioutil.TempDir("", "nomadtest-src")
@@ -0,0 +1,440 @@
+package getter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// checksumOption is the getter option verifying the checksum of an
+	// artifact. Only artifacts with a checksum are cached as the checksum
+	// guarantees the content of the artifact doesn't change.
+	checksumOption = "checksum"
+
+	// cacheTmpPrefix prefixes the paths artifacts are downloaded to before
+	// they are added to the cache and the paths of evicted entries that are
+	// being removed.
+	cacheTmpPrefix = ".tmp-"
+)
+
+// CacheStatus is how an artifact was fetched by the cache
+type CacheStatus int
+
+const (
+	// CacheSkipped means the artifact can't be cached and was downloaded
+	// into the task directory
+	CacheSkipped CacheStatus = iota
+
+	// CacheHit means the artifact was copied from the cache
+	CacheHit
+
+	// CacheMiss means the artifact was downloaded into the cache and copied
+	// from it
+	CacheMiss
+)
+
+// Cache is a client wide cache of downloaded artifacts. Artifacts are keyed
+// by their source and options and are only cached if they have a checksum,
+// so the cached content is the same as a new download. The least recently
+// used artifacts are evicted once the cache exceeds its maximum size.
+type Cache struct {
+	dir      string
+	maxBytes int64
+	logger   hclog.Logger
+
+	// l guards entries and size
+	l       sync.Mutex
+	entries map[string]*cacheEntry
+	size    int64
+}
+
+// cacheEntry is an artifact in the cache
+type cacheEntry struct {
+	key string
+
+	// ready is whether the artifact has been downloaded and size and
+	// lastUsed are its size in bytes and the last time it was copied.
+	ready    bool
+	size     int64
+	lastUsed time.Time
+
+	// refs is the number of fetches using the entry. Entries in use are
+	// never evicted.
+	refs int
+
+	// downloadLock serializes fetches of the entry so the artifact is
+	// downloaded once.
+	downloadLock sync.Mutex
+}
+
+// NewCache returns an artifact cache storing artifacts in the directory, up
+// to maxBytes in total. Artifacts cached by a previous cache in the directory
+// are reused.
+func NewCache(dir string, maxBytes int64, logger hclog.Logger) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create artifact cache dir %q: %v", dir, err)
+	}
+
+	c := &Cache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		logger:   logger.Named("artifact_cache"),
+		entries:  make(map[string]*cacheEntry),
+	}
+
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact cache dir %q: %v", dir, err)
+	}
+	for _, fi := range fis {
+		path := filepath.Join(dir, fi.Name())
+
+		// Remove incomplete downloads and evictions
+		if strings.HasPrefix(fi.Name(), cacheTmpPrefix) {
+			if err := os.RemoveAll(path); err != nil {
+				c.logger.Warn("failed to remove incomplete artifact", "path", path, "error", err)
+			}
+			continue
+		}
+
+		size, err := pathSize(path)
+		if err != nil {
+			c.logger.Warn("failed to determine size of cached artifact", "path", path, "error", err)
+			continue
+		}
+
+		c.entries[fi.Name()] = &cacheEntry{
+			key:      fi.Name(),
+			ready:    true,
+			size:     size,
+			lastUsed: fi.ModTime(),
+		}
+		c.size += size
+	}
+
+	c.l.Lock()
+	c.evictOverLimitLocked()
+	c.l.Unlock()
+	c.emitSize()
+
+	return c, nil
+}
+
+// GetArtifact fetches an artifact into the task directory within the limits,
+// which may be nil. Artifacts with a checksum are downloaded into the cache
+// once and copied into the task directory from there. Other artifacts are
+// downloaded into the task directory directly.
+func (c *Cache) GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact, taskDir string, limits *Limits) (CacheStatus, error) {
+	if taskEnv.ReplaceEnv(artifact.GetterOptions[checksumOption]) == "" {
+		return CacheSkipped, GetArtifact(taskEnv, artifact, taskDir, limits)
+	}
+
+	url, err := getGetterUrl(taskEnv, artifact)
+	if err != nil {
+		return CacheSkipped, newGetError(artifact.GetterSource, err, false)
+	}
+
+	key := cacheKey(url, artifact.GetterMode)
+	e := c.acquire(key)
+	defer c.release(e)
+
+	dest := filepath.Join(taskDir, artifact.RelativeDest)
+	status, err := c.download(e, url, artifact.GetterMode, dest, limits)
+	if err != nil {
+		return status, err
+	}
+
+	switch status {
+	case CacheHit:
+		metrics.IncrCounter([]string{"client", "artifact_cache", "hit"}, 1)
+	case CacheMiss:
+		metrics.IncrCounter([]string{"client", "artifact_cache", "miss"}, 1)
+	}
+
+	// The artifact was too large to be cached and is already in place
+	if status == CacheSkipped {
+		return status, nil
+	}
+
+	path := c.path(key)
+	if err := copyPath(path, dest); err != nil {
+		return status, newGetError(url, fmt.Errorf("failed to copy cached artifact: %v", err), true)
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		c.logger.Warn("failed to update modification time of cached artifact", "path", path, "error", err)
+	}
+	c.l.Lock()
+	e.lastUsed = now
+	c.l.Unlock()
+
+	return status, nil
+}
+
+// download downloads the artifact into the cache unless it has already been
+// downloaded. Artifacts larger than the cache are downloaded into the
+// destination and CacheSkipped is returned.
+func (c *Cache) download(e *cacheEntry, url, mode, dest string, limits *Limits) (CacheStatus, error) {
+	e.downloadLock.Lock()
+	defer e.downloadLock.Unlock()
+
+	c.l.Lock()
+	ready := e.ready
+	c.l.Unlock()
+	if ready {
+		return CacheHit, nil
+	}
+
+	tmp := filepath.Join(c.dir, cacheTmpPrefix+e.key)
+	if err := os.RemoveAll(tmp); err != nil {
+		return CacheMiss, newGetError(url, err, true)
+	}
+
+	if err := get(url, getterMode(mode), tmp, limits); err != nil {
+		os.RemoveAll(tmp)
+		return CacheMiss, err
+	}
+
+	size, err := pathSize(tmp)
+	if err != nil {
+		os.RemoveAll(tmp)
+		return CacheMiss, newGetError(url, err, true)
+	}
+
+	if size > c.maxBytes {
+		c.logger.Debug("artifact is larger than the cache", "artifact", url, "size", size)
+		defer os.RemoveAll(tmp)
+		if err := copyPath(tmp, dest); err != nil {
+			return CacheSkipped, newGetError(url, fmt.Errorf("failed to copy artifact: %v", err), true)
+		}
+		return CacheSkipped, nil
+	}
+
+	if err := os.Rename(tmp, c.path(e.key)); err != nil {
+		os.RemoveAll(tmp)
+		return CacheMiss, newGetError(url, err, true)
+	}
+
+	c.l.Lock()
+	e.ready = true
+	e.size = size
+	e.lastUsed = time.Now()
+	c.size += size
+	c.evictOverLimitLocked()
+	c.l.Unlock()
+	c.emitSize()
+
+	return CacheMiss, nil
+}
+
+// acquire returns the entry with the key, creating it if needed, and marks
+// it in use.
+func (c *Cache) acquire(key string) *cacheEntry {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		e = &cacheEntry{key: key}
+		c.entries[key] = e
+	}
+	e.refs++
+	return e
+}
+
+// release marks the entry as no longer in use by a fetch. Entries whose
+// download failed are removed.
+func (c *Cache) release(e *cacheEntry) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	e.refs--
+	if e.refs == 0 && !e.ready && c.entries[e.key] == e {
+		delete(c.entries, e.key)
+	}
+}
+
+// Dir returns the directory of the cache.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Size returns the total size in bytes of the cached artifacts.
+func (c *Cache) Size() int64 {
+	c.l.Lock()
+	defer c.l.Unlock()
+	return c.size
+}
+
+// Evict removes the least recently used artifact that isn't in use and
+// returns its size in bytes. Zero is returned if there is no artifact to
+// evict.
+func (c *Cache) Evict() int64 {
+	c.l.Lock()
+	path, size := c.evictLocked()
+	c.l.Unlock()
+
+	if path != "" {
+		c.remove(path)
+		c.emitSize()
+	}
+	return size
+}
+
+// evictOverLimitLocked evicts the least recently used artifacts until the
+// cache is within its maximum size. The lock must be held.
+func (c *Cache) evictOverLimitLocked() {
+	for c.size > c.maxBytes {
+		path, _ := c.evictLocked()
+		if path == "" {
+			return
+		}
+		go c.remove(path)
+	}
+}
+
+// evictLocked evicts the least recently used artifact that isn't in use by
+// moving it aside and returns the path it was moved to, which must be
+// removed, and its size. The lock must be held.
+func (c *Cache) evictLocked() (string, int64) {
+	var lru *cacheEntry
+	for _, e := range c.entries {
+		if !e.ready || e.refs > 0 {
+			continue
+		}
+		if lru == nil || e.lastUsed.Before(lru.lastUsed) {
+			lru = e
+		}
+	}
+	if lru == nil {
+		return "", 0
+	}
+
+	// Move the artifact aside so it can be removed without holding the lock
+	// and a new download of the artifact isn't affected by its removal.
+	path := c.path(lru.key)
+	evicted := filepath.Join(c.dir, fmt.Sprintf("%s%s-%d", cacheTmpPrefix, lru.key, time.Now().UnixNano()))
+	if err := os.Rename(path, evicted); err != nil {
+		c.logger.Warn("failed to evict cached artifact", "path", path, "error", err)
+		evicted = path
+	}
+
+	delete(c.entries, lru.key)
+	c.size -= lru.size
+	metrics.IncrCounter([]string{"client", "artifact_cache", "evicted"}, 1)
+	c.logger.Debug("evicted cached artifact", "key", lru.key, "size", lru.size)
+	return evicted, lru.size
+}
+
+// remove removes an evicted artifact
+func (c *Cache) remove(path string) {
+	if err := os.RemoveAll(path); err != nil {
+		c.logger.Warn("failed to remove evicted artifact", "path", path, "error", err)
+	}
+}
+
+// emitSize emits the size of the cache
+func (c *Cache) emitSize() {
+	metrics.SetGauge([]string{"client", "artifact_cache", "size_bytes"}, float32(c.Size()))
+}
+
+// path returns the path of the cached artifact with the key
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// cacheKey returns the key of the artifact downloaded from the getter URL,
+// which includes the artifact's options, in the given mode.
+func cacheKey(url, mode string) string {
+	h := sha256.New()
+	h.Write([]byte(mode))
+	h.Write([]byte{0})
+	h.Write([]byte(url))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// pathSize returns the total size of the files in the path
+func pathSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode().IsRegular() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// copyPath copies the file or directory tree at src to dst. Directories are
+// merged into existing directories at dst.
+func copyPath(src, dst string) error {
+	return filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case fi.IsDir():
+			return os.MkdirAll(target, fi.Mode().Perm())
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, fi.Mode().Perm())
+		}
+	})
+}
+
+// copyFile copies the file at src to dst with the given permissions
+func copyFile(src, dst string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	// The umask may have restricted the permissions of a new file
+	return os.Chmod(dst, perm)
+}
@@ -0,0 +1,238 @@
+package getter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// newCacheTestServer returns a server hosting the test fixtures and a
+// counter of the downloads it served.
+func newCacheTestServer() (*httptest.Server, *int32) {
+	var requests int32
+	fs := http.FileServer(http.Dir(filepath.Dir("./test-fixtures/")))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			atomic.AddInt32(&requests, 1)
+		}
+		fs.ServeHTTP(w, r)
+	}))
+	return ts, &requests
+}
+
+func cacheTestArtifacts(url string) (script, archive *structs.TaskArtifact) {
+	script = &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", url),
+		GetterOptions: map[string]string{
+			"checksum": "md5:bce963762aa2dbfed13caf492a45fb72",
+		},
+	}
+	archive = &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/archive.tar.gz", url),
+		GetterOptions: map[string]string{
+			"checksum": "sha1:20bab73c72c56490856f913cf594bad9a4d730f6",
+		},
+	}
+	return
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(t, err)
+	return dir
+}
+
+func TestCache_GetArtifact_HitMiss(t *testing.T) {
+	require := require.New(t)
+
+	ts, requests := newCacheTestServer()
+	defer ts.Close()
+
+	cacheDir := tempDir(t)
+	defer os.RemoveAll(cacheDir)
+	c, err := NewCache(cacheDir, 1024, testlog.HCLogger(t))
+	require.NoError(err)
+
+	_, archive := cacheTestArtifacts(ts.URL)
+	expected := map[string]string{
+		"exist/my.config": "hello world\n",
+		"new/my.config":   "hello world\n",
+		"test.sh":         "sleep 1\n",
+	}
+
+	// The first fetch downloads the artifact into the cache
+	taskDir1 := tempDir(t)
+	defer os.RemoveAll(taskDir1)
+	status, err := c.GetArtifact(taskEnv, archive, taskDir1, nil)
+	require.NoError(err)
+	require.Equal(CacheMiss, status)
+	checkContents(taskDir1, expected, t)
+	require.EqualValues(32, c.Size())
+
+	// The second fetch copies it from the cache
+	taskDir2 := tempDir(t)
+	defer os.RemoveAll(taskDir2)
+	status, err = c.GetArtifact(taskEnv, archive, taskDir2, nil)
+	require.NoError(err)
+	require.Equal(CacheHit, status)
+	checkContents(taskDir2, expected, t)
+
+	require.EqualValues(1, atomic.LoadInt32(requests))
+	require.EqualValues(32, c.Size())
+}
+
+func TestCache_GetArtifact_Skipped(t *testing.T) {
+	require := require.New(t)
+
+	ts, requests := newCacheTestServer()
+	defer ts.Close()
+
+	cacheDir := tempDir(t)
+	defer os.RemoveAll(cacheDir)
+	c, err := NewCache(cacheDir, 16, testlog.HCLogger(t))
+	require.NoError(err)
+
+	script, archive := cacheTestArtifacts(ts.URL)
+
+	// Artifacts without a checksum aren't cached
+	delete(script.GetterOptions, "checksum")
+	for i := 0; i < 2; i++ {
+		taskDir := tempDir(t)
+		defer os.RemoveAll(taskDir)
+
+		status, err := c.GetArtifact(taskEnv, script, taskDir, nil)
+		require.NoError(err)
+		require.Equal(CacheSkipped, status)
+		checkContents(taskDir, map[string]string{"test.sh": "sleep 1\n"}, t)
+	}
+	require.EqualValues(2, atomic.LoadInt32(requests))
+
+	// Artifacts larger than the cache are downloaded into the task dir
+	taskDir := tempDir(t)
+	defer os.RemoveAll(taskDir)
+	status, err := c.GetArtifact(taskEnv, archive, taskDir, nil)
+	require.NoError(err)
+	require.Equal(CacheSkipped, status)
+	checkContents(taskDir, map[string]string{"new/my.config": "hello world\n"}, t)
+
+	require.Zero(c.Size())
+	fis, err := ioutil.ReadDir(cacheDir)
+	require.NoError(err)
+	require.Empty(fis)
+}
+
+func TestCache_GetArtifact_InvalidChecksum(t *testing.T) {
+	require := require.New(t)
+
+	ts, _ := newCacheTestServer()
+	defer ts.Close()
+
+	cacheDir := tempDir(t)
+	defer os.RemoveAll(cacheDir)
+	c, err := NewCache(cacheDir, 1024, testlog.HCLogger(t))
+	require.NoError(err)
+
+	script, _ := cacheTestArtifacts(ts.URL)
+	script.GetterOptions["checksum"] = "md5:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	taskDir := tempDir(t)
+	defer os.RemoveAll(taskDir)
+	_, err = c.GetArtifact(taskEnv, script, taskDir, nil)
+	require.Error(err)
+
+	// Nothing is left behind in the cache
+	require.Zero(c.Size())
+	require.Empty(c.entries)
+	fis, err := ioutil.ReadDir(cacheDir)
+	require.NoError(err)
+	require.Empty(fis)
+}
+
+func TestCache_Evict(t *testing.T) {
+	require := require.New(t)
+
+	ts, _ := newCacheTestServer()
+	defer ts.Close()
+
+	cacheDir := tempDir(t)
+	defer os.RemoveAll(cacheDir)
+	c, err := NewCache(cacheDir, 36, testlog.HCLogger(t))
+	require.NoError(err)
+
+	script, archive := cacheTestArtifacts(ts.URL)
+
+	taskDir := tempDir(t)
+	defer os.RemoveAll(taskDir)
+	_, err = c.GetArtifact(taskEnv, script, taskDir, nil)
+	require.NoError(err)
+	require.EqualValues(8, c.Size())
+
+	// Caching the archive exceeds the limit and evicts the script
+	_, err = c.GetArtifact(taskEnv, archive, taskDir, nil)
+	require.NoError(err)
+	require.EqualValues(32, c.Size())
+	require.Len(c.entries, 1)
+
+	status, err := c.GetArtifact(taskEnv, archive, taskDir, nil)
+	require.NoError(err)
+	require.Equal(CacheHit, status)
+
+	// Evicting removes the archive
+	require.EqualValues(32, c.Evict())
+	require.Zero(c.Size())
+	require.Zero(c.Evict())
+
+	// The script is removed in the background
+	testutil.WaitForResult(func() (bool, error) {
+		fis, err := ioutil.ReadDir(cacheDir)
+		if err != nil {
+			return false, err
+		}
+		return len(fis) == 0, fmt.Errorf("expected empty cache dir, found %d entries", len(fis))
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestNewCache_Restore(t *testing.T) {
+	require := require.New(t)
+
+	ts, requests := newCacheTestServer()
+	defer ts.Close()
+
+	cacheDir := tempDir(t)
+	defer os.RemoveAll(cacheDir)
+	c, err := NewCache(cacheDir, 1024, testlog.HCLogger(t))
+	require.NoError(err)
+
+	_, archive := cacheTestArtifacts(ts.URL)
+	taskDir := tempDir(t)
+	defer os.RemoveAll(taskDir)
+	_, err = c.GetArtifact(taskEnv, archive, taskDir, nil)
+	require.NoError(err)
+
+	// Incomplete downloads are removed when the cache is restored
+	tmp := filepath.Join(cacheDir, cacheTmpPrefix+"foo")
+	require.NoError(os.MkdirAll(tmp, 0700))
+
+	c, err = NewCache(cacheDir, 1024, testlog.HCLogger(t))
+	require.NoError(err)
+	require.EqualValues(32, c.Size())
+	_, err = os.Stat(tmp)
+	require.True(os.IsNotExist(err))
+
+	status, err := c.GetArtifact(taskEnv, archive, taskDir, nil)
+	require.NoError(err)
+	require.Equal(CacheHit, status)
+	require.EqualValues(1, atomic.LoadInt32(requests))
+}
@@ -1,11 +1,14 @@
 package getter
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	gg "github.com/hashicorp/go-getter"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -89,8 +92,9 @@ func getGetterUrl(taskEnv EnvReplacer, artifact *structs.TaskArtifact) (string,
 	return url, nil
 }
 
-// GetArtifact downloads an artifact into the specified task directory.
-func GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact, taskDir string) error {
+// GetArtifact downloads an artifact into the specified task directory within
+// the limits, which may be nil.
+func GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact, taskDir string, limits *Limits) error {
 	url, err := getGetterUrl(taskEnv, artifact)
 	if err != nil {
 		return newGetError(artifact.GetterSource, err, false)
@@ -98,23 +102,71 @@ func GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact, taskDir st
 
 	// Download the artifact
 	dest := filepath.Join(taskDir, artifact.RelativeDest)
+	return get(url, getterMode(artifact.GetterMode), dest, limits)
+}
 
-	// Convert from string getter mode to go-getter const
-	mode := gg.ClientModeAny
-	switch artifact.GetterMode {
-	case structs.GetterModeFile:
-		mode = gg.ClientModeFile
-	case structs.GetterModeDir:
-		mode = gg.ClientModeDir
+// get downloads the getter URL into the destination within the limits, which
+// may be nil. Breaching a limit returns a non-recoverable GetError.
+func get(url string, mode gg.ClientMode, dst string, limits *Limits) error {
+	client := getClient(url, mode, dst)
+	if limits == nil {
+		if err := client.Get(); err != nil {
+			return newGetError(url, err, true)
+		}
+		return nil
 	}
 
-	if err := getClient(url, mode, dest).Get(); err != nil {
-		return newGetError(url, err, true)
+	l := newLimiter(limits)
+	client.ProgressListener = l
+	client.Decompressors = l.decompressors()
+
+	var deadline time.Time
+	if limits.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), limits.Timeout)
+		defer cancel()
+		client.Ctx = ctx
+		deadline, _ = ctx.Deadline()
+
+		// The HTTP getter only checks the context while copying the response
+		// body, so its requests are bounded by a client timeout.
+		httpGetter := &gg.HttpGetter{
+			Netrc:  true,
+			Client: &http.Client{Timeout: limits.Timeout},
+		}
+		getters := make(map[string]gg.Getter, len(client.Getters))
+		for scheme, getter := range client.Getters {
+			getters[scheme] = getter
+		}
+		getters["http"] = httpGetter
+		getters["https"] = httpGetter
+		client.Getters = getters
 	}
 
+	err := client.Get()
+	if lerr := l.Err(); lerr != nil {
+		return newGetError(url, lerr, false)
+	}
+	if err != nil && !deadline.IsZero() && !time.Now().Before(deadline) {
+		return newGetError(url, newLimitError("artifact download exceeded the timeout of %v", limits.Timeout), false)
+	}
+	if err != nil {
+		return newGetError(url, err, true)
+	}
 	return nil
 }
 
+// getterMode converts from string getter mode to go-getter const
+func getterMode(mode string) gg.ClientMode {
+	switch mode {
+	case structs.GetterModeFile:
+		return gg.ClientModeFile
+	case structs.GetterModeDir:
+		return gg.ClientModeDir
+	default:
+		return gg.ClientModeAny
+	}
+}
+
 // GetError wraps the underlying artifact fetching error with the URL. It
 // implements the RecoverableError interface.
 type GetError struct {
@@ -49,7 +49,7 @@ func TestGetArtifact_FileAndChecksum(t *testing.T) {
 	}
 
 	// Download the artifact
-	if err := GetArtifact(taskEnv, artifact, taskDir); err != nil {
+	if err := GetArtifact(taskEnv, artifact, taskDir, nil); err != nil {
 		t.Fatalf("GetArtifact failed: %v", err)
 	}
 
@@ -83,7 +83,7 @@ func TestGetArtifact_File_RelativeDest(t *testing.T) {
 	}
 
 	// Download the artifact
-	if err := GetArtifact(taskEnv, artifact, taskDir); err != nil {
+	if err := GetArtifact(taskEnv, artifact, taskDir, nil); err != nil {
 		t.Fatalf("GetArtifact failed: %v", err)
 	}
 
@@ -137,7 +137,7 @@ func TestGetArtifact_InvalidChecksum(t *testing.T) {
 	}
 
 	// Download the artifact and expect an error
-	if err := GetArtifact(taskEnv, artifact, taskDir); err == nil {
+	if err := GetArtifact(taskEnv, artifact, taskDir, nil); err == nil {
 		t.Fatalf("GetArtifact should have failed")
 	}
 }
@@ -202,7 +202,7 @@ func TestGetArtifact_Archive(t *testing.T) {
 		},
 	}
 
-	if err := GetArtifact(taskEnv, artifact, taskDir); err != nil {
+	if err := GetArtifact(taskEnv, artifact, taskDir, nil); err != nil {
 		t.Fatalf("GetArtifact failed: %v", err)
 	}
 
@@ -235,7 +235,7 @@ func TestGetArtifact_Setuid(t *testing.T) {
 		},
 	}
 
-	require.NoError(t, GetArtifact(taskEnv, artifact, taskDir))
+	require.NoError(t, GetArtifact(taskEnv, artifact, taskDir, nil))
 
 	var expected map[string]int
 
@@ -0,0 +1,326 @@
+package getter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	gg "github.com/hashicorp/go-getter"
+	"github.com/ulikunitz/xz"
+)
+
+// Limits bounds the resources used to fetch an artifact. Zero values are
+// unlimited.
+type Limits struct {
+	// MaxBytes is the maximum number of bytes downloaded over HTTP and the
+	// maximum number of bytes an archive may expand to.
+	MaxBytes int64
+
+	// MaxFiles is the maximum number of files in an archive.
+	MaxFiles int
+
+	// MaxDecompressionRatio is the maximum ratio between the size an archive
+	// expands to and the size of the archive.
+	MaxDecompressionRatio int
+
+	// Timeout is the maximum time taken to download and decompress an
+	// artifact.
+	Timeout time.Duration
+}
+
+// LimitError is returned when fetching an artifact breaches one of the
+// client's artifact limits.
+type LimitError struct {
+	msg string
+}
+
+func (e *LimitError) Error() string {
+	return e.msg
+}
+
+func newLimitError(format string, args ...interface{}) *LimitError {
+	return &LimitError{msg: fmt.Sprintf(format, args...)}
+}
+
+// limiter enforces the limits on a single artifact fetch. It tracks the
+// bytes downloaded and records the first breached limit, as go-getter doesn't
+// preserve the errors returned by progress trackers and decompressors.
+type limiter struct {
+	limits *Limits
+
+	l          sync.Mutex
+	downloaded int64
+	err        *LimitError
+}
+
+func newLimiter(limits *Limits) *limiter {
+	return &limiter{limits: limits}
+}
+
+// Err returns the first limit breached while fetching the artifact, if any
+func (l *limiter) Err() error {
+	l.l.Lock()
+	defer l.l.Unlock()
+	if l.err == nil {
+		return nil
+	}
+	return l.err
+}
+
+// breach records the breached limit and returns it
+func (l *limiter) breach(err *LimitError) error {
+	l.l.Lock()
+	defer l.l.Unlock()
+	if l.err == nil {
+		l.err = err
+	}
+	return err
+}
+
+// TrackProgress implements go-getter's ProgressTracker to stop downloads that
+// exceed the maximum size.
+func (l *limiter) TrackProgress(src string, currentSize, totalSize int64, stream io.ReadCloser) io.ReadCloser {
+	max := l.limits.MaxBytes
+	if max <= 0 {
+		return stream
+	}
+
+	r := &limitedReader{ReadCloser: stream, l: l}
+	if totalSize > max {
+		r.err = l.breach(newLimitError("artifact size of %d bytes exceeds the maximum of %d bytes", totalSize, max))
+	}
+	return r
+}
+
+// add adds to the bytes downloaded and returns an error if the total exceeds
+// the maximum size.
+func (l *limiter) add(n int) error {
+	l.l.Lock()
+	l.downloaded += int64(n)
+	downloaded := l.downloaded
+	l.l.Unlock()
+
+	if max := l.limits.MaxBytes; downloaded > max {
+		return l.breach(newLimitError("artifact download exceeds the maximum of %d bytes", max))
+	}
+	return nil
+}
+
+// limitedReader fails reads once the download exceeds the maximum size
+type limitedReader struct {
+	io.ReadCloser
+	l   *limiter
+	err error
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	if lerr := r.l.add(n); lerr != nil {
+		r.err = lerr
+		return n, lerr
+	}
+	return n, err
+}
+
+// decompressors returns go-getter's decompressors wrapped to check archives
+// against the limits before they are expanded.
+func (l *limiter) decompressors() map[string]gg.Decompressor {
+	if l.limits.MaxBytes <= 0 && l.limits.MaxFiles <= 0 && l.limits.MaxDecompressionRatio <= 0 {
+		return nil
+	}
+
+	ds := make(map[string]gg.Decompressor, len(gg.Decompressors))
+	for kind, d := range gg.Decompressors {
+		ds[kind] = &limitedDecompressor{
+			Decompressor: d,
+			kind:         kind,
+			l:            l,
+		}
+	}
+	return ds
+}
+
+// limitedDecompressor scans an archive and only decompresses it if its
+// expanded size and number of files are within the limits. Scanning reads
+// the archive without writing to disk and stops as soon as a limit is
+// breached, so archive bombs never reach the task directory.
+type limitedDecompressor struct {
+	gg.Decompressor
+	kind string
+	l    *limiter
+}
+
+func (d *limitedDecompressor) Decompress(dst, src string, dir bool, umask os.FileMode) error {
+	if err := d.l.scan(d.kind, src); err != nil {
+		return err
+	}
+	return d.Decompressor.Decompress(dst, src, dir, umask)
+}
+
+// scan checks the archive of the given kind against the limits
+func (l *limiter) scan(kind, src string) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	e := &expansion{l: l, archiveSize: fi.Size()}
+
+	if kind == "zip" {
+		return e.scanZip(src)
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch kind {
+	case "tar.gz", "tgz":
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		return e.scanTar(r)
+	case "tar.bz2", "tbz2":
+		return e.scanTar(bzip2.NewReader(f))
+	case "tar.xz", "txz":
+		r, err := xz.NewReader(f)
+		if err != nil {
+			return err
+		}
+		return e.scanTar(r)
+	case "gz":
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		return e.scanFile(r)
+	case "bz2":
+		return e.scanFile(bzip2.NewReader(f))
+	case "xz":
+		r, err := xz.NewReader(f)
+		if err != nil {
+			return err
+		}
+		return e.scanFile(r)
+	default:
+		// Unknown archives are expanded unchecked
+		return nil
+	}
+}
+
+// expansion tracks the files and bytes an archive expands to
+type expansion struct {
+	l           *limiter
+	archiveSize int64
+	files       int
+	bytes       int64
+}
+
+func (e *expansion) scanTar(r io.Reader) error {
+	tarR := tar.NewReader(r)
+	for {
+		hdr, err := tarR.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeXGlobalHeader, tar.TypeXHeader, tar.TypeDir:
+			continue
+		}
+		if err := e.scanFile(tarR); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *expansion) scanZip(src string) error {
+	zipR, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer zipR.Close()
+
+	for _, f := range zipR.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		// Read the files rather than trusting the sizes in their headers
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = e.scanFile(r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanFile counts a file in the archive and reads its content up to the
+// remaining byte limit.
+func (e *expansion) scanFile(r io.Reader) error {
+	limits := e.l.limits
+
+	e.files++
+	if limits.MaxFiles > 0 && e.files > limits.MaxFiles {
+		return e.l.breach(newLimitError("archive contains more than the maximum of %d files", limits.MaxFiles))
+	}
+
+	// Only the number of files is limited
+	max := e.maxBytes()
+	if max < 0 {
+		return nil
+	}
+
+	n, err := io.Copy(ioutil.Discard, io.LimitReader(r, max-e.bytes+1))
+	e.bytes += n
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxBytes > 0 && e.bytes > limits.MaxBytes {
+		return e.l.breach(newLimitError("archive expands to more than the maximum of %d bytes", limits.MaxBytes))
+	}
+	if e.bytes > max {
+		return e.l.breach(newLimitError("archive of %d bytes exceeds the maximum decompression ratio of %d",
+			e.archiveSize, limits.MaxDecompressionRatio))
+	}
+	return nil
+}
+
+// maxBytes returns the maximum number of bytes the archive may expand to or
+// -1 if it is unlimited.
+func (e *expansion) maxBytes() int64 {
+	limits := e.l.limits
+	max := int64(-1)
+	if limits.MaxBytes > 0 {
+		max = limits.MaxBytes
+	}
+	if limits.MaxDecompressionRatio > 0 {
+		ratioMax := e.archiveSize * int64(limits.MaxDecompressionRatio)
+		if max < 0 || ratioMax < max {
+			max = ratioMax
+		}
+	}
+	return max
+}
@@ -0,0 +1,158 @@
+package getter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// requireLimitError asserts the error is a non-recoverable GetError caused by
+// breaching a limit.
+func requireLimitError(t *testing.T, err error) {
+	require.Error(t, err)
+	gerr, ok := err.(*GetError)
+	require.True(t, ok, "expected GetError, got %T", err)
+	require.False(t, gerr.IsRecoverable())
+	require.IsType(t, &LimitError{}, gerr.Err)
+}
+
+func TestGetArtifact_Limits(t *testing.T) {
+	ts := httptest.NewServer(http.FileServer(http.Dir(filepath.Dir("./test-fixtures/"))))
+	defer ts.Close()
+
+	cases := []struct {
+		name   string
+		file   string
+		limits *Limits
+		err    bool
+	}{
+		{
+			name:   "within limits",
+			file:   "archive.tar.gz",
+			limits: &Limits{MaxBytes: 1024, MaxFiles: 3, MaxDecompressionRatio: 1, Timeout: time.Minute},
+		},
+		{
+			name:   "download too large",
+			file:   "test.sh",
+			limits: &Limits{MaxBytes: 4},
+			err:    true,
+		},
+		{
+			name:   "too many files",
+			file:   "archive.tar.gz",
+			limits: &Limits{MaxFiles: 2},
+			err:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			taskDir, err := ioutil.TempDir("", "nomad-test")
+			require.NoError(t, err)
+			defer os.RemoveAll(taskDir)
+
+			artifact := &structs.TaskArtifact{
+				GetterSource: fmt.Sprintf("%s/%s", ts.URL, c.file),
+			}
+
+			err = GetArtifact(taskEnv, artifact, taskDir, c.limits)
+			if !c.err {
+				require.NoError(t, err)
+				return
+			}
+
+			requireLimitError(t, err)
+		})
+	}
+}
+
+func TestGetArtifact_Limits_DecompressionRatio(t *testing.T) {
+	require := require.New(t)
+
+	// Create a highly compressible archive
+	srcDir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(srcDir)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err = w.Write(make([]byte, 1024*1024))
+	require.NoError(err)
+	require.NoError(w.Close())
+	require.NoError(ioutil.WriteFile(filepath.Join(srcDir, "zeros.gz"), buf.Bytes(), 0644))
+
+	ts := httptest.NewServer(http.FileServer(http.Dir(srcDir)))
+	defer ts.Close()
+
+	taskDir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(taskDir)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/zeros.gz", ts.URL),
+		GetterMode:   structs.GetterModeFile,
+		RelativeDest: "local/zeros",
+	}
+
+	err = GetArtifact(taskEnv, artifact, taskDir, &Limits{MaxDecompressionRatio: 100})
+	requireLimitError(t, err)
+	require.Contains(err.Error(), "decompression ratio")
+
+	// The archive is too large once expanded
+	err = GetArtifact(taskEnv, artifact, taskDir, &Limits{MaxBytes: 512 * 1024})
+	requireLimitError(t, err)
+	require.Contains(err.Error(), "expands to more than")
+
+	// Nothing is written to the task directory
+	fis, err := ioutil.ReadDir(taskDir)
+	require.NoError(err)
+	require.Empty(fis)
+
+	// The archive expands within a higher ratio
+	err = GetArtifact(taskEnv, artifact, taskDir, &Limits{MaxDecompressionRatio: 10000})
+	require.NoError(err)
+	fi, err := os.Stat(filepath.Join(taskDir, "local", "zeros"))
+	require.NoError(err)
+	require.EqualValues(1024*1024, fi.Size())
+}
+
+func TestGetArtifact_Limits_Timeout(t *testing.T) {
+	require := require.New(t)
+
+	// Stall requests until the test is done
+	doneCh := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-doneCh:
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer ts.Close()
+	defer close(doneCh)
+
+	taskDir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(taskDir)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/slow.sh", ts.URL),
+		GetterMode:   structs.GetterModeFile,
+		RelativeDest: "local/slow.sh",
+	}
+
+	start := time.Now()
+	err = GetArtifact(taskEnv, artifact, taskDir, &Limits{Timeout: 100 * time.Millisecond})
+	requireLimitError(t, err)
+	require.Contains(err.Error(), "timeout")
+	require.True(time.Since(start) < 5*time.Second)
+}
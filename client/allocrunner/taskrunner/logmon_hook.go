@@ -8,10 +8,12 @@ import (
 	"runtime"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	hclog "github.com/hashicorp/go-hclog"
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/logmon"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	bstructs "github.com/hashicorp/nomad/plugins/base/structs"
@@ -37,6 +39,15 @@ type logmonHook struct {
 
 	config *logmonHookConfig
 
+	// sinkStatsCancel stops the collection of the log sink metrics and
+	// sinkStatsDoneCh is closed once it has stopped
+	sinkStatsCancel context.CancelFunc
+	sinkStatsDoneCh chan struct{}
+
+	// sinkLines are the last line counters reported by each log sink, used to
+	// emit the counter metrics as deltas
+	sinkLines map[string]uint64
+
 	logger hclog.Logger
 }
 
@@ -48,9 +59,10 @@ type logmonHookConfig struct {
 
 func newLogMonHook(tr *TaskRunner, logger hclog.Logger) *logmonHook {
 	hook := &logmonHook{
-		runner: tr,
-		config: tr.logmonHookConfig,
-		logger: logger,
+		runner:    tr,
+		config:    tr.logmonHookConfig,
+		sinkLines: make(map[string]uint64),
+		logger:    logger,
 	}
 
 	return hook
@@ -108,6 +120,14 @@ func (h *logmonHook) Prestart(ctx context.Context,
 		return nil
 	}
 
+	// Logmon runs as the client's user, so tasks may only ship logs to the
+	// syslog servers allowed by the operator
+	if err := h.validateSinks(req.Task); err != nil {
+		h.logger.Error("invalid log sink", "error", err)
+		h.runner.EmitEvent(structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(err))
+		return err
+	}
+
 	attempts := 0
 	for {
 		err := h.prestartOneLoop(ctx, req)
@@ -139,6 +159,20 @@ func (h *logmonHook) Prestart(ctx context.Context,
 	}
 }
 
+// validateSinks returns an error if a syslog sink of the task ships logs to an
+// address which isn't allowed by the client's configuration.
+func (h *logmonHook) validateSinks(task *structs.Task) error {
+	for _, s := range task.LogConfig.Sinks {
+		if s.Type != structs.LogSinkTypeSyslog {
+			continue
+		}
+		if ok, _ := helper.SliceStringIsSubset(h.runner.clientConfig.LogSinkAddresses, []string{s.Address}); !ok {
+			return fmt.Errorf("log sink address %q is not allowed by the client", s.Address)
+		}
+	}
+	return nil
+}
+
 func (h *logmonHook) isLoggingDisabled() bool {
 	ic, ok := h.runner.driver.(drivers.InternalCapabilitiesDriver)
 	if !ok {
@@ -176,23 +210,129 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		}
 	}
 
-	err := h.logmon.Start(&logmon.LogConfig{
-		LogDir:        h.config.logDir,
-		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
-		StderrLogFile: fmt.Sprintf("%s.stderr", req.Task.Name),
-		StdoutFifo:    h.config.stdoutFifo,
-		StderrFifo:    h.config.stderrFifo,
-		MaxFiles:      req.Task.LogConfig.MaxFiles,
-		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
-	})
+	cfg := &logmon.LogConfig{
+		LogDir:         h.config.logDir,
+		StdoutLogFile:  fmt.Sprintf("%s.stdout", req.Task.Name),
+		StderrLogFile:  fmt.Sprintf("%s.stderr", req.Task.Name),
+		StdoutFifo:     h.config.stdoutFifo,
+		StderrFifo:     h.config.stderrFifo,
+		MaxFiles:       req.Task.LogConfig.MaxFiles,
+		MaxFileSizeMB:  req.Task.LogConfig.MaxFileSizeMB,
+		Compress:       req.Task.LogConfig.Compress,
+		RotateInterval: req.Task.LogConfig.RotateInterval,
+		MaxAge:         req.Task.LogConfig.MaxAge,
+		TaskName:       req.Task.Name,
+	}
+	if alloc := h.runner.Alloc(); alloc != nil {
+		cfg.AllocID = alloc.ID
+		cfg.Namespace = alloc.Namespace
+		cfg.JobID = alloc.JobID
+		cfg.TaskGroup = alloc.TaskGroup
+	}
+	for _, s := range req.Task.LogConfig.Sinks {
+		address := s.Address
+		if s.Type == structs.LogSinkTypeJSON {
+			// The log directory is shared by the tasks of the alloc
+			address = fmt.Sprintf("%s.%s", req.Task.Name, s.Address)
+		}
+		cfg.Sinks = append(cfg.Sinks, &logmon.SinkConfig{
+			Type:        s.Type,
+			Address:     address,
+			BufferLines: s.BufferLines,
+		})
+	}
+
+	err := h.logmon.Start(cfg)
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
 		return err
 	}
 
+	if len(cfg.Sinks) != 0 {
+		h.startSinkStats()
+	}
+
 	return nil
 }
 
+// startSinkStats starts collecting the metrics of the log sinks from the
+// current logmon process, stopping any previous collection.
+func (h *logmonHook) startSinkStats() {
+	h.stopSinkStats()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.sinkStatsCancel = cancel
+	h.sinkStatsDoneCh = make(chan struct{})
+	go h.collectSinkStats(ctx, h.logmon, h.sinkStatsDoneCh)
+}
+
+// stopSinkStats stops collecting the metrics of the log sinks and waits for
+// the collection to exit.
+func (h *logmonHook) stopSinkStats() {
+	if h.sinkStatsCancel == nil {
+		return
+	}
+	h.sinkStatsCancel()
+	<-h.sinkStatsDoneCh
+	h.sinkStatsCancel = nil
+}
+
+// collectSinkStats periodically emits the number of lines written and dropped
+// by each log sink.
+func (h *logmonHook) collectSinkStats(ctx context.Context, lm logmon.LogMon, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	ticker := time.NewTicker(h.runner.clientConfig.StatsCollectionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stats, err := lm.Stats()
+		if err != nil {
+			if grpc.Code(err) == codes.Unimplemented {
+				h.logger.Debug("logmon does not support log sink stats")
+				return
+			}
+			h.logger.Trace("failed to collect log sink stats", "error", err)
+			continue
+		}
+
+		for _, s := range stats {
+			h.emitSinkStats(s)
+		}
+	}
+}
+
+func (h *logmonHook) emitSinkStats(s *logmon.SinkStats) {
+	labels := append([]metrics.Label{
+		{Name: "sink_type", Value: s.Type},
+		{Name: "sink_address", Value: s.Address},
+	}, h.runner.baseLabels...)
+
+	emit := func(name string, lines uint64) {
+		key := fmt.Sprintf("%s/%s/%s", s.Type, s.Address, name)
+		last := h.sinkLines[key]
+		h.sinkLines[key] = lines
+
+		// The counters are reset when logmon restarts the task's logger
+		delta := lines
+		if lines >= last {
+			delta = lines - last
+		}
+		if delta == 0 || h.runner.clientConfig.DisableTaggedMetrics {
+			return
+		}
+		metrics.IncrCounterWithLabels([]string{"client", "allocs", "logmon", name}, float32(delta), labels)
+	}
+	emit("written_lines", s.WrittenLines)
+	emit("dropped_lines", s.DroppedLines)
+}
+
 func (h *logmonHook) Stop(_ context.Context, req *interfaces.TaskStopRequest, _ *interfaces.TaskStopResponse) error {
 
 	// It's possible that Stop was called without calling Prestart on agent
@@ -203,6 +343,8 @@ func (h *logmonHook) Stop(_ context.Context, req *interfaces.TaskStopRequest, _
 		}
 	}
 
+	h.stopSinkStats()
+
 	if h.logmon != nil {
 		h.logmon.Stop()
 	}
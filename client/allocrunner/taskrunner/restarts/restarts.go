@@ -18,6 +18,11 @@ const (
 	ReasonUnrecoverableErrror = "Error was unrecoverable"
 	ReasonWithinPolicy        = "Restart within policy"
 	ReasonDelay               = "Exceeded allowed attempts, applying a delay"
+	ReasonMaxRunDuration      = "Exceeded max run duration and policy allows no restarts"
+
+	// reasonMaxRunDurationAttempts is the reason a task exceeding its max
+	// run duration fails once its restart attempts are exhausted.
+	reasonMaxRunDurationAttempts = `Exceeded max run duration and allowed attempts %d in interval %v and mode is "fail"`
 )
 
 func NewRestartTracker(policy *structs.RestartPolicy, jobType string) *RestartTracker {
@@ -37,6 +42,7 @@ type RestartTracker struct {
 	exitRes          *drivers.ExitResult
 	startErr         error
 	killed           bool      // Whether the task has been killed
+	maxRunExceeded   bool      // Whether the task exceeded its max run duration
 	restartTriggered bool      // Whether the task has been signalled to be restarted
 	failure          bool      // Whether a failure triggered the restart
 	count            int       // Current number of attempts.
@@ -104,6 +110,17 @@ func (r *RestartTracker) SetKilled() *RestartTracker {
 	return r
 }
 
+// SetMaxRunDurationExceeded is used to mark that the task was killed for
+// exceeding its max run duration. The exit is a failure whatever its exit
+// code, so the task is restarted according to the restart policy.
+func (r *RestartTracker) SetMaxRunDurationExceeded() *RestartTracker {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.maxRunExceeded = true
+	r.failure = true
+	return r
+}
+
 // GetReason returns a human-readable description for the last state returned by
 // GetState.
 func (r *RestartTracker) GetReason() string {
@@ -140,6 +157,7 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		r.restartTriggered = false
 		r.failure = false
 		r.killed = false
+		r.maxRunExceeded = false
 	}()
 
 	// Hot path if task was killed
@@ -158,6 +176,13 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 	if r.policy.Attempts == 0 {
 		r.reason = ReasonNoRestartsAllowed
 
+		// A task exceeding its max run duration fails even if it handled
+		// being killed and exited successfully.
+		if r.maxRunExceeded {
+			r.reason = ReasonMaxRunDuration
+			return structs.TaskNotRestarting, 0
+		}
+
 		// If the task does not restart on a successful exit code and
 		// the exit code was successful: terminate.
 		if !r.onSuccess && r.exitRes != nil && r.exitRes.Successful() {
@@ -193,7 +218,7 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 	} else if r.exitRes != nil {
 		// If the task started successfully and restart on success isn't specified,
 		// don't restart but don't mark as failed.
-		if r.exitRes.Successful() && !r.onSuccess {
+		if r.exitRes.Successful() && !r.onSuccess && !r.maxRunExceeded {
 			r.reason = "Restart unnecessary as task terminated successfully"
 			return structs.TaskTerminated, 0
 		}
@@ -207,6 +232,9 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 			r.reason = fmt.Sprintf(
 				`Exceeded allowed attempts %d in interval %v and mode is "fail"`,
 				r.policy.Attempts, r.policy.Interval)
+			if r.maxRunExceeded {
+				r.reason = fmt.Sprintf(reasonMaxRunDurationAttempts, r.policy.Attempts, r.policy.Interval)
+			}
 			return structs.TaskNotRestarting, 0
 		} else {
 			r.reason = ReasonDelay
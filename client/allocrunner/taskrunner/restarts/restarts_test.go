@@ -2,6 +2,7 @@ package restarts
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -152,6 +153,40 @@ func TestClient_RestartTracker_RestartTriggered_Failure(t *testing.T) {
 	}
 }
 
+func TestClient_RestartTracker_MaxRunDurationExceeded(t *testing.T) {
+	t.Parallel()
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+	p.Attempts = 1
+
+	// A batch task exiting successfully after being killed is restarted
+	// within the policy and then fails
+	rt := NewRestartTracker(p, structs.JobTypeBatch)
+	if state, when := rt.SetMaxRunDurationExceeded().SetExitResult(testExitResult(0)).GetState(); state != structs.TaskRestarting || when == 0 {
+		t.Fatalf("expect restart got %v %v", state, when)
+	}
+	if state, when := rt.SetMaxRunDurationExceeded().SetExitResult(testExitResult(0)).GetState(); state != structs.TaskNotRestarting || when != 0 {
+		t.Fatalf("expect failed got %v %v", state, when)
+	}
+	if reason := rt.GetReason(); !strings.HasPrefix(reason, "Exceeded max run duration") {
+		t.Fatalf("expect max run duration reason got %q", reason)
+	}
+
+	// Without restarts the task fails with a distinct reason
+	p.Attempts = 0
+	rt = NewRestartTracker(p, structs.JobTypeBatch)
+	if state, when := rt.SetMaxRunDurationExceeded().SetExitResult(testExitResult(0)).GetState(); state != structs.TaskNotRestarting || when != 0 {
+		t.Fatalf("expect failed got %v %v", state, when)
+	}
+	if reason := rt.GetReason(); reason != ReasonMaxRunDuration {
+		t.Fatalf("expect reason %q got %q", ReasonMaxRunDuration, reason)
+	}
+
+	// The next run isn't considered to have exceeded its max run duration
+	if state, when := rt.SetExitResult(testExitResult(0)).GetState(); state != structs.TaskTerminated || when != 0 {
+		t.Fatalf("expect terminated got %v %v", state, when)
+	}
+}
+
 func TestClient_RestartTracker_StartError_Recoverable_Fail(t *testing.T) {
 	t.Parallel()
 	p := testPolicy(true, structs.RestartPolicyModeFail)
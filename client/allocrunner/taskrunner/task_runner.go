@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/hcl2/hcldec"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/restarts"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	"github.com/hashicorp/nomad/client/config"
@@ -190,6 +191,10 @@ type TaskRunner struct {
 	// handlers
 	driverManager drivermanager.Manager
 
+	// artifactCache is the client's artifact cache. It is nil if artifacts
+	// aren't cached.
+	artifactCache *getter.Cache
+
 	// maxEvents is the capacity of the TaskEvents on the TaskState.
 	// Defaults to defaultMaxEvents but overrideable for testing.
 	maxEvents int
@@ -238,6 +243,10 @@ type Config struct {
 	// ServersContactedCh is closed when the first GetClientAllocs call to
 	// servers succeeds and allocs are synced.
 	ServersContactedCh chan struct{}
+
+	// ArtifactCache is the client's artifact cache. It is nil if artifacts
+	// aren't cached.
+	ArtifactCache *getter.Cache
 }
 
 func NewTaskRunner(config *Config) (*TaskRunner, error) {
@@ -287,6 +296,7 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		driverManager:       config.DriverManager,
 		maxEvents:           defaultMaxEvents,
 		serversContactedCh:  config.ServersContactedCh,
+		artifactCache:       config.ArtifactCache,
 	}
 
 	// Create the logger based on the allocation ID
@@ -511,6 +521,9 @@ MAIN:
 			if resultCh, err := handle.WaitCh(context.Background()); err != nil {
 				tr.logger.Error("wait task failed", "error", err)
 			} else {
+				// Kill the task once it exceeds its max run duration
+				maxRunCh, stopMaxRun := tr.maxRunDurationTimer()
+
 				select {
 				case <-tr.killCtx.Done():
 					// We can go through the normal should restart check since
@@ -518,9 +531,15 @@ MAIN:
 					result = tr.handleKill()
 				case <-tr.shutdownCtx.Done():
 					// TaskRunner was told to exit immediately
+					stopMaxRun()
 					return
+				case <-maxRunCh:
+					// The restart tracker treats the exit as a failure so the
+					// restart policy decides whether the task runs again
+					result = tr.handleMaxRunDurationExceeded()
 				case result = <-resultCh:
 				}
+				stopMaxRun()
 
 				// WaitCh returned a result
 				if retryWait := tr.handleTaskExitResult(result); retryWait {
@@ -853,6 +872,69 @@ func (tr *TaskRunner) handleKill() *drivers.ExitResult {
 	}
 }
 
+// maxRunDurationTimer returns a channel receiving once the running task
+// exceeds its max run duration, measured from when it last started so restored
+// tasks keep their deadline. The channel is nil if the task may run
+// indefinitely. The returned func stops the timer.
+func (tr *TaskRunner) maxRunDurationTimer() (<-chan time.Time, func()) {
+	d := tr.Task().MaxRunDuration
+	if d <= 0 {
+		return nil, func() {}
+	}
+
+	remaining := d
+	if startedAt := tr.TaskState().StartedAt; !startedAt.IsZero() {
+		remaining = time.Until(startedAt.Add(d))
+	}
+
+	timer := time.NewTimer(remaining)
+	return timer.C, func() { timer.Stop() }
+}
+
+// handleMaxRunDurationExceeded kills a task which ran for longer than its max
+// run duration and returns its exit result. Unlike handleKill the restart
+// tracker is told the exit is a failure rather than a kill.
+func (tr *TaskRunner) handleMaxRunDurationExceeded() *drivers.ExitResult {
+	d := tr.Task().MaxRunDuration
+	tr.logger.Info("task exceeded max run duration; killing task", "max_run_duration", d)
+
+	tr.EmitEvent(structs.NewTaskEvent(structs.TaskMaxRunDurationExceeded).
+		SetKillReason(fmt.Sprintf("Task exceeded its max run duration of %v", d)))
+
+	// Run the pre killing hooks
+	tr.preKill()
+
+	tr.restartTracker.SetMaxRunDurationExceeded()
+
+	// Check it is running
+	handle := tr.getDriverHandle()
+	if handle == nil {
+		return nil
+	}
+
+	// Grab the wait channel before killing the task so the exit isn't missed
+	waitCh, err := handle.WaitCh(tr.shutdownCtx)
+	if err != nil {
+		if err != drivers.ErrTaskNotFound {
+			tr.logger.Error("failed to wait on task. Resources may have been leaked", "error", err)
+		}
+		return nil
+	}
+
+	// Kill the task using an exponential backoff in-case of failures.
+	if err := tr.killTask(handle); err != nil {
+		// We couldn't successfully destroy the resource created.
+		tr.logger.Error("failed to kill task. Resources may have been leaked", "error", err)
+	}
+
+	select {
+	case result := <-waitCh:
+		return result
+	case <-tr.shutdownCtx.Done():
+		return nil
+	}
+}
+
 // killTask kills the task handle. In the case that killing fails,
 // killTask will retry with an exponential backoff and will give up at a
 // given limit. Returns an error if the task could not be killed.
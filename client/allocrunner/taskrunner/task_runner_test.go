@@ -1684,6 +1684,103 @@ func TestTaskRunner_Run_RecoverableStartError(t *testing.T) {
 	require.Equal(t, structs.TaskNotRestarting, state.Events[5].Type)
 }
 
+// TestTaskRunner_MaxRunDuration asserts that tasks running for longer than
+// their max run duration are killed and restarted according to the restart
+// policy.
+func TestTaskRunner_MaxRunDuration(t *testing.T) {
+	t.Parallel()
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Config = map[string]interface{}{
+		"run_for": "10m",
+	}
+	task.MaxRunDuration = 100 * time.Millisecond
+
+	// Make the restart policy retry once
+	alloc.Job.TaskGroups[0].RestartPolicy = &structs.RestartPolicy{
+		Attempts: 1,
+		Interval: 10 * time.Minute,
+		Delay:    0,
+		Mode:     structs.RestartPolicyModeFail,
+	}
+
+	tr, _, cleanup := runTestTaskRunner(t, alloc, task.Name)
+	defer cleanup()
+
+	select {
+	case <-tr.WaitCh():
+	case <-time.After(time.Duration(testutil.TestMultiplier()*15) * time.Second):
+		require.Fail(t, "timed out waiting for task to exit")
+	}
+
+	expectedEvents := []string{
+		structs.TaskReceived,
+		structs.TaskSetup,
+		structs.TaskStarted,
+		structs.TaskMaxRunDurationExceeded,
+		structs.TaskTerminated,
+		structs.TaskRestarting,
+		structs.TaskStarted,
+		structs.TaskMaxRunDurationExceeded,
+		structs.TaskTerminated,
+		structs.TaskNotRestarting,
+	}
+
+	state := tr.TaskState()
+	actualEvents := make([]string, len(state.Events))
+	for i, e := range state.Events {
+		actualEvents[i] = e.Type
+	}
+	require.Equal(t, expectedEvents, actualEvents)
+	require.Equal(t, "Task exceeded its max run duration of 100ms", state.Events[3].DisplayMessage)
+
+	require.Equal(t, structs.TaskStateDead, state.State)
+	require.True(t, state.Failed, pretty.Sprint(state))
+}
+
+// TestTaskRunner_LogSink_NotAllowed asserts tasks with syslog sinks to
+// addresses not allowed by the client fail to start.
+func TestTaskRunner_LogSink_NotAllowed(t *testing.T) {
+	t.Parallel()
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.LogConfig.Sinks = []*structs.LogSink{{
+		Type:    structs.LogSinkTypeSyslog,
+		Address: "unix:///var/run/docker.sock",
+	}}
+
+	conf, cleanup := testTaskRunnerConfig(t, alloc, task.Name)
+	defer cleanup()
+	conf.ClientConfig.LogSinkAddresses = []string{"udp://127.0.0.1:514"}
+
+	tr, err := NewTaskRunner(conf)
+	require.NoError(t, err)
+	go tr.Run()
+	defer tr.Kill(context.Background(), structs.NewTaskEvent("cleanup"))
+
+	select {
+	case <-tr.WaitCh():
+	case <-time.After(time.Duration(testutil.TestMultiplier()*15) * time.Second):
+		require.Fail(t, "timed out waiting for task to exit")
+	}
+
+	state := tr.TaskState()
+	require.Equal(t, structs.TaskStateDead, state.State)
+	require.True(t, state.Failed, pretty.Sprint(state))
+
+	var found bool
+	for _, e := range state.Events {
+		if e.Type == structs.TaskSetupFailure {
+			found = true
+			require.Contains(t, e.DisplayMessage, `log sink address "unix:///var/run/docker.sock" is not allowed`)
+		}
+		require.NotEqual(t, structs.TaskStarted, e.Type)
+	}
+	require.True(t, found, pretty.Sprint(state.Events))
+}
+
 // TestTaskRunner_Template_Artifact asserts that tasks can use artifacts as templates.
 func TestTaskRunner_Template_Artifact(t *testing.T) {
 	t.Parallel()
@@ -23,6 +23,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	arstate "github.com/hashicorp/nomad/client/allocrunner/state"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/allocwatcher"
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
@@ -147,6 +148,7 @@ type AllocRunner interface {
 
 	GetTaskExecHandler(taskName string) drivermanager.TaskExecHandler
 	GetTaskDriverCapabilities(taskName string) (*drivers.Capabilities, error)
+	GetNetworkIsolation() *drivers.NetworkIsolationSpec
 }
 
 // Client is used to implement the client interaction with Nomad. Clients
@@ -163,6 +165,12 @@ type Client struct {
 	configCopy *config.Config
 	configLock sync.RWMutex
 
+	// metaStatic is the node metadata from the client configuration and
+	// metaDynamic is the metadata applied at runtime, which takes precedence.
+	// Both are protected by the configLock.
+	metaStatic  map[string]string
+	metaDynamic map[string]*string
+
 	logger    hclog.InterceptLogger
 	rpcLogger hclog.Logger
 
@@ -239,6 +247,10 @@ type Client struct {
 	// vaultClient is used to interact with Vault for token and secret renewals
 	vaultClient vaultclient.VaultClient
 
+	// artifactCache caches the artifacts downloaded by tasks. It is nil if
+	// artifacts aren't cached.
+	artifactCache *getter.Cache
+
 	// garbageCollector is used to garbage collect terminal allocations present
 	// in the node automatically
 	garbageCollector *AllocGarbageCollector
@@ -423,6 +435,19 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		Interval:            cfg.GCInterval,
 		ParallelDestroys:    cfg.GCParallelDestroys,
 		ReservedDiskMB:      cfg.Node.Reserved.DiskMB,
+
+		FailedAllocRetention: cfg.GCFailedAllocRetention,
+		MaxJobRetention:      cfg.GCMaxJobRetention,
+	}
+	if c.artifactCache != nil {
+		// Evicting artifacts only frees disk space for allocations when the
+		// cache is on the filesystem of the alloc dir. Otherwise the cache is
+		// only bounded by its maximum size.
+		if same, err := sameFilesystem(c.config.AllocDir, c.artifactCache.Dir()); err != nil {
+			c.logger.Warn("failed to compare the filesystems of the alloc dir and the artifact cache", "error", err)
+		} else if same {
+			gcConfig.ArtifactCache = c.artifactCache
+		}
 	}
 	c.garbageCollector = NewAllocGarbageCollector(c.logger, statsCollector, c, gcConfig)
 	go c.garbageCollector.Run()
@@ -540,6 +565,21 @@ func (c *Client) init() error {
 
 	c.stateDB = db
 
+	// Create the artifact cache if enabled
+	if c.config.ArtifactCacheMaxMB > 0 {
+		dir := c.config.ArtifactCacheDir
+		if dir == "" {
+			dir = filepath.Join(c.config.StateDir, "artifacts")
+		}
+
+		cache, err := getter.NewCache(dir, int64(c.config.ArtifactCacheMaxMB)*MB, c.logger)
+		if err != nil {
+			return err
+		}
+		c.artifactCache = cache
+		c.logger.Info("caching artifacts", "artifact_cache_dir", dir)
+	}
+
 	// Ensure the alloc dir exists if we have one
 	if c.config.AllocDir != "" {
 		if err := os.MkdirAll(c.config.AllocDir, 0711); err != nil {
@@ -1048,6 +1088,7 @@ func (c *Client) restoreState() error {
 			DeviceManager:       c.devicemanager,
 			DriverManager:       c.drivermanager,
 			ServersContactedCh:  c.serversContactedCh,
+			ArtifactCache:       c.artifactCache,
 		}
 		c.configLock.RUnlock()
 
@@ -1316,6 +1357,15 @@ func (c *Client) setupNode() error {
 		node.Meta["connect.log_level"] = defaultConnectLogLevel
 	}
 
+	// Apply the metadata previously set at runtime
+	dynamic, err := c.stateDB.GetNodeMeta()
+	if err != nil {
+		return fmt.Errorf("failed to restore node meta: %v", err)
+	}
+	c.metaStatic = helper.CopyMapStringString(node.Meta)
+	c.metaDynamic = dynamic
+	node.Meta = mergeNodeMeta(c.metaStatic, c.metaDynamic)
+
 	return nil
 }
 
@@ -1795,6 +1845,7 @@ func (c *Client) AllocStateUpdated(alloc *structs.Allocation) {
 	stripped.ClientStatus = alloc.ClientStatus
 	stripped.ClientDescription = alloc.ClientDescription
 	stripped.DeploymentStatus = alloc.DeploymentStatus
+	stripped.ArchiveStatus = alloc.ArchiveStatus
 
 	select {
 	case c.allocUpdates <- stripped:
@@ -2298,6 +2349,7 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		PrevAllocMigrator:   prevAllocMigrator,
 		DeviceManager:       c.devicemanager,
 		DriverManager:       c.drivermanager,
+		ArtifactCache:       c.artifactCache,
 	}
 	c.configLock.RUnlock()
 
@@ -182,6 +182,39 @@ type Config struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int
 
+	// GCFailedAllocRetention is the minimum duration failed allocations are
+	// kept before they are garbage collected to stay below GCMaxAllocs.
+	GCFailedAllocRetention time.Duration
+
+	// GCMaxJobRetention caps the minimum duration jobs may ask terminal
+	// allocations to be kept with their gc min_retention.
+	GCMaxJobRetention time.Duration
+
+	// ArtifactCacheDir is the directory artifacts are cached in. Defaults to
+	// the artifacts directory in the StateDir.
+	ArtifactCacheDir string
+
+	// ArtifactCacheMaxMB is the maximum size of the artifact cache. Zero
+	// disables caching artifacts.
+	ArtifactCacheMaxMB int
+
+	// ArtifactMaxSizeMB is the maximum size of an artifact download and of
+	// the contents of an artifact archive. Zero is unlimited.
+	ArtifactMaxSizeMB int
+
+	// ArtifactMaxFiles is the maximum number of files in an artifact
+	// archive. Zero is unlimited.
+	ArtifactMaxFiles int
+
+	// ArtifactMaxDecompressionRatio is the maximum ratio between the size of
+	// the contents of an artifact archive and the size of the archive. Zero
+	// is unlimited.
+	ArtifactMaxDecompressionRatio int
+
+	// ArtifactTimeout is the maximum time to fetch an artifact. Zero is
+	// unlimited.
+	ArtifactTimeout time.Duration
+
 	// LogLevel is the level of the logs to putout
 	LogLevel string
 
@@ -205,6 +238,11 @@ type Config struct {
 	// DisableRemoteExec disables remote exec targeting tasks on this client
 	DisableRemoteExec bool
 
+	// LogSinkAddresses are the addresses of the syslog servers the log sinks
+	// of tasks may ship logs to. Tasks with syslog sinks to other addresses
+	// fail to start.
+	LogSinkAddresses []string
+
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *ClientTemplateConfig
 
@@ -291,6 +329,8 @@ func DefaultConfig() *Config {
 		GCDiskUsageThreshold:    80,
 		GCInodeUsageThreshold:   70,
 		GCMaxAllocs:             50,
+		GCFailedAllocRetention:  1 * time.Hour,
+		GCMaxJobRetention:       24 * time.Hour,
 		NoHostUUID:              true,
 		DisableTaggedMetrics:    false,
 		DisableRemoteExec:       false,
@@ -1,10 +1,14 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"net/http"
 	"os"
@@ -19,6 +23,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/allocdir"
 	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -68,6 +73,13 @@ const (
 	OriginEnd   = "end"
 )
 
+// frameSender is the interface streamed file content is sent to. It is
+// implemented by the StreamFramer.
+type frameSender interface {
+	Send(file, fileEvent string, data []byte, offset int64) error
+	ExitCh() <-chan struct{}
+}
+
 // FileSystem endpoint is used for accessing the logs and filesystem of
 // allocations.
 type FileSystem struct {
@@ -78,6 +90,8 @@ func NewFileSystemEndpoint(c *Client) *FileSystem {
 	f := &FileSystem{c}
 	f.c.streamingRpcs.Register("FileSystem.Logs", f.logs)
 	f.c.streamingRpcs.Register("FileSystem.Stream", f.stream)
+	f.c.streamingRpcs.Register("FileSystem.Upload", f.upload)
+	f.c.streamingRpcs.Register("FileSystem.Archive", f.archive)
 	return f
 }
 
@@ -333,6 +347,190 @@ OUTER:
 	}
 }
 
+// upload is used to upload a file into an allocation's directory.
+func (f *FileSystem) upload(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "file_system", "upload"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.FsUploadRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	if req.AllocID == "" {
+		handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	alloc, err := f.c.GetAlloc(req.AllocID)
+	if err != nil {
+		handleStreamResultError(structs.NewErrUnknownAllocation(req.AllocID), helper.Int64ToPtr(404), encoder)
+		return
+	}
+
+	// Check write permissions
+	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(403), encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityWriteFS) {
+		handleStreamResultError(structs.ErrPermissionDenied, helper.Int64ToPtr(403), encoder)
+		return
+	}
+
+	// Validate the arguments
+	if req.Path == "" {
+		handleStreamResultError(pathNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	fs, err := f.c.GetAllocFS(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if structs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	perm := req.FileMode.Perm()
+	if perm == 0 {
+		perm = 0644
+	}
+
+	// Write the file as its content is received
+	r, w := io.Pipe()
+	go func() {
+		for {
+			var frame cstructs.FsUploadFrame
+			if err := decoder.Decode(&frame); err != nil {
+				// The stream ended before the file was complete
+				w.CloseWithError(io.ErrUnexpectedEOF)
+				return
+			}
+
+			if len(frame.Data) != 0 {
+				if _, err := w.Write(frame.Data); err != nil {
+					return
+				}
+			}
+
+			if frame.EOF {
+				w.Close()
+				return
+			}
+		}
+	}()
+
+	info, err := fs.WriteFile(req.Path, r, perm, req.Overwrite)
+	r.Close()
+	if err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Reply with the info of the uploaded file
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, structs.JsonHandle).Encode(info); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+	encoder.Encode(&cstructs.StreamErrWrapper{Payload: buf.Bytes()})
+}
+
+// archive is used to stream a tar archive of a path in an allocation
+// directory.
+func (f *FileSystem) archive(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "file_system", "archive"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.FsArchiveRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	if req.AllocID == "" {
+		handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	alloc, err := f.c.GetAlloc(req.AllocID)
+	if err != nil {
+		handleStreamResultError(structs.NewErrUnknownAllocation(req.AllocID), helper.Int64ToPtr(404), encoder)
+		return
+	}
+
+	// Check read permissions
+	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(403), encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+		handleStreamResultError(structs.ErrPermissionDenied, helper.Int64ToPtr(403), encoder)
+		return
+	}
+
+	// Validate the arguments
+	if req.Path == "" {
+		handleStreamResultError(pathNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	fs, err := f.c.GetAllocFS(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if structs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	// Stream the archive in payloads of up to streamFrameSize bytes
+	aw := &archiveWriter{encoder: encoder, conn: conn}
+	bw := bufio.NewWriterSize(aw, streamFrameSize)
+	err = fs.Archive(req.Path, bw, req.Compress)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		// The path is validated before anything is written, so an error
+		// before the first payload is caused by the request
+		code := helper.Int64ToPtr(500)
+		if !aw.written {
+			code = helper.Int64ToPtr(400)
+		}
+
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+}
+
+// archiveWriter sends the bytes written to it as stream payloads
+type archiveWriter struct {
+	encoder *codec.Encoder
+	conn    io.Writer
+	written bool
+}
+
+func (a *archiveWriter) Write(p []byte) (int, error) {
+	a.written = true
+	if err := a.encoder.Encode(&cstructs.StreamErrWrapper{Payload: p}); err != nil {
+		return 0, err
+	}
+	a.encoder.Reset(a.conn)
+	return len(p), nil
+}
+
 // logs is is used to stream a task's logs.
 func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	defer metrics.MeasureSince([]string{"client", "file_system", "logs"}, time.Now())
@@ -391,6 +589,12 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		return
 	}
 
+	filter, err := newLogFilter(&req)
+	if err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
 	fs, err := f.c.GetAllocFS(req.AllocID)
 	if err != nil {
 		code := helper.Int64ToPtr(500)
@@ -440,7 +644,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	// Start streaming
 	go func() {
 		if err := f.logsImpl(ctx, req.Follow, req.PlainText,
-			req.Offset, req.Origin, req.Task, req.LogType, fs, frames); err != nil {
+			req.Offset, req.Origin, req.Task, req.LogType, filter, fs, frames); err != nil {
 			select {
 			case errCh <- err:
 			case <-ctx.Done():
@@ -523,9 +727,11 @@ OUTER:
 
 // logsImpl is used to stream the logs of a the given task. Output is sent on
 // the passed frames channel and the method will return on EOF if follow is not
-// true otherwise when the context is cancelled or on an error.
+// true otherwise when the context is cancelled or on an error. If a filter is
+// given, only the matching lines are sent and the method also returns once the
+// end of the filter's time range has been reached.
 func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset int64,
-	origin, task, logType string,
+	origin, task, logType string, filter *logFilter,
 	fs allocdir.AllocDirFS, frames chan<- *sframer.StreamFrame) error {
 
 	// Create the framer
@@ -533,6 +739,14 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 	framer.Run()
 	defer framer.Destroy()
 
+	// Split the logs into lines and only send those that match the filter
+	var sender frameSender = framer
+	if filter != nil {
+		ff := newFilteringFramer(framer, filter, fs)
+		defer ff.Flush()
+		sender = ff
+	}
+
 	// Path to the logs
 	logPath := filepath.Join(allocdir.SharedAllocName, allocdir.LogDirName)
 
@@ -559,6 +773,7 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		if err != nil {
 			return fmt.Errorf("failed to list entries: %v", err)
 		}
+		entries = uncompressedLogSizes(fs, logPath, entries)
 
 		// If we are not following logs, determine the max index for the logs we are
 		// interested in so we can stop there.
@@ -591,7 +806,11 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		}
 
 		p := filepath.Join(logPath, logEntry.Name)
-		err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh)
+		if strings.HasSuffix(logEntry.Name, logging.CompressedSuffix) {
+			err = f.streamCompressedFile(ctx, openOffset, p, fs, sender)
+		} else {
+			err = f.streamFile(ctx, openOffset, p, 0, fs, sender, eofCancelCh)
+		}
 
 		// Check if the context is cancelled
 		select {
@@ -607,8 +826,9 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 				continue
 			}
 
-			// Check if the connection was closed
-			if err == syscall.EPIPE {
+			// Check if the connection was closed or the filter can't match
+			// any further lines
+			if err == syscall.EPIPE || err == errLogFilterDone {
 				return nil
 			}
 
@@ -639,7 +859,7 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 // read. eofCancelCh is used to cancel the stream if triggered while at EOF. If
 // the connection is broken an EPIPE error is returned
 func (f *FileSystem) streamFile(ctx context.Context, offset int64, path string, limit int64,
-	fs allocdir.AllocDirFS, framer *sframer.StreamFramer, eofCancelCh chan error) error {
+	fs allocdir.AllocDirFS, framer frameSender, eofCancelCh chan error) error {
 
 	// Get the reader
 	file, err := fs.ReadAt(path, offset)
@@ -762,6 +982,88 @@ OUTER:
 	}
 }
 
+// streamCompressedFile streams the content of a gzip compressed log file
+// starting at the offset into the uncompressed content. Compressed files have
+// been rotated and are never written to, so the stream ends at EOF.
+func (f *FileSystem) streamCompressedFile(ctx context.Context, offset int64, path string,
+	fs allocdir.AllocDirFS, framer frameSender) error {
+
+	file, err := fs.ReadAt(path, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	// Skip to the offset
+	if _, err := io.CopyN(ioutil.Discard, gz, offset); err != nil && err != io.EOF {
+		return err
+	}
+
+	data := make([]byte, streamFrameSize)
+	for {
+		n, readErr := gz.Read(data)
+		offset += int64(n)
+
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		if n != 0 {
+			if err := framer.Send(path, "", data[:n], offset); err != nil {
+				return parseFramerErr(err)
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+
+		select {
+		case <-framer.ExitCh():
+			return nil
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+}
+
+// uncompressedLogSizes returns the entries with the size of compressed log
+// files replaced by the size of their uncompressed content, which is read from
+// the gzip trailer. Rotated files are far smaller than the 4GB the trailer can
+// represent. Entries whose size can't be read are returned unchanged.
+func uncompressedLogSizes(fs allocdir.AllocDirFS, logPath string, entries []*cstructs.AllocFileInfo) []*cstructs.AllocFileInfo {
+	out := make([]*cstructs.AllocFileInfo, len(entries))
+	for i, entry := range entries {
+		out[i] = entry
+		if entry.IsDir || entry.Size < 4 || !strings.HasSuffix(entry.Name, logging.CompressedSuffix) {
+			continue
+		}
+
+		r, err := fs.ReadAt(filepath.Join(logPath, entry.Name), entry.Size-4)
+		if err != nil {
+			continue
+		}
+		var size uint32
+		err = binary.Read(r, binary.LittleEndian, &size)
+		r.Close()
+		if err != nil {
+			continue
+		}
+
+		e := *entry
+		e.Size = int64(size)
+		out[i] = &e
+	}
+	return out
+}
+
 // blockUntilNextLog returns a channel that will have data sent when the next
 // log index or anything greater is created.
 func blockUntilNextLog(ctx context.Context, fs allocdir.AllocDirFS, logPath, task, logType string, nextIndex int64) chan error {
@@ -834,10 +1136,12 @@ func (a indexTupleArray) Less(i, j int) bool { return a[i].idx < a[j].idx }
 func (a indexTupleArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
 // logIndexes takes a set of entries and returns a indexTupleArray of
-// the desired log file entries. If the indexes could not be determined, an
-// error is returned.
+// the desired log file entries. Compressed log files are included, unless the
+// uncompressed file with the same index still exists while it is being
+// compressed. If the indexes could not be determined, an error is returned.
 func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexTupleArray, error) {
 	var indexes []indexTuple
+	positions := make(map[int64]int)
 	prefix := fmt.Sprintf("%s.%s.", task, logType)
 	for _, entry := range entries {
 		if entry.IsDir {
@@ -850,13 +1154,25 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 			continue
 		}
 
+		compressed := strings.HasSuffix(idxStr, logging.CompressedSuffix)
+		idxStr = strings.TrimSuffix(idxStr, logging.CompressedSuffix)
+
 		// Convert to an int
 		idx, err := strconv.Atoi(idxStr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert %q to a log index: %v", idxStr, err)
 		}
 
-		indexes = append(indexes, indexTuple{idx: int64(idx), entry: entry})
+		tuple := indexTuple{idx: int64(idx), entry: entry}
+		if i, ok := positions[tuple.idx]; ok {
+			if !compressed {
+				indexes[i] = tuple
+			}
+			continue
+		}
+
+		positions[tuple.idx] = len(indexes)
+		indexes = append(indexes, tuple)
 	}
 
 	return indexTupleArray(indexes), nil
@@ -1,6 +1,9 @@
 package client
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -830,6 +833,458 @@ OUTER:
 	}
 }
 
+// uploadFile sends the upload request and file data to the handler and
+// returns the handler's reply.
+func uploadFile(t *testing.T, handler structs.StreamingRpcHandler,
+	req *cstructs.FsUploadRequest, data ...string) *cstructs.StreamErrWrapper {
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	// Start the handler
+	go handler(p2)
+
+	// Start the decoder
+	replyCh := make(chan *cstructs.StreamErrWrapper, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		var msg cstructs.StreamErrWrapper
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		if err := decoder.Decode(&msg); err != nil {
+			errCh <- err
+			return
+		}
+		replyCh <- &msg
+	}()
+
+	// Send the request and the file, which is not read on errors
+	go func() {
+		encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+		if err := encoder.Encode(req); err != nil {
+			return
+		}
+		for _, d := range data {
+			if err := encoder.Encode(&cstructs.FsUploadFrame{Data: []byte(d)}); err != nil {
+				return
+			}
+		}
+		encoder.Encode(&cstructs.FsUploadFrame{EOF: true})
+	}()
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	case err := <-errCh:
+		t.Fatalf("error decoding: %v", err)
+	case msg := <-replyCh:
+		return msg
+	}
+	return nil
+}
+
+func TestFS_Upload_NoAlloc(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a client
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Make the request with bad allocation id
+	req := &cstructs.FsUploadRequest{
+		AllocID:      uuid.Generate(),
+		Path:         "alloc/foo",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	handler, err := c.StreamingRpcHandler("FileSystem.Upload")
+	require.Nil(err)
+
+	msg := uploadFile(t, handler, req, "foo")
+	require.NotNil(msg.Error)
+	require.True(structs.IsErrUnknownAllocation(msg.Error))
+}
+
+func TestFS_Upload(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for alloc to be running
+	alloc := testutil.WaitForRunning(t, s.RPC, job)[0]
+
+	req := &cstructs.FsUploadRequest{
+		AllocID:      alloc.ID,
+		Path:         "web/local/tool.sh",
+		FileMode:     0755,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	handler, err := c.StreamingRpcHandler("FileSystem.Upload")
+	require.Nil(err)
+
+	// The file is uploaded and its info returned
+	msg := uploadFile(t, handler, req, "echo ", "hello")
+	require.Nil(msg.Error)
+
+	var info cstructs.AllocFileInfo
+	require.NoError(codec.NewDecoderBytes(msg.Payload, structs.JsonHandle).Decode(&info))
+	require.Equal("tool.sh", info.Name)
+	require.EqualValues(10, info.Size)
+	require.Equal(os.FileMode(0755).String(), info.FileMode)
+
+	fs, err := c.GetAllocFS(alloc.ID)
+	require.NoError(err)
+	r, err := fs.ReadAt(req.Path, 0)
+	require.NoError(err)
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	require.NoError(err)
+	require.Equal("echo hello", string(content))
+
+	// Existing files are only replaced if overwrite is set
+	msg = uploadFile(t, handler, req, "echo bye")
+	require.NotNil(msg.Error)
+	require.Contains(msg.Error.Error(), "already exists")
+	require.EqualValues(400, *msg.Error.Code)
+
+	req.Overwrite = true
+	msg = uploadFile(t, handler, req, "echo bye")
+	require.Nil(msg.Error)
+
+	// Uploads can't escape the alloc dir
+	req.Path = "../foo"
+	msg = uploadFile(t, handler, req, "foo")
+	require.NotNil(msg.Error)
+	require.Contains(msg.Error.Error(), "escapes")
+}
+
+func TestFS_Upload_ACL(t *testing.T) {
+	t.Parallel()
+
+	// Start a server
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityWriteFS})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for client to be running job
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:  "good token",
+			Token: tokenGood.SecretID,
+		},
+		{
+			Name:  "root token",
+			Token: root.SecretID,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.FsUploadRequest{
+				AllocID:   alloc.ID,
+				Path:      "alloc/foo",
+				Overwrite: true,
+				QueryOptions: structs.QueryOptions{
+					Namespace: structs.DefaultNamespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			// Get the handler
+			handler, err := client.StreamingRpcHandler("FileSystem.Upload")
+			require.Nil(t, err)
+
+			msg := uploadFile(t, handler, req, "foo")
+			if c.ExpectedError == "" {
+				require.Nil(t, msg.Error)
+				return
+			}
+			require.NotNil(t, msg.Error)
+			require.Contains(t, msg.Error.Error(), c.ExpectedError)
+		})
+	}
+}
+
+// downloadArchive sends the archive request to the handler and returns the
+// concatenated payloads or the streamed error.
+func downloadArchive(t *testing.T, handler structs.StreamingRpcHandler,
+	req *cstructs.FsArchiveRequest) ([]byte, *cstructs.RpcError) {
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	// Start the handler
+	go handler(p2)
+
+	// Start the decoder
+	type result struct {
+		data []byte
+		err  *cstructs.RpcError
+	}
+	resultCh := make(chan result, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		var data []byte
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err == io.EOF || strings.Contains(err.Error(), "closed") {
+					resultCh <- result{data: data}
+					return
+				}
+				errCh <- err
+				return
+			}
+			if msg.Error != nil {
+				resultCh <- result{err: msg.Error}
+				return
+			}
+			data = append(data, msg.Payload...)
+		}
+	}()
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.Nil(t, encoder.Encode(req))
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	case err := <-errCh:
+		t.Fatalf("error decoding: %v", err)
+	case res := <-resultCh:
+		return res.data, res.err
+	}
+	return nil, nil
+}
+
+func TestFS_Archive_NoAlloc(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a client
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Make the request with bad allocation id
+	req := &cstructs.FsArchiveRequest{
+		AllocID:      uuid.Generate(),
+		Path:         "foo",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("FileSystem.Archive")
+	require.Nil(err)
+
+	_, rpcErr := downloadArchive(t, handler, req)
+	require.NotNil(rpcErr)
+	require.True(structs.IsErrUnknownAllocation(rpcErr))
+	require.EqualValues(404, *rpcErr.Code)
+}
+
+func TestFS_Archive(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for alloc to be running
+	alloc := testutil.WaitForRunning(t, s.RPC, job)[0]
+
+	// Write a file large enough to span multiple payloads
+	content := strings.Repeat("abcdefgh", streamFrameSize/4)
+	fs, err := c.GetAllocFS(alloc.ID)
+	require.NoError(err)
+	_, err = fs.WriteFile("alloc/data/results/out.txt", strings.NewReader(content), 0644, false)
+	require.NoError(err)
+
+	req := &cstructs.FsArchiveRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/data",
+		Compress:     true,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	handler, err := c.StreamingRpcHandler("FileSystem.Archive")
+	require.Nil(err)
+
+	// The archive contains the directory and its files
+	data, rpcErr := downloadArchive(t, handler, req)
+	require.Nil(rpcErr)
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(err)
+	tr := tar.NewReader(gr)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+
+		b, err := ioutil.ReadAll(tr)
+		require.NoError(err)
+		files[hdr.Name] = string(b)
+	}
+	require.Contains(files, "alloc/data/results/")
+	require.Equal(content, files["alloc/data/results/out.txt"])
+
+	// Archives can't escape the alloc dir
+	req.Path = "../foo"
+	_, rpcErr = downloadArchive(t, handler, req)
+	require.NotNil(rpcErr)
+	require.Contains(rpcErr.Error(), "escapes")
+	require.EqualValues(400, *rpcErr.Code)
+}
+
+func TestFS_Archive_ACL(t *testing.T) {
+	t.Parallel()
+
+	// Start a server
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityReadFS})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for client to be running job
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:  "good token",
+			Token: tokenGood.SecretID,
+		},
+		{
+			Name:  "root token",
+			Token: root.SecretID,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.FsArchiveRequest{
+				AllocID: alloc.ID,
+				Path:    "alloc",
+				QueryOptions: structs.QueryOptions{
+					Namespace: structs.DefaultNamespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			// Get the handler
+			handler, err := client.StreamingRpcHandler("FileSystem.Archive")
+			require.Nil(t, err)
+
+			data, rpcErr := downloadArchive(t, handler, req)
+			if c.ExpectedError == "" {
+				require.Nil(t, rpcErr)
+				require.NotEmpty(t, data)
+				return
+			}
+			require.NotNil(t, rpcErr)
+			require.Contains(t, rpcErr.Error(), c.ExpectedError)
+		})
+	}
+}
+
 func TestFS_Logs_NoAlloc(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -1889,7 +2344,7 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 
 	if err := c.endpoints.FileSystem.logsImpl(
 		ctx, false, false, 0,
-		OriginStart, task, logType, ad, frames); err != nil {
+		OriginStart, task, logType, nil, ad, frames); err != nil {
 		t.Fatalf("logsImpl failed: %v", err)
 	}
 
@@ -1900,6 +2355,120 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 	}
 }
 
+func TestFS_logIndexes_Compressed(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	entries := []*cstructs.AllocFileInfo{
+		{Name: "foo.stdout.0.gz"},
+		{Name: "foo.stdout.1.gz"},
+		{Name: "foo.stdout.1"},
+		{Name: "foo.stdout.2"},
+	}
+
+	indexes, err := logIndexes(entries, "foo", "stdout")
+	require.NoError(err)
+	require.Len(indexes, 3)
+
+	// The uncompressed file is used while it is being compressed
+	require.Equal("foo.stdout.0.gz", indexes[0].entry.Name)
+	require.Equal("foo.stdout.1", indexes[1].entry.Name)
+	require.Equal("foo.stdout.2", indexes[2].entry.Name)
+}
+
+func TestFS_logsImpl_Compressed(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	defer os.RemoveAll(ad.AllocDir)
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	require.NoError(os.MkdirAll(logDir, 0777))
+
+	// Create compressed rotated log files followed by the current file
+	task := "foo"
+	logType := "stdout"
+	for i, data := range []string{"abc", "def"} {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(data))
+		require.NoError(err)
+		require.NoError(gz.Close())
+
+		logFile := fmt.Sprintf("%s.%s.%d.gz", task, logType, i)
+		require.NoError(ioutil.WriteFile(filepath.Join(logDir, logFile), buf.Bytes(), 0777))
+	}
+	logFile := fmt.Sprintf("%s.%s.%d", task, logType, 2)
+	require.NoError(ioutil.WriteFile(filepath.Join(logDir, logFile), []byte("ghi"), 0777))
+
+	// The size of compressed files is read from the gzip trailer
+	entries, err := ad.List(filepath.Join(allocdir.SharedAllocName, allocdir.LogDirName))
+	require.NoError(err)
+	entries = uncompressedLogSizes(ad, filepath.Join(allocdir.SharedAllocName, allocdir.LogDirName), entries)
+	for _, e := range entries {
+		require.EqualValues(3, e.Size, e.Name)
+	}
+
+	cases := []struct {
+		Name     string
+		Offset   int64
+		Origin   string
+		Expected string
+	}{
+		{
+			Name:     "start",
+			Origin:   OriginStart,
+			Expected: "abcdefghi",
+		},
+		{
+			Name:     "start offset",
+			Offset:   4,
+			Origin:   OriginStart,
+			Expected: "efghi",
+		},
+		{
+			Name:     "end offset",
+			Offset:   5,
+			Origin:   OriginEnd,
+			Expected: "efghi",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			frames := make(chan *sframer.StreamFrame, 32)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- c.endpoints.FileSystem.logsImpl(
+					ctx, false, false, tc.Offset,
+					tc.Origin, task, logType, nil, ad, frames)
+			}()
+
+			var received []byte
+			timeout := time.After(10 * time.Duration(testutil.TestMultiplier()) * streamBatchWindow)
+			for string(received) != tc.Expected {
+				select {
+				case frame := <-frames:
+					received = append(received, frame.Data...)
+				case <-timeout:
+					t.Fatalf("did not receive data: got %q", string(received))
+				}
+			}
+			if err := <-errCh; err != nil {
+				t.Fatalf("logsImpl failed: %v", err)
+			}
+		})
+	}
+}
+
 func TestFS_logsImpl_Follow(t *testing.T) {
 	t.Parallel()
 
@@ -1962,7 +2531,7 @@ func TestFS_logsImpl_Follow(t *testing.T) {
 	// Start streaming logs
 	go c.endpoints.FileSystem.logsImpl(
 		context.Background(), true, false, 0,
-		OriginStart, task, logType, ad, frames)
+		OriginStart, task, logType, nil, ad, frames)
 
 	select {
 	case <-firstResultCh:
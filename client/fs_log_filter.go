@@ -0,0 +1,264 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+)
+
+const (
+	// maxFilterLineSize is the largest line a log filter buffers. Longer
+	// lines are matched in parts.
+	maxFilterLineSize = streamFrameSize
+)
+
+var (
+	// errLogFilterDone is returned once the logs have passed the end of the
+	// filter's time range so no further lines can match.
+	errLogFilterDone = errors.New("end of log filter time range")
+)
+
+// logFilter selects the log lines that are streamed
+type logFilter struct {
+	substring []byte
+	regex     *regexp.Regexp
+	since     time.Time
+	until     time.Time
+}
+
+// newLogFilter returns the filter of a logs request or nil if the request
+// doesn't filter the logs.
+func newLogFilter(req *cstructs.FsLogsRequest) (*logFilter, error) {
+	if req.Filter == "" && req.Since.IsZero() && req.Until.IsZero() {
+		return nil, nil
+	}
+
+	if !req.Since.IsZero() && !req.Until.IsZero() && req.Until.Before(req.Since) {
+		return nil, fmt.Errorf("until (%v) must not be before since (%v)", req.Until, req.Since)
+	}
+
+	f := &logFilter{
+		since: req.Since,
+		until: req.Until,
+	}
+	if req.FilterRegex {
+		re, err := regexp.Compile(req.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile filter: %v", err)
+		}
+		f.regex = re
+	} else if req.Filter != "" {
+		f.substring = []byte(req.Filter)
+	}
+	return f, nil
+}
+
+// timeRange returns whether the filter selects lines by their time
+func (f *logFilter) timeRange() bool {
+	return !f.since.IsZero() || !f.until.IsZero()
+}
+
+// match returns whether the line, which was written at ts, is selected by the
+// filter. A zero ts means the time of the line is unknown and never matches a
+// time range.
+func (f *logFilter) match(line []byte, ts time.Time) bool {
+	if f.timeRange() {
+		if ts.IsZero() {
+			return false
+		}
+		if !f.since.IsZero() && ts.Before(f.since) {
+			return false
+		}
+		if !f.until.IsZero() && ts.After(f.until) {
+			return false
+		}
+	}
+
+	line = bytes.TrimRight(line, "\r\n")
+	switch {
+	case f.regex != nil:
+		return f.regex.Match(line)
+	case f.substring != nil:
+		return bytes.Contains(line, f.substring)
+	default:
+		return true
+	}
+}
+
+// filteringFramer sends the log lines selected by a filter to the underlying
+// framer. Data is buffered until the line it belongs to is complete.
+type filteringFramer struct {
+	*sframer.StreamFramer
+
+	filter *logFilter
+	fs     allocdir.AllocDirFS
+
+	// line is the partial line buffered so far and lineFile and lineOffset
+	// are the file and offset the line started at.
+	line       []byte
+	lineFile   string
+	lineOffset int64
+
+	// index is the timestamp index of the file lines were last looked up in
+	// and lastTimestamp the last time a line was found to be written at.
+	index         *timestampIndex
+	lastTimestamp time.Time
+}
+
+func newFilteringFramer(framer *sframer.StreamFramer, filter *logFilter, fs allocdir.AllocDirFS) *filteringFramer {
+	return &filteringFramer{
+		StreamFramer: framer,
+		filter:       filter,
+		fs:           fs,
+	}
+}
+
+// Send splits the data into lines and sends the lines that match the filter.
+// The offset is the offset of the end of the data in the file. Once the time
+// range of the filter has been passed, errLogFilterDone is returned.
+func (f *filteringFramer) Send(file, fileEvent string, data []byte, offset int64) error {
+	switch fileEvent {
+	case "":
+	case truncateEvent:
+		// The partial line is gone
+		f.line = f.line[:0]
+		return f.StreamFramer.Send(file, fileEvent, nil, offset)
+	default:
+		return f.StreamFramer.Send(file, fileEvent, nil, offset)
+	}
+
+	if f.index != nil {
+		f.index.stale = true
+	}
+
+	start := offset - int64(len(data))
+	for len(data) > 0 {
+		if len(f.line) == 0 {
+			f.lineFile = file
+			f.lineOffset = start
+		}
+
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			f.line = append(f.line, data...)
+			start += int64(len(data))
+			data = nil
+
+			if len(f.line) < maxFilterLineSize {
+				break
+			}
+		} else {
+			f.line = append(f.line, data[:idx+1]...)
+			start += int64(idx + 1)
+			data = data[idx+1:]
+		}
+
+		if err := f.sendLine(file, start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush sends the buffered partial line if it matches the filter. It is used
+// once there is no more data to be streamed.
+func (f *filteringFramer) Flush() error {
+	if len(f.line) == 0 {
+		return nil
+	}
+	return f.sendLine(f.lineFile, f.lineOffset+int64(len(f.line)))
+}
+
+// sendLine sends the buffered line, which ends at the offset of the file, if
+// it matches the filter.
+func (f *filteringFramer) sendLine(file string, offset int64) error {
+	line := f.line
+	f.line = f.line[:0]
+
+	ts := f.timestamp(f.lineFile, f.lineOffset)
+	if !f.filter.until.IsZero() && ts.After(f.filter.until) {
+		return errLogFilterDone
+	}
+	if !f.filter.match(line, ts) {
+		return nil
+	}
+	return f.StreamFramer.Send(file, "", line, offset)
+}
+
+// timestamp returns the time the line starting at the offset of the file was
+// written or a zero time if it isn't known. Lines that start before the first
+// record of a file's index share the time of the preceding line.
+func (f *filteringFramer) timestamp(file string, offset int64) time.Time {
+	if !f.filter.timeRange() {
+		return time.Time{}
+	}
+
+	indexPath := filepath.Join(filepath.Dir(file), logging.TimestampIndexName(filepath.Base(file)))
+	if f.index == nil || f.index.path != indexPath {
+		f.index = &timestampIndex{path: indexPath, stale: true}
+	}
+
+	if ts, ok := f.index.lookup(f.fs, offset); ok {
+		f.lastTimestamp = ts
+	}
+	return f.lastTimestamp
+}
+
+// timestampIndex is the timestamp index of a log file, recorded by logmon
+type timestampIndex struct {
+	path    string
+	records []*logging.TimestampRecord
+
+	// read is the number of bytes of the index that have been decoded and
+	// stale marks that the index may have grown since it was read.
+	read  int64
+	stale bool
+}
+
+// lookup returns the time at which the line starting at the offset was
+// written and whether it is known.
+func (t *timestampIndex) lookup(fs allocdir.AllocDirFS, offset int64) (time.Time, bool) {
+	// Reload the index if the offset is past the records read so far as it
+	// may have been recorded since
+	if t.stale && (len(t.records) == 0 || t.records[len(t.records)-1].Offset < offset) {
+		t.load(fs)
+	}
+
+	i := sort.Search(len(t.records), func(i int) bool {
+		return t.records[i].Offset > offset
+	})
+	if i == 0 {
+		return time.Time{}, false
+	}
+	return t.records[i-1].Timestamp, true
+}
+
+// load reads the records added to the index since it was last read. A missing
+// index has no records.
+func (t *timestampIndex) load(fs allocdir.AllocDirFS) {
+	t.stale = false
+
+	r, err := fs.ReadAt(t.path, t.read)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		return
+	}
+
+	records, n := logging.DecodeTimestampRecords(buf.Bytes())
+	t.records = append(t.records, records...)
+	t.read += int64(n)
+}
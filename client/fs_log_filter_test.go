@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogFilter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// No filter
+	f, err := newLogFilter(&cstructs.FsLogsRequest{FilterRegex: true})
+	require.NoError(err)
+	require.Nil(f)
+
+	// Invalid regex
+	_, err = newLogFilter(&cstructs.FsLogsRequest{Filter: "(", FilterRegex: true})
+	require.Error(err)
+	require.Contains(err.Error(), "failed to compile filter")
+
+	// Invalid time range
+	now := time.Now()
+	_, err = newLogFilter(&cstructs.FsLogsRequest{Since: now, Until: now.Add(-time.Second)})
+	require.Error(err)
+	require.Contains(err.Error(), "must not be before since")
+}
+
+func TestLogFilter_Match(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	cases := []struct {
+		Name    string
+		Request *cstructs.FsLogsRequest
+		Line    string
+		Time    time.Time
+		Match   bool
+	}{
+		{
+			Name:    "substring",
+			Request: &cstructs.FsLogsRequest{Filter: "error"},
+			Line:    "an error occurred\n",
+			Match:   true,
+		},
+		{
+			Name:    "substring no match",
+			Request: &cstructs.FsLogsRequest{Filter: "error"},
+			Line:    "all is well\n",
+		},
+		{
+			Name:    "substring is not a regex",
+			Request: &cstructs.FsLogsRequest{Filter: "err.r"},
+			Line:    "an error occurred\n",
+		},
+		{
+			Name:    "regex",
+			Request: &cstructs.FsLogsRequest{Filter: "^an? err.r$", FilterRegex: true},
+			Line:    "an error\r\n",
+			Match:   true,
+		},
+		{
+			Name:    "since",
+			Request: &cstructs.FsLogsRequest{Since: now},
+			Line:    "line\n",
+			Time:    now.Add(time.Second),
+			Match:   true,
+		},
+		{
+			Name:    "before since",
+			Request: &cstructs.FsLogsRequest{Since: now},
+			Line:    "line\n",
+			Time:    now.Add(-time.Second),
+		},
+		{
+			Name:    "after until",
+			Request: &cstructs.FsLogsRequest{Until: now},
+			Line:    "line\n",
+			Time:    now.Add(time.Second),
+		},
+		{
+			Name:    "unknown time",
+			Request: &cstructs.FsLogsRequest{Since: now},
+			Line:    "line\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			f, err := newLogFilter(tc.Request)
+			require.NoError(t, err)
+			require.Equal(t, tc.Match, f.match([]byte(tc.Line), tc.Time))
+		})
+	}
+}
+
+func TestFS_logsImpl_Filter(t *testing.T) {
+	t.Parallel()
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	defer os.RemoveAll(ad.AllocDir)
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	require.NoError(t, os.MkdirAll(logDir, 0777))
+
+	// Create two log files with a line spanning both and a final partial
+	// line, along with the timestamp indexes logmon records
+	task := "foo"
+	logType := "stdout"
+	start := time.Now().Truncate(time.Second)
+	files := []struct {
+		Data    string
+		Offsets []int64
+	}{
+		{
+			Data:    "a error 1\nb info 2\nc err",
+			Offsets: []int64{0, 10, 19},
+		},
+		{
+			Data:    "or 3\nd info 4\ne tail",
+			Offsets: []int64{5, 14},
+		},
+	}
+	minute := 0
+	for i, f := range files {
+		logFile := fmt.Sprintf("%s.%s.%d", task, logType, i)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(logDir, logFile), []byte(f.Data), 0777))
+
+		var index []byte
+		for _, offset := range f.Offsets {
+			index = append(index, logging.EncodeTimestampRecord(offset, start.Add(time.Duration(minute)*time.Minute))...)
+			minute++
+		}
+		indexFile := filepath.Join(logDir, logging.TimestampIndexName(logFile))
+		require.NoError(t, ioutil.WriteFile(indexFile, index, 0777))
+	}
+
+	cases := []struct {
+		Name     string
+		Request  *cstructs.FsLogsRequest
+		Follow   bool
+		Expected string
+	}{
+		{
+			Name:     "substring",
+			Request:  &cstructs.FsLogsRequest{Filter: "error"},
+			Expected: "a error 1\nc error 3\n",
+		},
+		{
+			Name:     "regex",
+			Request:  &cstructs.FsLogsRequest{Filter: "^[bd] ", FilterRegex: true},
+			Expected: "b info 2\nd info 4\n",
+		},
+		{
+			Name:     "partial last line",
+			Request:  &cstructs.FsLogsRequest{Filter: "tail"},
+			Expected: "e tail",
+		},
+		{
+			Name:     "since",
+			Request:  &cstructs.FsLogsRequest{Since: start.Add(90 * time.Second)},
+			Expected: "c error 3\nd info 4\ne tail",
+		},
+		{
+			Name:     "until",
+			Request:  &cstructs.FsLogsRequest{Until: start.Add(90 * time.Second)},
+			Expected: "a error 1\nb info 2\n",
+		},
+		{
+			Name: "time range and substring",
+			Request: &cstructs.FsLogsRequest{
+				Filter: "error",
+				Since:  start.Add(time.Minute),
+				Until:  start.Add(3 * time.Minute),
+			},
+			Expected: "c error 3\n",
+		},
+		{
+			Name:     "follow until",
+			Request:  &cstructs.FsLogsRequest{Until: start.Add(90 * time.Second)},
+			Follow:   true,
+			Expected: "a error 1\nb info 2\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			filter, err := newLogFilter(tc.Request)
+			require.NoError(t, err)
+
+			frames := make(chan *sframer.StreamFrame, 32)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// The stream ends once the logs have been filtered, including
+			// when following logs past the end of the time range
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- c.endpoints.FileSystem.logsImpl(
+					ctx, tc.Follow, false, 0,
+					OriginStart, task, logType, filter, ad, frames)
+			}()
+
+			var received []byte
+			timeout := time.After(10 * time.Duration(testutil.TestMultiplier()) * streamBatchWindow)
+		OUTER:
+			for {
+				select {
+				case frame, ok := <-frames:
+					if !ok {
+						break OUTER
+					}
+					received = append(received, frame.Data...)
+				case <-timeout:
+					t.Fatalf("stream did not end: got %q", string(received))
+				}
+			}
+
+			require.NoError(t, <-errCh)
+			require.Equal(t, tc.Expected, string(received))
+		})
+	}
+}
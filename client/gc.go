@@ -3,11 +3,13 @@ package client
 import (
 	"container/heap"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/stats"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -26,6 +28,18 @@ type GCConfig struct {
 	Interval            time.Duration
 	ReservedDiskMB      int
 	ParallelDestroys    int
+
+	// FailedAllocRetention is the minimum duration failed allocations are
+	// kept before they are collected to stay below MaxAllocs.
+	FailedAllocRetention time.Duration
+
+	// MaxJobRetention caps the minimum duration jobs may ask their terminal
+	// allocations to be kept.
+	MaxJobRetention time.Duration
+
+	// ArtifactCache is evicted from to free disk space once there are no
+	// terminal allocations left to collect. It may be nil.
+	ArtifactCache ArtifactCache
 }
 
 // AllocCounter is used by AllocGarbageCollector to discover how many un-GC'd
@@ -34,6 +48,14 @@ type AllocCounter interface {
 	NumAllocs() int
 }
 
+// ArtifactCache is used by AllocGarbageCollector to free the disk space used by
+// cached artifacts and is generally fulfilled by the client's artifact cache.
+type ArtifactCache interface {
+	// Evict removes the least recently used artifact and returns the bytes
+	// freed, or zero if no artifact could be removed.
+	Evict() int64
+}
+
 // AllocGarbageCollector garbage collects terminated allocations on a node
 type AllocGarbageCollector struct {
 	config *GCConfig
@@ -128,36 +150,38 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 		}
 
 		// See if we are below thresholds for used disk space and inode usage
-		diskStats := a.statsCollector.Stats().AllocDirStats
-		reason := ""
+		liveAllocs := a.allocCounter.NumAllocs()
+		reason, diskReason := a.gcReason(a.statsCollector.Stats().AllocDirStats, liveAllocs)
 		logf := a.logger.Warn
 
-		liveAllocs := a.allocCounter.NumAllocs()
+		if reason == "" {
+			// No reason to gc, exit
+			break
+		}
 
-		switch {
-		case diskStats.UsedPercent > a.config.DiskUsageThreshold:
-			reason = fmt.Sprintf("disk usage of %.0f is over gc threshold of %.0f",
-				diskStats.UsedPercent, a.config.DiskUsageThreshold)
-		case diskStats.InodesUsedPercent > a.config.InodeUsageThreshold:
-			reason = fmt.Sprintf("inode usage of %.0f is over gc threshold of %.0f",
-				diskStats.InodesUsedPercent, a.config.InodeUsageThreshold)
-		case liveAllocs > a.config.MaxAllocs:
+		// Collect an allocation. Allocations are only collected before
+		// their retention expires to free disk space.
+		var gcAlloc *GCAlloc
+		if diskReason {
+			gcAlloc = a.allocRunners.Pop()
+		} else {
 			// if we're unable to gc, don't WARN until at least 2x over limit
 			if liveAllocs < (a.config.MaxAllocs * 2) {
 				logf = a.logger.Info
 			}
-			reason = fmt.Sprintf("number of allocations (%d) is over the limit (%d)", liveAllocs, a.config.MaxAllocs)
+			gcAlloc = a.allocRunners.PopExpired(time.Now())
 		}
-
-		if reason == "" {
-			// No reason to gc, exit
-			break
-		}
-
-		// Collect an allocation
-		gcAlloc := a.allocRunners.Pop()
 		if gcAlloc == nil {
-			logf("garbage collection skipped because no terminal allocations", "reason", reason)
+			// Free disk space used by cached artifacts
+			if diskReason && a.evictArtifact(reason) > 0 {
+				continue
+			}
+
+			if a.allocRunners.Length() > 0 {
+				logf("garbage collection skipped because terminal allocations are retained", "reason", reason)
+			} else {
+				logf("garbage collection skipped because no terminal allocations", "reason", reason)
+			}
 			break
 		}
 
@@ -167,6 +191,26 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 	return nil
 }
 
+// gcReason returns why allocations should be garbage collected given the disk
+// stats of the alloc dir and the number of un-GC'd allocations, or an empty
+// string if they don't need to be. diskReason is true if disk space or inodes
+// must be freed.
+func (a *AllocGarbageCollector) gcReason(diskStats *stats.DiskStats, liveAllocs int) (reason string, diskReason bool) {
+	switch {
+	case diskStats != nil && diskStats.UsedPercent > a.config.DiskUsageThreshold:
+		reason = fmt.Sprintf("disk usage of %.0f is over gc threshold of %.0f",
+			diskStats.UsedPercent, a.config.DiskUsageThreshold)
+		diskReason = true
+	case diskStats != nil && diskStats.InodesUsedPercent > a.config.InodeUsageThreshold:
+		reason = fmt.Sprintf("inode usage of %.0f is over gc threshold of %.0f",
+			diskStats.InodesUsedPercent, a.config.InodeUsageThreshold)
+		diskReason = true
+	case liveAllocs > a.config.MaxAllocs:
+		reason = fmt.Sprintf("number of allocations (%d) is over the limit (%d)", liveAllocs, a.config.MaxAllocs)
+	}
+	return reason, diskReason
+}
+
 // destroyAllocRunner is used to destroy an allocation runner. It will acquire a
 // lock to restrict parallelism and then destroy the alloc runner, returning
 // once the allocation has been destroyed.
@@ -245,7 +289,7 @@ func (a *AllocGarbageCollector) MakeRoomFor(allocations []*structs.Allocation) e
 		default:
 		}
 
-		gcAlloc := a.allocRunners.Pop()
+		gcAlloc := a.allocRunners.PopExpired(time.Now())
 		if gcAlloc == nil {
 			// It's fine if we can't lower below the limit here as
 			// we'll keep trying to drop below the limit with each
@@ -312,7 +356,14 @@ func (a *AllocGarbageCollector) MakeRoomFor(allocations []*structs.Allocation) e
 
 		gcAlloc := a.allocRunners.Pop()
 		if gcAlloc == nil {
-			break
+			// Free disk space used by cached artifacts
+			freed := a.evictArtifact("freeing disk space for new allocations")
+			if freed == 0 {
+				break
+			}
+
+			diskCleared += freed / MB
+			continue
 		}
 
 		ar := gcAlloc.allocRunner
@@ -334,13 +385,93 @@ func (a *AllocGarbageCollector) MakeRoomFor(allocations []*structs.Allocation) e
 	return nil
 }
 
+// evictArtifact evicts the least recently used artifact from the artifact
+// cache and returns the bytes freed.
+func (a *AllocGarbageCollector) evictArtifact(reason string) int64 {
+	if a.config.ArtifactCache == nil {
+		return 0
+	}
+
+	freed := a.config.ArtifactCache.Evict()
+	if freed > 0 {
+		a.logger.Info("evicted cached artifact", "bytes", freed, "reason", reason)
+	}
+	return freed
+}
+
 // MarkForCollection starts tracking an allocation for Garbage Collection
 func (a *AllocGarbageCollector) MarkForCollection(allocID string, ar AllocRunner) {
-	if a.allocRunners.Push(allocID, ar) {
+	retention, reason := a.retention(ar)
+	if a.allocRunners.Push(allocID, ar, retention, reason) {
 		a.logger.Info("marking allocation for GC", "alloc_id", allocID)
 	}
 }
 
+// retention returns the minimum duration a terminal allocation is kept before
+// it is collected to stay below MaxAllocs and the reason it is kept.
+func (a *AllocGarbageCollector) retention(ar AllocRunner) (time.Duration, string) {
+	var retention time.Duration
+	var reason string
+
+	// The retention of jobs is capped by the client
+	if job := ar.Alloc().Job; job != nil && job.GC != nil && job.GC.MinRetention > 0 && a.config.MaxJobRetention > 0 {
+		retention = job.GC.MinRetention
+		if retention > a.config.MaxJobRetention {
+			retention = a.config.MaxJobRetention
+		}
+		reason = "job gc min_retention"
+	}
+
+	// Failed allocations are kept longer for debugging
+	if a.config.FailedAllocRetention > retention &&
+		ar.AllocState().ClientStatus == structs.AllocClientStatusFailed {
+		retention = a.config.FailedAllocRetention
+		reason = "failed allocation"
+	}
+
+	return retention, reason
+}
+
+// Status returns the allocations marked for collection in the order they
+// will be collected and why they would be collected now.
+func (a *AllocGarbageCollector) Status() *cstructs.GCStatusResponse {
+	resp := &cstructs.GCStatusResponse{
+		NumAllocs:           a.allocCounter.NumAllocs(),
+		MaxAllocs:           a.config.MaxAllocs,
+		DiskUsageThreshold:  a.config.DiskUsageThreshold,
+		InodeUsageThreshold: a.config.InodeUsageThreshold,
+	}
+
+	var diskStats *stats.DiskStats
+	if hostStats := a.statsCollector.Stats(); hostStats != nil {
+		diskStats = hostStats.AllocDirStats
+	}
+	if diskStats != nil {
+		resp.DiskUsedPercent = diskStats.UsedPercent
+		resp.InodesUsedPercent = diskStats.InodesUsedPercent
+	}
+	resp.Reason, _ = a.gcReason(diskStats, resp.NumAllocs)
+
+	now := time.Now()
+	for _, gcAlloc := range a.allocRunners.List() {
+		alloc := gcAlloc.allocRunner.Alloc()
+		status := &cstructs.GCAllocStatus{
+			AllocID:   gcAlloc.allocID,
+			Namespace: alloc.Namespace,
+			JobID:     alloc.JobID,
+			TaskGroup: alloc.TaskGroup,
+			MarkedAt:  gcAlloc.timeStamp,
+		}
+		if gcAlloc.retainUntil.After(now) {
+			status.RetainUntil = gcAlloc.retainUntil
+			status.RetainReason = gcAlloc.retainReason
+		}
+		resp.Allocs = append(resp.Allocs, status)
+	}
+
+	return resp
+}
+
 // GCAlloc wraps an allocation runner and an index enabling it to be used within
 // a PQ
 type GCAlloc struct {
@@ -348,6 +479,11 @@ type GCAlloc struct {
 	allocID     string
 	allocRunner AllocRunner
 	index       int
+
+	// retainUntil is the time until which the allocation is only collected
+	// to free disk space, and retainReason is why.
+	retainUntil  time.Time
+	retainReason string
 }
 
 type GCAllocPQImpl []*GCAlloc
@@ -357,7 +493,7 @@ func (pq GCAllocPQImpl) Len() int {
 }
 
 func (pq GCAllocPQImpl) Less(i, j int) bool {
-	return pq[i].timeStamp.Before(pq[j].timeStamp)
+	return pq[i].retainUntil.Before(pq[j].retainUntil)
 }
 
 func (pq GCAllocPQImpl) Swap(i, j int) {
@@ -383,7 +519,7 @@ func (pq *GCAllocPQImpl) Pop() interface{} {
 }
 
 // IndexedGCAllocPQ is an indexed PQ which maintains a list of allocation runner
-// based on their termination time.
+// based on their termination time plus their retention.
 type IndexedGCAllocPQ struct {
 	index map[string]*GCAlloc
 	heap  GCAllocPQImpl
@@ -398,9 +534,9 @@ func NewIndexedGCAllocPQ() *IndexedGCAllocPQ {
 	}
 }
 
-// Push an alloc runner into the GC queue. Returns true if alloc was added,
-// false if the alloc already existed.
-func (i *IndexedGCAllocPQ) Push(allocID string, ar AllocRunner) bool {
+// Push an alloc runner into the GC queue, retaining it for the given duration.
+// Returns true if alloc was added, false if the alloc already existed.
+func (i *IndexedGCAllocPQ) Push(allocID string, ar AllocRunner, retention time.Duration, reason string) bool {
 	i.pqLock.Lock()
 	defer i.pqLock.Unlock()
 
@@ -408,10 +544,13 @@ func (i *IndexedGCAllocPQ) Push(allocID string, ar AllocRunner) bool {
 		// No work to do
 		return false
 	}
+	now := time.Now()
 	gcAlloc := &GCAlloc{
-		timeStamp:   time.Now(),
-		allocID:     allocID,
-		allocRunner: ar,
+		timeStamp:    now,
+		allocID:      allocID,
+		allocRunner:  ar,
+		retainUntil:  now.Add(retention),
+		retainReason: reason,
 	}
 	i.index[allocID] = gcAlloc
 	heap.Push(&i.heap, gcAlloc)
@@ -431,6 +570,34 @@ func (i *IndexedGCAllocPQ) Pop() *GCAlloc {
 	return gcAlloc
 }
 
+// PopExpired pops the next alloc whose retention has expired at the given
+// time. Returns nil if there is none.
+func (i *IndexedGCAllocPQ) PopExpired(now time.Time) *GCAlloc {
+	i.pqLock.Lock()
+	defer i.pqLock.Unlock()
+
+	if len(i.heap) == 0 || i.heap[0].retainUntil.After(now) {
+		return nil
+	}
+
+	gcAlloc := heap.Pop(&i.heap).(*GCAlloc)
+	delete(i.index, gcAlloc.allocRunner.Alloc().ID)
+	return gcAlloc
+}
+
+// List returns the allocs in the order they will be popped.
+func (i *IndexedGCAllocPQ) List() []*GCAlloc {
+	i.pqLock.Lock()
+	defer i.pqLock.Unlock()
+
+	allocs := make([]*GCAlloc, len(i.heap))
+	copy(allocs, i.heap)
+	sort.SliceStable(allocs, func(a, b int) bool {
+		return allocs[a].retainUntil.Before(allocs[b].retainUntil)
+	})
+	return allocs
+}
+
 // Remove alloc from GC. Returns nil if alloc doesn't exist.
 func (i *IndexedGCAllocPQ) Remove(allocID string) *GCAlloc {
 	i.pqLock.Lock()
@@ -2,6 +2,9 @@ package client
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -9,6 +12,7 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/stats"
 	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -23,6 +27,7 @@ func gcConfig() *GCConfig {
 		Interval:            1 * time.Minute,
 		ReservedDiskMB:      0,
 		MaxAllocs:           100,
+		MaxJobRetention:     24 * time.Hour,
 	}
 }
 
@@ -49,10 +54,10 @@ func TestIndexedGCAllocPQ(t *testing.T) {
 	ar4, cleanup4 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	defer cleanup4()
 
-	pq.Push(ar1.Alloc().ID, ar1)
-	pq.Push(ar2.Alloc().ID, ar2)
-	pq.Push(ar3.Alloc().ID, ar3)
-	pq.Push(ar4.Alloc().ID, ar4)
+	pq.Push(ar1.Alloc().ID, ar1, 0, "")
+	pq.Push(ar2.Alloc().ID, ar2, 0, "")
+	pq.Push(ar3.Alloc().ID, ar3, 0, "")
+	pq.Push(ar4.Alloc().ID, ar4, 0, "")
 
 	allocID := pq.Pop().allocRunner.Alloc().ID
 	if allocID != ar1.Alloc().ID {
@@ -80,7 +85,41 @@ func TestIndexedGCAllocPQ(t *testing.T) {
 	}
 }
 
-// MockAllocCounter implements AllocCounter interface.
+func TestIndexedGCAllocPQ_Retention(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	pq := NewIndexedGCAllocPQ()
+
+	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanup1()
+	ar2, cleanup2 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanup2()
+	ar3, cleanup3 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanup3()
+
+	pq.Push(ar1.Alloc().ID, ar1, time.Hour, "failed allocation")
+	pq.Push(ar2.Alloc().ID, ar2, 0, "")
+	pq.Push(ar3.Alloc().ID, ar3, time.Minute, "job gc min_retention")
+
+	// Retained allocs are listed last
+	list := pq.List()
+	require.Len(list, 3)
+	require.Equal(ar2.Alloc().ID, list[0].allocID)
+	require.Equal(ar3.Alloc().ID, list[1].allocID)
+	require.Equal(ar1.Alloc().ID, list[2].allocID)
+
+	// Only allocs whose retention expired are popped
+	now := time.Now()
+	require.Equal(ar2.Alloc().ID, pq.PopExpired(now).allocID)
+	require.Nil(pq.PopExpired(now))
+	require.Equal(ar3.Alloc().ID, pq.PopExpired(now.Add(time.Minute)).allocID)
+
+	// Retained allocs are still popped to free disk space
+	require.Equal(ar1.Alloc().ID, pq.Pop().allocID)
+	require.Nil(pq.Pop())
+}
+
+// MockAllocCounter implements AllocCounter interface.// MockAllocCounter implements AllocCounter interface.
 type MockAllocCounter struct {
 	allocs int
 }
@@ -121,6 +160,22 @@ func (m *MockStatsCollector) Stats() *stats.HostStats {
 	}
 }
 
+// MockArtifactCache implements the ArtifactCache interface.
+type MockArtifactCache struct {
+	sizes   []int64
+	evicted int
+}
+
+func (m *MockArtifactCache) Evict() int64 {
+	if len(m.sizes) == 0 {
+		return 0
+	}
+	size := m.sizes[0]
+	m.sizes = m.sizes[1:]
+	m.evicted++
+	return size
+}
+
 func TestAllocGarbageCollector_MarkForCollection(t *testing.T) {
 	t.Parallel()
 	logger := testlog.HCLogger(t)
@@ -563,3 +618,167 @@ func TestAllocGarbageCollector_UsedPercentThreshold(t *testing.T) {
 		t.Fatalf("gcAlloc: %v", gcAlloc)
 	}
 }
+
+func TestAllocGarbageCollector_UsedPercentThreshold_ArtifactCache(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	logger := testlog.HCLogger(t)
+	statsCollector := &MockStatsCollector{}
+	cache := &MockArtifactCache{sizes: []int64{10 * MB, 10 * MB, 10 * MB}}
+	conf := gcConfig()
+	conf.ArtifactCache = cache
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+
+	// Without terminal allocations cached artifacts are evicted until disk
+	// usage is below the threshold
+	statsCollector.availableValues = []uint64{1000, 1000, 1000}
+	statsCollector.usedPercents = []float64{85, 85, 60}
+	statsCollector.inodePercents = []float64{0, 0, 0}
+
+	require.NoError(gc.keepUsageBelowThreshold())
+	require.Equal(2, cache.evicted)
+}
+
+func TestAllocGarbageCollector_MakeRoomForAllocations_ArtifactCache(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	logger := testlog.HCLogger(t)
+	statsCollector := &MockStatsCollector{}
+	cache := &MockArtifactCache{sizes: []int64{10 * MB, 10 * MB}}
+	conf := gcConfig()
+	conf.ReservedDiskMB = 20
+	conf.ArtifactCache = cache
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+
+	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanup1()
+	go ar1.Run()
+	gc.MarkForCollection(ar1.Alloc().ID, ar1)
+	exitAllocRunner(ar1)
+
+	// Make stats collector report enough free space after the alloc and an
+	// artifact are collected
+	statsCollector.availableValues = []uint64{80 * MB, 80 * MB, 90 * MB, 100 * MB, 200 * MB}
+	statsCollector.usedPercents = []float64{0, 0, 0, 0, 0}
+	statsCollector.inodePercents = []float64{0, 0, 0, 0, 0}
+
+	alloc := mock.Alloc()
+	alloc.AllocatedResources.Shared.DiskMB = 150
+	require.NoError(gc.MakeRoomFor([]*structs.Allocation{alloc}))
+
+	// The alloc runner is collected before any artifact is evicted
+	require.Nil(gc.allocRunners.Pop())
+	require.Equal(2, cache.evicted)
+}
+
+func TestAllocGarbageCollector_MaxAllocs_Retention(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	logger := testlog.HCLogger(t)
+	statsCollector := &MockStatsCollector{}
+	conf := gcConfig()
+	conf.MaxAllocs = 1
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{allocs: 3}, conf)
+
+	retained := mock.Alloc()
+	retained.Job.GC = &structs.JobGCConfig{MinRetention: time.Hour}
+	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, retained)
+	defer cleanup1()
+	ar2, cleanup2 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanup2()
+
+	go ar1.Run()
+	go ar2.Run()
+
+	gc.MarkForCollection(ar1.Alloc().ID, ar1)
+	gc.MarkForCollection(ar2.Alloc().ID, ar2)
+
+	// Exit the alloc runners
+	exitAllocRunner(ar1, ar2)
+
+	// The retained alloc is listed last with the reason it is retained
+	status := gc.Status()
+	require.Equal(3, status.NumAllocs)
+	require.Contains(status.Reason, "number of allocations (3) is over the limit (1)")
+	require.Len(status.Allocs, 2)
+	require.Equal(ar2.Alloc().ID, status.Allocs[0].AllocID)
+	require.True(status.Allocs[0].RetainUntil.IsZero())
+	require.Equal(ar1.Alloc().ID, status.Allocs[1].AllocID)
+	require.Equal("job gc min_retention", status.Allocs[1].RetainReason)
+	require.False(status.Allocs[1].RetainUntil.IsZero())
+
+	statsCollector.availableValues = []uint64{1000}
+	statsCollector.usedPercents = []float64{0}
+	statsCollector.inodePercents = []float64{0}
+
+	require.NoError(gc.keepUsageBelowThreshold())
+
+	// Only the alloc that isn't retained is collected to stay below the
+	// max allocs
+	require.True(ar2.IsDestroyed())
+	require.False(ar1.IsDestroyed())
+	require.Equal(1, gc.allocRunners.Length())
+
+	// The retained alloc is collected to free disk space
+	statsCollector.availableValues = []uint64{1000, 1000}
+	statsCollector.usedPercents = []float64{85, 60}
+	statsCollector.inodePercents = []float64{0, 0}
+	gc.allocCounter = &MockAllocCounter{}
+
+	require.NoError(gc.keepUsageBelowThreshold())
+	require.True(ar1.IsDestroyed())
+	require.Zero(gc.allocRunners.Length())
+}
+
+func TestAllocGarbageCollector_MaxJobRetention(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	logger := testlog.HCLogger(t)
+	conf := gcConfig()
+	conf.MaxJobRetention = time.Hour
+	gc := NewAllocGarbageCollector(logger, &MockStatsCollector{}, &MockAllocCounter{}, conf)
+
+	retained := mock.Alloc()
+	retained.Job.GC = &structs.JobGCConfig{MinRetention: 1000 * time.Hour}
+	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, retained)
+	defer cleanup1()
+
+	start := time.Now()
+	gc.MarkForCollection(ar1.Alloc().ID, ar1)
+
+	// The retention of the job is capped by the client
+	status := gc.Status()
+	require.Len(status.Allocs, 1)
+	require.Equal("job gc min_retention", status.Allocs[0].RetainReason)
+	require.False(status.Allocs[0].RetainUntil.After(time.Now().Add(time.Hour)))
+	require.False(status.Allocs[0].RetainUntil.Before(start.Add(time.Hour)))
+
+	// Jobs can't retain allocations when the cap is zero
+	gc.config.MaxJobRetention = 0
+	unretained := retained.Copy()
+	unretained.ID = uuid.Generate()
+	ar2, cleanup2 := allocrunner.TestAllocRunnerFromAlloc(t, unretained)
+	defer cleanup2()
+	gc.MarkForCollection(ar2.Alloc().ID, ar2)
+
+	status = gc.Status()
+	require.Len(status.Allocs, 2)
+	require.Equal(ar2.Alloc().ID, status.Allocs[0].AllocID)
+	require.True(status.Allocs[0].RetainUntil.IsZero())
+}
+
+func TestSameFilesystem(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	sub := filepath.Join(dir, "artifacts")
+	require.NoError(os.Mkdir(sub, 0700))
+
+	same, err := sameFilesystem(dir, sub)
+	require.NoError(err)
+	require.True(same)
+}
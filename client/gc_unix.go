@@ -0,0 +1,35 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package client
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// sameFilesystem returns whether the two paths are on the same filesystem.
+func sameFilesystem(a, b string) (bool, error) {
+	devA, err := deviceID(a)
+	if err != nil {
+		return false, err
+	}
+	devB, err := deviceID(b)
+	if err != nil {
+		return false, err
+	}
+	return devA == devB, nil
+}
+
+// deviceID returns the ID of the device the path is on.
+func deviceID(path string) (uint64, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("failed to get the device of %q", path)
+	}
+	return uint64(stat.Dev), nil
+}
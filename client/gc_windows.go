@@ -0,0 +1,19 @@
+package client
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// sameFilesystem returns whether the two paths are on the same volume.
+func sameFilesystem(a, b string) (bool, error) {
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(filepath.VolumeName(absA), filepath.VolumeName(absB)), nil
+}
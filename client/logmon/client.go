@@ -24,8 +24,23 @@ func (c *logmonClient) Start(cfg *LogConfig) error {
 		StderrFileName: cfg.StderrLogFile,
 		MaxFiles:       uint32(cfg.MaxFiles),
 		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
+		Compress:       cfg.Compress,
+		RotateInterval: int64(cfg.RotateInterval),
+		MaxAge:         int64(cfg.MaxAge),
 		StdoutFifo:     cfg.StdoutFifo,
 		StderrFifo:     cfg.StderrFifo,
+		AllocId:        cfg.AllocID,
+		Namespace:      cfg.Namespace,
+		JobId:          cfg.JobID,
+		TaskGroup:      cfg.TaskGroup,
+		TaskName:       cfg.TaskName,
+	}
+	for _, s := range cfg.Sinks {
+		req.Sinks = append(req.Sinks, &proto.LogSink{
+			Type:        s.Type,
+			Address:     s.Address,
+			BufferLines: uint32(s.BufferLines),
+		})
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
@@ -42,3 +57,25 @@ func (c *logmonClient) Stop() error {
 	_, err := c.client.Stop(ctx, req)
 	return grpcutils.HandleGrpcErr(err, c.doneCtx)
 }
+
+func (c *logmonClient) Stats() ([]*SinkStats, error) {
+	req := &proto.StatsRequest{}
+	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
+	defer cancel()
+
+	resp, err := c.client.Stats(ctx, req)
+	if err != nil {
+		return nil, grpcutils.HandleGrpcErr(err, c.doneCtx)
+	}
+
+	stats := make([]*SinkStats, len(resp.Sinks))
+	for i, s := range resp.Sinks {
+		stats[i] = &SinkStats{
+			Type:         s.Type,
+			Address:      s.Address,
+			WrittenLines: s.WrittenLines,
+			DroppedLines: s.DroppedLines,
+		}
+	}
+	return stats, nil
+}
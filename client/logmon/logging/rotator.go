@@ -3,7 +3,10 @@ package logging
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"encoding/binary"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -31,27 +34,69 @@ const (
 
 	// newLineDelimiter is the delimiter used for new lines.
 	newLineDelimiter = '\n'
+
+	// maxAgePurgeInterval is the interval at which rotated files are checked
+	// against the max age.
+	maxAgePurgeInterval = 1 * time.Minute
+
+	// CompressedSuffix is appended to the name of rotated files once they are
+	// compressed.
+	CompressedSuffix = ".gz"
+
+	// TimestampIndexSuffix is appended to the hidden file recording the
+	// timestamps of the lines of a rotated file.
+	TimestampIndexSuffix = ".ts"
 )
 
+// RotateOptions are optional settings of a FileRotator
+type RotateOptions struct {
+	// Compress gzips files once they are rotated
+	Compress bool
+
+	// RotateInterval is the age at which a file is rotated even if it has not
+	// reached the maximum size. Zero disables time based rotation.
+	RotateInterval time.Duration
+
+	// MaxAge is the age after which rotated files are purged, even if there
+	// are fewer than the maximum number of files. Zero disables age based
+	// retention.
+	MaxAge time.Duration
+
+	// Timestamps records the time each line was written in a timestamp
+	// index next to each rotated file
+	Timestamps bool
+}
+
 // FileRotator writes bytes to a rotated set of files
 type FileRotator struct {
 	MaxFiles int   // MaxFiles is the maximum number of rotated files allowed in a path
 	FileSize int64 // FileSize is the size a rotated file is allowed to grow
 
+	Compress       bool          // Compress gzips files once they are rotated
+	RotateInterval time.Duration // RotateInterval is the age at which a file is rotated even if not full
+	MaxAge         time.Duration // MaxAge is the age after which rotated files are purged
+	Timestamps     bool          // Timestamps records the time lines are written in a timestamp index
+
 	path             string // path is the path on the file system where the rotated set of files are opened
 	baseFileName     string // baseFileName is the base file name of the rotated files
 	logFileIdx       int    // logFileIdx is the current index of the rotated files
 	oldestLogFileIdx int    // oldestLogFileIdx is the index of the oldest log file in a path
 
-	currentFile *os.File // currentFile is the file that is currently getting written
-	currentWr   int64    // currentWr is the number of bytes written to the current file
-	bufw        *bufio.Writer
-	bufLock     sync.Mutex
+	currentFile   *os.File  // currentFile is the file that is currently getting written
+	currentWr     int64     // currentWr is the number of bytes written to the current file
+	currentOpened time.Time // currentOpened is the time the current file was opened
+	bufw          *bufio.Writer
+	bufLock       sync.Mutex
+
+	tsFile    *os.File // tsFile is the timestamp index of the current file
+	tsLast    int64    // tsLast is the last timestamp recorded in the current index
+	lineStart bool     // lineStart is whether the next byte written starts a line
 
 	flushTicker *time.Ticker
 	logger      hclog.Logger
 	purgeCh     chan struct{}
 	doneCh      chan struct{}
+	compressWg  sync.WaitGroup
 
 	closed     bool
 	closedLock sync.Mutex
@@ -60,10 +105,24 @@ type FileRotator struct {
 // NewFileRotator returns a new file rotator
 func NewFileRotator(path string, baseFile string, maxFiles int,
 	fileSize int64, logger hclog.Logger) (*FileRotator, error) {
+	return NewFileRotatorWithOptions(path, baseFile, maxFiles, fileSize, nil, logger)
+}
+
+// NewFileRotatorWithOptions returns a new file rotator using the optional
+// rotation settings
+func NewFileRotatorWithOptions(path string, baseFile string, maxFiles int,
+	fileSize int64, opts *RotateOptions, logger hclog.Logger) (*FileRotator, error) {
 	logger = logger.Named("rotator")
+	if opts == nil {
+		opts = &RotateOptions{}
+	}
 	rotator := &FileRotator{
-		MaxFiles: maxFiles,
-		FileSize: fileSize,
+		MaxFiles:       maxFiles,
+		FileSize:       fileSize,
+		Compress:       opts.Compress,
+		RotateInterval: opts.RotateInterval,
+		MaxAge:         opts.MaxAge,
+		Timestamps:     opts.Timestamps,
 
 		path:         path,
 		baseFileName: baseFile,
@@ -72,6 +131,8 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 		logger:      logger,
 		purgeCh:     make(chan struct{}, 1),
 		doneCh:      make(chan struct{}, 1),
+
+		lineStart: true,
 	}
 
 	if err := rotator.lastFile(); err != nil {
@@ -87,6 +148,13 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 func (f *FileRotator) Write(p []byte) (n int, err error) {
 	n = 0
 	var forceRotate bool
+	now := time.Now()
+
+	// Rotate a file that has been written to for longer than the rotate
+	// interval
+	if f.RotateInterval > 0 && f.currentWr > 0 && time.Since(f.currentOpened) >= f.RotateInterval {
+		forceRotate = true
+	}
 
 	for n < len(p) {
 		// Check if we still have space in the current file, otherwise close and
@@ -95,6 +163,7 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 			forceRotate = false
 			f.flushBuffer()
 			f.currentFile.Close()
+			f.closeTimestampIndex()
 			if err := f.nextFile(); err != nil {
 				f.logger.Error("error creating next file", "err", err)
 				return 0, err
@@ -137,12 +206,15 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 			nw, err = f.writeToBuffer(p[n:])
 		}
 
+		// Record the time of any line started by the written bytes
+		f.recordTimestamp(p[n:n+nw], f.currentWr, now)
+
 		// Increment the number of bytes written so far in this method
 		// invocation
 		n += nw
 
 		// Increment the total number of bytes in the file
-		f.currentWr += int64(n)
+		f.currentWr += int64(nw)
 		if err != nil {
 			f.logger.Error("error writing to file", "err", err)
 
@@ -160,6 +232,7 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 // nextFile opens the next file and purges older files if the number of rotated
 // files is larger than the maximum files configured by the user
 func (f *FileRotator) nextFile() error {
+	prevFileIdx := f.logFileIdx
 	nextFileIdx := f.logFileIdx
 	for {
 		nextFileIdx += 1
@@ -169,12 +242,20 @@ func (f *FileRotator) nextFile() error {
 				continue
 			}
 		}
+		if _, err := os.Stat(logFileName + CompressedSuffix); err == nil {
+			continue
+		}
 		f.logFileIdx = nextFileIdx
 		if err := f.createFile(); err != nil {
 			return err
 		}
 		break
 	}
+
+	if f.Compress {
+		f.compressFileAsync(prevFileIdx)
+	}
+
 	// Purge old files if we have more files than MaxFiles
 	f.closedLock.Lock()
 	defer f.closedLock.Unlock()
@@ -194,28 +275,63 @@ func (f *FileRotator) lastFile() error {
 		return err
 	}
 
-	prefix := fmt.Sprintf("%s.", f.baseFileName)
+	var uncompressed []int
 	for _, fi := range finfos {
 		if fi.IsDir() {
 			continue
 		}
-		if strings.HasPrefix(fi.Name(), prefix) {
-			fileIdx := strings.TrimPrefix(fi.Name(), prefix)
-			n, err := strconv.Atoi(fileIdx)
-			if err != nil {
-				continue
-			}
-			if n > f.logFileIdx {
-				f.logFileIdx = n
-			}
+		n, compressed, ok := f.fileIndex(fi.Name())
+		if !ok {
+			continue
+		}
+		if !compressed {
+			uncompressed = append(uncompressed, n)
 		}
+		if n > f.logFileIdx {
+			f.logFileIdx = n
+		}
+	}
+
+	// A compressed file can't be appended to, so start a new file
+	compressedFileName := filepath.Join(f.path, fmt.Sprintf("%s.%d%s", f.baseFileName, f.logFileIdx, CompressedSuffix))
+	if _, err := os.Stat(compressedFileName); err == nil {
+		f.logFileIdx++
 	}
+
 	if err := f.createFile(); err != nil {
 		return err
 	}
+
+	// Compress files that were rotated but not compressed before the
+	// rotator was last stopped
+	if f.Compress {
+		for _, n := range uncompressed {
+			if n < f.logFileIdx {
+				f.compressFileAsync(n)
+			}
+		}
+	}
 	return nil
 }
 
+// fileIndex returns the index of a rotated file from its name and whether the
+// file is compressed. False is returned if the name isn't a rotated file.
+func (f *FileRotator) fileIndex(name string) (int, bool, bool) {
+	prefix := fmt.Sprintf("%s.", f.baseFileName)
+	if !strings.HasPrefix(name, prefix) {
+		return 0, false, false
+	}
+
+	idx := strings.TrimPrefix(name, prefix)
+	compressed := strings.HasSuffix(idx, CompressedSuffix)
+	idx = strings.TrimSuffix(idx, CompressedSuffix)
+	n, err := strconv.Atoi(idx)
+	if err != nil {
+		return 0, false, false
+	}
+	return n, compressed, true
+}
+
 // createFile opens a new or existing file for writing
 func (f *FileRotator) createFile() error {
 	logFileName := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, f.logFileIdx))
@@ -230,10 +346,57 @@ func (f *FileRotator) createFile() error {
 		return err
 	}
 	f.currentWr = fi.Size()
+	f.currentOpened = time.Now()
 	f.createOrResetBuffer()
+
+	if f.Timestamps {
+		tsFileName := filepath.Join(f.path, TimestampIndexName(filepath.Base(logFileName)))
+		tsFile, err := os.OpenFile(tsFileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		f.tsFile = tsFile
+		f.tsLast = 0
+	}
 	return nil
 }
 
+// closeTimestampIndex closes the timestamp index of the current file
+func (f *FileRotator) closeTimestampIndex() {
+	if f.tsFile != nil {
+		f.tsFile.Close()
+		f.tsFile = nil
+	}
+}
+
+// recordTimestamp records the time of the first line started in p, which is
+// written at the offset of the current file. Lines are only recorded when
+// the time, truncated to milliseconds, differs from the last recorded line so
+// lines without a record share the time of the preceding record.
+func (f *FileRotator) recordTimestamp(p []byte, offset int64, now time.Time) {
+	if len(p) == 0 {
+		return
+	}
+
+	start := -1
+	if f.lineStart {
+		start = 0
+	} else if idx := bytes.IndexByte(p, newLineDelimiter); idx >= 0 && idx+1 < len(p) {
+		start = idx + 1
+	}
+	f.lineStart = p[len(p)-1] == newLineDelimiter
+
+	ts := now.UnixNano() / int64(time.Millisecond)
+	if f.tsFile == nil || start < 0 || ts == f.tsLast {
+		return
+	}
+	f.tsLast = ts
+
+	if _, err := f.tsFile.Write(EncodeTimestampRecord(offset+int64(start), now)); err != nil {
+		f.logger.Error("error writing timestamp index", "err", err)
+	}
+}
+
 // flushPeriodically flushes the buffered writer every 100ms to the underlying
 // file
 func (f *FileRotator) flushPeriodically() {
@@ -257,58 +420,175 @@ func (f *FileRotator) Close() error {
 		close(f.purgeCh)
 		f.closed = true
 		f.currentFile.Close()
+		f.closeTimestampIndex()
 	}
 
+	// Wait for rotated files to be compressed
+	f.compressWg.Wait()
+
 	return nil
 }
 
+// compressFileAsync compresses the rotated file with the given index in the
+// background.
+func (f *FileRotator) compressFileAsync(idx int) {
+	f.compressWg.Add(1)
+	go func() {
+		defer f.compressWg.Done()
+		if err := f.compressFile(idx); err != nil {
+			f.logger.Error("error compressing file", "index", idx, "err", err)
+		}
+	}()
+}
+
+// compressFile gzips the rotated file with the given index and removes the
+// uncompressed file. The compressed file keeps the modification time of the
+// original so it ages out at the same time.
+func (f *FileRotator) compressFile(idx int) error {
+	src := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, idx))
+	dst := src + CompressedSuffix
+
+	// The temporary file is hidden so it isn't mistaken for a rotated file
+	tmp := filepath.Join(f.path, fmt.Sprintf(".%s.%d%s.tmp", f.baseFileName, idx, CompressedSuffix))
+
+	in, err := os.Open(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Already purged or compressed
+			return nil
+		}
+		return err
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Chtimes(tmp, fi.ModTime(), fi.ModTime()); err != nil {
+		f.logger.Warn("error setting modification time of compressed file", "filename", tmp, "err", err)
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(src)
+}
+
 // purgeOldFiles removes older files and keeps only the last N files rotated for
 // a file
 func (f *FileRotator) purgeOldFiles() {
+	var ageCh <-chan time.Time
+	if f.MaxAge > 0 {
+		ticker := time.NewTicker(maxAgePurgeInterval)
+		defer ticker.Stop()
+		ageCh = ticker.C
+	}
+
 	for {
 		select {
 		case <-f.purgeCh:
-			var fIndexes []int
-			files, err := ioutil.ReadDir(f.path)
-			if err != nil {
+			if err := f.purge(); err != nil {
 				f.logger.Error("error getting directory listing", "err", err)
 				return
 			}
-			// Inserting all the rotated files in a slice
-			for _, fi := range files {
-				if strings.HasPrefix(fi.Name(), f.baseFileName) {
-					fileIdx := strings.TrimPrefix(fi.Name(), fmt.Sprintf("%s.", f.baseFileName))
-					n, err := strconv.Atoi(fileIdx)
-					if err != nil {
-						f.logger.Error("error extracting file index", "err", err)
-						continue
-					}
-					fIndexes = append(fIndexes, n)
-				}
+		case <-ageCh:
+			if err := f.purge(); err != nil {
+				f.logger.Error("error getting directory listing", "err", err)
+				return
 			}
+		case <-f.doneCh:
+			return
+		}
+	}
+}
 
-			// Not continuing to delete files if the number of files is not more
-			// than MaxFiles
-			if len(fIndexes) <= f.MaxFiles {
-				continue
-			}
+// purge removes the oldest rotated files beyond MaxFiles and any rotated
+// files older than MaxAge. The file with the largest index is the current
+// file and is never purged for its age.
+func (f *FileRotator) purge() error {
+	files, err := ioutil.ReadDir(f.path)
+	if err != nil {
+		return err
+	}
 
-			// Sorting the file indexes so that we can purge the older files and keep
-			// only the number of files as configured by the user
-			sort.Sort(sort.IntSlice(fIndexes))
-			toDelete := fIndexes[0 : len(fIndexes)-f.MaxFiles]
-			for _, fIndex := range toDelete {
-				fname := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, fIndex))
-				err := os.RemoveAll(fname)
-				if err != nil {
-					f.logger.Error("error removing file", "filename", fname, "err", err)
+	// Group the rotated files by index as a file and its compressed copy
+	// may briefly both exist
+	rotated := make(map[int][]os.FileInfo)
+	for _, fi := range files {
+		n, _, ok := f.fileIndex(fi.Name())
+		if !ok {
+			continue
+		}
+		rotated[n] = append(rotated[n], fi)
+	}
+
+	var fIndexes []int
+	for n := range rotated {
+		fIndexes = append(fIndexes, n)
+	}
+	if len(fIndexes) == 0 {
+		return nil
+	}
+
+	// Sorting the file indexes so that we can purge the older files and keep
+	// only the number of files as configured by the user
+	sort.Sort(sort.IntSlice(fIndexes))
+
+	var toDelete []int
+	if len(fIndexes) > f.MaxFiles {
+		toDelete = fIndexes[0 : len(fIndexes)-f.MaxFiles]
+	}
+	if kept := fIndexes[len(toDelete):]; f.MaxAge > 0 && len(kept) > 1 {
+		cutoff := time.Now().Add(-f.MaxAge)
+		for _, n := range kept[:len(kept)-1] {
+			for _, fi := range rotated[n] {
+				if fi.ModTime().Before(cutoff) {
+					toDelete = append(toDelete, n)
+					break
 				}
 			}
-			f.oldestLogFileIdx = fIndexes[0]
-		case <-f.doneCh:
-			return
 		}
 	}
+
+	for _, fIndex := range toDelete {
+		for _, fi := range rotated[fIndex] {
+			fname := filepath.Join(f.path, fi.Name())
+			err := os.RemoveAll(fname)
+			if err != nil {
+				f.logger.Error("error removing file", "filename", fname, "err", err)
+			}
+		}
+
+		tsFileName := filepath.Join(f.path, TimestampIndexName(fmt.Sprintf("%s.%d", f.baseFileName, fIndex)))
+		if err := os.Remove(tsFileName); err != nil && !os.IsNotExist(err) {
+			f.logger.Error("error removing file", "filename", tsFileName, "err", err)
+		}
+	}
+	f.oldestLogFileIdx = fIndexes[0]
+	return nil
 }
 
 // flushBuffer flushes the buffer
@@ -339,3 +619,51 @@ func (f *FileRotator) createOrResetBuffer() {
 		f.bufw.Reset(f.currentFile)
 	}
 }
+
+// TimestampRecord is the time at which the line starting at an offset of a
+// rotated file was written
+type TimestampRecord struct {
+	Offset    int64
+	Timestamp time.Time
+}
+
+// TimestampIndexName returns the name of the hidden timestamp index of the
+// rotated file with the given name. Compressed files share the index of the
+// file they were compressed from as offsets refer to the uncompressed content.
+func TimestampIndexName(name string) string {
+	return fmt.Sprintf(".%s%s", strings.TrimSuffix(name, CompressedSuffix), TimestampIndexSuffix)
+}
+
+// EncodeTimestampRecord encodes a timestamp index record for the line
+// starting at the offset. Times are recorded with millisecond precision.
+func EncodeTimestampRecord(offset int64, t time.Time) []byte {
+	buf := make([]byte, 2*binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, uint64(offset))
+	n += binary.PutVarint(buf[n:], t.UnixNano()/int64(time.Millisecond))
+	return buf[:n]
+}
+
+// DecodeTimestampRecords decodes the complete records of a timestamp index.
+// The number of bytes decoded is returned so a partially written record can
+// be decoded once the rest of it has been written.
+func DecodeTimestampRecords(p []byte) ([]*TimestampRecord, int) {
+	var records []*TimestampRecord
+	read := 0
+	for read < len(p) {
+		offset, n := binary.Uvarint(p[read:])
+		if n <= 0 {
+			break
+		}
+		ms, m := binary.Varint(p[read+n:])
+		if m <= 0 {
+			break
+		}
+		read += n + m
+
+		records = append(records, &TimestampRecord{
+			Offset:    int64(offset),
+			Timestamp: time.Unix(0, ms*int64(time.Millisecond)),
+		})
+	}
+	return records, read
+}
@@ -1,15 +1,18 @@
 package logging
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -349,6 +352,126 @@ func TestFileRotator_PurgeOldFiles(t *testing.T) {
 	})
 }
 
+func TestFileRotator_Compress(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	path, err := ioutil.TempDir("", pathPrefix)
+	require.NoError(err)
+	defer os.RemoveAll(path)
+
+	opts := &RotateOptions{Compress: true}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 5, opts, testlog.HCLogger(t))
+	require.NoError(err)
+
+	_, err = fr.Write([]byte("abcdefghij"))
+	require.NoError(err)
+	require.NoError(fr.Close())
+
+	// The rotated file is compressed and the current file is not
+	_, err = os.Stat(filepath.Join(path, "redis.stdout.0"))
+	require.True(os.IsNotExist(err))
+
+	f, err := os.Open(filepath.Join(path, "redis.stdout.0.gz"))
+	require.NoError(err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(err)
+	data, err := ioutil.ReadAll(gz)
+	require.NoError(err)
+	require.Equal("abcde", string(data))
+
+	data, err = ioutil.ReadFile(filepath.Join(path, "redis.stdout.1"))
+	require.NoError(err)
+	require.Equal("fghij", string(data))
+
+	// Reopening the rotator continues after the compressed files
+	fr, err = NewFileRotatorWithOptions(path, baseFileName, 10, 5, opts, testlog.HCLogger(t))
+	require.NoError(err)
+	require.Equal(filepath.Join(path, "redis.stdout.1"), fr.currentFile.Name())
+	require.NoError(fr.Close())
+}
+
+func TestFileRotator_Compress_OpenLastFile(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	path, err := ioutil.TempDir("", pathPrefix)
+	require.NoError(err)
+	defer os.RemoveAll(path)
+
+	// A rotated file left uncompressed and a compressed last file
+	require.NoError(ioutil.WriteFile(filepath.Join(path, "redis.stdout.0"), []byte("abc"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(path, "redis.stdout.1.gz"), nil, 0644))
+
+	opts := &RotateOptions{Compress: true}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 5, opts, testlog.HCLogger(t))
+	require.NoError(err)
+	require.Equal(filepath.Join(path, "redis.stdout.2"), fr.currentFile.Name())
+	require.NoError(fr.Close())
+
+	_, err = os.Stat(filepath.Join(path, "redis.stdout.0.gz"))
+	require.NoError(err)
+}
+
+func TestFileRotator_RotateInterval(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	path, err := ioutil.TempDir("", pathPrefix)
+	require.NoError(err)
+	defer os.RemoveAll(path)
+
+	opts := &RotateOptions{RotateInterval: 50 * time.Millisecond}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 1024, opts, testlog.HCLogger(t))
+	require.NoError(err)
+	defer fr.Close()
+
+	_, err = fr.Write([]byte("abc\n"))
+	require.NoError(err)
+	_, err = fr.Write([]byte("def\n"))
+	require.NoError(err)
+	require.Equal(filepath.Join(path, "redis.stdout.0"), fr.currentFile.Name())
+
+	time.Sleep(60 * time.Millisecond)
+	_, err = fr.Write([]byte("ghi\n"))
+	require.NoError(err)
+	require.Equal(filepath.Join(path, "redis.stdout.1"), fr.currentFile.Name())
+}
+
+func TestFileRotator_PurgeMaxAge(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	path, err := ioutil.TempDir("", pathPrefix)
+	require.NoError(err)
+	defer os.RemoveAll(path)
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, name := range []string{"redis.stdout.0.gz", "redis.stdout.1", "redis.stdout.2", "redis.stdout.3"} {
+		fname := filepath.Join(path, name)
+		require.NoError(ioutil.WriteFile(fname, []byte("abc"), 0644))
+		if name != "redis.stdout.2" {
+			require.NoError(os.Chtimes(fname, old, old))
+		}
+	}
+
+	opts := &RotateOptions{MaxAge: time.Hour}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 1024, opts, testlog.HCLogger(t))
+	require.NoError(err)
+	defer fr.Close()
+	require.NoError(fr.purge())
+
+	// Old rotated files are purged but the current file is kept
+	files, err := ioutil.ReadDir(path)
+	require.NoError(err)
+	var names []string
+	for _, fi := range files {
+		names = append(names, fi.Name())
+	}
+	require.Equal([]string{"redis.stdout.2", "redis.stdout.3"}, names)
+}
+
 func BenchmarkRotator(b *testing.B) {
 	kb := 1024
 	for _, inputSize := range []int{kb, 2 * kb, 4 * kb, 8 * kb, 16 * kb, 32 * kb, 64 * kb, 128 * kb, 256 * kb} {
@@ -393,3 +516,75 @@ func benchmarkRotatorWithInputSize(size int, b *testing.B) {
 		}
 	}
 }
+
+func TestFileRotator_Timestamps(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	path, err := ioutil.TempDir("", pathPrefix)
+	require.NoError(err)
+	defer os.RemoveAll(path)
+
+	opts := &RotateOptions{Timestamps: true}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 1024, opts, testlog.HCLogger(t))
+	require.NoError(err)
+
+	// Lines are recorded when they start, including lines started after a
+	// partial line was written
+	var times []time.Time
+	for _, data := range []string{"ab\ncd\n", "ef", "gh\nij\n"} {
+		before := time.Now()
+		_, err = fr.Write([]byte(data))
+		require.NoError(err)
+		times = append(times, before)
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.NoError(fr.Close())
+
+	index, err := ioutil.ReadFile(filepath.Join(path, ".redis.stdout.0.ts"))
+	require.NoError(err)
+	records, n := DecodeTimestampRecords(index)
+	require.Equal(len(index), n)
+	require.Len(records, 3)
+	for i, offset := range []int64{0, 6, 11} {
+		require.Equal(offset, records[i].Offset)
+		require.False(records[i].Timestamp.Before(times[i].Truncate(time.Millisecond)))
+	}
+
+	// Partially written records are not decoded
+	records, n = DecodeTimestampRecords(index[:len(index)-1])
+	require.Len(records, 2)
+	require.Equal(len(index)-len(EncodeTimestampRecord(11, records[1].Timestamp)), n)
+}
+
+func TestFileRotator_Timestamps_Purge(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	path, err := ioutil.TempDir("", pathPrefix)
+	require.NoError(err)
+	defer os.RemoveAll(path)
+
+	opts := &RotateOptions{Timestamps: true}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 1, 5, opts, testlog.HCLogger(t))
+	require.NoError(err)
+	defer fr.Close()
+
+	for _, data := range []string{"abcd\n", "efgh\n", "ijkl\n"} {
+		_, err = fr.Write([]byte(data))
+		require.NoError(err)
+	}
+
+	// The indexes of purged files are removed
+	testutil.WaitForResult(func() (bool, error) {
+		for _, name := range []string{".redis.stdout.0.ts", ".redis.stdout.1.ts"} {
+			if _, err := os.Stat(filepath.Join(path, name)); !os.IsNotExist(err) {
+				return false, fmt.Errorf("%q not purged: %v", name, err)
+			}
+		}
+		_, err := os.Stat(filepath.Join(path, ".redis.stdout.2.ts"))
+		return err == nil, err
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
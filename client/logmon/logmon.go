@@ -41,11 +41,44 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// Compress gzips log files once they are rotated
+	Compress bool
+
+	// RotateInterval is the age at which a log file is rotated even if it
+	// hasn't reached MaxFileSizeMB
+	RotateInterval time.Duration
+
+	// MaxAge is the age after which rotated log files are removed
+	MaxAge time.Duration
+
+	// Sinks are additional destinations log lines are shipped to
+	Sinks []*SinkConfig
+
+	// AllocID, Namespace, JobID, TaskGroup and TaskName identify the task
+	// whose logs are shipped to the sinks
+	AllocID   string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	TaskName  string
+}
+
+// rotateOptions returns the options of the config's log rotators
+func (c *LogConfig) rotateOptions() *logging.RotateOptions {
+	return &logging.RotateOptions{
+		Compress:       c.Compress,
+		RotateInterval: c.RotateInterval,
+		MaxAge:         c.MaxAge,
+	}
 }
 
 type LogMon interface {
 	Start(*LogConfig) error
 	Stop() error
+
+	// Stats returns the counters of the sinks of the running TaskLogger
+	Stats() ([]*SinkStats, error)
 }
 
 func NewLogMon(logger hclog.Logger) LogMon {
@@ -98,6 +131,15 @@ func (l *logmonImpl) Stop() error {
 	return nil
 }
 
+func (l *logmonImpl) Stats() ([]*SinkStats, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.tl == nil {
+		return nil, nil
+	}
+	return l.tl.Stats(), nil
+}
+
 type TaskLogger struct {
 	config *LogConfig
 
@@ -106,6 +148,9 @@ type TaskLogger struct {
 
 	// rotator for stderr
 	lre *logRotatorWrapper
+
+	// sinks lines from both stdout and stderr are shipped to
+	sinks []*logSink
 }
 
 // IsRunning will return true as long as one rotator wrapper is still running
@@ -137,33 +182,64 @@ func (tl *TaskLogger) Close() {
 		}()
 	}
 	wg.Wait()
+
+	// Close the sinks once the streams feeding them are closed
+	for _, s := range tl.sinks {
+		s.Close()
+	}
+}
+
+// Stats returns the counters of the TaskLogger's sinks
+func (tl *TaskLogger) Stats() []*SinkStats {
+	stats := make([]*SinkStats, len(tl.sinks))
+	for i, s := range tl.sinks {
+		stats[i] = s.stats()
+	}
+	return stats
 }
 
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
+	for _, sc := range cfg.Sinks {
+		s, err := newLogSink(cfg, sc, logger)
+		if err != nil {
+			tl.Close()
+			return nil, fmt.Errorf("failed to create %s log sink for %q: %v", sc.Type, sc.Address, err)
+		}
+		tl.sinks = append(tl.sinks, s)
+	}
+
+	// Record the time lines are written so logs can be filtered by time
 	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
-	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+	opts := cfg.rotateOptions()
+	opts.Timestamps = true
+
+	lro, err := logging.NewFileRotatorWithOptions(cfg.LogDir, cfg.StdoutLogFile,
+		cfg.MaxFiles, logFileSize, opts, logger)
 	if err != nil {
+		tl.Close()
 		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
 	}
 
-	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
+	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, tl.streamWriter(streamStdout, lro))
 	if err != nil {
+		tl.Close()
 		return nil, err
 	}
 
 	tl.lro = wrapperOut
 
-	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+	lre, err := logging.NewFileRotatorWithOptions(cfg.LogDir, cfg.StderrLogFile,
+		cfg.MaxFiles, logFileSize, opts, logger)
 	if err != nil {
+		tl.Close()
 		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
 	}
 
-	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
+	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, tl.streamWriter(streamStderr, lre))
 	if err != nil {
+		tl.Close()
 		return nil, err
 	}
 
@@ -173,6 +249,15 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 
 }
 
+// streamWriter returns the writer the output of a stream is copied to. The
+// rotator is used directly unless there are sinks to fan the lines out to.
+func (tl *TaskLogger) streamWriter(stream string, rotator io.WriteCloser) io.WriteCloser {
+	if len(tl.sinks) == 0 {
+		return rotator
+	}
+	return newLineWriter(stream, rotator, tl.sinks)
+}
+
 // logRotatorWrapper wraps our log rotator and exposes a pipe that can feed the
 // log rotator data. The processOutWriter should be attached to the process and
 // data will be copied from the reader to the rotator.
@@ -24,23 +24,32 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type StartRequest struct {
-	LogDir               string   `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
-	StdoutFileName       string   `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
-	StderrFileName       string   `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
-	MaxFiles             uint32   `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
-	MaxFileSizeMb        uint32   `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
-	StdoutFifo           string   `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
-	StderrFifo           string   `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	LogDir               string     `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
+	StdoutFileName       string     `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
+	StderrFileName       string     `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
+	MaxFiles             uint32     `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
+	MaxFileSizeMb        uint32     `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
+	StdoutFifo           string     `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
+	StderrFifo           string     `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	Sinks                []*LogSink `protobuf:"bytes,8,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	AllocId              string     `protobuf:"bytes,9,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	Namespace            string     `protobuf:"bytes,10,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	JobId                string     `protobuf:"bytes,11,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TaskGroup            string     `protobuf:"bytes,12,opt,name=task_group,json=taskGroup,proto3" json:"task_group,omitempty"`
+	TaskName             string     `protobuf:"bytes,13,opt,name=task_name,json=taskName,proto3" json:"task_name,omitempty"`
+	Compress             bool       `protobuf:"varint,14,opt,name=compress,proto3" json:"compress,omitempty"`
+	RotateInterval       int64      `protobuf:"varint,15,opt,name=rotate_interval,json=rotateInterval,proto3" json:"rotate_interval,omitempty"`
+	MaxAge               int64      `protobuf:"varint,16,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *StartRequest) Reset()         { *m = StartRequest{} }
 func (m *StartRequest) String() string { return proto.CompactTextString(m) }
 func (*StartRequest) ProtoMessage()    {}
 func (*StartRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_logmon_c8f5fe5f286cd193, []int{0}
+	return fileDescriptor_logmon_21f8365e3f4e8706, []int{0}
 }
 func (m *StartRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StartRequest.Unmarshal(m, b)
@@ -109,6 +118,123 @@ func (m *StartRequest) GetStderrFifo() string {
 	return ""
 }
 
+func (m *StartRequest) GetSinks() []*LogSink {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+func (m *StartRequest) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+func (m *StartRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *StartRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *StartRequest) GetTaskGroup() string {
+	if m != nil {
+		return m.TaskGroup
+	}
+	return ""
+}
+
+func (m *StartRequest) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+func (m *StartRequest) GetCompress() bool {
+	if m != nil {
+		return m.Compress
+	}
+	return false
+}
+
+func (m *StartRequest) GetRotateInterval() int64 {
+	if m != nil {
+		return m.RotateInterval
+	}
+	return 0
+}
+
+func (m *StartRequest) GetMaxAge() int64 {
+	if m != nil {
+		return m.MaxAge
+	}
+	return 0
+}
+
+type LogSink struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	BufferLines          uint32   `protobuf:"varint,3,opt,name=buffer_lines,json=bufferLines,proto3" json:"buffer_lines,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSink) Reset()         { *m = LogSink{} }
+func (m *LogSink) String() string { return proto.CompactTextString(m) }
+func (*LogSink) ProtoMessage()    {}
+func (*LogSink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logmon_21f8365e3f4e8706, []int{1}
+}
+func (m *LogSink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSink.Unmarshal(m, b)
+}
+func (m *LogSink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSink.Marshal(b, m, deterministic)
+}
+func (dst *LogSink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSink.Merge(dst, src)
+}
+func (m *LogSink) XXX_Size() int {
+	return xxx_messageInfo_LogSink.Size(m)
+}
+func (m *LogSink) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSink proto.InternalMessageInfo
+
+func (m *LogSink) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LogSink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *LogSink) GetBufferLines() uint32 {
+	if m != nil {
+		return m.BufferLines
+	}
+	return 0
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -119,7 +245,7 @@ func (m *StartResponse) Reset()         { *m = StartResponse{} }
 func (m *StartResponse) String() string { return proto.CompactTextString(m) }
 func (*StartResponse) ProtoMessage()    {}
 func (*StartResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_logmon_c8f5fe5f286cd193, []int{1}
+	return fileDescriptor_logmon_21f8365e3f4e8706, []int{2}
 }
 func (m *StartResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StartResponse.Unmarshal(m, b)
@@ -149,7 +275,7 @@ func (m *StopRequest) Reset()         { *m = StopRequest{} }
 func (m *StopRequest) String() string { return proto.CompactTextString(m) }
 func (*StopRequest) ProtoMessage()    {}
 func (*StopRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_logmon_c8f5fe5f286cd193, []int{2}
+	return fileDescriptor_logmon_21f8365e3f4e8706, []int{3}
 }
 func (m *StopRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StopRequest.Unmarshal(m, b)
@@ -179,7 +305,7 @@ func (m *StopResponse) Reset()         { *m = StopResponse{} }
 func (m *StopResponse) String() string { return proto.CompactTextString(m) }
 func (*StopResponse) ProtoMessage()    {}
 func (*StopResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_logmon_c8f5fe5f286cd193, []int{3}
+	return fileDescriptor_logmon_21f8365e3f4e8706, []int{4}
 }
 func (m *StopResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StopResponse.Unmarshal(m, b)
@@ -199,11 +325,145 @@ func (m *StopResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopResponse proto.InternalMessageInfo
 
+type StatsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
+func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatsRequest) ProtoMessage()    {}
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logmon_21f8365e3f4e8706, []int{5}
+}
+func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsRequest.Unmarshal(m, b)
+}
+func (m *StatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsRequest.Marshal(b, m, deterministic)
+}
+func (dst *StatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsRequest.Merge(dst, src)
+}
+func (m *StatsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatsRequest.Size(m)
+}
+func (m *StatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsRequest proto.InternalMessageInfo
+
+type StatsResponse struct {
+	Sinks                []*SinkStats `protobuf:"bytes,1,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
+func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()    {}
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logmon_21f8365e3f4e8706, []int{6}
+}
+func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsResponse.Unmarshal(m, b)
+}
+func (m *StatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsResponse.Marshal(b, m, deterministic)
+}
+func (dst *StatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsResponse.Merge(dst, src)
+}
+func (m *StatsResponse) XXX_Size() int {
+	return xxx_messageInfo_StatsResponse.Size(m)
+}
+func (m *StatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsResponse proto.InternalMessageInfo
+
+func (m *StatsResponse) GetSinks() []*SinkStats {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+type SinkStats struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	WrittenLines         uint64   `protobuf:"varint,3,opt,name=written_lines,json=writtenLines,proto3" json:"written_lines,omitempty"`
+	DroppedLines         uint64   `protobuf:"varint,4,opt,name=dropped_lines,json=droppedLines,proto3" json:"dropped_lines,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SinkStats) Reset()         { *m = SinkStats{} }
+func (m *SinkStats) String() string { return proto.CompactTextString(m) }
+func (*SinkStats) ProtoMessage()    {}
+func (*SinkStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logmon_21f8365e3f4e8706, []int{7}
+}
+func (m *SinkStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SinkStats.Unmarshal(m, b)
+}
+func (m *SinkStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SinkStats.Marshal(b, m, deterministic)
+}
+func (dst *SinkStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SinkStats.Merge(dst, src)
+}
+func (m *SinkStats) XXX_Size() int {
+	return xxx_messageInfo_SinkStats.Size(m)
+}
+func (m *SinkStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_SinkStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SinkStats proto.InternalMessageInfo
+
+func (m *SinkStats) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *SinkStats) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *SinkStats) GetWrittenLines() uint64 {
+	if m != nil {
+		return m.WrittenLines
+	}
+	return 0
+}
+
+func (m *SinkStats) GetDroppedLines() uint64 {
+	if m != nil {
+		return m.DroppedLines
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*StartRequest)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest")
+	proto.RegisterType((*LogSink)(nil), "hashicorp.nomad.client.logmon.proto.LogSink")
 	proto.RegisterType((*StartResponse)(nil), "hashicorp.nomad.client.logmon.proto.StartResponse")
 	proto.RegisterType((*StopRequest)(nil), "hashicorp.nomad.client.logmon.proto.StopRequest")
 	proto.RegisterType((*StopResponse)(nil), "hashicorp.nomad.client.logmon.proto.StopResponse")
+	proto.RegisterType((*StatsRequest)(nil), "hashicorp.nomad.client.logmon.proto.StatsRequest")
+	proto.RegisterType((*StatsResponse)(nil), "hashicorp.nomad.client.logmon.proto.StatsResponse")
+	proto.RegisterType((*SinkStats)(nil), "hashicorp.nomad.client.logmon.proto.SinkStats")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -220,6 +480,7 @@ const _ = grpc.SupportPackageIsVersion4
 type LogMonClient interface {
 	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error)
 	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
+	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 }
 
 type logMonClient struct {
@@ -248,10 +509,20 @@ func (c *logMonClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *logMonClient) Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.client.logmon.proto.LogMon/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LogMonServer is the server API for LogMon service.
 type LogMonServer interface {
 	Start(context.Context, *StartRequest) (*StartResponse, error)
 	Stop(context.Context, *StopRequest) (*StopResponse, error)
+	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
 }
 
 func RegisterLogMonServer(s *grpc.Server, srv LogMonServer) {
@@ -294,6 +565,24 @@ func _LogMon_Stop_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LogMon_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogMonServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.client.logmon.proto.LogMon/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogMonServer).Stats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _LogMon_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hashicorp.nomad.client.logmon.proto.LogMon",
 	HandlerType: (*LogMonServer)(nil),
@@ -306,35 +595,57 @@ var _LogMon_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Stop",
 			Handler:    _LogMon_Stop_Handler,
 		},
+		{
+			MethodName: "Stats",
+			Handler:    _LogMon_Stats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "client/logmon/proto/logmon.proto",
 }
 
 func init() {
-	proto.RegisterFile("client/logmon/proto/logmon.proto", fileDescriptor_logmon_c8f5fe5f286cd193)
-}
-
-var fileDescriptor_logmon_c8f5fe5f286cd193 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x31, 0x6f, 0xc2, 0x30,
-	0x10, 0x85, 0x1b, 0x0a, 0xa1, 0x1c, 0x0d, 0x45, 0x5e, 0x1a, 0xd1, 0xa1, 0x28, 0x1d, 0xca, 0x14,
-	0x0a, 0xfd, 0x07, 0x55, 0xd5, 0xa9, 0x74, 0x80, 0xad, 0x4b, 0x64, 0xc0, 0x09, 0x96, 0x62, 0x5f,
-	0x6a, 0x1b, 0x09, 0xb1, 0xf6, 0xd7, 0xf6, 0x5f, 0x54, 0x71, 0x4c, 0x94, 0x11, 0x26, 0xeb, 0xee,
-	0x7d, 0x4f, 0xf7, 0xfc, 0x60, 0xbc, 0xc9, 0x39, 0x93, 0x66, 0x9a, 0x63, 0x26, 0x50, 0x4e, 0x0b,
-	0x85, 0x06, 0xdd, 0x10, 0xdb, 0x81, 0x3c, 0xed, 0xa8, 0xde, 0xf1, 0x0d, 0xaa, 0x22, 0x96, 0x28,
-	0xe8, 0x36, 0xae, 0x1c, 0x71, 0x13, 0x8a, 0x7e, 0x5b, 0x70, 0xbb, 0x32, 0x54, 0x99, 0x25, 0xfb,
-	0xd9, 0x33, 0x6d, 0xc8, 0x3d, 0x74, 0x73, 0xcc, 0x92, 0x2d, 0x57, 0xa1, 0x37, 0xf6, 0x26, 0xbd,
-	0xa5, 0x9f, 0x63, 0xf6, 0xce, 0x15, 0x99, 0xc0, 0x50, 0x9b, 0x2d, 0xee, 0x4d, 0x92, 0xf2, 0x9c,
-	0x25, 0x92, 0x0a, 0x16, 0xb6, 0x2c, 0x31, 0xa8, 0xf6, 0x1f, 0x3c, 0x67, 0x5f, 0x54, 0x30, 0x47,
-	0x32, 0xa5, 0x1a, 0xe4, 0x75, 0x4d, 0x32, 0xa5, 0x6a, 0xf2, 0x01, 0x7a, 0x82, 0x1e, 0x2c, 0xa6,
-	0xc3, 0xf6, 0xd8, 0x9b, 0x04, 0xcb, 0x1b, 0x41, 0x0f, 0xa5, 0xae, 0xc9, 0x33, 0x0c, 0x4f, 0x62,
-	0xa2, 0xf9, 0x91, 0x25, 0x62, 0x1d, 0x76, 0x2c, 0x13, 0x38, 0x66, 0xc5, 0x8f, 0x6c, 0xb1, 0x26,
-	0x8f, 0xd0, 0xaf, 0x93, 0xa5, 0x18, 0xfa, 0xf6, 0x14, 0x9c, 0x42, 0xa5, 0xe8, 0x80, 0x2a, 0x50,
-	0x8a, 0x61, 0xb7, 0x06, 0x6c, 0x96, 0x14, 0xa3, 0x3b, 0x08, 0x5c, 0x09, 0xba, 0x40, 0xa9, 0x59,
-	0x14, 0x40, 0x7f, 0x65, 0xb0, 0x70, 0xa5, 0x44, 0x83, 0xb2, 0xa4, 0x72, 0xac, 0xe4, 0xf9, 0x9f,
-	0x07, 0xfe, 0x27, 0x66, 0x0b, 0x94, 0xa4, 0x80, 0x8e, 0xb5, 0x92, 0x59, 0x7c, 0x46, 0xdf, 0x71,
-	0xb3, 0xeb, 0xd1, 0xfc, 0x12, 0x8b, 0x4b, 0x76, 0x45, 0x04, 0xb4, 0xcb, 0x30, 0xe4, 0xe5, 0x4c,
-	0x77, 0xfd, 0x8d, 0xd1, 0xec, 0x02, 0xc7, 0xe9, 0xdc, 0x5b, 0xf7, 0xbb, 0x63, 0xf7, 0x6b, 0xdf,
-	0x3e, 0xaf, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb4, 0x87, 0xfc, 0x7a, 0x02, 0x00, 0x00,
+	proto.RegisterFile("client/logmon/proto/logmon.proto", fileDescriptor_logmon_21f8365e3f4e8706)
+}
+
+var fileDescriptor_logmon_21f8365e3f4e8706 = []byte{
+	// 605 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xcd, 0x6e, 0xdb, 0x3a,
+	0x10, 0x85, 0xa3, 0xc4, 0xbf, 0x63, 0xcb, 0x09, 0x08, 0x5c, 0x5c, 0xde, 0xdc, 0x16, 0x75, 0x95,
+	0x45, 0xbc, 0x28, 0x94, 0x26, 0x7d, 0x82, 0x06, 0x41, 0x8b, 0x00, 0x49, 0x17, 0x32, 0xba, 0xc9,
+	0x46, 0xa0, 0x2d, 0x4a, 0x61, 0x22, 0x89, 0x2a, 0x49, 0xb7, 0x69, 0xb6, 0x7d, 0xa2, 0x3e, 0x4f,
+	0x5f, 0xa6, 0xe0, 0x90, 0x56, 0xbc, 0xb4, 0x57, 0xf6, 0x9c, 0xf9, 0xc6, 0xe4, 0x39, 0x1c, 0xc3,
+	0x74, 0x59, 0x0a, 0x5e, 0x9b, 0xb3, 0x52, 0x16, 0x95, 0xac, 0xcf, 0x1a, 0x25, 0x8d, 0xf4, 0x45,
+	0x8c, 0x05, 0x39, 0xb9, 0x67, 0xfa, 0x5e, 0x2c, 0xa5, 0x6a, 0xe2, 0x5a, 0x56, 0x2c, 0x8b, 0xdd,
+	0x44, 0xbc, 0x09, 0x45, 0xbf, 0x3b, 0x30, 0x9e, 0x1b, 0xa6, 0x4c, 0xc2, 0xbf, 0xad, 0xb8, 0x36,
+	0xe4, 0x5f, 0xe8, 0x97, 0xb2, 0x48, 0x33, 0xa1, 0x68, 0x30, 0x0d, 0x66, 0xc3, 0xa4, 0x57, 0xca,
+	0xe2, 0x4a, 0x28, 0x32, 0x83, 0x23, 0x6d, 0x32, 0xb9, 0x32, 0x69, 0x2e, 0x4a, 0x9e, 0xd6, 0xac,
+	0xe2, 0x74, 0x1f, 0x89, 0x89, 0xd3, 0x3f, 0x89, 0x92, 0x7f, 0x61, 0x15, 0xf7, 0x24, 0x57, 0x6a,
+	0x83, 0x3c, 0x68, 0x49, 0xae, 0x54, 0x4b, 0xfe, 0x0f, 0xc3, 0x8a, 0x3d, 0x21, 0xa6, 0x69, 0x67,
+	0x1a, 0xcc, 0xc2, 0x64, 0x50, 0xb1, 0x27, 0xdb, 0xd7, 0xe4, 0x14, 0x8e, 0xd6, 0xcd, 0x54, 0x8b,
+	0x67, 0x9e, 0x56, 0x0b, 0xda, 0x45, 0x26, 0xf4, 0xcc, 0x5c, 0x3c, 0xf3, 0xdb, 0x05, 0x79, 0x03,
+	0xa3, 0xf6, 0x66, 0xb9, 0xa4, 0x3d, 0x3c, 0x0a, 0xd6, 0x97, 0xca, 0xa5, 0x07, 0xdc, 0x85, 0x72,
+	0x49, 0xfb, 0x2d, 0x80, 0x77, 0xc9, 0x25, 0xb9, 0x84, 0xae, 0x16, 0xf5, 0xa3, 0xa6, 0x83, 0xe9,
+	0xc1, 0x6c, 0x74, 0xf1, 0x2e, 0xde, 0x22, 0xba, 0xf8, 0x46, 0x16, 0x73, 0x51, 0x3f, 0x26, 0x6e,
+	0x94, 0xfc, 0x07, 0x03, 0x56, 0x96, 0x72, 0x99, 0x8a, 0x8c, 0x0e, 0xf1, 0x84, 0x3e, 0xd6, 0xd7,
+	0x19, 0x79, 0x05, 0x43, 0x1b, 0x82, 0x6e, 0xd8, 0x92, 0x53, 0xc0, 0xde, 0x8b, 0x40, 0xfe, 0x81,
+	0xde, 0x83, 0x5c, 0xd8, 0xb1, 0x11, 0xb6, 0xba, 0x0f, 0x72, 0x71, 0x9d, 0x91, 0xd7, 0x00, 0x86,
+	0xe9, 0xc7, 0xb4, 0x50, 0x72, 0xd5, 0xd0, 0xb1, 0x9b, 0xb2, 0xca, 0x67, 0x2b, 0xd8, 0xe8, 0xb0,
+	0x8d, 0xe9, 0x86, 0xd8, 0x1d, 0x58, 0x01, 0x73, 0x3d, 0x86, 0xc1, 0x52, 0x56, 0x8d, 0xe2, 0x5a,
+	0xd3, 0xc9, 0x34, 0x98, 0x0d, 0x92, 0xb6, 0x26, 0xa7, 0x70, 0xa8, 0xa4, 0x61, 0x86, 0xa7, 0xa2,
+	0x36, 0x5c, 0x7d, 0x67, 0x25, 0x3d, 0x9c, 0x06, 0xb3, 0x83, 0x64, 0xe2, 0xe4, 0x6b, 0xaf, 0xda,
+	0x4d, 0xb0, 0xf9, 0xb3, 0x82, 0xd3, 0x23, 0x04, 0x7a, 0x15, 0x7b, 0xfa, 0x58, 0xf0, 0xe8, 0x0e,
+	0xfa, 0xde, 0x3b, 0x21, 0xd0, 0x31, 0x3f, 0x1b, 0xee, 0x57, 0x05, 0xbf, 0x13, 0x0a, 0x7d, 0x96,
+	0x65, 0x78, 0xf6, 0xbe, 0xcf, 0xc1, 0x95, 0xe4, 0x2d, 0x8c, 0x17, 0xab, 0x3c, 0xe7, 0x2a, 0x2d,
+	0x45, 0xcd, 0x35, 0x2e, 0x45, 0x98, 0x8c, 0x9c, 0x76, 0x63, 0xa5, 0xe8, 0x10, 0x42, 0xbf, 0x8e,
+	0xba, 0x91, 0xb5, 0xe6, 0x51, 0x08, 0xa3, 0xb9, 0x91, 0x8d, 0x5f, 0xcf, 0x68, 0x02, 0x63, 0x57,
+	0xfa, 0x36, 0xd6, 0xcc, 0xe8, 0x75, 0xff, 0x2b, 0x84, 0xbe, 0x76, 0x00, 0xb9, 0x5a, 0x3f, 0x6d,
+	0x80, 0x4f, 0x1b, 0x6f, 0xf5, 0xb4, 0xd6, 0x9b, 0xfb, 0x19, 0x37, 0x1c, 0xfd, 0x0a, 0x60, 0xd8,
+	0x8a, 0x3b, 0xba, 0x3e, 0x81, 0xf0, 0x87, 0x12, 0xc6, 0xf0, 0x7a, 0xc3, 0x76, 0x27, 0x19, 0x7b,
+	0x11, 0x7d, 0x5b, 0x28, 0x53, 0xb2, 0x69, 0x78, 0xe6, 0xa1, 0x8e, 0x83, 0xbc, 0x88, 0xd0, 0xc5,
+	0x9f, 0x7d, 0xe8, 0xdd, 0xc8, 0xe2, 0x56, 0xd6, 0xa4, 0x81, 0x2e, 0xe6, 0x44, 0xce, 0xb7, 0x33,
+	0xb4, 0xf1, 0x17, 0x3f, 0xbe, 0xd8, 0x65, 0xc4, 0xe7, 0xbc, 0x47, 0x2a, 0xe8, 0xd8, 0xe4, 0xc9,
+	0xfb, 0x2d, 0xa7, 0xdb, 0x37, 0x3b, 0x3e, 0xdf, 0x61, 0xa2, 0x3d, 0xce, 0x19, 0x34, 0x7a, 0x7b,
+	0x83, 0x46, 0xef, 0x6c, 0xf0, 0x65, 0x4f, 0xa2, 0xbd, 0xcb, 0xfe, 0x5d, 0x17, 0x1b, 0x8b, 0x1e,
+	0x7e, 0x7c, 0xf8, 0x3b, 0x00, 0xd6, 0x15, 0x1a, 0x08, 0x63, 0x05, 0x00, 0x00,
 }
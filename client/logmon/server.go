@@ -1,6 +1,8 @@
 package logmon
 
 import (
+	"time"
+
 	"golang.org/x/net/context"
 
 	plugin "github.com/hashicorp/go-plugin"
@@ -14,13 +16,28 @@ type logmonServer struct {
 
 func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*proto.StartResponse, error) {
 	cfg := &LogConfig{
-		LogDir:        req.LogDir,
-		StdoutLogFile: req.StdoutFileName,
-		StderrLogFile: req.StderrFileName,
-		MaxFiles:      int(req.MaxFiles),
-		MaxFileSizeMB: int(req.MaxFileSizeMb),
-		StdoutFifo:    req.StdoutFifo,
-		StderrFifo:    req.StderrFifo,
+		LogDir:         req.LogDir,
+		StdoutLogFile:  req.StdoutFileName,
+		StderrLogFile:  req.StderrFileName,
+		MaxFiles:       int(req.MaxFiles),
+		MaxFileSizeMB:  int(req.MaxFileSizeMb),
+		Compress:       req.Compress,
+		RotateInterval: time.Duration(req.RotateInterval),
+		MaxAge:         time.Duration(req.MaxAge),
+		StdoutFifo:     req.StdoutFifo,
+		StderrFifo:     req.StderrFifo,
+		AllocID:        req.AllocId,
+		Namespace:      req.Namespace,
+		JobID:          req.JobId,
+		TaskGroup:      req.TaskGroup,
+		TaskName:       req.TaskName,
+	}
+	for _, s := range req.Sinks {
+		cfg.Sinks = append(cfg.Sinks, &SinkConfig{
+			Type:        s.Type,
+			Address:     s.Address,
+			BufferLines: int(s.BufferLines),
+		})
 	}
 
 	err := s.impl.Start(cfg)
@@ -34,3 +51,21 @@ func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*pro
 func (s *logmonServer) Stop(ctx context.Context, req *proto.StopRequest) (*proto.StopResponse, error) {
 	return &proto.StopResponse{}, s.impl.Stop()
 }
+
+func (s *logmonServer) Stats(ctx context.Context, req *proto.StatsRequest) (*proto.StatsResponse, error) {
+	stats, err := s.impl.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.StatsResponse{}
+	for _, s := range stats {
+		resp.Sinks = append(resp.Sinks, &proto.SinkStats{
+			Type:         s.Type,
+			Address:      s.Address,
+			WrittenLines: s.WrittenLines,
+			DroppedLines: s.DroppedLines,
+		})
+	}
+	return resp, nil
+}
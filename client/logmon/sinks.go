@@ -0,0 +1,459 @@
+package logmon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+)
+
+const (
+	// SinkTypeSyslog ships log lines in the RFC5424 format to a syslog server
+	// over TCP, UDP or a Unix socket.
+	SinkTypeSyslog = "syslog"
+
+	// SinkTypeJSON writes log lines as JSON objects, along with the alloc, job
+	// and task metadata, to a rotated file in the log directory.
+	SinkTypeJSON = "json"
+
+	// defaultSinkBufferLines is the number of lines buffered for a sink when
+	// the sink does not configure a buffer size.
+	defaultSinkBufferLines = 1024
+
+	// sinkMaxLineBytes is the maximum length of a line sent to a sink. Longer
+	// lines are split.
+	sinkMaxLineBytes = 64 * 1024
+
+	// sinkDialTimeout is the timeout for connecting to a syslog server
+	sinkDialTimeout = 5 * time.Second
+
+	// sinkWriteTimeout is the timeout for writing a line to a syslog server
+	sinkWriteTimeout = 5 * time.Second
+
+	// sinkReconnectInterval is the minimum interval between attempts to
+	// connect to a syslog server. Lines sent while the sink is disconnected
+	// are dropped.
+	sinkReconnectInterval = 1 * time.Second
+
+	// sinkDrainTimeout is how long buffered lines are shipped for once a sink
+	// is closed.
+	sinkDrainTimeout = 2 * time.Second
+
+	// streamStdout and streamStderr are the names of the streams lines are
+	// read from
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+)
+
+// SinkConfig configures a destination that log lines are shipped to alongside
+// the rotated log files.
+type SinkConfig struct {
+	// Type is the type of the sink
+	Type string
+
+	// Address is the URL of the syslog server for syslog sinks and the file
+	// name relative to LogDir for JSON sinks
+	Address string
+
+	// BufferLines is the number of lines buffered before new lines are
+	// dropped
+	BufferLines int
+}
+
+// SinkStats are the counters of a sink since it was started
+type SinkStats struct {
+	Type         string
+	Address      string
+	WrittenLines uint64
+	DroppedLines uint64
+}
+
+// logLine is a single line read from a task's stdout or stderr
+type logLine struct {
+	stream    string
+	timestamp time.Time
+	data      []byte
+}
+
+// sinkWriter writes log lines to the destination of a sink
+type sinkWriter interface {
+	writeLine(l *logLine) error
+	Close() error
+}
+
+// logSink ships the lines it is sent to a sinkWriter. Lines are buffered and
+// dropped once the buffer is full so a slow or unavailable destination never
+// blocks the task's output.
+type logSink struct {
+	config *SinkConfig
+	writer sinkWriter
+	logger hclog.Logger
+
+	lines chan *logLine
+
+	written uint64
+	dropped uint64
+
+	shutdownCh   chan struct{}
+	shutdownOnce sync.Once
+	doneCh       chan struct{}
+}
+
+func newLogSink(cfg *LogConfig, sc *SinkConfig, logger hclog.Logger) (*logSink, error) {
+	logger = logger.With("sink", sc.Type, "address", sc.Address)
+
+	var w sinkWriter
+	var err error
+	switch sc.Type {
+	case SinkTypeSyslog:
+		w, err = newSyslogSinkWriter(cfg, sc.Address)
+	case SinkTypeJSON:
+		w, err = newJSONSinkWriter(cfg, sc.Address, logger)
+	default:
+		err = fmt.Errorf("unknown sink type %q", sc.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	size := sc.BufferLines
+	if size <= 0 {
+		size = defaultSinkBufferLines
+	}
+
+	s := &logSink{
+		config:     sc,
+		writer:     w,
+		logger:     logger,
+		lines:      make(chan *logLine, size),
+		shutdownCh: make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// send buffers a line to be shipped, dropping it if the buffer is full.
+func (s *logSink) send(l *logLine) {
+	select {
+	case s.lines <- l:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+func (s *logSink) run() {
+	defer close(s.doneCh)
+	defer s.writer.Close()
+
+	for {
+		select {
+		case l := <-s.lines:
+			s.write(l)
+		case <-s.shutdownCh:
+			s.drain()
+			return
+		}
+	}
+}
+
+// drain ships the buffered lines until the buffer is empty or the drain
+// timeout is reached.
+func (s *logSink) drain() {
+	deadline := time.Now().Add(sinkDrainTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case l := <-s.lines:
+			s.write(l)
+		default:
+			return
+		}
+	}
+}
+
+func (s *logSink) write(l *logLine) {
+	if err := s.writer.writeLine(l); err != nil {
+		if atomic.AddUint64(&s.dropped, 1) == 1 {
+			s.logger.Warn("failed to ship log line", "error", err)
+		} else {
+			s.logger.Trace("failed to ship log line", "error", err)
+		}
+		return
+	}
+	atomic.AddUint64(&s.written, 1)
+}
+
+func (s *logSink) stats() *SinkStats {
+	return &SinkStats{
+		Type:         s.config.Type,
+		Address:      s.config.Address,
+		WrittenLines: atomic.LoadUint64(&s.written),
+		DroppedLines: atomic.LoadUint64(&s.dropped),
+	}
+}
+
+// Close stops the sink after shipping the buffered lines.
+func (s *logSink) Close() {
+	s.shutdownOnce.Do(func() {
+		close(s.shutdownCh)
+	})
+	<-s.doneCh
+}
+
+// lineWriter writes the output of a stream to the rotator and sends each
+// complete line to the sinks.
+type lineWriter struct {
+	stream  string
+	rotator io.WriteCloser
+	sinks   []*logSink
+
+	buf []byte
+}
+
+func newLineWriter(stream string, rotator io.WriteCloser, sinks []*logSink) *lineWriter {
+	return &lineWriter{
+		stream:  stream,
+		rotator: rotator,
+		sinks:   sinks,
+	}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	n, err := w.rotator.Write(p)
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	for len(w.buf) >= sinkMaxLineBytes {
+		w.emit(w.buf[:sinkMaxLineBytes])
+		w.buf = w.buf[sinkMaxLineBytes:]
+	}
+
+	// Copy the remainder so the buffer does not pin large writes
+	w.buf = append([]byte(nil), w.buf...)
+	return n, err
+}
+
+func (w *lineWriter) emit(data []byte) {
+	data = bytes.TrimSuffix(data, []byte{'\r'})
+	l := &logLine{
+		stream:    w.stream,
+		timestamp: time.Now(),
+		data:      append([]byte(nil), data...),
+	}
+	for _, s := range w.sinks {
+		s.send(l)
+	}
+}
+
+// Close sends any partial line to the sinks and closes the rotator.
+func (w *lineWriter) Close() error {
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = nil
+	}
+	return w.rotator.Close()
+}
+
+// syslogSinkWriter writes lines in the RFC5424 format to a syslog server.
+// Stream transports use octet counting framing and datagram transports send
+// one message per datagram.
+type syslogSinkWriter struct {
+	network string
+	addr    string
+	stream  bool
+
+	hostname string
+	appName  string
+	procID   string
+
+	conn     net.Conn
+	lastDial time.Time
+}
+
+func newSyslogSinkWriter(cfg *LogConfig, address string) (*syslogSinkWriter, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid syslog address %q: %v", address, err)
+	}
+
+	w := &syslogSinkWriter{
+		appName: syslogHeaderField(cfg.TaskName, 48),
+		procID:  syslogHeaderField(cfg.AllocID, 128),
+	}
+	switch u.Scheme {
+	case "tcp", "udp":
+		w.network = u.Scheme
+		w.addr = u.Host
+	case "unix":
+		w.network = u.Scheme
+		w.addr = u.Path
+	default:
+		return nil, fmt.Errorf("unsupported syslog address scheme %q", u.Scheme)
+	}
+
+	hostname, _ := os.Hostname()
+	w.hostname = syslogHeaderField(hostname, 255)
+	return w, nil
+}
+
+// dial connects to the syslog server. Unix sockets are tried as datagram
+// sockets first, falling back to stream sockets.
+func (w *syslogSinkWriter) dial() error {
+	if !w.lastDial.IsZero() && time.Since(w.lastDial) < sinkReconnectInterval {
+		return fmt.Errorf("not connected to syslog server %q", w.addr)
+	}
+	w.lastDial = time.Now()
+
+	networks := []string{w.network}
+	if w.network == "unix" {
+		networks = []string{"unixgram", "unix"}
+	}
+
+	var err error
+	for _, network := range networks {
+		var conn net.Conn
+		conn, err = net.DialTimeout(network, w.addr, sinkDialTimeout)
+		if err == nil {
+			w.conn = conn
+			w.stream = network == "tcp" || network == "unix"
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to connect to syslog server %q: %v", w.addr, err)
+}
+
+func (w *syslogSinkWriter) writeLine(l *logLine) error {
+	if w.conn == nil {
+		if err := w.dial(); err != nil {
+			return err
+		}
+	}
+
+	msg := formatSyslogMessage(l, w.hostname, w.appName, w.procID)
+	if w.stream {
+		msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	if _, err := w.conn.Write(msg); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (w *syslogSinkWriter) Close() error {
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// formatSyslogMessage formats a line as an RFC5424 message. Lines from stdout
+// are sent with the user facility at the informational severity and lines
+// from stderr at the error severity.
+func formatSyslogMessage(l *logLine, hostname, appName, procID string) []byte {
+	pri := 14
+	if l.stream == streamStderr {
+		pri = 11
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %s %s - ",
+		pri,
+		l.timestamp.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		hostname, appName, procID, l.stream)
+	buf.Write(l.data)
+	return buf.Bytes()
+}
+
+// syslogHeaderField returns the value as a valid RFC5424 header field: a
+// printable ASCII string without spaces, truncated to the maximum length, or
+// the nil value "-" if empty.
+func syslogHeaderField(v string, max int) string {
+	v = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, v)
+	if len(v) > max {
+		v = v[:max]
+	}
+	if v == "" {
+		return "-"
+	}
+	return v
+}
+
+// jsonLogLine is the object written for each line by JSON sinks
+type jsonLogLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Stream    string    `json:"stream"`
+	Message   string    `json:"message"`
+	AllocID   string    `json:"alloc_id,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	JobID     string    `json:"job_id,omitempty"`
+	TaskGroup string    `json:"task_group,omitempty"`
+	Task      string    `json:"task,omitempty"`
+}
+
+// jsonSinkWriter writes lines as JSON objects to a file rotated like the
+// task's log files.
+type jsonSinkWriter struct {
+	cfg     *LogConfig
+	rotator *logging.FileRotator
+	enc     *json.Encoder
+}
+
+func newJSONSinkWriter(cfg *LogConfig, fileName string, logger hclog.Logger) (*jsonSinkWriter, error) {
+	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
+	rotator, err := logging.NewFileRotatorWithOptions(cfg.LogDir, fileName, cfg.MaxFiles,
+		logFileSize, cfg.rotateOptions(), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create json log file for %q: %v", fileName, err)
+	}
+
+	return &jsonSinkWriter{
+		cfg:     cfg,
+		rotator: rotator,
+		enc:     json.NewEncoder(rotator),
+	}, nil
+}
+
+func (w *jsonSinkWriter) writeLine(l *logLine) error {
+	return w.enc.Encode(&jsonLogLine{
+		Timestamp: l.timestamp.UTC(),
+		Stream:    l.stream,
+		Message:   string(l.data),
+		AllocID:   w.cfg.AllocID,
+		Namespace: w.cfg.Namespace,
+		JobID:     w.cfg.JobID,
+		TaskGroup: w.cfg.TaskGroup,
+		Task:      w.cfg.TaskName,
+	})
+}
+
+func (w *jsonSinkWriter) Close() error {
+	return w.rotator.Close()
+}
@@ -0,0 +1,329 @@
+package logmon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/lib/fifo"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// bufferWriteCloser records the data written to it
+type bufferWriteCloser struct {
+	data   []byte
+	closed bool
+}
+
+func (b *bufferWriteCloser) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
+
+func (b *bufferWriteCloser) Close() error {
+	b.closed = true
+	return nil
+}
+
+// blockingSinkWriter blocks writes until unblocked
+type blockingSinkWriter struct {
+	unblockCh chan struct{}
+}
+
+func (b *blockingSinkWriter) writeLine(*logLine) error {
+	<-b.unblockCh
+	return nil
+}
+
+func (b *blockingSinkWriter) Close() error { return nil }
+
+func testSink(t *testing.T, size int, w sinkWriter) *logSink {
+	s := &logSink{
+		config:     &SinkConfig{Type: "test"},
+		writer:     w,
+		logger:     testlog.HCLogger(t),
+		lines:      make(chan *logLine, size),
+		shutdownCh: make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func TestLineWriter_Split(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	rotator := &bufferWriteCloser{}
+	sink := &logSink{lines: make(chan *logLine, 10)}
+	w := newLineWriter(streamStdout, rotator, []*logSink{sink})
+
+	_, err := w.Write([]byte("hello\nwor"))
+	require.NoError(err)
+	_, err = w.Write([]byte("ld\r\npartial"))
+	require.NoError(err)
+	require.NoError(w.Close())
+
+	require.Equal("hello\nworld\r\npartial", string(rotator.data))
+	require.True(rotator.closed)
+
+	require.Len(sink.lines, 3)
+	for _, expected := range []string{"hello", "world", "partial"} {
+		l := <-sink.lines
+		require.Equal(streamStdout, l.stream)
+		require.Equal(expected, string(l.data))
+	}
+}
+
+func TestLineWriter_LongLine(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	sink := &logSink{lines: make(chan *logLine, 10)}
+	w := newLineWriter(streamStdout, &bufferWriteCloser{}, []*logSink{sink})
+
+	_, err := w.Write([]byte(strings.Repeat("a", sinkMaxLineBytes+10)))
+	require.NoError(err)
+	require.Len(sink.lines, 1)
+	require.Len((<-sink.lines).data, sinkMaxLineBytes)
+
+	require.NoError(w.Close())
+	require.Len(sink.lines, 1)
+	require.Len((<-sink.lines).data, 10)
+}
+
+func TestLogSink_Dropped(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	w := &blockingSinkWriter{unblockCh: make(chan struct{})}
+	s := testSink(t, 2, w)
+
+	// The first line is taken by the blocked writer and the next two fill
+	// the buffer
+	s.send(&logLine{})
+	testutil.WaitForResult(func() (bool, error) {
+		return len(s.lines) == 0, fmt.Errorf("line not taken")
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	for i := 0; i < 5; i++ {
+		s.send(&logLine{})
+	}
+	require.EqualValues(3, s.stats().DroppedLines)
+
+	close(w.unblockCh)
+	s.Close()
+
+	stats := s.stats()
+	require.EqualValues(3, stats.WrittenLines)
+	require.EqualValues(3, stats.DroppedLines)
+}
+
+func TestFormatSyslogMessage(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2019, 11, 5, 10, 30, 0, 123456000, time.UTC)
+	out := formatSyslogMessage(&logLine{
+		stream:    streamStderr,
+		timestamp: ts,
+		data:      []byte("oh no"),
+	}, "host", syslogHeaderField("my task", 48), syslogHeaderField("", 128))
+
+	require.Equal(t, "<11>1 2019-11-05T10:30:00.123456Z host my_task - stderr - oh no", string(out))
+}
+
+func TestSyslogSink_TCP(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer ln.Close()
+
+	cfg := &LogConfig{AllocID: "alloc", TaskName: "web"}
+	w, err := newSyslogSinkWriter(cfg, "tcp://"+ln.Addr().String())
+	require.NoError(err)
+	defer w.Close()
+
+	require.NoError(w.writeLine(&logLine{stream: streamStdout, timestamp: time.Now(), data: []byte("hello")}))
+
+	conn, err := ln.Accept()
+	require.NoError(err)
+	defer conn.Close()
+
+	// Messages are framed with their length
+	r := bufio.NewReader(conn)
+	length, err := r.ReadString(' ')
+	require.NoError(err)
+
+	var n int
+	_, err = fmt.Sscanf(length, "%d ", &n)
+	require.NoError(err)
+
+	msg := make([]byte, n)
+	_, err = r.Read(msg)
+	require.NoError(err)
+	require.True(strings.HasPrefix(string(msg), "<14>1 "))
+	require.True(strings.HasSuffix(string(msg), " web alloc stdout - hello"))
+}
+
+func TestSyslogSink_UDP(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(err)
+	defer conn.Close()
+
+	w, err := newSyslogSinkWriter(&LogConfig{TaskName: "web"}, "udp://"+conn.LocalAddr().String())
+	require.NoError(err)
+	defer w.Close()
+
+	require.NoError(w.writeLine(&logLine{stream: streamStdout, timestamp: time.Now(), data: []byte("hello")}))
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(err)
+	require.True(strings.HasPrefix(string(buf[:n]), "<14>1 "))
+	require.True(strings.HasSuffix(string(buf[:n]), " web - stdout - hello"))
+}
+
+func TestSyslogSink_Reconnect(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	w, err := newSyslogSinkWriter(&LogConfig{}, "tcp://"+addr)
+	require.NoError(err)
+	defer w.Close()
+
+	// Lines are dropped while the server is unavailable
+	l := &logLine{stream: streamStdout, timestamp: time.Now(), data: []byte("hello")}
+	require.Error(w.writeLine(l))
+	require.Error(w.writeLine(l))
+
+	ln, err = net.Listen("tcp", addr)
+	require.NoError(err)
+	defer ln.Close()
+
+	testutil.WaitForResult(func() (bool, error) {
+		err := w.writeLine(l)
+		return err == nil, err
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestLogmon_Start_JSONSink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("windows does not support pushing data to a pipe with no servers")
+	}
+
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	stdoutFifoPath := filepath.Join(dir, "stdout.fifo")
+	stderrFifoPath := filepath.Join(dir, "stderr.fifo")
+
+	cfg := &LogConfig{
+		LogDir:        dir,
+		StdoutLogFile: "stdout",
+		StdoutFifo:    stdoutFifoPath,
+		StderrLogFile: "stderr",
+		StderrFifo:    stderrFifoPath,
+		MaxFiles:      2,
+		MaxFileSizeMB: 1,
+		Sinks: []*SinkConfig{
+			{Type: SinkTypeJSON, Address: "json"},
+		},
+		AllocID:   "alloc",
+		Namespace: "default",
+		JobID:     "job",
+		TaskGroup: "group",
+		TaskName:  "task",
+	}
+
+	lm := NewLogMon(testlog.HCLogger(t))
+	require.NoError(lm.Start(cfg))
+
+	stdout, err := fifo.OpenWriter(stdoutFifoPath)
+	require.NoError(err)
+	stderr, err := fifo.OpenWriter(stderrFifoPath)
+	require.NoError(err)
+
+	_, err = stdout.Write([]byte("hello\n"))
+	require.NoError(err)
+
+	// Wait for stdout to be written before writing to stderr to keep the
+	// order of the lines
+	testutil.WaitForResult(func() (bool, error) {
+		stats, err := lm.Stats()
+		if err != nil {
+			return false, err
+		}
+		return len(stats) == 1 && stats[0].WrittenLines == 1, fmt.Errorf("unexpected stats: %#v", stats)
+	}, func(err error) {
+		require.NoError(err)
+	})
+
+	_, err = stderr.Write([]byte("world\n"))
+	require.NoError(err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		stats, err := lm.Stats()
+		if err != nil {
+			return false, err
+		}
+		return stats[0].WrittenLines == 2, fmt.Errorf("unexpected stats: %#v", stats[0])
+	}, func(err error) {
+		require.NoError(err)
+	})
+
+	stdout.Close()
+	stderr.Close()
+	require.NoError(lm.Stop())
+
+	f, err := os.Open(filepath.Join(dir, "json.0"))
+	require.NoError(err)
+	defer f.Close()
+
+	var lines []*jsonLogLine
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var l jsonLogLine
+		require.NoError(dec.Decode(&l))
+		lines = append(lines, &l)
+	}
+
+	require.Len(lines, 2)
+	require.Equal(streamStdout, lines[0].Stream)
+	require.Equal("hello", lines[0].Message)
+	require.Equal(streamStderr, lines[1].Stream)
+	require.Equal("world", lines[1].Message)
+	for _, l := range lines {
+		require.Equal("alloc", l.AllocID)
+		require.Equal("default", l.Namespace)
+		require.Equal("job", l.JobID)
+		require.Equal("group", l.TaskGroup)
+		require.Equal("task", l.Task)
+	}
+}
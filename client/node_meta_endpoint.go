@@ -0,0 +1,59 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/client/structs"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodeMeta endpoint is used for reading and updating the metadata of a client
+// at runtime
+type NodeMeta struct {
+	c *Client
+}
+
+// Apply is used to update the metadata of the client without restarting it.
+func (n *NodeMeta) Apply(args *structs.NodeMetaApplyRequest, reply *structs.NodeMetaResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "apply"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	if len(args.Meta) == 0 {
+		return fmt.Errorf("missing metadata to apply")
+	}
+	for k := range args.Meta {
+		if k == "" {
+			return fmt.Errorf("metadata keys must not be empty")
+		}
+	}
+
+	if err := n.c.applyNodeMeta(args.Meta); err != nil {
+		return err
+	}
+
+	reply.Meta, reply.Static, reply.Dynamic = n.c.nodeMeta()
+	return nil
+}
+
+// Read is used to retrieve the metadata of the client.
+func (n *NodeMeta) Read(args *nstructs.NodeSpecificRequest, reply *structs.NodeMetaResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "read"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	reply.Meta, reply.Static, reply.Dynamic = n.c.nodeMeta()
+	return nil
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMeta_ApplyRead(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.StateDBFactory = func(logger hclog.Logger, _ string) (state.StateDB, error) {
+			return state.NewMemDB(logger), nil
+		}
+		c.Node.Meta = map[string]string{
+			"static":  "a",
+			"removed": "b",
+		}
+	})
+	defer cleanup()
+
+	// Apply dynamic metadata, overriding and unsetting static keys
+	req := &structs.NodeMetaApplyRequest{
+		Meta: map[string]*string{
+			"static":  helper.StringToPtr("c"),
+			"removed": nil,
+			"dynamic": helper.StringToPtr("d"),
+		},
+	}
+	var resp structs.NodeMetaResponse
+	require.NoError(client.ClientRPC("NodeMeta.Apply", req, &resp))
+	require.Equal("c", resp.Meta["static"])
+	require.Equal("d", resp.Meta["dynamic"])
+	require.NotContains(resp.Meta, "removed")
+	require.Equal("a", resp.Static["static"])
+
+	// The node and the state DB reflect the change
+	require.Equal(resp.Meta, client.Node().Meta)
+	stored, err := client.stateDB.GetNodeMeta()
+	require.NoError(err)
+	require.Equal(req.Meta, stored)
+
+	// Unsetting a dynamic key removes it
+	req = &structs.NodeMetaApplyRequest{
+		Meta: map[string]*string{"dynamic": nil},
+	}
+	require.NoError(client.ClientRPC("NodeMeta.Apply", req, &resp))
+
+	var read structs.NodeMetaResponse
+	require.NoError(client.ClientRPC("NodeMeta.Read", &nstructs.NodeSpecificRequest{}, &read))
+	require.Equal("c", read.Meta["static"])
+	require.NotContains(read.Meta, "dynamic")
+	require.NotContains(read.Dynamic, "dynamic")
+
+	// Empty requests are rejected
+	err = client.ClientRPC("NodeMeta.Apply", &structs.NodeMetaApplyRequest{}, &resp)
+	require.Error(err)
+}
+
+func TestNodeMeta_Apply_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	server, addr, root, cleanupS := testACLServer(t, nil)
+	defer cleanupS()
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer cleanupC()
+
+	req := &structs.NodeMetaApplyRequest{
+		Meta: map[string]*string{"foo": helper.StringToPtr("bar")},
+	}
+
+	// Try request without a token and expect failure
+	{
+		var resp structs.NodeMetaResponse
+		err := client.ClientRPC("NodeMeta.Apply", req, &resp)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a read only token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "read", mock.NodePolicy(acl.PolicyRead))
+		req.AuthToken = token.SecretID
+
+		var resp structs.NodeMetaResponse
+		err := client.ClientRPC("NodeMeta.Apply", req, &resp)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+
+		// Reading is allowed
+		read := &nstructs.NodeSpecificRequest{}
+		read.AuthToken = token.SecretID
+		require.NoError(client.ClientRPC("NodeMeta.Read", read, &resp))
+	}
+
+	// Try request with a management token
+	{
+		req.AuthToken = root.SecretID
+
+		var resp structs.NodeMetaResponse
+		require.NoError(client.ClientRPC("NodeMeta.Apply", req, &resp))
+		require.Equal("bar", resp.Meta["foo"])
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/nomad/client/devicemanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -256,3 +257,73 @@ func (b *batchNodeUpdates) batchDevicesUpdates(f devicemanager.UpdateNodeDevices
 	f(b.devices)
 	return nil
 }
+
+// mergeNodeMeta returns the effective node metadata by applying the dynamic
+// metadata on top of the static metadata. A nil dynamic value unsets the key.
+func mergeNodeMeta(static map[string]string, dynamic map[string]*string) map[string]string {
+	meta := make(map[string]string, len(static)+len(dynamic))
+	for k, v := range static {
+		meta[k] = v
+	}
+	for k, v := range dynamic {
+		if v == nil {
+			delete(meta, k)
+		} else {
+			meta[k] = *v
+		}
+	}
+	return meta
+}
+
+// nodeMeta returns the effective, static and dynamic metadata of the node.
+func (c *Client) nodeMeta() (map[string]string, map[string]string, map[string]*string) {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+
+	dynamic := make(map[string]*string, len(c.metaDynamic))
+	for k, v := range c.metaDynamic {
+		dynamic[k] = v
+	}
+	return helper.CopyMapStringString(c.config.Node.Meta), helper.CopyMapStringString(c.metaStatic), dynamic
+}
+
+// applyNodeMeta applies the given metadata to the node at runtime, persisting
+// it in the state DB so it survives restarts, and re-registers the node so
+// the servers schedule against the new metadata immediately.
+func (c *Client) applyNodeMeta(meta map[string]*string) error {
+	c.configLock.Lock()
+
+	dynamic := make(map[string]*string, len(c.metaDynamic)+len(meta))
+	for k, v := range c.metaDynamic {
+		dynamic[k] = v
+	}
+	for k, v := range meta {
+		// Unsetting a key that is not in the client configuration only
+		// needs to drop the dynamic value.
+		if _, ok := c.metaStatic[k]; v == nil && !ok {
+			delete(dynamic, k)
+			continue
+		}
+		dynamic[k] = v
+	}
+
+	if err := c.stateDB.PutNodeMeta(dynamic); err != nil {
+		c.configLock.Unlock()
+		return fmt.Errorf("failed to persist node meta: %v", err)
+	}
+
+	c.metaDynamic = dynamic
+	c.config.Node.Meta = mergeNodeMeta(c.metaStatic, dynamic)
+	c.configCopy.Node = c.config.Node.Copy()
+	c.configLock.Unlock()
+
+	// Register right away rather than waiting for the batched node update
+	// and fall back to it if the registration fails.
+	if err := c.registerNode(); err != nil {
+		c.logger.Warn("failed to register node after meta update, retrying", "error", err)
+		c.configLock.Lock()
+		c.updateNodeLocked()
+		c.configLock.Unlock()
+	}
+	return nil
+}
@@ -244,6 +244,34 @@ func TestStateDB_DriverManager(t *testing.T) {
 	})
 }
 
+// TestStateDB_NodeMeta asserts the behavior of node meta related StateDB
+// methods.
+func TestStateDB_NodeMeta(t *testing.T) {
+	t.Parallel()
+
+	testDB(t, func(t *testing.T, db StateDB) {
+		require := require.New(t)
+
+		// Getting nonexistent meta should return nil
+		meta, err := db.GetNodeMeta()
+		require.NoError(err)
+		require.Nil(meta)
+
+		// Putting meta should work
+		rack := "r1"
+		expected := map[string]*string{
+			"rack":  &rack,
+			"unset": nil,
+		}
+		require.NoError(db.PutNodeMeta(expected))
+
+		// Getting should return the available meta
+		meta, err = db.GetNodeMeta()
+		require.NoError(err)
+		require.Equal(expected, meta)
+	})
+}
+
 // TestStateDB_Upgrade asserts calling Upgrade on new databases always
 // succeeds.
 func TestStateDB_Upgrade(t *testing.T) {
@@ -77,6 +77,14 @@ func (m *ErrDB) PutDriverPluginState(ps *driverstate.PluginState) error {
 	return fmt.Errorf("Error!")
 }
 
+func (m *ErrDB) GetNodeMeta() (map[string]*string, error) {
+	return nil, fmt.Errorf("Error!")
+}
+
+func (m *ErrDB) PutNodeMeta(meta map[string]*string) error {
+	return fmt.Errorf("Error!")
+}
+
 func (m *ErrDB) Close() error {
 	return fmt.Errorf("Error!")
 }
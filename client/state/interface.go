@@ -69,6 +69,13 @@ type StateDB interface {
 	// state.
 	PutDriverPluginState(state *driverstate.PluginState) error
 
+	// GetNodeMeta is used to retrieve the node metadata applied at runtime.
+	// A nil value for a key unsets it.
+	GetNodeMeta() (map[string]*string, error)
+
+	// PutNodeMeta is used to store the node metadata applied at runtime.
+	PutNodeMeta(map[string]*string) error
+
 	// Close the database. Unsafe for further use after calling regardless
 	// of return value.
 	Close() error
@@ -29,6 +29,9 @@ type MemDB struct {
 	// drivermanager -> plugin-state
 	driverManagerPs *driverstate.PluginState
 
+	// nodemeta -> meta
+	nodeMeta map[string]*string
+
 	logger hclog.Logger
 
 	mu sync.RWMutex
@@ -193,6 +196,19 @@ func (m *MemDB) PutDriverPluginState(ps *driverstate.PluginState) error {
 	return nil
 }
 
+func (m *MemDB) GetNodeMeta() (map[string]*string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nodeMeta, nil
+}
+
+func (m *MemDB) PutNodeMeta(meta map[string]*string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nodeMeta = meta
+	return nil
+}
+
 func (m *MemDB) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
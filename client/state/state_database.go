@@ -35,6 +35,9 @@ devicemanager/
 
 drivermanager/
 |--> plugin_state -> *dmstate.PluginState
+
+nodemeta/
+|--> meta -> nodeMetaEntry{map[string]*string}
 */
 
 var (
@@ -80,6 +83,14 @@ var (
 	// managerPluginStateKey is the key by which plugin manager plugin state is
 	// stored at
 	managerPluginStateKey = []byte("plugin_state")
+
+	// nodeMetaBucket is the bucket name containing the node metadata applied
+	// at runtime
+	nodeMetaBucket = []byte("nodemeta")
+
+	// nodeMetaKey is the key the node metadata is stored under encapsulated
+	// in nodeMetaEntry structs.
+	nodeMetaKey = []byte("meta")
 )
 
 // taskBucketName returns the bucket name for the given task name.
@@ -594,6 +605,54 @@ func (s *BoltStateDB) GetDriverPluginState() (*driverstate.PluginState, error) {
 	return ps, nil
 }
 
+// nodeMetaEntry wraps the node metadata so it can be stored and retrieved
+// with a single key.
+type nodeMetaEntry struct {
+	Meta map[string]*string
+}
+
+// PutNodeMeta stores the node metadata applied at runtime or returns an
+// error.
+func (s *BoltStateDB) PutNodeMeta(meta map[string]*string) error {
+	return s.db.Update(func(tx *boltdd.Tx) error {
+		// Retrieve the root node meta bucket
+		metaBkt, err := tx.CreateBucketIfNotExists(nodeMetaBucket)
+		if err != nil {
+			return err
+		}
+
+		return metaBkt.Put(nodeMetaKey, &nodeMetaEntry{Meta: meta})
+	})
+}
+
+// GetNodeMeta retrieves the node metadata applied at runtime or returns an
+// error.
+func (s *BoltStateDB) GetNodeMeta() (map[string]*string, error) {
+	var entry nodeMetaEntry
+
+	err := s.db.View(func(tx *boltdd.Tx) error {
+		metaBkt := tx.Bucket(nodeMetaBucket)
+		if metaBkt == nil {
+			// No state, return
+			return nil
+		}
+
+		if err := metaBkt.Get(nodeMetaKey, &entry); err != nil {
+			if !boltdd.IsErrNotFound(err) {
+				return fmt.Errorf("failed to read node meta: %v", err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.Meta, nil
+}
+
 // init initializes metadata entries in a newly created state database.
 func (s *BoltStateDB) init() error {
 	return s.db.Update(func(tx *boltdd.Tx) error {
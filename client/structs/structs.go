@@ -4,6 +4,7 @@ package structs
 
 import (
 	"errors"
+	"os"
 	"time"
 
 	"github.com/hashicorp/nomad/client/stats"
@@ -34,6 +35,87 @@ type ClientStatsResponse struct {
 	structs.QueryMeta
 }
 
+// NodeMetaApplyRequest is used to update the metadata of a node at runtime.
+type NodeMetaApplyRequest struct {
+	// NodeID is the node whose metadata is being updated.
+	NodeID string
+
+	// Meta is the metadata to apply. A nil value unsets the key, removing it
+	// from the node even if it is set in the client configuration.
+	Meta map[string]*string
+
+	structs.QueryOptions
+}
+
+// NodeMetaResponse is used to return the metadata of a node.
+type NodeMetaResponse struct {
+	// Meta is the effective metadata of the node.
+	Meta map[string]string
+
+	// Dynamic is the metadata applied at runtime, which takes precedence
+	// over Static.
+	Dynamic map[string]*string
+
+	// Static is the metadata from the client configuration.
+	Static map[string]string
+
+	structs.QueryMeta
+}
+
+// GCStatusResponse is used to return the allocations a client has marked for
+// garbage collection.
+type GCStatusResponse struct {
+	// Allocs are the terminal allocations in the order they will be
+	// garbage collected.
+	Allocs []*GCAllocStatus
+
+	// Reason is why the client would garbage collect allocations now, or
+	// empty if it is below all of its thresholds.
+	Reason string
+
+	// NumAllocs is the number of allocations that have not been garbage
+	// collected and MaxAllocs the limit beyond which they are.
+	NumAllocs int
+	MaxAllocs int
+
+	// DiskUsedPercent and InodesUsedPercent are the usage of the alloc dir's
+	// disk and the thresholds beyond which allocations are garbage collected.
+	DiskUsedPercent     float64
+	DiskUsageThreshold  float64
+	InodesUsedPercent   float64
+	InodeUsageThreshold float64
+
+	structs.QueryMeta
+}
+
+// FilterAllocs removes the allocations whose namespace isn't allowed.
+func (r *GCStatusResponse) FilterAllocs(allowed func(namespace string) bool) {
+	allocs := make([]*GCAllocStatus, 0, len(r.Allocs))
+	for _, alloc := range r.Allocs {
+		if allowed(alloc.Namespace) {
+			allocs = append(allocs, alloc)
+		}
+	}
+	r.Allocs = allocs
+}
+
+// GCAllocStatus describes an allocation marked for garbage collection.
+type GCAllocStatus struct {
+	AllocID   string
+	Namespace string
+	JobID     string
+	TaskGroup string
+
+	// MarkedAt is when the allocation was marked for garbage collection.
+	MarkedAt time.Time
+
+	// RetainUntil is the time until which the allocation is only garbage
+	// collected to free disk space, and RetainReason is why. It is the zero
+	// time if the allocation isn't retained.
+	RetainUntil  time.Time
+	RetainReason string
+}
+
 // MonitorRequest is used to request and stream logs from a client node.
 type MonitorRequest struct {
 	// LogLevel is the log level filter we want to stream logs on
@@ -129,6 +211,50 @@ type FsStreamRequest struct {
 	structs.QueryOptions
 }
 
+// FsUploadRequest is the initial request for uploading a file into an
+// allocation's directory. The content of the file follows in FsUploadFrames.
+type FsUploadRequest struct {
+	// AllocID is the allocation to upload the file to
+	AllocID string
+
+	// Path is the path of the file relative to the allocation directory
+	Path string
+
+	// FileMode is the permissions of the uploaded file
+	FileMode os.FileMode
+
+	// Overwrite replaces an existing file at the path
+	Overwrite bool
+
+	structs.QueryOptions
+}
+
+// FsUploadFrame carries the content of an uploaded file after the initial
+// FsUploadRequest
+type FsUploadFrame struct {
+	// Data is the next chunk of the file
+	Data []byte
+
+	// EOF indicates the file is complete
+	EOF bool
+}
+
+// FsArchiveRequest is the initial request for streaming a tar archive of a
+// path in an allocation's directory.
+type FsArchiveRequest struct {
+	// AllocID is the allocation to archive files from
+	AllocID string
+
+	// Path is the path of the file or directory to archive relative to the
+	// allocation directory
+	Path string
+
+	// Compress gzip compresses the archive
+	Compress bool
+
+	structs.QueryOptions
+}
+
 // FsLogsRequest is the initial request for accessing allocation logs.
 type FsLogsRequest struct {
 	// AllocID is the allocation to stream logs from
@@ -153,6 +279,17 @@ type FsLogsRequest struct {
 	// Follow follows logs.
 	Follow bool
 
+	// Filter limits the streamed logs to the lines containing it.
+	Filter string
+
+	// FilterRegex interprets Filter as a regular expression.
+	FilterRegex bool
+
+	// Since and Until limit the streamed logs to the lines written within the
+	// time range. A zero time leaves that end of the range open.
+	Since time.Time
+	Until time.Time
+
 	structs.QueryOptions
 }
 
@@ -182,6 +319,31 @@ type AllocExecRequest struct {
 	structs.QueryOptions
 }
 
+// AllocPortForwardRequest is the initial request for forwarding a connection
+// to a port of an allocation
+type AllocPortForwardRequest struct {
+	// AllocID is the allocation to forward the connection to
+	AllocID string
+
+	// Task is an optional task whose address the connection is forwarded to
+	Task string
+
+	// Port is the port the connection is forwarded to
+	Port int
+
+	structs.QueryOptions
+}
+
+// PortForwardFrame carries data from the forwarded connection to the
+// allocation after the initial AllocPortForwardRequest
+type PortForwardFrame struct {
+	// Data is the data read from the forwarded connection
+	Data []byte
+
+	// Close indicates the forwarded connection has no more data to send
+	Close bool
+}
+
 // AllocStatsRequest is used to request the resource usage of a given
 // allocation, potentially filtering by task
 type AllocStatsRequest struct {
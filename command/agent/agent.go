@@ -209,6 +209,7 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.Server.UpgradeVersion != "" {
 		conf.UpgradeVersion = agentConfig.Server.UpgradeVersion
 	}
+	conf.HealthGateHosts = agentConfig.Server.HealthGateHosts
 	if agentConfig.Autopilot != nil {
 		if agentConfig.Autopilot.CleanupDeadServers != nil {
 			conf.AutopilotConfig.CleanupDeadServers = *agentConfig.Autopilot.CleanupDeadServers
@@ -474,6 +475,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.ClientMaxPort = uint(agentConfig.Client.ClientMaxPort)
 	conf.ClientMinPort = uint(agentConfig.Client.ClientMinPort)
 	conf.DisableRemoteExec = agentConfig.Client.DisableRemoteExec
+	conf.LogSinkAddresses = agentConfig.Client.LogSinkAddresses
 	conf.TemplateConfig.FunctionBlacklist = agentConfig.Client.TemplateConfig.FunctionBlacklist
 	conf.TemplateConfig.DisableSandbox = agentConfig.Client.TemplateConfig.DisableSandbox
 
@@ -540,6 +542,14 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.GCDiskUsageThreshold = agentConfig.Client.GCDiskUsageThreshold
 	conf.GCInodeUsageThreshold = agentConfig.Client.GCInodeUsageThreshold
 	conf.GCMaxAllocs = agentConfig.Client.GCMaxAllocs
+	conf.GCFailedAllocRetention = agentConfig.Client.GCFailedAllocRetention
+	conf.GCMaxJobRetention = agentConfig.Client.GCMaxJobRetention
+	conf.ArtifactCacheDir = agentConfig.Client.ArtifactCacheDir
+	conf.ArtifactCacheMaxMB = agentConfig.Client.ArtifactCacheMaxMB
+	conf.ArtifactMaxSizeMB = agentConfig.Client.ArtifactMaxSizeMB
+	conf.ArtifactMaxFiles = agentConfig.Client.ArtifactMaxFiles
+	conf.ArtifactMaxDecompressionRatio = agentConfig.Client.ArtifactMaxDecompressionRatio
+	conf.ArtifactTimeout = agentConfig.Client.ArtifactTimeout
 	if agentConfig.Client.NoHostUUID != nil {
 		conf.NoHostUUID = *agentConfig.Client.NoHostUUID
 	} else {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/golang/snappy"
 	"github.com/gorilla/websocket"
+	"github.com/hashicorp/nomad/api"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -147,6 +148,8 @@ func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Requ
 		return s.allocStats(allocID, resp, req)
 	case "exec":
 		return s.allocExec(allocID, resp, req)
+	case "port-forward":
+		return s.allocPortForward(allocID, resp, req)
 	case "snapshot":
 		if s.agent.client == nil {
 			return nil, clientNotRunning
@@ -198,6 +201,74 @@ func (s *HTTPServer) ClientGCRequest(resp http.ResponseWriter, req *http.Request
 	return nil, rpcErr
 }
 
+func (s *HTTPServer) ClientGCStatusRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Get the requested Node ID
+	requestedNode := req.URL.Query().Get("node_id")
+
+	// Build the request and parse the ACL token
+	args := structs.NodeSpecificRequest{
+		NodeID: requestedNode,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForNode(requestedNode)
+
+	// Make the RPC
+	var reply cstructs.GCStatusResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("Allocations.GCStatus", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientAllocations.GCStatus", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientAllocations.GCStatus", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		} else if strings.Contains(rpcErr.Error(), "Unknown node") {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+
+		return nil, rpcErr
+	}
+
+	return gcStatusToApi(&reply), nil
+}
+
+func gcStatusToApi(in *cstructs.GCStatusResponse) *api.GCStatus {
+	out := &api.GCStatus{
+		Allocs:              make([]*api.GCAllocStatus, len(in.Allocs)),
+		Reason:              in.Reason,
+		NumAllocs:           in.NumAllocs,
+		MaxAllocs:           in.MaxAllocs,
+		DiskUsedPercent:     in.DiskUsedPercent,
+		DiskUsageThreshold:  in.DiskUsageThreshold,
+		InodesUsedPercent:   in.InodesUsedPercent,
+		InodeUsageThreshold: in.InodeUsageThreshold,
+	}
+	for i, a := range in.Allocs {
+		out.Allocs[i] = &api.GCAllocStatus{
+			AllocID:      a.AllocID,
+			Namespace:    a.Namespace,
+			JobID:        a.JobID,
+			TaskGroup:    a.TaskGroup,
+			MarkedAt:     a.MarkedAt,
+			RetainUntil:  a.RetainUntil,
+			RetainReason: a.RetainReason,
+		}
+	}
+	return out
+}
+
 func (s *HTTPServer) allocRestart(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// Build the request and parse the ACL token
 	args := structs.AllocRestartRequest{
@@ -401,9 +472,42 @@ func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *ht
 	return s.execStreamImpl(conn, &args)
 }
 
+func (s *HTTPServer) allocPortForward(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request and parse the ACL token
+	port, err := strconv.Atoi(req.URL.Query().Get("port"))
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("port value is not an integer: %v", err))
+	}
+
+	args := cstructs.AllocPortForwardRequest{
+		AllocID: allocID,
+		Task:    req.URL.Query().Get("task"),
+		Port:    port,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	conn, err := s.wsUpgrader.Upgrade(resp, req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade connection: %v", err)
+	}
+
+	return s.allocWebsocketStreamImpl(conn, allocID, "Allocations.PortForward", &args,
+		forwardPortForwardInput, true, websocket.BinaryMessage)
+}
+
 func (s *HTTPServer) execStreamImpl(ws *websocket.Conn, args *cstructs.AllocExecRequest) (interface{}, error) {
-	allocID := args.AllocID
-	method := "Allocations.Exec"
+	return s.allocWebsocketStreamImpl(ws, args.AllocID, "Allocations.Exec", args,
+		forwardExecInput, false, websocket.TextMessage)
+}
+
+// allocWebsocketStreamImpl bridges the websocket with the streaming RPC of
+// the alloc. The request is sent to the RPC and forwardInput forwards the
+// messages of the websocket to it. If endOnInputClose is set, the RPC is
+// closed once forwardInput returns. The payloads the RPC responds with are
+// written to the websocket as messages of the given type.
+func (s *HTTPServer) allocWebsocketStreamImpl(ws *websocket.Conn, allocID, method string, args interface{},
+	forwardInput func(*codec.Encoder, *websocket.Conn, chan<- HTTPCodedError), endOnInputClose bool,
+	messageType int) (interface{}, error) {
 
 	// Get the correct handler
 	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
@@ -448,7 +552,12 @@ func (s *HTTPServer) execStreamImpl(ws *websocket.Conn, args *cstructs.AllocExec
 			return
 		}
 
-		go forwardExecInput(encoder, ws, errCh)
+		go func() {
+			forwardInput(encoder, ws, errCh)
+			if endOnInputClose {
+				cancel()
+			}
+		}()
 
 		for {
 			select {
@@ -481,7 +590,7 @@ func (s *HTTPServer) execStreamImpl(ws *websocket.Conn, args *cstructs.AllocExec
 				return
 			}
 
-			if err := ws.WriteMessage(websocket.TextMessage, res.Payload); err != nil {
+			if err := ws.WriteMessage(messageType, res.Payload); err != nil {
 				errCh <- CodedError(500, err.Error())
 				return
 			}
@@ -549,3 +658,29 @@ func forwardExecInput(encoder *codec.Encoder, ws *websocket.Conn, errCh chan<- H
 		}
 	}
 }
+
+// forwardPortForwardInput forwards the data of the forwarded connection from
+// the websocket connection to the streaming RPC connection to client. An empty
+// message signals that the forwarded connection has no more data to send.
+func forwardPortForwardInput(encoder *codec.Encoder, ws *websocket.Conn, errCh chan<- HTTPCodedError) {
+	for {
+		_, data, err := ws.ReadMessage()
+		if err == io.EOF || websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			return
+		}
+
+		if err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		frame := &cstructs.PortForwardFrame{
+			Data:  data,
+			Close: len(data) == 0,
+		}
+		if err := encoder.Encode(frame); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/golang/snappy"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -911,6 +912,42 @@ func TestHTTP_AllocAllGC(t *testing.T) {
 
 }
 
+func TestHTTP_ClientGCStatus(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Local node, local resp
+		{
+			req, err := http.NewRequest("GET", "/v1/client/gc/status", nil)
+			require.Nil(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.ClientGCStatusRequest(respW, req)
+			require.Nil(err)
+
+			status := obj.(*api.GCStatus)
+			require.Equal(s.client.GetConfig().GCMaxAllocs, status.MaxAllocs)
+			require.Empty(status.Allocs)
+		}
+
+		// Local node, server resp
+		{
+			srv := s.server
+			s.server = nil
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("/v1/client/gc/status?node_id=%s", uuid.Generate()), nil)
+			require.Nil(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.ClientGCStatusRequest(respW, req)
+			require.NotNil(err)
+			require.Contains(err.Error(), "Unknown node")
+
+			s.server = srv
+		}
+	})
+}
+
 func TestHTTP_AllocAllGC_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
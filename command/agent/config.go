@@ -250,6 +250,41 @@ type ClientConfig struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int `hcl:"gc_max_allocs"`
 
+	// GCFailedAllocRetention is the minimum duration failed allocations are
+	// kept before they are garbage collected to stay below GCMaxAllocs.
+	GCFailedAllocRetention    time.Duration
+	GCFailedAllocRetentionHCL string `hcl:"gc_failed_alloc_retention" json:"-"`
+
+	// GCMaxJobRetention caps the minimum duration jobs may ask terminal
+	// allocations to be kept with their gc min_retention.
+	GCMaxJobRetention    time.Duration
+	GCMaxJobRetentionHCL string `hcl:"gc_max_job_retention" json:"-"`
+
+	// ArtifactCacheDir is the directory downloaded artifacts are cached in.
+	ArtifactCacheDir string `hcl:"artifact_cache_dir"`
+
+	// ArtifactCacheMaxMB is the maximum size of the artifact cache in MB.
+	// Zero disables caching artifacts.
+	ArtifactCacheMaxMB int `hcl:"artifact_cache_max_mb"`
+
+	// ArtifactMaxSizeMB is the maximum size in MB of an artifact download and
+	// of the contents of an artifact archive. Zero is unlimited.
+	ArtifactMaxSizeMB int `hcl:"artifact_max_size_mb"`
+
+	// ArtifactMaxFiles is the maximum number of files in an artifact archive.
+	// Zero is unlimited.
+	ArtifactMaxFiles int `hcl:"artifact_max_files"`
+
+	// ArtifactMaxDecompressionRatio is the maximum ratio between the size of
+	// the contents of an artifact archive and the size of the archive. Zero
+	// is unlimited.
+	ArtifactMaxDecompressionRatio int `hcl:"artifact_max_decompression_ratio"`
+
+	// ArtifactTimeout is the maximum time to fetch an artifact. Zero is
+	// unlimited.
+	ArtifactTimeout    time.Duration
+	ArtifactTimeoutHCL string `hcl:"artifact_timeout" json:"-"`
+
 	// NoHostUUID disables using the host's UUID and will force generation of a
 	// random UUID.
 	NoHostUUID *bool `hcl:"no_host_uuid"`
@@ -257,6 +292,10 @@ type ClientConfig struct {
 	// DisableRemoteExec disables remote exec targeting tasks on this client
 	DisableRemoteExec bool `hcl:"disable_remote_exec"`
 
+	// LogSinkAddresses are the addresses of the syslog servers the log sinks
+	// of tasks may ship logs to
+	LogSinkAddresses []string `hcl:"log_sink_addresses"`
+
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *ClientTemplateConfig `hcl:"template"`
 
@@ -438,6 +477,10 @@ type ServerConfig struct {
 	// performing upgrade migrations.
 	UpgradeVersion string `hcl:"upgrade_version"`
 
+	// HealthGateHosts are the hosts the health gates of deployments may send
+	// requests to
+	HealthGateHosts []string `hcl:"health_gate_hosts"`
+
 	// Encryption key to use for the Serf communication
 	EncryptKey string `hcl:"encrypt" json:"-"`
 
@@ -811,18 +854,20 @@ func DefaultConfig() *Config {
 		Consul:         config.DefaultConsulConfig(),
 		Vault:          config.DefaultVaultConfig(),
 		Client: &ClientConfig{
-			Enabled:               false,
-			MaxKillTimeout:        "30s",
-			ClientMinPort:         14000,
-			ClientMaxPort:         14512,
-			Reserved:              &Resources{},
-			GCInterval:            1 * time.Minute,
-			GCParallelDestroys:    2,
-			GCDiskUsageThreshold:  80,
-			GCInodeUsageThreshold: 70,
-			GCMaxAllocs:           50,
-			NoHostUUID:            helper.BoolToPtr(true),
-			DisableRemoteExec:     false,
+			Enabled:                false,
+			MaxKillTimeout:         "30s",
+			ClientMinPort:          14000,
+			ClientMaxPort:          14512,
+			Reserved:               &Resources{},
+			GCInterval:             1 * time.Minute,
+			GCParallelDestroys:     2,
+			GCDiskUsageThreshold:   80,
+			GCInodeUsageThreshold:  70,
+			GCMaxAllocs:            50,
+			GCFailedAllocRetention: 1 * time.Hour,
+			GCMaxJobRetention:      24 * time.Hour,
+			NoHostUUID:             helper.BoolToPtr(true),
+			DisableRemoteExec:      false,
 			ServerJoin: &ServerJoin{
 				RetryJoin:        []string{},
 				RetryInterval:    30 * time.Second,
@@ -1340,6 +1385,9 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
+	// Add the health gate hosts
+	result.HealthGateHosts = append(result.HealthGateHosts, b.HealthGateHosts...)
+
 	// Copy the start join addresses
 	result.StartJoin = make([]string, 0, len(a.StartJoin)+len(b.StartJoin))
 	result.StartJoin = append(result.StartJoin, a.StartJoin...)
@@ -1414,6 +1462,42 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.GCMaxAllocs != 0 {
 		result.GCMaxAllocs = b.GCMaxAllocs
 	}
+	// Zero disables the retention, so merge it when it was configured
+	if b.GCFailedAllocRetention != 0 || b.GCFailedAllocRetentionHCL != "" {
+		result.GCFailedAllocRetention = b.GCFailedAllocRetention
+	}
+	if b.GCFailedAllocRetentionHCL != "" {
+		result.GCFailedAllocRetentionHCL = b.GCFailedAllocRetentionHCL
+	}
+	// Zero prevents jobs from retaining allocations, so merge it when it was
+	// configured
+	if b.GCMaxJobRetention != 0 || b.GCMaxJobRetentionHCL != "" {
+		result.GCMaxJobRetention = b.GCMaxJobRetention
+	}
+	if b.GCMaxJobRetentionHCL != "" {
+		result.GCMaxJobRetentionHCL = b.GCMaxJobRetentionHCL
+	}
+	if b.ArtifactCacheDir != "" {
+		result.ArtifactCacheDir = b.ArtifactCacheDir
+	}
+	if b.ArtifactCacheMaxMB != 0 {
+		result.ArtifactCacheMaxMB = b.ArtifactCacheMaxMB
+	}
+	if b.ArtifactMaxSizeMB != 0 {
+		result.ArtifactMaxSizeMB = b.ArtifactMaxSizeMB
+	}
+	if b.ArtifactMaxFiles != 0 {
+		result.ArtifactMaxFiles = b.ArtifactMaxFiles
+	}
+	if b.ArtifactMaxDecompressionRatio != 0 {
+		result.ArtifactMaxDecompressionRatio = b.ArtifactMaxDecompressionRatio
+	}
+	if b.ArtifactTimeout != 0 {
+		result.ArtifactTimeout = b.ArtifactTimeout
+	}
+	if b.ArtifactTimeoutHCL != "" {
+		result.ArtifactTimeoutHCL = b.ArtifactTimeoutHCL
+	}
 	// NoHostUUID defaults to true, merge if false
 	if b.NoHostUUID != nil {
 		result.NoHostUUID = b.NoHostUUID
@@ -1430,6 +1514,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	// Add the servers
 	result.Servers = append(result.Servers, b.Servers...)
 
+	// Add the log sink addresses
+	result.LogSinkAddresses = append(result.LogSinkAddresses, b.LogSinkAddresses...)
+
 	// Add the options map values
 	if result.Options == nil {
 		result.Options = make(map[string]string)
@@ -50,6 +50,9 @@ func ParseConfigFile(path string) (*Config, error) {
 	// convert strings to time.Durations
 	err = durations([]td{
 		{"gc_interval", &c.Client.GCInterval, &c.Client.GCIntervalHCL},
+		{"gc_failed_alloc_retention", &c.Client.GCFailedAllocRetention, &c.Client.GCFailedAllocRetentionHCL},
+		{"gc_max_job_retention", &c.Client.GCMaxJobRetention, &c.Client.GCMaxJobRetentionHCL},
+		{"artifact_timeout", &c.Client.ArtifactTimeout, &c.Client.ArtifactTimeoutHCL},
 		{"acl.token_ttl", &c.ACL.TokenTTL, &c.ACL.TokenTTLHCL},
 		{"acl.policy_ttl", &c.ACL.PolicyTTL, &c.ACL.PolicyTTLHCL},
 		{"client.server_join.retry_interval", &c.Client.ServerJoin.RetryInterval, &c.Client.ServerJoin.RetryIntervalHCL},
@@ -75,14 +75,26 @@ var basicConfig = &Config{
 			DiskMB:        10,
 			ReservedPorts: "1,100,10-12",
 		},
-		GCInterval:            6 * time.Second,
-		GCIntervalHCL:         "6s",
-		GCParallelDestroys:    6,
-		GCDiskUsageThreshold:  82,
-		GCInodeUsageThreshold: 91,
-		GCMaxAllocs:           50,
-		NoHostUUID:            helper.BoolToPtr(false),
-		DisableRemoteExec:     true,
+		GCInterval:                    6 * time.Second,
+		GCIntervalHCL:                 "6s",
+		GCParallelDestroys:            6,
+		GCDiskUsageThreshold:          82,
+		GCInodeUsageThreshold:         91,
+		GCMaxAllocs:                   50,
+		GCFailedAllocRetention:        2 * time.Hour,
+		GCFailedAllocRetentionHCL:     "2h",
+		GCMaxJobRetention:             12 * time.Hour,
+		GCMaxJobRetentionHCL:          "12h",
+		ArtifactCacheDir:              "/tmp/artifacts",
+		ArtifactCacheMaxMB:            2048,
+		ArtifactMaxSizeMB:             512,
+		ArtifactMaxFiles:              1000,
+		ArtifactMaxDecompressionRatio: 50,
+		ArtifactTimeout:               10 * time.Minute,
+		ArtifactTimeoutHCL:            "10m",
+		NoHostUUID:                    helper.BoolToPtr(false),
+		DisableRemoteExec:             true,
+		LogSinkAddresses:              []string{"udp://127.0.0.1:514"},
 		HostVolumes: []*structs.ClientHostVolumeConfig{
 			{Name: "tmp", Path: "/tmp"},
 		},
@@ -115,6 +127,7 @@ var basicConfig = &Config{
 		NonVotingServer:        true,
 		RedundancyZone:         "foo",
 		UpgradeVersion:         "0.8.0",
+		HealthGateHosts:        []string{"gate.example.com"},
 		EncryptKey:             "abc",
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
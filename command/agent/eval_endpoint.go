@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -17,6 +18,12 @@ func (s *HTTPServer) EvalsRequest(resp http.ResponseWriter, req *http.Request) (
 		return nil, nil
 	}
 
+	query := req.URL.Query()
+	args.FilterJobID = query.Get("job")
+	args.FilterEvalStatus = query.Get("status")
+	args.FilterTriggeredBy = query.Get("triggered_by")
+	args.FilterNodeID = query.Get("node")
+
 	var out structs.EvalListResponse
 	if err := s.agent.RPC("Eval.List", &args, &out); err != nil {
 		return nil, err
@@ -29,6 +36,32 @@ func (s *HTTPServer) EvalsRequest(resp http.ResponseWriter, req *http.Request) (
 	return out.Evaluations, nil
 }
 
+func (s *HTTPServer) EvalsDeleteRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var deleteReq api.EvalDeleteRequest
+	if err := decodeBody(req, &deleteReq); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if len(deleteReq.EvalIDs) == 0 {
+		return nil, CodedError(400, "must specify at least one evaluation ID")
+	}
+
+	args := structs.EvalDeleteRequest{
+		Evals: deleteReq.EvalIDs,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Eval.Delete", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
 func (s *HTTPServer) EvalSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/v1/evaluation/")
 	switch {
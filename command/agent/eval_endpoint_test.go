@@ -5,8 +5,10 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_EvalList(t *testing.T) {
@@ -54,6 +56,102 @@ func TestHTTP_EvalList(t *testing.T) {
 	})
 }
 
+func TestHTTP_EvalList_FilterPaginate(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		eval1 := mock.Eval()
+		eval1.ID = "aaaaaaaa-e8f7-fd38-c855-ab94ceb89706"
+		eval1.JobID = "example"
+		eval2 := mock.Eval()
+		eval2.ID = "aaaabbbb-e8f7-fd38-c855-ab94ceb89706"
+		eval2.JobID = "example"
+		eval3 := mock.Eval()
+		eval3.ID = "aaaacccc-e8f7-fd38-c855-ab94ceb89706"
+		require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{eval1, eval2, eval3}))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/evaluations?job=example&per_page=1", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.EvalsRequest(respW, req)
+		require.NoError(err)
+		e := obj.([]*structs.Evaluation)
+		require.Len(e, 1)
+		require.Equal(eval1.ID, e[0].ID)
+		require.Equal(eval2.ID, respW.HeaderMap.Get("X-Nomad-NextToken"))
+
+		// Request the next page
+		req, err = http.NewRequest("GET", "/v1/evaluations?job=example&per_page=1&next_token="+eval2.ID, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.EvalsRequest(respW, req)
+		require.NoError(err)
+		e = obj.([]*structs.Evaluation)
+		require.Len(e, 1)
+		require.Equal(eval2.ID, e[0].ID)
+		require.Empty(respW.HeaderMap.Get("X-Nomad-NextToken"))
+
+		// Invalid page sizes are rejected
+		req, err = http.NewRequest("GET", "/v1/evaluations?per_page=abc", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.EvalsRequest(respW, req)
+		require.NoError(err)
+		require.Equal(400, respW.Code)
+		require.Contains(respW.Body.String(), "Invalid per_page")
+	})
+}
+
+func TestHTTP_EvalsDelete(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		eval1 := mock.Eval()
+		require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{eval1}))
+
+		// Missing evaluation IDs are rejected
+		buf := encodeReq(&api.EvalDeleteRequest{})
+		req, err := http.NewRequest("PUT", "/v1/evaluations/delete", buf)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.EvalsDeleteRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "at least one evaluation ID")
+
+		// Pause the eval broker
+		config := &structs.SchedulerSetConfigRequest{
+			Config:       structs.SchedulerConfiguration{PauseEvalBroker: true},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var configResp structs.SchedulerSetConfigurationResponse
+		require.NoError(s.Agent.RPC("Operator.SchedulerSetConfiguration", config, &configResp))
+
+		buf = encodeReq(&api.EvalDeleteRequest{EvalIDs: []string{eval1.ID}})
+		req, err = http.NewRequest("PUT", "/v1/evaluations/delete", buf)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.EvalsDeleteRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		out, err := state.EvalByID(nil, eval1.ID)
+		require.NoError(err)
+		require.Nil(out)
+	})
+}
+
 func TestHTTP_EvalPrefixList(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
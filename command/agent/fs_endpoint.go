@@ -7,8 +7,10 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -25,6 +27,12 @@ var (
 	invalidOrigin         = CodedError(400, "origin must be start or end")
 )
 
+const (
+	// fsUploadFrameSize is the maximum number of bytes sent in a single frame
+	// of an uploaded file
+	fsUploadFrameSize = 64 * 1024
+)
+
 func (s *HTTPServer) FsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/v1/client/fs/")
 	switch {
@@ -40,6 +48,10 @@ func (s *HTTPServer) FsRequest(resp http.ResponseWriter, req *http.Request) (int
 		return s.Stream(resp, req)
 	case strings.HasPrefix(path, "logs/"):
 		return s.Logs(resp, req)
+	case strings.HasPrefix(path, "upload/"):
+		return s.FileUploadRequest(resp, req)
+	case strings.HasPrefix(path, "archive/"):
+		return s.FileArchiveRequest(resp, req)
 	default:
 		return nil, CodedError(404, ErrInvalidMethod)
 	}
@@ -191,6 +203,38 @@ func (s *HTTPServer) FileCatRequest(resp http.ResponseWriter, req *http.Request)
 	return s.fsStreamImpl(resp, req, "FileSystem.Stream", fsReq, fsReq.AllocID)
 }
 
+func (s *HTTPServer) FileArchiveRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var allocID, path string
+	var err error
+
+	q := req.URL.Query()
+
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/archive/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+	if path = q.Get("path"); path == "" {
+		path = "/"
+	}
+
+	var compress bool
+	if compressStr := q.Get("compress"); compressStr != "" {
+		if compress, err = strconv.ParseBool(compressStr); err != nil {
+			return nil, fmt.Errorf("failed to parse compress field to boolean: %v", err)
+		}
+	}
+
+	// Create the request arguments
+	fsReq := &cstructs.FsArchiveRequest{
+		AllocID:  allocID,
+		Path:     path,
+		Compress: compress,
+	}
+	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
+
+	// Make the request
+	return s.fsStreamImpl(resp, req, "FileSystem.Archive", fsReq, fsReq.AllocID)
+}
+
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 // * path: path to file to stream.
@@ -257,6 +301,10 @@ func (s *HTTPServer) Stream(resp http.ResponseWriter, req *http.Request) (interf
 // * offset: The offset to start streaming data at, defaults to zero.
 // * origin: Either "start" or "end" and defines from where the offset is
 //           applied. Defaults to "start".
+// * filter: Only stream the lines containing the filter.
+// * regex: A boolean of whether the filter is a regular expression.
+// * since/until: Only stream the lines written within the time range. Each is
+//                an RFC3339 timestamp or a duration before now.
 func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var allocID, task, logType string
 	var plain, follow bool
@@ -308,15 +356,36 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 		return nil, invalidOrigin
 	}
 
+	var regex bool
+	if regexStr := q.Get("regex"); regexStr != "" {
+		if regex, err = strconv.ParseBool(regexStr); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("failed to parse regex field to boolean: %v", err))
+		}
+	}
+
+	now := time.Now()
+	since, err := parseLogTime(q.Get("since"), now)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("error parsing since: %v", err))
+	}
+	until, err := parseLogTime(q.Get("until"), now)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("error parsing until: %v", err))
+	}
+
 	// Create the request arguments
 	fsReq := &cstructs.FsLogsRequest{
-		AllocID:   allocID,
-		Task:      task,
-		LogType:   logType,
-		Offset:    offset,
-		Origin:    origin,
-		PlainText: plain,
-		Follow:    follow,
+		AllocID:     allocID,
+		Task:        task,
+		LogType:     logType,
+		Offset:      offset,
+		Origin:      origin,
+		PlainText:   plain,
+		Follow:      follow,
+		Filter:      q.Get("filter"),
+		FilterRegex: regex,
+		Since:       since,
+		Until:       until,
 	}
 	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
 
@@ -324,13 +393,136 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 	return s.fsStreamImpl(resp, req, "FileSystem.Logs", fsReq, fsReq.AllocID)
 }
 
-// fsStreamImpl is used to make a streaming filesystem call that serializes the
-// args and then expects a stream of StreamErrWrapper results where the payload
-// is copied to the response body.
-func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
-	req *http.Request, method string, args interface{}, allocID string) (interface{}, error) {
+// parseLogTime parses the time bounding the logs to stream. The time is either
+// an RFC3339 timestamp or a duration before now. An empty value returns the
+// zero time.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
 
-	// Get the correct handler
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be an RFC3339 timestamp or a duration: %q", value)
+	}
+	return t, nil
+}
+
+// FileUploadRequest writes the request body to a file in an allocation's
+// directory. The parameters are:
+// * path: path of the file relative to the allocation directory.
+// * mode: the octal permissions of the file, defaults to 0644.
+// * overwrite: A boolean of whether an existing file is replaced.
+func (s *HTTPServer) FileUploadRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var allocID, path string
+	var err error
+
+	q := req.URL.Query()
+
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/upload/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+	if path = q.Get("path"); path == "" {
+		return nil, fileNameNotPresentErr
+	}
+
+	mode := os.FileMode(0644)
+	if modeStr := q.Get("mode"); modeStr != "" {
+		m, err := strconv.ParseUint(modeStr, 8, 32)
+		if err != nil || os.FileMode(m) != os.FileMode(m).Perm() {
+			return nil, CodedError(400, fmt.Sprintf("invalid file mode %q", modeStr))
+		}
+		mode = os.FileMode(m)
+	}
+
+	var overwrite bool
+	if overwriteStr := q.Get("overwrite"); overwriteStr != "" {
+		if overwrite, err = strconv.ParseBool(overwriteStr); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("failed to parse overwrite field to boolean: %v", err))
+		}
+	}
+
+	// Create the request arguments
+	args := &cstructs.FsUploadRequest{
+		AllocID:   allocID,
+		Path:      path,
+		FileMode:  mode,
+		Overwrite: overwrite,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	handler, err := s.streamingRpcHandlerForAlloc("FileSystem.Upload", allocID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a pipe connecting the (possibly remote) handler to the request
+	httpPipe, handlerPipe := net.Pipe()
+	defer httpPipe.Close()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	go handler(handlerPipe)
+
+	if err := encoder.Encode(args); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Send the file while waiting for the result, as the handler may reply
+	// with an error before reading the file
+	go func() {
+		buf := make([]byte, fsUploadFrameSize)
+		for {
+			n, err := req.Body.Read(buf)
+			if n != 0 {
+				if err := encoder.Encode(&cstructs.FsUploadFrame{Data: buf[:n]}); err != nil {
+					return
+				}
+			}
+
+			if err == io.EOF {
+				encoder.Encode(&cstructs.FsUploadFrame{EOF: true})
+				return
+			} else if err != nil {
+				// Abort the upload
+				httpPipe.Close()
+				return
+			}
+		}
+	}()
+
+	var res cstructs.StreamErrWrapper
+	if err := decoder.Decode(&res); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	if err := res.Error; err != nil {
+		code := 500
+		if err.Code != nil {
+			code = int(*err.Code)
+		}
+		return nil, CodedError(code, err.Error())
+	}
+
+	var info cstructs.AllocFileInfo
+	if err := codec.NewDecoderBytes(res.Payload, structs.JsonHandle).Decode(&info); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+	return &info, nil
+}
+
+// streamingRpcHandlerForAlloc returns the handler of the streaming RPC for
+// the allocation, which is either served by the local client or forwarded to
+// the servers.
+func (s *HTTPServer) streamingRpcHandlerForAlloc(method, allocID string) (structs.StreamingRpcHandler, error) {
 	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
 	var handler structs.StreamingRpcHandler
 	var handlerErr error
@@ -345,6 +537,20 @@ func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 	if handlerErr != nil {
 		return nil, CodedError(500, handlerErr.Error())
 	}
+	return handler, nil
+}
+
+// fsStreamImpl is used to make a streaming filesystem call that serializes the
+// args and then expects a stream of StreamErrWrapper results where the payload
+// is copied to the response body.
+func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
+	req *http.Request, method string, args interface{}, allocID string) (interface{}, error) {
+
+	// Get the correct handler
+	handler, err := s.streamingRpcHandlerForAlloc(method, allocID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create a pipe connecting the (possibly remote) handler to the http response
 	httpPipe, handlerPipe := net.Pipe()
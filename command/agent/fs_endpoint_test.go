@@ -1,11 +1,15 @@
 package agent
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -212,6 +216,41 @@ func TestHTTP_FS_Stream_MissingParams(t *testing.T) {
 	})
 }
 
+func TestHTTP_FS_Upload_MissingParams(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/v1/client/fs/upload/foo?path=foo", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.FileUploadRequest(respW, req)
+		require.EqualError(err, ErrInvalidMethod)
+
+		req, err = http.NewRequest("PUT", "/v1/client/fs/upload/", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.FileUploadRequest(respW, req)
+		require.EqualError(err, allocIDNotPresentErr.Error())
+
+		req, err = http.NewRequest("PUT", "/v1/client/fs/upload/foo", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.FileUploadRequest(respW, req)
+		require.EqualError(err, fileNameNotPresentErr.Error())
+
+		req, err = http.NewRequest("PUT", "/v1/client/fs/upload/foo?path=foo&mode=999", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.FileUploadRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "invalid file mode")
+	})
+}
+
 // TestHTTP_FS_Logs_MissingParams asserts proper error codes and messages are
 // returned for incorrect parameters (eg missing tasks).
 func TestHTTP_FS_Logs_MissingParams(t *testing.T) {
@@ -343,6 +382,96 @@ func TestHTTP_FS_Cat(t *testing.T) {
 	})
 }
 
+func TestHTTP_FS_Upload(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), nil)
+		addAllocToClient(s, a, terminalClientAlloc)
+
+		path := fmt.Sprintf("/v1/client/fs/upload/%s?path=alloc/data/foo&mode=600", a.ID)
+		req, err := http.NewRequest("PUT", path, strings.NewReader("hello"))
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.FileUploadRequest(respW, req)
+		require.Nil(err)
+
+		info := obj.(*cstructs.AllocFileInfo)
+		require.Equal("foo", info.Name)
+		require.EqualValues(5, info.Size)
+		require.Equal("-rw-------", info.FileMode)
+
+		// Read the uploaded file
+		path = fmt.Sprintf("/v1/client/fs/cat/%s?path=alloc/data/foo", a.ID)
+		req, err = http.NewRequest("GET", path, nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.FileCatRequest(respW, req)
+		require.Nil(err)
+
+		output, err := ioutil.ReadAll(respW.Result().Body)
+		require.Nil(err)
+		require.EqualValues("hello", output)
+
+		// Existing files are not replaced by default
+		path = fmt.Sprintf("/v1/client/fs/upload/%s?path=alloc/data/foo", a.ID)
+		req, err = http.NewRequest("PUT", path, strings.NewReader("bye"))
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.FileUploadRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "already exists")
+		codedErr, ok := err.(HTTPCodedError)
+		require.True(ok)
+		require.Equal(400, codedErr.Code())
+	})
+}
+
+func TestHTTP_FS_Archive(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), nil)
+		addAllocToClient(s, a, terminalClientAlloc)
+
+		path := fmt.Sprintf("/v1/client/fs/archive/%s?path=alloc/logs&compress=true", a.ID)
+		req, err := http.NewRequest("GET", path, nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.FileArchiveRequest(respW, req)
+		require.Nil(err)
+
+		gr, err := gzip.NewReader(respW.Result().Body)
+		require.Nil(err)
+		tr := tar.NewReader(gr)
+		files := make(map[string]string)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.Nil(err)
+
+			output, err := ioutil.ReadAll(tr)
+			require.Nil(err)
+			files[hdr.Name] = string(output)
+		}
+		require.Equal(defaultLoggerMockDriverStdout, files["alloc/logs/web.stdout.0"])
+
+		// Invalid paths are rejected
+		path = fmt.Sprintf("/v1/client/fs/archive/%s?path=../foo", a.ID)
+		req, err = http.NewRequest("GET", path, nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.FileArchiveRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "escapes")
+		codedErr, ok := err.(HTTPCodedError)
+		require.True(ok)
+		require.Equal(400, codedErr.Code())
+	})
+}
+
 func TestHTTP_FS_Stream_NoFollow(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -466,6 +595,60 @@ func TestHTTP_FS_Logs(t *testing.T) {
 	})
 }
 
+func TestHTTP_FS_Logs_Filter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), nil)
+		addAllocToClient(s, a, terminalClientAlloc)
+
+		path := fmt.Sprintf("/v1/client/fs/logs/%s?type=stdout&task=web&plain=true&filter=%s&regex=true&since=1h",
+			a.ID, url.QueryEscape("other s[a-z]+$"))
+
+		req, err := http.NewRequest("GET", path, nil)
+		require.Nil(err)
+		respW := testutil.NewResponseRecorder()
+		go func() {
+			_, err = s.Server.Logs(respW, req)
+			require.Nil(err)
+		}()
+
+		out := ""
+		testutil.WaitForResult(func() (bool, error) {
+			output, err := ioutil.ReadAll(respW)
+			if err != nil {
+				return false, err
+			}
+
+			out += string(output)
+			return out == defaultLoggerMockDriverStdout, fmt.Errorf("%q != %q", out, defaultLoggerMockDriverStdout)
+		}, func(err error) {
+			t.Fatal(err)
+		})
+	})
+}
+
+func TestHTTP_FS_parseLogTime(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	now := time.Now()
+	ts, err := parseLogTime("", now)
+	require.NoError(err)
+	require.True(ts.IsZero())
+
+	ts, err = parseLogTime("10m", now)
+	require.NoError(err)
+	require.Equal(now.Add(-10*time.Minute), ts)
+
+	ts, err = parseLogTime("2019-11-05T10:30:00Z", now)
+	require.NoError(err)
+	require.Equal(time.Date(2019, 11, 5, 10, 30, 0, 0, time.UTC), ts)
+
+	_, err = parseLogTime("yesterday", now)
+	require.Error(err)
+}
+
 func TestHTTP_FS_Logs_Follow(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -153,12 +153,15 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/job/", s.wrap(s.JobSpecificRequest))
 
 	s.mux.HandleFunc("/v1/nodes", s.wrap(s.NodesRequest))
+	s.mux.HandleFunc("/v1/nodes/drain", s.wrap(s.NodesDrainRequest))
+	s.mux.HandleFunc("/v1/nodes/eligibility", s.wrap(s.NodesEligibilityRequest))
 	s.mux.HandleFunc("/v1/node/", s.wrap(s.NodeSpecificRequest))
 
 	s.mux.HandleFunc("/v1/allocations", s.wrap(s.AllocsRequest))
 	s.mux.HandleFunc("/v1/allocation/", s.wrap(s.AllocSpecificRequest))
 
 	s.mux.HandleFunc("/v1/evaluations", s.wrap(s.EvalsRequest))
+	s.mux.HandleFunc("/v1/evaluations/delete", s.wrap(s.EvalsDeleteRequest))
 	s.mux.HandleFunc("/v1/evaluation/", s.wrap(s.EvalSpecificRequest))
 
 	s.mux.HandleFunc("/v1/deployments", s.wrap(s.DeploymentsRequest))
@@ -174,7 +177,9 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 
 	s.mux.Handle("/v1/client/fs/", wrapCORS(s.wrap(s.FsRequest)))
 	s.mux.HandleFunc("/v1/client/gc", s.wrap(s.ClientGCRequest))
+	s.mux.HandleFunc("/v1/client/gc/status", s.wrap(s.ClientGCStatusRequest))
 	s.mux.Handle("/v1/client/stats", wrapCORS(s.wrap(s.ClientStatsRequest)))
+	s.mux.HandleFunc("/v1/client/metadata", s.wrap(s.NodeMetaRequest))
 	s.mux.Handle("/v1/client/allocation/", wrapCORS(s.wrap(s.ClientAllocRequest)))
 
 	s.mux.HandleFunc("/v1/agent/self", s.wrap(s.AgentSelfRequest))
@@ -198,6 +203,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/status/peers", s.wrap(s.StatusPeersRequest))
 
 	s.mux.HandleFunc("/v1/search", s.wrap(s.SearchRequest))
+	s.mux.HandleFunc("/v1/search/fuzzy", s.wrap(s.FuzzySearchRequest))
 
 	s.mux.HandleFunc("/v1/operator/raft/", s.wrap(s.OperatorRequest))
 	s.mux.HandleFunc("/v1/operator/autopilot/configuration", s.wrap(s.OperatorAutopilotConfiguration))
@@ -207,6 +213,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/system/reconcile/summaries", s.wrap(s.ReconcileJobSummaries))
 
 	s.mux.HandleFunc("/v1/operator/scheduler/configuration", s.wrap(s.OperatorSchedulerConfiguration))
+	s.mux.HandleFunc("/v1/operator/scheduler/simulate", s.wrap(s.OperatorSchedulerSimulate))
 
 	if uiEnabled {
 		s.mux.Handle("/ui/", http.StripPrefix("/ui/", handleUI(http.FileServer(&UIAssetWrapper{FileSystem: assetFS()}))))
@@ -440,6 +447,14 @@ func setMeta(resp http.ResponseWriter, m *structs.QueryMeta) {
 	setIndex(resp, m.Index)
 	setLastContact(resp, m.LastContact)
 	setKnownLeader(resp, m.KnownLeader)
+	setNextToken(resp, m.NextToken)
+}
+
+// setNextToken is used to set the next token header for pagination
+func setNextToken(resp http.ResponseWriter, nextToken string) {
+	if nextToken != "" {
+		resp.Header().Set("X-Nomad-NextToken", nextToken)
+	}
 }
 
 // setHeaders is used to set canonical response header fields
@@ -474,6 +489,24 @@ func parseWait(resp http.ResponseWriter, req *http.Request, b *structs.QueryOpti
 	return false
 }
 
+// parsePagination is used to parse the ?per_page, ?next_token and ?filter
+// query params. Returns true on error
+func parsePagination(resp http.ResponseWriter, req *http.Request, b *structs.QueryOptions) bool {
+	query := req.URL.Query()
+	if perPage := query.Get("per_page"); perPage != "" {
+		n, err := strconv.ParseInt(perPage, 10, 32)
+		if err != nil || n < 0 {
+			resp.WriteHeader(400)
+			resp.Write([]byte("Invalid per_page"))
+			return true
+		}
+		b.PerPage = int32(n)
+	}
+	b.NextToken = query.Get("next_token")
+	b.Filter = query.Get("filter")
+	return false
+}
+
 // parseConsistency is used to parse the ?stale query params.
 func parseConsistency(req *http.Request, b *structs.QueryOptions) {
 	query := req.URL.Query()
@@ -523,6 +556,9 @@ func (s *HTTPServer) parse(resp http.ResponseWriter, req *http.Request, r *strin
 	parseConsistency(req, b)
 	parsePrefix(req, b)
 	parseNamespace(req, &b.Namespace)
+	if parsePagination(resp, req, b) {
+		return true
+	}
 	return parseWait(resp, req, b)
 }
 
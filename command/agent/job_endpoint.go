@@ -157,6 +157,7 @@ func (s *HTTPServer) jobPlan(resp http.ResponseWriter, req *http.Request,
 		Job:            sJob,
 		Diff:           args.Diff,
 		PolicyOverride: args.PolicyOverride,
+		Explain:        args.Explain,
 		WriteRequest: structs.WriteRequest{
 			Region: sJob.Region,
 		},
@@ -680,6 +681,22 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if job.GC != nil {
+		j.GC = &structs.JobGCConfig{
+			MinRetention: *job.GC.MinRetention,
+		}
+	}
+
+	if l := len(job.DependsOn); l != 0 {
+		j.DependsOn = make([]*structs.JobDependency, l)
+		for i, dep := range job.DependsOn {
+			j.DependsOn[i] = &structs.JobDependency{
+				JobID:  dep.JobID,
+				Status: *dep.Status,
+			}
+		}
+	}
+
 	if l := len(job.TaskGroups); l != 0 {
 		j.TaskGroups = make([]*structs.TaskGroup, l)
 		for i, taskGroup := range job.TaskGroups {
@@ -712,6 +729,10 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 		tg.ShutdownDelay = taskGroup.ShutdownDelay
 	}
 
+	if taskGroup.MaxRunDuration != nil {
+		tg.MaxRunDuration = taskGroup.MaxRunDuration
+	}
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -764,6 +785,13 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 		}
 	}
 
+	if taskGroup.Archive != nil {
+		tg.Archive = &structs.AllocArchive{
+			Paths:  taskGroup.Archive.Paths,
+			Volume: taskGroup.Archive.Volume,
+		}
+	}
+
 	if taskGroup.Update != nil {
 		tg.Update = &structs.UpdateStrategy{
 			Stagger:          *taskGroup.Update.Stagger,
@@ -773,6 +801,20 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 			HealthyDeadline:  *taskGroup.Update.HealthyDeadline,
 			ProgressDeadline: *taskGroup.Update.ProgressDeadline,
 			Canary:           *taskGroup.Update.Canary,
+			RolloutSteps:     taskGroup.Update.RolloutSteps,
+		}
+
+		if taskGroup.Update.CanaryPercent != nil {
+			tg.Update.CanaryPercent = *taskGroup.Update.CanaryPercent
+		}
+
+		if gate := taskGroup.Update.HealthGate; gate != nil {
+			tg.Update.HealthGate = &structs.HealthGate{
+				URL:           *gate.URL,
+				Timeout:       *gate.Timeout,
+				Attempts:      *gate.Attempts,
+				RetryInterval: *gate.RetryInterval,
+			}
 		}
 
 		// boolPtr fields may be nil, others will have pointers to default values via Canonicalize
@@ -807,6 +849,7 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 	structsTask.Meta = apiTask.Meta
 	structsTask.KillTimeout = *apiTask.KillTimeout
 	structsTask.ShutdownDelay = apiTask.ShutdownDelay
+	structsTask.MaxRunDuration = apiTask.MaxRunDuration
 	structsTask.KillSignal = apiTask.KillSignal
 	structsTask.Kind = structs.TaskKind(apiTask.Kind)
 	structsTask.Constraints = ApiConstraintsToStructs(apiTask.Constraints)
@@ -872,8 +915,18 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 	structsTask.Resources = ApiResourcesToStructs(apiTask.Resources)
 
 	structsTask.LogConfig = &structs.LogConfig{
-		MaxFiles:      *apiTask.LogConfig.MaxFiles,
-		MaxFileSizeMB: *apiTask.LogConfig.MaxFileSizeMB,
+		MaxFiles:       *apiTask.LogConfig.MaxFiles,
+		MaxFileSizeMB:  *apiTask.LogConfig.MaxFileSizeMB,
+		Compress:       *apiTask.LogConfig.Compress,
+		RotateInterval: *apiTask.LogConfig.RotateInterval,
+		MaxAge:         *apiTask.LogConfig.MaxAge,
+	}
+	for _, s := range apiTask.LogConfig.Sinks {
+		structsTask.LogConfig.Sinks = append(structsTask.LogConfig.Sinks, &structs.LogSink{
+			Type:        s.Type,
+			Address:     s.Address,
+			BufferLines: s.BufferLines,
+		})
 	}
 
 	if l := len(apiTask.Artifacts); l != 0 {
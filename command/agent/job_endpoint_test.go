@@ -3,6 +3,7 @@ package agent
 import (
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strings"
 	"testing"
@@ -69,6 +70,48 @@ func TestHTTP_JobsList(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobsList_Filter(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the jobs
+		for _, job := range []*structs.Job{mock.Job(), mock.BatchJob()} {
+			args := structs.JobRegisterRequest{
+				Job: job,
+				WriteRequest: structs.WriteRequest{
+					Region:    "global",
+					Namespace: structs.DefaultNamespace,
+				},
+			}
+			var resp structs.JobRegisterResponse
+			require.NoError(s.Agent.RPC("Job.Register", &args, &resp))
+		}
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/jobs?filter="+url.QueryEscape(`Type == "batch"`), nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobsRequest(respW, req)
+		require.NoError(err)
+		j := obj.([]*structs.JobListStub)
+		require.Len(j, 1)
+		require.Equal(structs.JobTypeBatch, j[0].Type)
+
+		// Invalid filters return a coded error
+		req, err = http.NewRequest("GET", "/v1/jobs?filter="+url.QueryEscape(`Type ==`), nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.JobsRequest(respW, req)
+		require.Error(err)
+		code, _, ok := structs.CodeFromRPCCodedErr(err)
+		require.True(ok)
+		require.Equal(400, code)
+	})
+}
+
 func TestHTTP_PrefixJobsList(t *testing.T) {
 	ids := []string{
 		"aaaaaaaa-e8f7-fd38-c855-ab94ceb89706",
@@ -1432,13 +1475,20 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			MetaOptional: []string{"c", "d"},
 		},
 		Payload: []byte("payload"),
+		DependsOn: []*api.JobDependency{
+			{
+				JobID:  "db",
+				Status: helper.StringToPtr("healthy"),
+			},
+		},
 		Meta: map[string]string{
 			"foo": "bar",
 		},
 		TaskGroups: []*api.TaskGroup{
 			{
-				Name:  helper.StringToPtr("group1"),
-				Count: helper.IntToPtr(5),
+				Name:           helper.StringToPtr("group1"),
+				Count:          helper.IntToPtr(5),
+				MaxRunDuration: helper.TimeToPtr(2 * time.Hour),
 				Constraints: []*api.Constraint{
 					{
 						LTarget: "x",
@@ -1670,8 +1720,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Meta: map[string]string{
 							"lol": "code",
 						},
-						KillTimeout: helper.TimeToPtr(10 * time.Second),
-						KillSignal:  "SIGQUIT",
+						KillTimeout:    helper.TimeToPtr(10 * time.Second),
+						MaxRunDuration: time.Hour,
+						KillSignal:     "SIGQUIT",
 						LogConfig: &api.LogConfig{
 							MaxFiles:      helper.IntToPtr(10),
 							MaxFileSizeMB: helper.IntToPtr(100),
@@ -1778,13 +1829,20 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			MetaOptional: []string{"c", "d"},
 		},
 		Payload: []byte("payload"),
+		DependsOn: []*structs.JobDependency{
+			{
+				JobID:  "db",
+				Status: "healthy",
+			},
+		},
 		Meta: map[string]string{
 			"foo": "bar",
 		},
 		TaskGroups: []*structs.TaskGroup{
 			{
-				Name:  "group1",
-				Count: 5,
+				Name:           "group1",
+				Count:          5,
+				MaxRunDuration: helper.TimeToPtr(2 * time.Hour),
 				Constraints: []*structs.Constraint{
 					{
 						LTarget: "x",
@@ -2015,8 +2073,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Meta: map[string]string{
 							"lol": "code",
 						},
-						KillTimeout: 10 * time.Second,
-						KillSignal:  "SIGQUIT",
+						KillTimeout:    10 * time.Second,
+						MaxRunDuration: time.Hour,
+						KillSignal:     "SIGQUIT",
 						LogConfig: &structs.LogConfig{
 							MaxFiles:      10,
 							MaxFileSizeMB: 100,
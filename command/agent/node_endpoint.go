@@ -32,6 +32,80 @@ func (s *HTTPServer) NodesRequest(resp http.ResponseWriter, req *http.Request) (
 	return out.Nodes, nil
 }
 
+func (s *HTTPServer) NodesDrainRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var drainRequest api.NodeBatchUpdateDrainRequest
+	if err := decodeBody(req, &drainRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if drainRequest.Selector == nil {
+		return nil, CodedError(400, "missing node selector")
+	}
+
+	args := structs.NodeBatchUpdateDrainRequest{
+		Selector:     apiNodeSelectorToStructs(drainRequest.Selector),
+		MarkEligible: drainRequest.MarkEligible,
+	}
+	if drainRequest.DrainSpec != nil {
+		args.DrainStrategy = &structs.DrainStrategy{
+			DrainSpec: structs.DrainSpec{
+				Deadline:         drainRequest.DrainSpec.Deadline,
+				IgnoreSystemJobs: drainRequest.DrainSpec.IgnoreSystemJobs,
+			},
+		}
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.NodeBatchDrainUpdateResponse
+	if err := s.agent.RPC("Node.BatchUpdateDrain", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) NodesEligibilityRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var eligibilityRequest api.NodeBatchUpdateEligibilityRequest
+	if err := decodeBody(req, &eligibilityRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if eligibilityRequest.Selector == nil {
+		return nil, CodedError(400, "missing node selector")
+	}
+
+	args := structs.NodeBatchUpdateEligibilityRequest{
+		Selector:    apiNodeSelectorToStructs(eligibilityRequest.Selector),
+		Eligibility: eligibilityRequest.Eligibility,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.NodeBatchEligibilityUpdateResponse
+	if err := s.agent.RPC("Node.BatchUpdateEligibility", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func apiNodeSelectorToStructs(in *api.NodeSelector) *structs.NodeSelector {
+	if in == nil {
+		return nil
+	}
+
+	return &structs.NodeSelector{
+		Datacenter: in.Datacenter,
+		NodeClass:  in.NodeClass,
+		Filters:    ApiConstraintsToStructs(in.Filters),
+	}
+}
+
 func (s *HTTPServer) NodeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/v1/node/")
 	switch {
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) NodeMetaRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.nodeMetaRead(resp, req)
+	case "PUT", "POST":
+		return s.nodeMetaApply(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) nodeMetaRead(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Get the requested Node ID
+	requestedNode := req.URL.Query().Get("node_id")
+
+	// Build the request and parse the ACL token
+	args := structs.NodeSpecificRequest{
+		NodeID: requestedNode,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.NodeMetaResponse
+	if err := s.nodeMetaRPC(requestedNode, "NodeMeta.Read", &args, &reply); err != nil {
+		return nil, err
+	}
+	return nodeMetaResponseToApi(&reply), nil
+}
+
+func (s *HTTPServer) nodeMetaApply(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var applyRequest api.NodeMetaApplyRequest
+	if err := decodeBody(req, &applyRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	// Get the requested Node ID, preferring the query parameter
+	requestedNode := req.URL.Query().Get("node_id")
+	if requestedNode == "" {
+		requestedNode = applyRequest.NodeID
+	}
+
+	// Build the request and parse the ACL token
+	args := cstructs.NodeMetaApplyRequest{
+		NodeID: requestedNode,
+		Meta:   applyRequest.Meta,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.NodeMetaResponse
+	if err := s.nodeMetaRPC(requestedNode, "NodeMeta.Apply", &args, &reply); err != nil {
+		return nil, err
+	}
+	return nodeMetaResponseToApi(&reply), nil
+}
+
+// nodeMetaRPC makes the node meta RPC using the local client if it is the
+// requested node or through the servers otherwise.
+func (s *HTTPServer) nodeMetaRPC(nodeID, method string, args, reply interface{}) error {
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForNode(nodeID)
+
+	// Make the RPC
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC(method, args, reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC(method, args, reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC(method, args, reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		} else if strings.Contains(rpcErr.Error(), "Unknown node") {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+	}
+	return rpcErr
+}
+
+func nodeMetaResponseToApi(in *cstructs.NodeMetaResponse) *api.NodeMetaResponse {
+	return &api.NodeMetaResponse{
+		Meta:    in.Meta,
+		Dynamic: in.Dynamic,
+		Static:  in.Static,
+	}
+}
@@ -254,6 +254,9 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 			SystemSchedulerEnabled:  conf.PreemptionConfig.SystemSchedulerEnabled,
 			BatchSchedulerEnabled:   conf.PreemptionConfig.BatchSchedulerEnabled,
 			ServiceSchedulerEnabled: conf.PreemptionConfig.ServiceSchedulerEnabled},
+		PlacementTraceEnabled: conf.PlacementTraceEnabled,
+		NamespaceWeights:      conf.NamespaceWeights,
+		PauseEvalBroker:       conf.PauseEvalBroker,
 	}
 
 	// Check for cas value
@@ -274,3 +277,51 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 	setIndex(resp, reply.Index)
 	return reply, nil
 }
+
+// OperatorSchedulerSimulate is used to simulate the scheduling of jobs and
+// node changes against a snapshot of the cluster state.
+func (s *HTTPServer) OperatorSchedulerSimulate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var sim api.SchedulerSimulateRequest
+	if err := decodeBody(req, &sim); err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing simulation: %v", err))
+	}
+
+	var args structs.SchedulerSimulateRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	for _, job := range sim.Jobs {
+		if job == nil || job.ID == nil {
+			return nil, CodedError(http.StatusBadRequest, "Job must have a valid ID")
+		}
+
+		// If 'global' region is specified or if no region is given,
+		// default to region of the node you're submitting to
+		if job.Region == nil || *job.Region == "" || *job.Region == api.GlobalRegion {
+			job.Region = &s.agent.config.Region
+		}
+		args.Jobs = append(args.Jobs, ApiJobToStructJob(job))
+	}
+	for _, add := range sim.AddNodes {
+		if add == nil {
+			continue
+		}
+		args.AddNodes = append(args.AddNodes, &structs.SimulateAddNode{
+			NodeID: add.NodeID,
+			Count:  add.Count,
+		})
+	}
+	args.RemoveNodes = sim.RemoveNodes
+
+	var reply structs.SchedulerSimulateResponse
+	if err := s.agent.RPC("Operator.SchedulerSimulate", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+	return reply, nil
+}
@@ -382,3 +382,43 @@ func TestOperator_SchedulerCASConfiguration(t *testing.T) {
 		require.False(reply.SchedulerConfig.PreemptionConfig.BatchSchedulerEnabled)
 	})
 }
+
+func TestOperator_SchedulerSimulate(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		job := MockJob()
+		args := api.SchedulerSimulateRequest{
+			Jobs: []*api.Job{job},
+		}
+		buf := encodeReq(args)
+		req, _ := http.NewRequest("PUT", "/v1/operator/scheduler/simulate", buf)
+		resp := httptest.NewRecorder()
+		obj, err := s.Server.OperatorSchedulerSimulate(resp, req)
+		require.Nil(err)
+		require.Equal(200, resp.Code)
+		require.NotEqual("", resp.Header().Get("X-Nomad-Index"))
+		out, ok := obj.(structs.SchedulerSimulateResponse)
+		require.True(ok)
+		require.Len(out.Evals, 1)
+		require.Equal(*job.ID, out.Evals[0].JobID)
+
+		// The job is not registered
+		getReq := structs.JobSpecificRequest{
+			JobID: *job.ID,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var getResp structs.SingleJobResponse
+		require.Nil(s.Agent.RPC("Job.GetJob", &getReq, &getResp))
+		require.Nil(getResp.Job)
+
+		// Only PUT and POST are allowed
+		req, _ = http.NewRequest("GET", "/v1/operator/scheduler/simulate", nil)
+		_, err = s.Server.OperatorSchedulerSimulate(httptest.NewRecorder(), req)
+		require.Error(err)
+	})
+}
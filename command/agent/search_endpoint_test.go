@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_SearchWithIllegalMethod(t *testing.T) {
@@ -333,3 +334,32 @@ func TestHTTP_Search_AllContext(t *testing.T) {
 		assert.Equal("8000", respW.HeaderMap.Get("X-Nomad-Index"))
 	})
 }
+
+func TestHTTP_FuzzySearch(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		createJobForTest("fuzzy-job", s, t)
+
+		data := structs.FuzzySearchRequest{Text: "my-j", Context: structs.All}
+		req, err := http.NewRequest("POST", "/v1/search/fuzzy", encodeReq(data))
+		require.NoError(err)
+
+		respW := httptest.NewRecorder()
+		resp, err := s.Server.FuzzySearchRequest(respW, req)
+		require.NoError(err)
+
+		res := resp.(structs.FuzzySearchResponse)
+		require.Equal([]structs.FuzzyMatch{
+			{ID: "my-job", Scope: []string{structs.DefaultNamespace, "fuzzy-job"}},
+		}, res.Matches[structs.Jobs])
+		require.Empty(res.Matches[structs.Nodes])
+		require.Equal("1000", respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Other methods are not accepted
+		req, err = http.NewRequest("DELETE", "/v1/search/fuzzy", nil)
+		require.NoError(err)
+		_, err = s.Server.FuzzySearchRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+	})
+}
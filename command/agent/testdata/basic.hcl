@@ -84,13 +84,23 @@ client {
     collection_interval = "5s"
   }
 
-  gc_interval              = "6s"
-  gc_parallel_destroys     = 6
-  gc_disk_usage_threshold  = 82
-  gc_inode_usage_threshold = 91
-  gc_max_allocs            = 50
-  no_host_uuid             = false
-  disable_remote_exec      = true
+  gc_interval               = "6s"
+  gc_parallel_destroys      = 6
+  gc_disk_usage_threshold   = 82
+  gc_inode_usage_threshold  = 91
+  gc_max_allocs             = 50
+  gc_failed_alloc_retention = "2h"
+  gc_max_job_retention      = "12h"
+  artifact_cache_dir        = "/tmp/artifacts"
+  artifact_cache_max_mb     = 2048
+  no_host_uuid              = false
+  disable_remote_exec       = true
+  log_sink_addresses        = ["udp://127.0.0.1:514"]
+
+  artifact_max_size_mb             = 512
+  artifact_max_files               = 1000
+  artifact_max_decompression_ratio = 50
+  artifact_timeout                 = "10m"
 
   host_volume "tmp" {
     path = "/tmp"
@@ -122,6 +132,7 @@ server {
   non_voting_server         = true
   redundancy_zone           = "foo"
   upgrade_version           = "0.8.0"
+  health_gate_hosts         = ["gate.example.com"]
   encrypt                   = "abc"
 
   server_join {
@@ -40,6 +40,12 @@ Alias: nomad fs
   or displays the file at the given path. The path is relative to the root of the alloc
   dir and defaults to root if unspecified.
 
+  With -put, fs uploads a local file to the given path instead. Uploading files
+  requires a token with the 'write-fs' capability when ACLs are enabled.
+
+  With -archive, fs downloads a tar archive of the file or directory at the
+  given path instead.
+
 General Options:
 
   ` + generalOptionsUsage() + `
@@ -72,6 +78,18 @@ FS Specific Options:
 
   -c
     Sets the tail location in number of bytes relative to the end of the file.
+
+  -put <local-file>
+    Uploads the local file to the path, which is required. Missing parent
+    directories are created. If the local file is "-", stdin is uploaded.
+
+  -overwrite
+    Replaces an existing file when uploading with -put.
+
+  -archive <local-file>
+    Downloads a tar archive of the path to the local file, or to stdout if it
+    is "-". The archive is gzip compressed if the local file ends in ".tar.gz"
+    or ".tgz".
 `
 	return strings.TrimSpace(helpText)
 }
@@ -83,14 +101,17 @@ func (f *AllocFSCommand) Synopsis() string {
 func (c *AllocFSCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-H":       complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
-			"-job":     complete.PredictAnything,
-			"-stat":    complete.PredictNothing,
-			"-f":       complete.PredictNothing,
-			"-tail":    complete.PredictNothing,
-			"-n":       complete.PredictAnything,
-			"-c":       complete.PredictAnything,
+			"-H":         complete.PredictNothing,
+			"-verbose":   complete.PredictNothing,
+			"-job":       complete.PredictAnything,
+			"-stat":      complete.PredictNothing,
+			"-f":         complete.PredictNothing,
+			"-tail":      complete.PredictNothing,
+			"-n":         complete.PredictAnything,
+			"-c":         complete.PredictAnything,
+			"-put":       complete.PredictFiles("*"),
+			"-overwrite": complete.PredictNothing,
+			"-archive":   complete.PredictFiles("*"),
 		})
 }
 
@@ -112,8 +133,9 @@ func (f *AllocFSCommand) AutocompleteArgs() complete.Predictor {
 func (f *AllocFSCommand) Name() string { return "alloc fs" }
 
 func (f *AllocFSCommand) Run(args []string) int {
-	var verbose, machine, job, stat, tail, follow bool
+	var verbose, machine, job, stat, tail, follow, overwrite bool
 	var numLines, numBytes int64
+	var put, archive string
 
 	flags := f.Meta.FlagSet(f.Name(), FlagSetClient)
 	flags.Usage = func() { f.Ui.Output(f.Help()) }
@@ -125,6 +147,9 @@ func (f *AllocFSCommand) Run(args []string) int {
 	flags.BoolVar(&tail, "tail", false, "")
 	flags.Int64Var(&numLines, "n", -1, "")
 	flags.Int64Var(&numBytes, "c", -1, "")
+	flags.StringVar(&put, "put", "", "")
+	flags.BoolVar(&overwrite, "overwrite", false, "")
+	flags.StringVar(&archive, "archive", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -147,6 +172,18 @@ func (f *AllocFSCommand) Run(args []string) int {
 		return 1
 	}
 
+	if put != "" && archive != "" {
+		f.Ui.Error("The -put and -archive flags are mutually exclusive")
+		f.Ui.Error(commandErrorText(f))
+		return 1
+	}
+
+	if put != "" && len(args) != 2 {
+		f.Ui.Error("A path is required to upload a file")
+		f.Ui.Error(commandErrorText(f))
+		return 1
+	}
+
 	path := "/"
 	if len(args) == 2 {
 		path = args[1]
@@ -202,6 +239,14 @@ func (f *AllocFSCommand) Run(args []string) int {
 		return 1
 	}
 
+	if put != "" {
+		return f.upload(client, alloc, put, path, overwrite)
+	}
+
+	if archive != "" {
+		return f.archive(client, alloc, path, archive)
+	}
+
 	// Get file stat info
 	file, _, err := client.AllocFS().Stat(alloc, path, nil)
 	if err != nil {
@@ -336,6 +381,88 @@ func (f *AllocFSCommand) Run(args []string) int {
 	return 0
 }
 
+// upload uploads the local file, or stdin if it is "-", to the path in the
+// allocation directory and outputs the info of the uploaded file.
+func (f *AllocFSCommand) upload(client *api.Client, alloc *api.Allocation,
+	localPath, path string, overwrite bool) int {
+
+	var r io.Reader
+	mode := os.FileMode(0644)
+	if localPath == "-" {
+		r = os.Stdin
+	} else {
+		file, err := os.Open(localPath)
+		if err != nil {
+			f.Ui.Error(fmt.Sprintf("Error opening file: %s", err))
+			return 1
+		}
+		defer file.Close()
+
+		fi, err := file.Stat()
+		if err != nil {
+			f.Ui.Error(fmt.Sprintf("Error opening file: %s", err))
+			return 1
+		}
+		if fi.IsDir() {
+			f.Ui.Error(fmt.Sprintf("Error opening file: %q is a directory", localPath))
+			return 1
+		}
+
+		r = file
+		mode = fi.Mode().Perm()
+	}
+
+	info, _, err := client.AllocFS().Upload(alloc, path, r, mode, overwrite, nil)
+	if err != nil {
+		f.Ui.Error(fmt.Sprintf("Error uploading file: %s", err))
+		return 1
+	}
+
+	f.Ui.Output(fmt.Sprintf("Uploaded %s to %s (%s)", localPath, path, humanize.IBytes(uint64(info.Size))))
+	return 0
+}
+
+// archive downloads a tar archive of the path in the allocation directory to
+// the local file, or to stdout if it is "-".
+func (f *AllocFSCommand) archive(client *api.Client, alloc *api.Allocation, path, localPath string) int {
+	compress := strings.HasSuffix(localPath, ".tar.gz") || strings.HasSuffix(localPath, ".tgz")
+	r, err := client.AllocFS().Archive(alloc, path, compress, nil)
+	if err != nil {
+		f.Ui.Error(fmt.Sprintf("Error downloading archive: %s", err))
+		return 1
+	}
+	defer r.Close()
+
+	if localPath == "-" {
+		if _, err := io.Copy(os.Stdout, r); err != nil {
+			f.Ui.Error(fmt.Sprintf("Error downloading archive: %s", err))
+			return 1
+		}
+		return 0
+	}
+
+	file, err := os.Create(localPath)
+	if err != nil {
+		f.Ui.Error(fmt.Sprintf("Error creating file: %s", err))
+		return 1
+	}
+
+	n, err := io.Copy(file, r)
+	if err == nil {
+		err = file.Close()
+	} else {
+		file.Close()
+	}
+	if err != nil {
+		os.Remove(localPath)
+		f.Ui.Error(fmt.Sprintf("Error downloading archive: %s", err))
+		return 1
+	}
+
+	f.Ui.Output(fmt.Sprintf("Downloaded %s to %s (%s)", path, localPath, humanize.IBytes(uint64(n))))
+	return 0
+}
+
 // followFile outputs the contents of the file to stdout relative to the end of
 // the file. If numLines does not equal -1, then tail -n behavior is used.
 func (f *AllocFSCommand) followFile(client *api.Client, alloc *api.Allocation,
@@ -51,6 +51,24 @@ func TestFSCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 
+	// Fails on upload without a path
+	if code := cmd.Run([]string{"-put=foo", "foobar"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "A path is required to upload a file") {
+		t.Fatalf("expected path error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on uploading and archiving at once
+	if code := cmd.Run([]string{"-put=foo", "-archive=bar", "foobar", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "mutually exclusive") {
+		t.Fatalf("expected flag error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
 	// Fails on connection failure
 	if code := cmd.Run([]string{"-address=nope", "foobar"}); code != 1 {
 		t.Fatalf("expected exit code 1, got: %d", code)
@@ -55,6 +55,20 @@ Logs Specific Options:
 
   -c
     Sets the tail location in number of bytes relative to the end of the logs.
+
+  -grep <regexp>
+    Only display the log lines matching the regular expression. Lines are
+    filtered by the client running the allocation so only matching lines are
+    transferred.
+
+  -since <time>
+    Only display the log lines written after the given time. The time is
+    either an RFC3339 timestamp or a duration before now, such as "15m".
+
+  -until <time>
+    Only display the log lines written before the given time, in the same
+    format as -since. When following logs, the command exits once the logs
+    pass the given time.
   `
 	return strings.TrimSpace(helpText)
 }
@@ -73,6 +87,9 @@ func (c *AllocLogsCommand) AutocompleteFlags() complete.Flags {
 			"-tail":    complete.PredictAnything,
 			"-n":       complete.PredictAnything,
 			"-c":       complete.PredictAnything,
+			"-grep":    complete.PredictAnything,
+			"-since":   complete.PredictAnything,
+			"-until":   complete.PredictAnything,
 		})
 }
 
@@ -96,6 +113,7 @@ func (l *AllocLogsCommand) Name() string { return "alloc logs" }
 func (l *AllocLogsCommand) Run(args []string) int {
 	var verbose, job, tail, stderr, follow bool
 	var numLines, numBytes int64
+	var grep, since, until string
 
 	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
 	flags.Usage = func() { l.Ui.Output(l.Help()) }
@@ -106,6 +124,9 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	flags.BoolVar(&stderr, "stderr", false, "")
 	flags.Int64Var(&numLines, "n", -1, "")
 	flags.Int64Var(&numBytes, "c", -1, "")
+	flags.StringVar(&grep, "grep", "", "")
+	flags.StringVar(&since, "since", "", "")
+	flags.StringVar(&until, "until", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -127,6 +148,17 @@ func (l *AllocLogsCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Build the filter of the log lines
+	var filter *api.LogFilter
+	if grep != "" || since != "" || until != "" {
+		filter = &api.LogFilter{
+			Filter: grep,
+			Regex:  true,
+			Since:  since,
+			Until:  until,
+		}
+	}
+
 	client, err := l.Meta.Client()
 	if err != nil {
 		l.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
@@ -203,7 +235,7 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	var r io.ReadCloser
 	var readErr error
 	if !tail {
-		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginStart, 0)
+		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginStart, 0, filter)
 		if readErr != nil {
 			readErr = fmt.Errorf("Error reading file: %v", readErr)
 		}
@@ -222,7 +254,7 @@ func (l *AllocLogsCommand) Run(args []string) int {
 			numLines = defaultTailLines
 		}
 
-		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginEnd, offset)
+		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginEnd, offset, filter)
 
 		// If numLines is set, wrap the reader
 		if numLines != -1 {
@@ -250,12 +282,12 @@ func (l *AllocLogsCommand) Run(args []string) int {
 }
 
 // followFile outputs the contents of the file to stdout relative to the end of
-// the file.
+// the file. If a filter is given, only the matching lines are output.
 func (l *AllocLogsCommand) followFile(client *api.Client, alloc *api.Allocation,
-	follow bool, task, logType, origin string, offset int64) (io.ReadCloser, error) {
+	follow bool, task, logType, origin string, offset int64, filter *api.LogFilter) (io.ReadCloser, error) {
 
 	cancel := make(chan struct{})
-	frames, errCh := client.AllocFS().Logs(alloc, follow, task, logType, origin, offset, cancel, nil)
+	frames, errCh := client.AllocFS().LogsWithFilter(alloc, follow, task, logType, origin, offset, filter, cancel, nil)
 	select {
 	case err := <-errCh:
 		return nil, err
@@ -0,0 +1,245 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocPortForwardCommand struct {
+	Meta
+}
+
+func (c *AllocPortForwardCommand) Help() string {
+	helpText := `
+Usage: nomad alloc port-forward [options] <allocation> [<local-port>:]<remote-port>
+
+  Forward connections to a local port to a port of the given allocation. The
+  connections are streamed through the servers to the allocation's client,
+  which connects to the port of the task, or to the port inside the network
+  namespace of the allocation when it uses bridge networking. Without bridge
+  networking only ports reserved or dynamically allocated to the allocation can
+  be forwarded to. If the local port is omitted, the remote port is used.
+
+  When ACLs are enabled, this command requires a token with the
+  'alloc-port-forward' capability for the allocation's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Port Forward Specific Options:
+
+  -task <task-name>
+    Sets the task whose address is used to reach the port. Defaults to the
+    address of the first task of the allocation with a network.
+
+  -listen-address <address>
+    Sets the local address to listen on. Defaults to 127.0.0.1.
+
+  -verbose
+    Show full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocPortForwardCommand) Synopsis() string {
+	return "Forward local connections to an allocation port"
+}
+
+func (c *AllocPortForwardCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-task":           complete.PredictAnything,
+			"-listen-address": complete.PredictAnything,
+			"-verbose":        complete.PredictNothing,
+		})
+}
+
+func (c *AllocPortForwardCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Allocs]
+	})
+}
+
+func (c *AllocPortForwardCommand) Name() string { return "alloc port-forward" }
+
+func (c *AllocPortForwardCommand) Run(args []string) int {
+	var verbose bool
+	var task, listenAddr string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.StringVar(&task, "task", "", "")
+	flags.StringVar(&listenAddr, "listen-address", "127.0.0.1", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly two arguments
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <alloc-id> [<local-port>:]<remote-port>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	localPort, remotePort, err := parsePortForward(args[1])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	allocID := args[0]
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Query the allocation info
+	if len(allocID) == 1 {
+		c.Ui.Error(fmt.Sprintf("Alloc ID must contain at least two characters."))
+		return 1
+	}
+
+	allocID = sanitizeUUIDPrefix(allocID)
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	allocs, _, err := client.Allocations().PrefixList(allocID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return 1
+	}
+	if len(allocs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+		return 1
+	}
+	if len(allocs) > 1 {
+		// Format the allocs
+		out := formatAllocListStubs(allocs, verbose, length)
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return 1
+	}
+
+	// Prefix lookup matched a single allocation
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	if task != "" {
+		if err := validateTaskExistsInAllocation(task, alloc); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(listenAddr, strconv.Itoa(localPort)))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listening on local port: %s", err))
+		return 1
+	}
+	defer ln.Close()
+
+	c.Ui.Output(fmt.Sprintf("Forwarding from %s -> %d", ln.Addr(), remotePort))
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	// Stop listening on interrupt
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+	go func() {
+		select {
+		case <-signalCh:
+			cancelFn()
+			ln.Close()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0
+			}
+			c.Ui.Error(fmt.Sprintf("Error accepting connection: %s", err))
+			return 1
+		}
+
+		go c.forward(ctx, client, alloc, task, remotePort, conn)
+	}
+}
+
+// forward forwards the connection to the port of the allocation until either
+// side closes it.
+func (c *AllocPortForwardCommand) forward(ctx context.Context, client *api.Client, alloc *api.Allocation,
+	task string, port int, conn net.Conn) {
+
+	defer conn.Close()
+
+	c.Ui.Output(fmt.Sprintf("Handling connection from %s", conn.RemoteAddr()))
+	err := client.Allocations().PortForward(ctx, alloc, task, port, conn, nil)
+	if err != nil && err != context.Canceled {
+		c.Ui.Error(fmt.Sprintf("Error forwarding connection from %s: %s", conn.RemoteAddr(), err))
+	}
+}
+
+// parsePortForward parses a port mapping of the form [<local>:]<remote>
+func parsePortForward(mapping string) (local, remote int, err error) {
+	parts := strings.Split(mapping, ":")
+	if len(parts) > 2 {
+		return 0, 0, fmt.Errorf("Invalid port mapping %q, expected [<local-port>:]<remote-port>", mapping)
+	}
+
+	ports := make([]int, len(parts))
+	for i, p := range parts {
+		port, err := strconv.Atoi(p)
+		if err != nil || port < 0 || port > 65535 {
+			return 0, 0, fmt.Errorf("Invalid port %q in mapping %q", p, mapping)
+		}
+		ports[i] = port
+	}
+
+	remote = ports[len(ports)-1]
+	if remote == 0 {
+		return 0, 0, fmt.Errorf("Invalid remote port 0 in mapping %q", mapping)
+	}
+
+	local = remote
+	if len(ports) == 2 {
+		local = ports[0]
+	}
+	return local, remote, nil
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocPortForwardCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &AllocPortForwardCommand{}
+}
+
+func TestAllocPortForwardCommand_Fails(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	require := require.New(t)
+
+	ui := new(cli.MockUi)
+	cmd := &AllocPortForwardCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on lack of arguments
+	require.Equal(1, cmd.Run([]string{}))
+	require.Contains(ui.ErrorWriter.String(), "This command takes two arguments")
+	ui.ErrorWriter.Reset()
+
+	// Fails on invalid port mapping
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "foobar", "80:90:100"}))
+	require.Contains(ui.ErrorWriter.String(), "Invalid port mapping")
+	ui.ErrorWriter.Reset()
+
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "foobar", "http"}))
+	require.Contains(ui.ErrorWriter.String(), "Invalid port")
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	require.Equal(1, cmd.Run([]string{"-address=nope", "foobar", "8080"}))
+	require.Contains(ui.ErrorWriter.String(), "Error querying allocation")
+	ui.ErrorWriter.Reset()
+
+	// Fails on missing alloc
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "26470238-5CF2-438F-8772-DC67CFB0705C", "8080"}))
+	require.Contains(ui.ErrorWriter.String(), "No allocation(s) with prefix or id")
+	ui.ErrorWriter.Reset()
+
+	// Fail on identifier with too few characters
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "2", "8080"}))
+	require.Contains(ui.ErrorWriter.String(), "must contain at least two characters.")
+	ui.ErrorWriter.Reset()
+}
+
+func TestParsePortForward(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		mapping string
+		local   int
+		remote  int
+		err     bool
+	}{
+		{mapping: "8080", local: 8080, remote: 8080},
+		{mapping: "9090:8080", local: 9090, remote: 8080},
+		{mapping: "0:8080", local: 0, remote: 8080},
+		{mapping: "8080:0", err: true},
+		{mapping: "70000", err: true},
+		{mapping: "a:8080", err: true},
+		{mapping: "1:2:3", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.mapping, func(t *testing.T) {
+			local, remote, err := parsePortForward(c.mapping)
+			if c.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.local, local)
+			require.Equal(t, c.remote, remote)
+		})
+	}
+}
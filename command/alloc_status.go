@@ -284,6 +284,13 @@ func formatAllocBasicInfo(alloc *api.Allocation, client *api.Client, uuidLength
 		basic = append(basic,
 			fmt.Sprintf("Replacement Alloc ID|%s", limit(alloc.NextAllocation, uuidLength)))
 	}
+	if a := alloc.ArchiveStatus; a != nil {
+		if a.Error != "" {
+			basic = append(basic, fmt.Sprintf("Archive Error|%s", a.Error))
+		} else {
+			basic = append(basic, fmt.Sprintf("Archive|%s:%s", a.Volume, a.Path))
+		}
+	}
 	if alloc.FollowupEvalID != "" {
 		nextEvalTime := futureEvalTimePretty(alloc.FollowupEvalID, client)
 		if nextEvalTime != "" {
@@ -496,6 +503,12 @@ func buildDisplayMessage(event *api.TaskEvent) string {
 		desc = event.DriverMessage
 	case api.TaskLeaderDead:
 		desc = "Leader Task in Group dead"
+	case api.TaskMaxRunDurationExceeded:
+		if event.KillReason != "" {
+			desc = event.KillReason
+		} else {
+			desc = "Task exceeded its max run duration"
+		}
 	default:
 		desc = event.Message
 	}
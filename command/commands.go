@@ -149,6 +149,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"alloc port-forward": func() (cli.Command, error) {
+			return &AllocPortForwardCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc signal": func() (cli.Command, error) {
 			return &AllocSignalCommand{
 				Meta: meta,
@@ -241,6 +246,16 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"eval delete": func() (cli.Command, error) {
+			return &EvalDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"eval list": func() (cli.Command, error) {
+			return &EvalListCommand{
+				Meta: meta,
+			}, nil
+		},
 		"eval status": func() (cli.Command, error) {
 			return &EvalStatusCommand{
 				Meta: meta,
@@ -426,6 +441,21 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"node meta": func() (cli.Command, error) {
+			return &NodeMetaCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node meta apply": func() (cli.Command, error) {
+			return &NodeMetaApplyCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node meta read": func() (cli.Command, error) {
+			return &NodeMetaReadCommand{
+				Meta: meta,
+			}, nil
+		},
 		"node-status": func() (cli.Command, error) {
 			return &NodeStatusCommand{
 				Meta: meta,
@@ -469,6 +499,18 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator scheduler": func() (cli.Command, error) {
+			return &OperatorSchedulerCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"operator scheduler simulate": func() (cli.Command, error) {
+			return &OperatorSchedulerSimulateCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"operator raft": func() (cli.Command, error) {
 			return &OperatorRaftCommand{
 				Meta: meta,
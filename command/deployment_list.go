@@ -24,6 +24,15 @@ General Options:
 
 List Options:
 
+  -filter <expression>
+    Only list deployments matching the filter expression.
+
+  -per-page <num>
+    How many results to show per page. Defaults to showing all results.
+
+  -page-token <token>
+    Where to start pagination, as given by the previous page.
+
   -json
     Output the deployments in a JSON format.
 
@@ -39,9 +48,12 @@ List Options:
 func (c *DeploymentListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json":    complete.PredictNothing,
-			"-t":       complete.PredictAnything,
-			"-verbose": complete.PredictNothing,
+			"-filter":     complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
+			"-json":       complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
 		})
 }
 
@@ -57,10 +69,14 @@ func (c *DeploymentListCommand) Name() string { return "deployment list" }
 
 func (c *DeploymentListCommand) Run(args []string) int {
 	var json, verbose bool
-	var tmpl string
+	var tmpl, filter, pageToken string
+	var perPage int
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&filter, "filter", "", "")
+	flags.IntVar(&perPage, "per-page", 0, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
@@ -90,7 +106,12 @@ func (c *DeploymentListCommand) Run(args []string) int {
 		return 1
 	}
 
-	deploys, _, err := client.Deployments().List(nil)
+	q := &api.QueryOptions{
+		Filter:    filter,
+		PerPage:   int32(perPage),
+		NextToken: pageToken,
+	}
+	deploys, qm, err := client.Deployments().List(q)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error retrieving deployments: %s", err))
 		return 1
@@ -108,6 +129,10 @@ func (c *DeploymentListCommand) Run(args []string) int {
 	}
 
 	c.Ui.Output(formatDeployments(deploys, length))
+
+	if qm.NextToken != "" {
+		c.Ui.Output(formatNextPageHint(qm.NextToken))
+	}
 	return 0
 }
 
@@ -190,12 +190,48 @@ func formatDeployment(d *api.Deployment, uuidLength int) string {
 	}
 	base += "\n\n[bold]Deployed[reset]\n"
 	base += formatDeploymentGroups(d, uuidLength)
+
+	if gates := formatHealthGates(d); gates != "" {
+		base += "\n\n[bold]Health Gates[reset]\n"
+		base += gates
+	}
 	return base
 }
 
+// formatHealthGates returns the health gate results of the task groups of a
+// deployment or an empty string if there are none.
+func formatHealthGates(d *api.Deployment) string {
+	var tgNames []string
+	for name, state := range d.TaskGroups {
+		if state.HealthGate != nil {
+			tgNames = append(tgNames, name)
+		}
+	}
+	if len(tgNames) == 0 {
+		return ""
+	}
+	sort.Strings(tgNames)
+
+	rows := make([]string, 0, len(tgNames)+1)
+	rows = append(rows, "Task Group|Phase|Result|Attempts|Description")
+	for _, tg := range tgNames {
+		gate := d.TaskGroups[tg].HealthGate
+		phase, result := "all", "failed"
+		if gate.Canary {
+			phase = "canary"
+		}
+		if gate.Passed {
+			result = "passed"
+		}
+		rows = append(rows, fmt.Sprintf("%s|%s|%s|%d|%s", tg, phase, result, gate.Attempts, gate.Description))
+	}
+
+	return formatList(rows)
+}
+
 func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	// Detect if we need to add these columns
-	var canaries, autorevert, progressDeadline bool
+	var canaries, autorevert, progressDeadline, steps bool
 	tgNames := make([]string, 0, len(d.TaskGroups))
 	for name, state := range d.TaskGroups {
 		tgNames = append(tgNames, name)
@@ -208,6 +244,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 		if state.ProgressDeadline != 0 {
 			progressDeadline = true
 		}
+		if len(state.RolloutSteps) != 0 {
+			steps = true
+		}
 	}
 
 	// Sort the task group names to get a reliable ordering
@@ -226,6 +265,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 		rowString += "Canaries|"
 	}
 	rowString += "Placed|Healthy|Unhealthy"
+	if steps {
+		rowString += "|Rollout Step"
+	}
 	if progressDeadline {
 		rowString += "|Progress Deadline"
 	}
@@ -251,6 +293,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 			row += fmt.Sprintf("%d|", state.DesiredCanaries)
 		}
 		row += fmt.Sprintf("%d|%d|%d", state.PlacedAllocs, state.HealthyAllocs, state.UnhealthyAllocs)
+		if steps {
+			row += fmt.Sprintf("|%s", formatRolloutStep(state))
+		}
 		if progressDeadline {
 			if state.RequireProgressBy.IsZero() {
 				row += fmt.Sprintf("|%v", "N/A")
@@ -264,3 +309,16 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 
 	return formatList(rows)
 }
+
+// formatRolloutStep returns the current rollout step of a deployment state
+// along with the percentage of the group it allows to be updated.
+func formatRolloutStep(state *api.DeploymentState) string {
+	if len(state.RolloutSteps) == 0 {
+		return "N/A"
+	}
+	if state.CurrentStep >= len(state.RolloutSteps) {
+		return fmt.Sprintf("%d/%d (100%%)", len(state.RolloutSteps)+1, len(state.RolloutSteps)+1)
+	}
+	return fmt.Sprintf("%d/%d (%d%%)", state.CurrentStep+1, len(state.RolloutSteps)+1,
+		state.RolloutSteps[state.CurrentStep])
+}
@@ -19,10 +19,18 @@ Usage: nomad eval <subcommand> [options] [args]
   detail but can be useful for debugging placement failures when the cluster
   does not have the resources to run a given job.
 
+  List the evaluations:
+
+      $ nomad eval list
+
   Examine an evaluations status:
 
       $ nomad eval status <eval-id>
 
+  Delete the evaluations of a job while the eval broker is paused:
+
+      $ nomad eval delete -job <job-id>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
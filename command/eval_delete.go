@@ -0,0 +1,210 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+const (
+	// evalDeleteBatchSize is the maximum number of evaluations deleted by a
+	// single request.
+	evalDeleteBatchSize = 1000
+)
+
+type EvalDeleteCommand struct {
+	Meta
+}
+
+func (c *EvalDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad eval delete [options] [<evaluation>...]
+
+  Delete is used to delete evaluations, either the evaluations with the given
+  IDs or ID prefixes, or all the evaluations matching the given filters.
+  Evaluations can only be deleted while the eval broker is paused, which is
+  done by setting PauseEvalBroker in the scheduler configuration. Deleting
+  evaluations is intended to recover a cluster from a backlog of evaluations
+  that can't be processed and should be used with care.
+
+  When ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Eval Delete Options:
+
+  -job <job-id>
+    Delete the evaluations of the given job.
+
+  -status <status>
+    Delete the evaluations with the given status.
+
+  -triggered-by <trigger>
+    Delete the evaluations triggered by the given event.
+
+  -node <node-id>
+    Delete the evaluations triggered by updates of the given node.
+
+  -yes
+    Automatically answer "yes" when asked to confirm deleting the evaluations
+    matching the filters.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalDeleteCommand) Synopsis() string {
+	return "Delete evaluations while the eval broker is paused"
+}
+
+func (c *EvalDeleteCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job":          complete.PredictAnything,
+			"-status":       complete.PredictSet("pending", "blocked", "complete", "failed", "canceled"),
+			"-triggered-by": complete.PredictAnything,
+			"-node":         complete.PredictAnything,
+			"-yes":          complete.PredictNothing,
+		})
+}
+
+func (c *EvalDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Evals, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Evals]
+	})
+}
+
+func (c *EvalDeleteCommand) Name() string { return "eval delete" }
+
+func (c *EvalDeleteCommand) Run(args []string) int {
+	var autoYes bool
+	var filter evalListFilter
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	filter.setFlags(flags)
+	flags.BoolVar(&autoYes, "yes", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got either evaluation IDs or filters
+	args = flags.Args()
+	if len(args) == 0 && !filter.isSet() {
+		c.Ui.Error("This command takes evaluation IDs or filter flags")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if len(args) != 0 && filter.isSet() {
+		c.Ui.Error("Evaluation IDs and filter flags are mutually exclusive")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var evalIDs []string
+	if len(args) != 0 {
+		for _, prefix := range args {
+			evalID, err := resolveEvalID(client, prefix)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+			evalIDs = append(evalIDs, evalID)
+		}
+	} else {
+		q := filter.queryOptions()
+		q.PerPage = evalDeleteBatchSize
+		for {
+			evals, qm, err := client.Evaluations().List(q)
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Error retrieving evaluations: %s", err))
+				return 1
+			}
+			for _, eval := range evals {
+				evalIDs = append(evalIDs, eval.ID)
+			}
+			if qm.NextToken == "" {
+				break
+			}
+			q.NextToken = qm.NextToken
+		}
+
+		if len(evalIDs) == 0 {
+			c.Ui.Output("No evaluations found")
+			return 0
+		}
+
+		if !autoYes {
+			question := fmt.Sprintf("Are you sure you want to delete %d evaluation(s)? [y/N]", len(evalIDs))
+			if ok, code := askConfirmation(c.Ui, question, "Cancelling evaluation deletion"); !ok {
+				return code
+			}
+		}
+	}
+
+	deleted := 0
+	for len(evalIDs) != 0 {
+		n := evalDeleteBatchSize
+		if len(evalIDs) < n {
+			n = len(evalIDs)
+		}
+
+		if _, err := client.Evaluations().Delete(evalIDs[:n], nil); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error deleting evaluations: %s", err))
+			if deleted != 0 {
+				c.Ui.Error(fmt.Sprintf("Deleted %d evaluation(s) before the error", deleted))
+			}
+			return 1
+		}
+		deleted += n
+		evalIDs = evalIDs[n:]
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted %d evaluation(s)", deleted))
+	return 0
+}
+
+// resolveEvalID returns the full ID of the evaluation matching the given
+// prefix.
+func resolveEvalID(client *api.Client, prefix string) (string, error) {
+	if len(prefix) == 1 {
+		return "", fmt.Errorf("Identifier must contain at least two characters.")
+	}
+
+	evalID := sanitizeUUIDPrefix(prefix)
+	evals, _, err := client.Evaluations().PrefixList(evalID)
+	if err != nil {
+		return "", fmt.Errorf("Error querying evaluation: %v", err)
+	}
+	switch len(evals) {
+	case 0:
+		return "", fmt.Errorf("No evaluation(s) with prefix or id %q found", prefix)
+	case 1:
+		return evals[0].ID, nil
+	default:
+		return "", fmt.Errorf("Prefix %q matched multiple evaluations\n\n%s", prefix,
+			formatEvalList(evals, shortId))
+	}
+}
@@ -0,0 +1,219 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type EvalListCommand struct {
+	Meta
+}
+
+func (c *EvalListCommand) Help() string {
+	helpText := `
+Usage: nomad eval list [options]
+
+  List is used to list the set of evaluations tracked by Nomad. Evaluations
+  that were canceled in favor of a more recent evaluation of the same job list
+  the evaluation they were coalesced into.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+List Options:
+
+  -job <job-id>
+    Only list evaluations of the given job.
+
+  -status <status>
+    Only list evaluations with the given status, such as "pending",
+    "blocked", "complete", "failed" or "canceled".
+
+  -triggered-by <trigger>
+    Only list evaluations triggered by the given event, such as
+    "job-register" or "node-update".
+
+  -node <node-id>
+    Only list evaluations triggered by updates of the given node.
+
+  -filter <expression>
+    Only list evaluations matching the filter expression.
+
+  -per-page <num>
+    How many results to show per page. Defaults to showing all results.
+
+  -page-token <token>
+    Where to start pagination, as given by the previous page.
+
+  -json
+    Output the evaluations in a JSON format.
+
+  -t
+    Format and display the evaluations using a Go template.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job":          complete.PredictAnything,
+			"-status":       complete.PredictSet("pending", "blocked", "complete", "failed", "canceled"),
+			"-triggered-by": complete.PredictAnything,
+			"-node":         complete.PredictAnything,
+			"-filter":       complete.PredictAnything,
+			"-per-page":     complete.PredictAnything,
+			"-page-token":   complete.PredictAnything,
+			"-json":         complete.PredictNothing,
+			"-t":            complete.PredictAnything,
+			"-verbose":      complete.PredictNothing,
+		})
+}
+
+func (c *EvalListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *EvalListCommand) Synopsis() string {
+	return "List all evaluations"
+}
+
+func (c *EvalListCommand) Name() string { return "eval list" }
+
+func (c *EvalListCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl, filterExpr, pageToken string
+	var perPage int
+	var filter evalListFilter
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	filter.setFlags(flags)
+	flags.StringVar(&filterExpr, "filter", "", "")
+	flags.IntVar(&perPage, "per-page", 0, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	q := filter.queryOptions()
+	q.Filter = filterExpr
+	q.PerPage = int32(perPage)
+	q.NextToken = pageToken
+	evals, qm, err := client.Evaluations().List(q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving evaluations: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, evals)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatEvalList(evals, length))
+
+	if qm.NextToken != "" {
+		c.Ui.Output(formatNextPageHint(qm.NextToken))
+	}
+	return 0
+}
+
+// evalListFilter is the set of flags used to filter evaluations
+type evalListFilter struct {
+	jobID       string
+	status      string
+	triggeredBy string
+	nodeID      string
+}
+
+func (f *evalListFilter) setFlags(flags *flag.FlagSet) {
+	flags.StringVar(&f.jobID, "job", "", "")
+	flags.StringVar(&f.status, "status", "", "")
+	flags.StringVar(&f.triggeredBy, "triggered-by", "", "")
+	flags.StringVar(&f.nodeID, "node", "", "")
+}
+
+// isSet returns whether any of the filters is set
+func (f *evalListFilter) isSet() bool {
+	return f.jobID != "" || f.status != "" || f.triggeredBy != "" || f.nodeID != ""
+}
+
+// queryOptions returns the query options used to list the evaluations
+// matching the filters.
+func (f *evalListFilter) queryOptions() *api.QueryOptions {
+	params := make(map[string]string)
+	if f.jobID != "" {
+		params["job"] = f.jobID
+	}
+	if f.status != "" {
+		params["status"] = f.status
+	}
+	if f.triggeredBy != "" {
+		params["triggered_by"] = f.triggeredBy
+	}
+	if f.nodeID != "" {
+		params["node"] = f.nodeID
+	}
+	return &api.QueryOptions{Params: params}
+}
+
+func formatEvalList(evals []*api.Evaluation, uuidLength int) string {
+	if len(evals) == 0 {
+		return "No evaluations found"
+	}
+
+	rows := make([]string, len(evals)+1)
+	rows[0] = "ID|Priority|Triggered By|Job ID|Status|Placement Failures|Coalesced Into"
+	for i, eval := range evals {
+		failures, _ := evalFailureStatus(eval)
+		rows[i+1] = fmt.Sprintf("%s|%d|%s|%s|%s|%s|%s",
+			limit(eval.ID, uuidLength),
+			eval.Priority,
+			eval.TriggeredBy,
+			eval.JobID,
+			eval.Status,
+			failures,
+			limit(eval.CoalescedInto, uuidLength))
+	}
+	return formatList(rows)
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalListCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &EvalListCommand{}
+}
+
+func TestEvalListCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &EvalListCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving evaluations") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestEvalListCommand_FormatCoalesced(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	require.Equal("No evaluations found", formatEvalList(nil, shortId))
+
+	evals := []*api.Evaluation{
+		{
+			ID:          "22222222-2222-2222-2222-222222222222",
+			Priority:    50,
+			TriggeredBy: "node-update",
+			JobID:       "example",
+			Status:      "pending",
+		},
+		{
+			ID:            "11111111-1111-1111-1111-111111111111",
+			Priority:      50,
+			TriggeredBy:   "node-update",
+			JobID:         "example",
+			Status:        "canceled",
+			CoalescedInto: "22222222-2222-2222-2222-222222222222",
+		},
+	}
+
+	out := formatEvalList(evals, shortId)
+	lines := strings.Split(out, "\n")
+	require.Len(lines, 3)
+	require.Contains(lines[0], "Coalesced Into")
+	require.True(strings.HasPrefix(lines[2], "11111111"))
+	require.True(strings.HasSuffix(strings.TrimSpace(lines[2]), "22222222"))
+}
@@ -32,6 +32,11 @@ Eval Status Options:
   -monitor
     Monitor an outstanding evaluation
 
+  -explain
+    Display why each node was rejected for the failed placements. The trace
+    is only recorded if the placement trace is enabled in the scheduler
+    configuration.
+
   -verbose
     Show full information.
 
@@ -52,6 +57,7 @@ func (c *EvalStatusCommand) Synopsis() string {
 func (c *EvalStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
+			"-explain": complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-monitor": complete.PredictNothing,
 			"-t":       complete.PredictAnything,
@@ -81,12 +87,13 @@ func (c *EvalStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *EvalStatusCommand) Name() string { return "eval status" }
 
 func (c *EvalStatusCommand) Run(args []string) int {
-	var monitor, verbose, json bool
+	var monitor, verbose, json, explain bool
 	var tmpl string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&monitor, "monitor", false, "")
+	flags.BoolVar(&explain, "explain", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
@@ -238,6 +245,11 @@ func (c *EvalStatusCommand) Run(args []string) int {
 			fmt.Sprintf("Wait Until|%s", formatTime(eval.WaitUntil)))
 	}
 
+	if eval.CoalescedInto != "" {
+		basic = append(basic,
+			fmt.Sprintf("Coalesced Into|%s", limit(eval.CoalescedInto, length)))
+	}
+
 	if verbose {
 		// NextEval, PreviousEval, BlockedEval
 		basic = append(basic,
@@ -259,6 +271,14 @@ func (c *EvalStatusCommand) Run(args []string) int {
 			}
 			c.Ui.Output(fmt.Sprintf("Task Group %q (failed to place %d %s):", tg, metrics.CoalescedFailures+1, noun))
 			c.Ui.Output(formatAllocMetrics(metrics, false, "  "))
+			if explain {
+				c.Ui.Output("")
+				if trace := formatPlacementTrace(metrics, "  "); trace != "" {
+					c.Ui.Output(trace)
+				} else {
+					c.Ui.Output("  No placement trace was recorded for the evaluation")
+				}
+			}
 			c.Ui.Output("")
 		}
 
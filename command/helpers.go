@@ -59,6 +59,33 @@ func limit(s string, length int) string {
 	return s[:length]
 }
 
+// formatNextPageHint returns the message displayed after a paginated list,
+// with the command line to get the next page.
+func formatNextPageHint(nextToken string) string {
+	return fmt.Sprintf(`
+Results have been paginated. To get the next page run:
+
+%s -page-token %s`, argsWithoutPageToken(os.Args), nextToken)
+}
+
+// argsWithoutPageToken returns the command line without the page token, so
+// the next page can be requested by appending a new one.
+func argsWithoutPageToken(osArgs []string) string {
+	args := []string{}
+	for i := 0; i < len(osArgs); i++ {
+		arg := osArgs[i]
+		switch {
+		case arg == "-page-token" || arg == "--page-token":
+			i++
+			continue
+		case strings.HasPrefix(arg, "-page-token=") || strings.HasPrefix(arg, "--page-token="):
+			continue
+		}
+		args = append(args, arg)
+	}
+	return strings.Join(args, " ")
+}
+
 // wrapAtLengthWithPadding wraps the given text at the maxLineLength, taking
 // into account any provided left padding.
 func wrapAtLengthWithPadding(s string, pad int) string {
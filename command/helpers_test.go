@@ -46,6 +46,24 @@ func TestHelpers_FormatList(t *testing.T) {
 	}
 }
 
+func TestHelpers_ArgsWithoutPageToken(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"nomad", "eval", "list", "-per-page", "10"}, "nomad eval list -per-page 10"},
+		{[]string{"nomad", "eval", "list", "-per-page", "10", "-page-token", "abc"}, "nomad eval list -per-page 10"},
+		{[]string{"nomad", "status", "-page-token=abc", "-per-page=10"}, "nomad status -per-page=10"},
+		{[]string{"nomad", "node", "status", "--page-token", "abc", "-verbose"}, "nomad node status -verbose"},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.expected, argsWithoutPageToken(tc.args))
+	}
+}
+
 func TestHelpers_NodeID(t *testing.T) {
 	t.Parallel()
 	srv, _, _ := testServer(t, false, nil)
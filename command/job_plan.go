@@ -71,6 +71,10 @@ Plan Options:
     Determines whether the diff between the remote job and planned job is shown.
     Defaults to true.
 
+  -explain
+    Display why each node was rejected for the allocations that failed to
+    be placed.
+
   -policy-override
     Sets the flag to force override any soft mandatory Sentinel policies.
 
@@ -88,6 +92,7 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-diff":            complete.PredictNothing,
+			"-explain":         complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
 			"-verbose":         complete.PredictNothing,
 		})
@@ -99,12 +104,13 @@ func (c *JobPlanCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *JobPlanCommand) Name() string { return "job plan" }
 func (c *JobPlanCommand) Run(args []string) int {
-	var diff, policyOverride, verbose bool
+	var diff, policyOverride, verbose, explain bool
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&diff, "diff", true, "")
 	flags.BoolVar(&policyOverride, "policy-override", false, "")
+	flags.BoolVar(&explain, "explain", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 
 	if err := flags.Parse(args); err != nil {
@@ -152,6 +158,9 @@ func (c *JobPlanCommand) Run(args []string) int {
 	if policyOverride {
 		opts.PolicyOverride = true
 	}
+	if explain {
+		opts.Explain = true
+	}
 
 	// Submit the job
 	resp, _, err := client.Jobs().PlanOpts(job, opts, nil)
@@ -171,6 +180,11 @@ func (c *JobPlanCommand) Run(args []string) int {
 	c.Ui.Output(c.Colorize().Color(formatDryRun(resp, job)))
 	c.Ui.Output("")
 
+	// Print the placement trace of the failed placements if explaining
+	if explain && len(resp.FailedTGAllocs) > 0 {
+		c.addPlacementTrace(resp)
+	}
+
 	// Print any warnings if there are any
 	if resp.Warnings != "" {
 		c.Ui.Output(
@@ -187,6 +201,20 @@ func (c *JobPlanCommand) Run(args []string) int {
 	return getExitCode(resp)
 }
 
+// addPlacementTrace shows why each node was rejected for the failed placements
+func (c *JobPlanCommand) addPlacementTrace(resp *api.JobPlanResponse) {
+	c.Ui.Output(c.Colorize().Color("[bold]Placement Trace:[reset]"))
+	for _, tg := range sortedTaskGroupFromMetrics(resp.FailedTGAllocs) {
+		c.Ui.Output(fmt.Sprintf("Task Group %q:", tg))
+		if trace := formatPlacementTrace(resp.FailedTGAllocs[tg], "  "); trace != "" {
+			c.Ui.Output(trace)
+		} else {
+			c.Ui.Output("  No nodes were rejected")
+		}
+		c.Ui.Output("")
+	}
+}
+
 // addPreemptions shows details about preempted allocations
 func (c *JobPlanCommand) addPreemptions(resp *api.JobPlanResponse) {
 	c.Ui.Output(c.Colorize().Color("[bold][yellow]Preemptions:\n[reset]"))
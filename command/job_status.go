@@ -24,6 +24,9 @@ type JobStatusCommand struct {
 	evals     bool
 	allAllocs bool
 	verbose   bool
+	filter    string
+	perPage   int
+	pageToken string
 }
 
 func (c *JobStatusCommand) Help() string {
@@ -52,6 +55,17 @@ Status Options:
 
   -verbose
     Display full information.
+
+  -filter <expression>
+    Only list jobs matching the filter expression. Used only when no job is
+    being queried.
+
+  -per-page <num>
+    How many jobs to list per page. Used only when no job is being queried.
+    Defaults to listing all jobs.
+
+  -page-token <token>
+    Where to start pagination, as given by the previous page.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -67,6 +81,9 @@ func (c *JobStatusCommand) AutocompleteFlags() complete.Flags {
 			"-evals":      complete.PredictNothing,
 			"-short":      complete.PredictNothing,
 			"-verbose":    complete.PredictNothing,
+			"-filter":     complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
 		})
 }
 
@@ -96,6 +113,9 @@ func (c *JobStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.evals, "evals", false, "")
 	flags.BoolVar(&c.allAllocs, "all-allocs", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
+	flags.StringVar(&c.filter, "filter", "", "")
+	flags.IntVar(&c.perPage, "per-page", 0, "")
+	flags.StringVar(&c.pageToken, "page-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -124,7 +144,12 @@ func (c *JobStatusCommand) Run(args []string) int {
 
 	// Invoke list mode if no job ID.
 	if len(args) == 0 {
-		jobs, _, err := client.Jobs().List(nil)
+		q := &api.QueryOptions{
+			Filter:    c.filter,
+			PerPage:   int32(c.perPage),
+			NextToken: c.pageToken,
+		}
+		jobs, qm, err := client.Jobs().List(q)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying jobs: %s", err))
 			return 1
@@ -136,6 +161,10 @@ func (c *JobStatusCommand) Run(args []string) int {
 		} else {
 			c.Ui.Output(createStatusListOutput(jobs))
 		}
+
+		if qm.NextToken != "" {
+			c.Ui.Output(formatNextPageHint(qm.NextToken))
+		}
 		return 0
 	}
 
@@ -339,6 +368,7 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 	// Determine latest evaluation with failures whose follow up hasn't
 	// completed, this is done while formatting
 	var latestFailedPlacement *api.Evaluation
+	var dependencyEval *api.Evaluation
 	blockedEval := false
 
 	// Format the evals
@@ -356,6 +386,9 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 
 		if eval.Status == "blocked" {
 			blockedEval = true
+			if len(eval.UnmetDependencies) != 0 {
+				dependencyEval = eval
+			}
 		}
 
 		if len(eval.FailedTGAllocs) == 0 {
@@ -377,6 +410,10 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 		c.outputFailedPlacements(latestFailedPlacement)
 	}
 
+	if dependencyEval != nil {
+		c.outputUnmetDependencies(job, dependencyEval)
+	}
+
 	c.outputReschedulingEvals(client, job, jobAllocs, c.length)
 
 	if latestDeployment != nil {
@@ -638,6 +675,26 @@ func (c *JobStatusCommand) outputFailedPlacements(failedEval *api.Evaluation) {
 	}
 }
 
+// outputUnmetDependencies prints the dependencies of the job that the blocked
+// evaluation is waiting on.
+func (c *JobStatusCommand) outputUnmetDependencies(job *api.Job, blockedEval *api.Evaluation) {
+	statuses := make(map[string]string, len(job.DependsOn))
+	for _, dep := range job.DependsOn {
+		if dep.Status != nil {
+			statuses[dep.JobID] = *dep.Status
+		}
+	}
+
+	deps := make([]string, len(blockedEval.UnmetDependencies)+1)
+	deps[0] = "Job ID|Required Status|Reason"
+	for i, id := range blockedEval.UnmetDependencies {
+		deps[i+1] = fmt.Sprintf("%s|%s|%s", id, statuses[id], blockedEval.UnmetDependencyReasons[id])
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Unmet Dependencies[reset]"))
+	c.Ui.Output(formatList(deps))
+}
+
 // list general information about a list of jobs
 func createStatusListOutput(jobs []*api.JobListStub) string {
 	out := make([]string, len(jobs)+1)
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
 )
 
 const (
@@ -306,6 +307,16 @@ func (m *monitor) monitor(evalID string, allowPrefix bool) int {
 			continue
 		}
 
+		// Monitor the eval this one was coalesced into, if present
+		if eval.CoalescedInto != "" {
+			m.ui.Info(fmt.Sprintf("Evaluation %q was coalesced into evaluation %q",
+				limit(eval.ID, m.length), limit(eval.CoalescedInto, m.length)))
+
+			// Reset the state and monitor the new eval
+			m.state = newEvalState()
+			return m.monitor(eval.CoalescedInto, allowPrefix)
+		}
+
 		// Monitor the next eval in the chain, if present
 		if eval.NextEval != "" {
 			if eval.Wait.Nanoseconds() != 0 {
@@ -415,3 +426,32 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 	out = strings.TrimSuffix(out, "\n")
 	return out
 }
+
+// formatPlacementTrace formats the reasons nodes were rejected for a
+// placement, recorded when the placement is explained. It returns an empty
+// string if no trace was recorded.
+func formatPlacementTrace(metrics *api.AllocationMetric, prefix string) string {
+	if len(metrics.PlacementTrace) == 0 {
+		return ""
+	}
+
+	// Reasons may contain the default delimiter, such as in regexp
+	// constraints, so use a tab instead
+	out := make([]string, 0, len(metrics.PlacementTrace)+1)
+	out = append(out, "Node ID\tNode Name\tChecker\tReason")
+	for _, trace := range metrics.PlacementTrace {
+		out = append(out, fmt.Sprintf("%s\t%s\t%s\t%s",
+			limit(trace.NodeID, shortId), trace.NodeName, trace.Checker, trace.Reason))
+	}
+
+	columnConf := columnize.DefaultConfig()
+	columnConf.Delim = "\t"
+	columnConf.Prefix = prefix
+	columnConf.Empty = "<none>"
+	formatted := columnize.Format(out, columnConf)
+
+	if dropped := metrics.PlacementTraceDropped; dropped > 0 {
+		formatted += fmt.Sprintf("\n%s(%d more rejected nodes not recorded)", prefix, dropped)
+	}
+	return formatted
+}
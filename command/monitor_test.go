@@ -5,8 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMonitor_Update_Eval(t *testing.T) {
@@ -285,3 +287,30 @@ func TestMonitor_MonitorWithPrefix(t *testing.T) {
 	}
 
 }
+
+func TestMonitor_FormatPlacementTrace(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Nothing is formatted without a trace
+	require.Empty(formatPlacementTrace(&api.AllocationMetric{}, "  "))
+
+	metrics := &api.AllocationMetric{
+		PlacementTrace: []*api.NodePlacementTrace{
+			{
+				NodeID:   "3f5f7a29-1f4e-2b7c-9ad5-2b3cd5f2e1a0",
+				NodeName: "node1",
+				Checker:  "constraint",
+				Reason:   `${attr.kernel.name} regexp linux|darwin: ${attr.kernel.name} is "windows" on the node`,
+			},
+		},
+		PlacementTraceDropped: 3,
+	}
+	out := formatPlacementTrace(metrics, "  ")
+	lines := strings.Split(out, "\n")
+	require.Len(lines, 3)
+	require.True(strings.HasPrefix(lines[0], "  Node ID"))
+	require.Contains(lines[1], "3f5f7a29")
+	require.Contains(lines[1], "linux|darwin")
+	require.Equal("  (3 more rejected nodes not recorded)", lines[2])
+}
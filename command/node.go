@@ -1,8 +1,10 @@
 package command
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
 )
 
@@ -47,3 +49,94 @@ func (f *NodeCommand) Name() string { return "node" }
 func (f *NodeCommand) Run(args []string) int {
 	return cli.RunResultHelp
 }
+
+// nodeSelectorHelp is the help text for the flags used to select nodes by
+// their properties.
+const nodeSelectorHelp = `
+  -dc <datacenter>
+    Select all nodes in the given datacenter instead of a single node.
+
+  -class <node class>
+    Select all nodes of the given node class instead of a single node.
+
+  -filter <expression>
+    Select all nodes satisfying the given constraint expression instead of a
+    single node. The expression takes the form "<attribute> <operator> <value>",
+    such as "${meta.rack} = r1" or "${attr.kernel.name} = linux", and uses the
+    same operators as job constraints. May be specified multiple times, in
+    which case a node must satisfy every filter.`
+
+// parseNodeSelector builds a node selector from the selector flags. A nil
+// selector is returned if no selector flags were set.
+func parseNodeSelector(datacenter, class string, filters []string) (*api.NodeSelector, error) {
+	if datacenter == "" && class == "" && len(filters) == 0 {
+		return nil, nil
+	}
+
+	selector := &api.NodeSelector{
+		Datacenter: datacenter,
+		NodeClass:  class,
+	}
+	for _, f := range filters {
+		c, err := parseNodeFilter(f)
+		if err != nil {
+			return nil, err
+		}
+		selector.Filters = append(selector.Filters, c)
+	}
+	return selector, nil
+}
+
+// parseNodeFilter parses a filter expression of the form
+// "<attribute> <operator> [value]" into a constraint.
+func parseNodeFilter(filter string) (*api.Constraint, error) {
+	fields := strings.Fields(filter)
+	switch len(fields) {
+	case 0, 1:
+		return nil, fmt.Errorf("invalid filter %q: expected \"<attribute> <operator> <value>\"", filter)
+	case 2:
+		return api.NewConstraint(fields[0], fields[1], ""), nil
+	default:
+		return api.NewConstraint(fields[0], fields[1], strings.Join(fields[2:], " ")), nil
+	}
+}
+
+// formatNodeSelector returns a human readable description of the selector.
+func formatNodeSelector(selector *api.NodeSelector) string {
+	var parts []string
+	if selector.Datacenter != "" {
+		parts = append(parts, fmt.Sprintf("datacenter %q", selector.Datacenter))
+	}
+	if selector.NodeClass != "" {
+		parts = append(parts, fmt.Sprintf("class %q", selector.NodeClass))
+	}
+	for _, c := range selector.Filters {
+		expr := strings.TrimSpace(fmt.Sprintf("%s %s %s", c.LTarget, c.Operand, c.RTarget))
+		parts = append(parts, fmt.Sprintf("filter %q", expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// askConfirmation asks the user the given question and returns whether to
+// proceed. If not, the exit code the command should return is also given.
+func askConfirmation(ui cli.Ui, question, cancelMsg string) (bool, int) {
+	answer, err := ui.Ask(question)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to parse answer: %v", err))
+		return false, 1
+	}
+
+	if answer == "" || strings.ToLower(answer)[0] == 'n' {
+		// No case
+		ui.Output(cancelMsg)
+		return false, 0
+	} else if strings.ToLower(answer)[0] == 'y' && len(answer) > 1 {
+		// Non exact match yes
+		ui.Output("For confirmation, an exact ‘y’ is required.")
+		return false, 0
+	} else if answer != "y" {
+		ui.Output("No confirmation detected. For confirmation, an exact 'y' is required.")
+		return false, 1
+	}
+	return true, 0
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/posener/complete"
 )
 
@@ -29,6 +30,11 @@ Usage: nomad node drain [options] <node>
   that either -enable or -disable is specified, but not both.
   The -self flag is useful to drain the local node.
 
+  Alternatively, the -dc, -class and -filter flags can be used to toggle
+  draining on every node matching them. The matching nodes are resolved by
+  the servers and updated together. Draining many nodes does not enter
+  monitor mode.
+
 General Options:
 
   ` + generalOptionsUsage() + `
@@ -70,6 +76,7 @@ Node Drain Options:
 
   -self
     Set the drain status of the local node.
+` + nodeSelectorHelp + `
 
   -yes
     Automatic yes to prompts.
@@ -93,6 +100,9 @@ func (c *NodeDrainCommand) AutocompleteFlags() complete.Flags {
 			"-ignore-system":   complete.PredictNothing,
 			"-keep-ineligible": complete.PredictNothing,
 			"-self":            complete.PredictNothing,
+			"-dc":              complete.PredictAnything,
+			"-class":           complete.PredictAnything,
+			"-filter":          complete.PredictAnything,
 			"-yes":             complete.PredictNothing,
 		})
 }
@@ -118,7 +128,8 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	var enable, disable, detach, force,
 		noDeadline, ignoreSystem, keepIneligible,
 		self, autoYes, monitor bool
-	var deadline string
+	var deadline, datacenter, class string
+	var filters []string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -133,6 +144,9 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	flags.BoolVar(&self, "self", false, "")
 	flags.BoolVar(&autoYes, "yes", false, "Automatic yes to prompts.")
 	flags.BoolVar(&monitor, "monitor", false, "Monitor drain status.")
+	flags.StringVar(&datacenter, "dc", "", "")
+	flags.StringVar(&class, "class", "", "")
+	flags.Var((*flaghelper.StringFlag)(&filters), "filter", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -152,9 +166,27 @@ func (c *NodeDrainCommand) Run(args []string) int {
 		return 1
 	}
 
+	selector, err := parseNodeSelector(datacenter, class, filters)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Check that we got a node ID
 	args = flags.Args()
-	if l := len(args); self && l != 0 || !self && l != 1 {
+	if selector != nil {
+		if self || len(args) != 0 {
+			c.Ui.Error("-dc, -class and -filter can't be combined with a node ID or -self")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+		if monitor {
+			c.Ui.Error("The -monitor flag cannot be used with -dc, -class or -filter")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+	} else if l := len(args); self && l != 0 || !self && l != 1 {
 		c.Ui.Error("Node ID must be specified if -self isn't being used")
 		c.Ui.Error(commandErrorText(c))
 		return 1
@@ -206,6 +238,18 @@ func (c *NodeDrainCommand) Run(args []string) int {
 		return 1
 	}
 
+	var spec *api.DrainSpec
+	if enable {
+		spec = &api.DrainSpec{
+			Deadline:         d,
+			IgnoreSystemJobs: ignoreSystem,
+		}
+	}
+
+	if selector != nil {
+		return c.drainSelector(client, selector, spec, !keepIneligible, autoYes)
+	}
+
 	// If -self flag is set then determine the current node.
 	var nodeID string
 	if !self {
@@ -266,31 +310,8 @@ func (c *NodeDrainCommand) Run(args []string) int {
 			verb = "disable"
 		}
 		question := fmt.Sprintf("Are you sure you want to %s drain mode for node %q? [y/N]", verb, node.ID)
-		answer, err := c.Ui.Ask(question)
-		if err != nil {
-			c.Ui.Error(fmt.Sprintf("Failed to parse answer: %v", err))
-			return 1
-		}
-
-		if answer == "" || strings.ToLower(answer)[0] == 'n' {
-			// No case
-			c.Ui.Output("Canceling drain toggle")
-			return 0
-		} else if strings.ToLower(answer)[0] == 'y' && len(answer) > 1 {
-			// Non exact match yes
-			c.Ui.Output("For confirmation, an exact ‘y’ is required.")
-			return 0
-		} else if answer != "y" {
-			c.Ui.Output("No confirmation detected. For confirmation, an exact 'y' is required.")
-			return 1
-		}
-	}
-
-	var spec *api.DrainSpec
-	if enable {
-		spec = &api.DrainSpec{
-			Deadline:         d,
-			IgnoreSystemJobs: ignoreSystem,
+		if ok, code := askConfirmation(c.Ui, question, "Canceling drain toggle"); !ok {
+			return code
 		}
 	}
 
@@ -333,3 +354,36 @@ func (c *NodeDrainCommand) monitorDrain(client *api.Client, ctx context.Context,
 		}
 	}
 }
+
+// drainSelector toggles draining on all nodes matching the selector.
+func (c *NodeDrainCommand) drainSelector(client *api.Client, selector *api.NodeSelector,
+	spec *api.DrainSpec, markEligible, autoYes bool) int {
+
+	// Always confirm since the selector may match many nodes.
+	if !autoYes {
+		verb := "enable"
+		if spec == nil {
+			verb = "disable"
+		}
+		question := fmt.Sprintf("Are you sure you want to %s drain mode for all nodes matching %s? [y/N]",
+			verb, formatNodeSelector(selector))
+		if ok, code := askConfirmation(c.Ui, question, "Canceling drain toggle"); !ok {
+			return code
+		}
+	}
+
+	resp, err := client.Nodes().BatchUpdateDrain(selector, spec, markEligible, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error updating drain specification: %s", err))
+		return 1
+	}
+
+	for _, nodeID := range resp.NodeIDs {
+		if spec != nil {
+			c.Ui.Output(fmt.Sprintf("Node %q drain strategy set", nodeID))
+		} else {
+			c.Ui.Output(fmt.Sprintf("Node %q drain strategy unset", nodeID))
+		}
+	}
+	return 0
+}
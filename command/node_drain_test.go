@@ -409,6 +409,42 @@ func TestNodeDrainCommand_Fails(t *testing.T) {
 		}
 		ui.ErrorWriter.Reset()
 	}
+
+	// Fail on combining a node selector with a node ID
+	if code := cmd.Run([]string{"-address=" + url, "-enable", "-dc=dc1", "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "can't be combined with a node ID") {
+		t.Fatalf("got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fail on monitoring a node selector
+	if code := cmd.Run([]string{"-address=" + url, "-monitor", "-class=foo"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-monitor flag cannot be used with -dc") {
+		t.Fatalf("got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fail on a malformed filter
+	if code := cmd.Run([]string{"-address=" + url, "-enable", "-filter=${meta.rack}"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "invalid filter") {
+		t.Fatalf("got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fail on a node selector matching no nodes
+	if code := cmd.Run([]string{"-address=" + url, "-enable", "-yes", "-dc=nope"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "no nodes matched") {
+		t.Fatalf("got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
 }
 
 func TestNodeDrainCommand_AutocompleteArgs(t *testing.T) {
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/posener/complete"
 )
 
@@ -23,6 +25,10 @@ Usage: nomad node eligibility [options] <node>
   It is required that either -enable or -disable is specified, but not both.
   The -self flag is useful to set the scheduling eligibility of the local node.
 
+  Alternatively, the -dc, -class and -filter flags can be used to set the
+  scheduling eligibility of every node matching them. The matching nodes are
+  resolved by the servers and updated together.
+
 General Options:
 
   ` + generalOptionsUsage() + `
@@ -37,6 +43,10 @@ Node Eligibility Options:
 
   -self
     Set the eligibility of the local node.
+` + nodeSelectorHelp + `
+
+  -yes
+    Automatic yes to prompts.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -51,6 +61,10 @@ func (c *NodeEligibilityCommand) AutocompleteFlags() complete.Flags {
 			"-disable": complete.PredictNothing,
 			"-enable":  complete.PredictNothing,
 			"-self":    complete.PredictNothing,
+			"-dc":      complete.PredictAnything,
+			"-class":   complete.PredictAnything,
+			"-filter":  complete.PredictAnything,
+			"-yes":     complete.PredictNothing,
 		})
 }
 
@@ -72,13 +86,19 @@ func (c *NodeEligibilityCommand) AutocompleteArgs() complete.Predictor {
 func (c *NodeEligibilityCommand) Name() string { return "node-eligibility" }
 
 func (c *NodeEligibilityCommand) Run(args []string) int {
-	var enable, disable, self bool
+	var enable, disable, self, autoYes bool
+	var datacenter, class string
+	var filters []string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&enable, "enable", false, "Mark node as eligibile for scheduling")
 	flags.BoolVar(&disable, "disable", false, "Mark node as ineligibile for scheduling")
 	flags.BoolVar(&self, "self", false, "")
+	flags.BoolVar(&autoYes, "yes", false, "Automatic yes to prompts.")
+	flags.StringVar(&datacenter, "dc", "", "")
+	flags.StringVar(&class, "class", "", "")
+	flags.Var((*flaghelper.StringFlag)(&filters), "filter", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -91,9 +111,22 @@ func (c *NodeEligibilityCommand) Run(args []string) int {
 		return 1
 	}
 
+	selector, err := parseNodeSelector(datacenter, class, filters)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Check that we got a node ID
 	args = flags.Args()
-	if l := len(args); self && l != 0 || !self && l != 1 {
+	if selector != nil {
+		if self || len(args) != 0 {
+			c.Ui.Error("-dc, -class and -filter can't be combined with a node ID or -self")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+	} else if l := len(args); self && l != 0 || !self && l != 1 {
 		c.Ui.Error("Node ID must be specified if -self isn't being used")
 		c.Ui.Error(commandErrorText(c))
 		return 1
@@ -106,6 +139,10 @@ func (c *NodeEligibilityCommand) Run(args []string) int {
 		return 1
 	}
 
+	if selector != nil {
+		return c.toggleSelector(client, selector, enable, autoYes)
+	}
+
 	// If -self flag is set then determine the current node.
 	var nodeID string
 	if !self {
@@ -161,3 +198,35 @@ func (c *NodeEligibilityCommand) Run(args []string) int {
 	}
 	return 0
 }
+
+// toggleSelector sets the scheduling eligibility of all nodes matching the
+// selector.
+func (c *NodeEligibilityCommand) toggleSelector(client *api.Client, selector *api.NodeSelector, enable, autoYes bool) int {
+	// Always confirm since the selector may match many nodes.
+	if !autoYes {
+		state := "eligible"
+		if !enable {
+			state = "ineligible"
+		}
+		question := fmt.Sprintf("Are you sure you want to mark all nodes matching %s as %s for scheduling? [y/N]",
+			formatNodeSelector(selector), state)
+		if ok, code := askConfirmation(c.Ui, question, "Canceling eligibility toggle"); !ok {
+			return code
+		}
+	}
+
+	resp, err := client.Nodes().BatchToggleEligibility(selector, enable, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error updating scheduling eligibility: %s", err))
+		return 1
+	}
+
+	for _, nodeID := range resp.NodeIDs {
+		if enable {
+			c.Ui.Output(fmt.Sprintf("Node %q scheduling eligibility set: eligible for scheduling", nodeID))
+		} else {
+			c.Ui.Output(fmt.Sprintf("Node %q scheduling eligibility set: ineligible for scheduling", nodeID))
+		}
+	}
+	return 0
+}
@@ -87,6 +87,24 @@ func TestNodeEligibilityCommand_Fails(t *testing.T) {
 		t.Fatalf("expected not exist error, got: %s", out)
 	}
 	ui.ErrorWriter.Reset()
+
+	// Fail on combining a node selector with -self
+	if code := cmd.Run([]string{"-address=" + url, "-enable", "-self", "-class=foo"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "can't be combined with a node ID or -self") {
+		t.Fatalf("got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fail on a node selector matching no nodes
+	if code := cmd.Run([]string{"-address=" + url, "-disable", "-yes", "-filter=${meta.rack} = r1"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "no nodes matched") {
+		t.Fatalf("expected no match error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
 }
 
 func TestNodeEligibilityCommand_AutocompleteArgs(t *testing.T) {
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type NodeMetaCommand struct {
+	Meta
+}
+
+func (c *NodeMetaCommand) Help() string {
+	helpText := `
+Usage: nomad node meta <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with the metadata of client
+  nodes. Metadata updated with these commands is applied at runtime, without
+  restarting the client, and takes precedence over the metadata in the client
+  configuration.
+
+  Read the metadata of the local node:
+
+      $ nomad node meta read
+
+  Set the "rack" and unset the "maintenance" metadata of a node:
+
+      $ nomad node meta apply -node-id <node-id> -unset maintenance rack=r1
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaCommand) Synopsis() string {
+	return "Interact with node metadata"
+}
+
+func (c *NodeMetaCommand) Name() string { return "node meta" }
+
+func (c *NodeMetaCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// lookupNodeID resolves a node ID prefix to the full ID of a single node.
+func lookupNodeID(client *api.Client, nodeID string) (string, error) {
+	if len(nodeID) == 1 {
+		return "", fmt.Errorf("Identifier must contain at least two characters.")
+	}
+
+	nodeID = sanitizeUUIDPrefix(nodeID)
+	nodes, _, err := client.Nodes().PrefixList(nodeID)
+	if err != nil {
+		return "", fmt.Errorf("Error querying node: %s", err)
+	}
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("No node(s) with prefix or id %q found", nodeID)
+	}
+	if len(nodes) > 1 {
+		return "", fmt.Errorf("Prefix matched multiple nodes\n\n%s", formatNodeStubList(nodes, true))
+	}
+	return nodes[0].ID, nil
+}
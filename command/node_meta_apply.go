@@ -0,0 +1,152 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type NodeMetaApplyCommand struct {
+	Meta
+}
+
+func (c *NodeMetaApplyCommand) Help() string {
+	helpText := `
+Usage: nomad node meta apply [options] <key>=<value>...
+
+  Modify the metadata of a client node at runtime. The changes are persisted by
+  the client, survive restarts and take precedence over the metadata in the
+  client configuration. The node is re-registered with the servers so the new
+  metadata is used for scheduling immediately.
+
+  If ACLs are enabled, this command requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Node Meta Apply Options:
+
+  -node-id <id>
+    Update the metadata of the given node instead of the local node.
+
+  -unset <key1>[,<key2>,...]
+    Unset the given comma separated keys, including keys set in the client
+    configuration.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaApplyCommand) Synopsis() string {
+	return "Modify node metadata at runtime"
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictFunc(func(a complete.Args) []string {
+				client, err := c.Meta.Client()
+				if err != nil {
+					return nil
+				}
+
+				resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
+				if err != nil {
+					return []string{}
+				}
+				return resp.Matches[contexts.Nodes]
+			}),
+			"-unset": complete.PredictAnything,
+		})
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *NodeMetaApplyCommand) Name() string { return "node meta apply" }
+
+func (c *NodeMetaApplyCommand) Run(args []string) int {
+	var nodeID, unset string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.StringVar(&unset, "unset", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	// Build the metadata to apply
+	meta, err := parseNodeMetaArgs(args, unset)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if len(meta) == 0 {
+		c.Ui.Error("At least one <key>=<value> argument or -unset key must be given")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if nodeID != "" {
+		if nodeID, err = lookupNodeID(client, nodeID); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	req := &api.NodeMetaApplyRequest{
+		NodeID: nodeID,
+		Meta:   meta,
+	}
+	if _, err := client.Nodes().Meta().Apply(req, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying node metadata: %s", err))
+		return 1
+	}
+
+	return 0
+}
+
+// parseNodeMetaArgs builds the metadata to apply from the <key>=<value>
+// arguments and the comma separated keys to unset.
+func parseNodeMetaArgs(args []string, unset string) (map[string]*string, error) {
+	meta := make(map[string]*string, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid metadata %q: expected <key>=<value>", arg)
+		}
+		value := parts[1]
+		meta[parts[0]] = &value
+	}
+
+	if unset != "" {
+		for _, key := range strings.Split(unset, ",") {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+			if _, ok := meta[key]; ok {
+				return nil, fmt.Errorf("Metadata key %q can't be both set and unset", key)
+			}
+			meta[key] = nil
+		}
+	}
+
+	return meta, nil
+}
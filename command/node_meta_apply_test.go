@@ -0,0 +1,54 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestNodeMetaApplyCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodeMetaApplyCommand{}
+}
+
+func TestNodeMetaApplyCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &NodeMetaApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on malformed metadata
+	if code := cmd.Run([]string{"-address=nope", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "expected <key>=<value>") {
+		t.Fatalf("expected malformed metadata error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on keys both set and unset
+	if code := cmd.Run([]string{"-address=nope", "-unset=foo", "foo=bar"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "both set and unset") {
+		t.Fatalf("expected conflicting key error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "foo=bar"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error applying node metadata") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
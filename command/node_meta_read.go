@@ -0,0 +1,138 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type NodeMetaReadCommand struct {
+	Meta
+}
+
+func (c *NodeMetaReadCommand) Help() string {
+	helpText := `
+Usage: nomad node meta read [options]
+
+  Read the metadata of a client node. By default the effective metadata is
+  shown, combining the client configuration with the metadata applied at
+  runtime.
+
+  If ACLs are enabled, this command requires a token with the 'node:read'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Node Meta Read Options:
+
+  -node-id <id>
+    Read the metadata of the given node instead of the local node.
+
+  -json
+    Output the effective, dynamic and static metadata in its JSON format.
+
+  -t
+    Format and display the metadata using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaReadCommand) Synopsis() string {
+	return "Read node metadata"
+}
+
+func (c *NodeMetaReadCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictFunc(func(a complete.Args) []string {
+				client, err := c.Meta.Client()
+				if err != nil {
+					return nil
+				}
+
+				resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
+				if err != nil {
+					return []string{}
+				}
+				return resp.Matches[contexts.Nodes]
+			}),
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *NodeMetaReadCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeMetaReadCommand) Name() string { return "node meta read" }
+
+func (c *NodeMetaReadCommand) Run(args []string) int {
+	var nodeID, tmpl string
+	var json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if nodeID != "" {
+		if nodeID, err = lookupNodeID(client, nodeID); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	meta, err := client.Nodes().Meta().Read(nodeID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading node metadata: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, meta)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	keys := make([]string, 0, len(meta.Meta))
+	for k := range meta.Meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, fmt.Sprintf("%s|%s", k, meta.Meta[k]))
+	}
+	c.Ui.Output(formatKV(out))
+	return 0
+}
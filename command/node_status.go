@@ -33,6 +33,9 @@ type NodeStatusCommand struct {
 	stats       bool
 	json        bool
 	tmpl        string
+	filter      string
+	perPage     int
+	pageToken   string
 }
 
 func (c *NodeStatusCommand) Help() string {
@@ -75,6 +78,17 @@ Node Status Options:
 
   -t
     Format and display node using a Go template.
+
+  -filter <expression>
+    Only list nodes matching the filter expression. Used only when no node is
+    being queried.
+
+  -per-page <num>
+    How many nodes to list per page. Used only when no node is being queried.
+    Defaults to listing all nodes.
+
+  -page-token <token>
+    Where to start pagination, as given by the previous page.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -86,13 +100,16 @@ func (c *NodeStatusCommand) Synopsis() string {
 func (c *NodeStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-allocs":  complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-self":    complete.PredictNothing,
-			"-short":   complete.PredictNothing,
-			"-stats":   complete.PredictNothing,
-			"-t":       complete.PredictAnything,
-			"-verbose": complete.PredictNothing,
+			"-allocs":     complete.PredictNothing,
+			"-json":       complete.PredictNothing,
+			"-self":       complete.PredictNothing,
+			"-short":      complete.PredictNothing,
+			"-stats":      complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
+			"-filter":     complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
 		})
 }
 
@@ -124,6 +141,9 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.stats, "stats", false, "")
 	flags.BoolVar(&c.json, "json", false, "")
 	flags.StringVar(&c.tmpl, "t", "", "")
+	flags.StringVar(&c.filter, "filter", "", "")
+	flags.IntVar(&c.perPage, "per-page", 0, "")
+	flags.StringVar(&c.pageToken, "page-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -154,7 +174,12 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	if len(args) == 0 && !c.self {
 
 		// Query the node info
-		nodes, _, err := client.Nodes().List(nil)
+		q := &api.QueryOptions{
+			Filter:    c.filter,
+			PerPage:   int32(c.perPage),
+			NextToken: c.pageToken,
+		}
+		nodes, qm, err := client.Nodes().List(q)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying node status: %s", err))
 			return 1
@@ -220,6 +245,10 @@ func (c *NodeStatusCommand) Run(args []string) int {
 
 		// Dump the output
 		c.Ui.Output(formatList(out))
+
+		if qm.NextToken != "" {
+			c.Ui.Output(formatNextPageHint(qm.NextToken))
+		}
 		return 0
 	}
 
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorSchedulerCommand struct {
+	Meta
+}
+
+func (c *OperatorSchedulerCommand) Name() string { return "operator scheduler" }
+
+func (c *OperatorSchedulerCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *OperatorSchedulerCommand) Synopsis() string {
+	return "Provides tools for inspecting the scheduler"
+}
+
+func (c *OperatorSchedulerCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler <subcommand> [options]
+
+  This command groups subcommands for interacting with Nomad's schedulers.
+
+  Simulate the placement of a job on the cluster with two more nodes like an
+  existing node:
+
+      $ nomad operator scheduler simulate -add-node=f7476465:2 example.nomad
+
+  Please see the individual subcommand help for detailed usage information.
+  `
+	return strings.TrimSpace(helpText)
+}
@@ -0,0 +1,289 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
+	"github.com/posener/complete"
+)
+
+type OperatorSchedulerSimulateCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *OperatorSchedulerSimulateCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler simulate [options] [<path>...]
+
+  Simulate replays the registration of jobs, the addition of nodes and the
+  removal of nodes against a snapshot of the cluster state, and displays the
+  resulting placements, placement failures and the utilization of each node.
+  The simulation does not result in any changes to the cluster.
+
+  Node changes are applied first, in the order removals then additions, and
+  the jobs at the given paths are then registered in order. If a path is "-",
+  the jobfile is read from stdin. Otherwise it is read from the file at the
+  supplied path or downloaded and read from URL specified.
+
+  This command requires operator:read permissions. Only the evaluations and
+  allocations of namespaces the token has read-job permissions for are
+  displayed. A simulation may add at most 1000 nodes and register at most 100
+  jobs.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Simulate Options:
+
+  -add-node=<node-id>[:<count>]
+    Adds count clones of an existing node to the cluster. The count defaults
+    to 1. May be specified multiple times.
+
+  -remove-node=<node-id>
+    Removes an existing node from the cluster, as if it went down. May be
+    specified multiple times.
+
+  -json
+    Output the simulation results in its JSON format.
+
+  -t
+    Format and display the simulation results using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerSimulateCommand) Synopsis() string {
+	return "Simulate scheduling changes without committing them"
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-add-node":    complete.PredictAnything,
+			"-remove-node": complete.PredictAnything,
+			"-json":        complete.PredictNothing,
+			"-t":           complete.PredictAnything,
+		})
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(complete.PredictFiles("*.nomad"), complete.PredictFiles("*.hcl"))
+}
+
+func (c *OperatorSchedulerSimulateCommand) Name() string { return "operator scheduler simulate" }
+
+func (c *OperatorSchedulerSimulateCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+	var addNodes, removeNodes []string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Var((*flaghelper.StringFlag)(&addNodes), "add-node", "")
+	flags.Var((*flaghelper.StringFlag)(&removeNodes), "remove-node", "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that there is something to simulate
+	args = flags.Args()
+	if len(args) == 0 && len(addNodes) == 0 && len(removeNodes) == 0 {
+		c.Ui.Error("This command takes job paths or node changes")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	req := &api.SchedulerSimulateRequest{}
+	for _, path := range args {
+		job, err := c.JobGetter.ApiJob(path)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+			return 1
+		}
+		req.Jobs = append(req.Jobs, job)
+	}
+
+	for _, arg := range removeNodes {
+		nodeID, err := resolveSimulateNodeID(client, arg)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		req.RemoveNodes = append(req.RemoveNodes, nodeID)
+	}
+
+	for _, arg := range addNodes {
+		prefix, count := arg, 1
+		if idx := strings.LastIndex(arg, ":"); idx != -1 {
+			prefix = arg[:idx]
+			count, err = strconv.Atoi(arg[idx+1:])
+			if err != nil || count < 1 {
+				c.Ui.Error(fmt.Sprintf("Invalid node count in %q", arg))
+				return 1
+			}
+		}
+
+		nodeID, err := resolveSimulateNodeID(client, prefix)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		req.AddNodes = append(req.AddNodes, &api.SimulateAddNode{
+			NodeID: nodeID,
+			Count:  count,
+		})
+	}
+
+	resp, _, err := client.Operator().SchedulerSimulate(req, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running simulation: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, resp)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	// Print any warnings if there are any
+	if resp.Warnings != "" {
+		c.Ui.Output(
+			c.Colorize().Color(fmt.Sprintf("[bold][yellow]Job Warnings:\n%s[reset]\n", resp.Warnings)))
+	}
+
+	c.Ui.Output(c.Colorize().Color("[bold]Evaluations[reset]"))
+	c.Ui.Output(formatSimulatedEvals(resp.Evals))
+
+	if failures := formatSimulatedFailures(resp.Evals); failures != "" {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Placement Failures[reset]"))
+		c.Ui.Output(c.Colorize().Color(failures))
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Node Utilization[reset]"))
+	c.Ui.Output(formatSimulatedNodes(resp.Nodes))
+	return 0
+}
+
+// resolveSimulateNodeID returns the full ID of the node matching the given
+// prefix.
+func resolveSimulateNodeID(client *api.Client, prefix string) (string, error) {
+	if len(prefix) == 1 {
+		return "", fmt.Errorf("Node identifier must contain at least two characters.")
+	}
+
+	nodeID := sanitizeUUIDPrefix(prefix)
+	nodes, _, err := client.Nodes().PrefixList(nodeID)
+	if err != nil {
+		return "", fmt.Errorf("Error querying node info: %s", err)
+	}
+	switch len(nodes) {
+	case 0:
+		return "", fmt.Errorf("No node(s) with prefix %q found", prefix)
+	case 1:
+		return nodes[0].ID, nil
+	default:
+		return "", fmt.Errorf("Prefix %q matched multiple nodes\n\n%s", prefix,
+			formatNodeStubList(nodes, false))
+	}
+}
+
+func formatSimulatedEvals(evals []*api.SimulatedEval) string {
+	if len(evals) == 0 {
+		return "No evaluations"
+	}
+
+	out := make([]string, 0, len(evals)+1)
+	out = append(out, "Job ID|Namespace|Triggered By|Placed|Stopped|Preempted|Failed")
+	for _, eval := range evals {
+		failed := 0
+		for _, metrics := range eval.FailedTGAllocs {
+			failed += metrics.CoalescedFailures + 1
+		}
+		out = append(out, fmt.Sprintf("%s|%s|%s|%d|%d|%d|%d",
+			eval.JobID,
+			eval.Namespace,
+			eval.TriggeredBy,
+			len(eval.Placed),
+			len(eval.Stopped),
+			len(eval.Preempted),
+			failed))
+	}
+	return formatList(out)
+}
+
+func formatSimulatedFailures(evals []*api.SimulatedEval) string {
+	var out string
+	for _, eval := range evals {
+		if len(eval.FailedTGAllocs) == 0 {
+			continue
+		}
+
+		// Sort the task groups
+		tgs := make([]string, 0, len(eval.FailedTGAllocs))
+		for tg := range eval.FailedTGAllocs {
+			tgs = append(tgs, tg)
+		}
+		sort.Strings(tgs)
+
+		for _, tg := range tgs {
+			metrics := eval.FailedTGAllocs[tg]
+
+			noun := "allocation"
+			if metrics.CoalescedFailures > 0 {
+				noun += "s"
+			}
+			out += fmt.Sprintf("[yellow]Job %q Task Group %q (failed to place %d %s):\n[reset]", eval.JobID, tg, metrics.CoalescedFailures+1, noun)
+			out += fmt.Sprintf("[yellow]%s[reset]\n\n", formatAllocMetrics(metrics, false, strings.Repeat(" ", 2)))
+		}
+	}
+	return strings.TrimSuffix(out, "\n\n")
+}
+
+func formatSimulatedNodes(nodes []*api.SimulatedNode) string {
+	if len(nodes) == 0 {
+		return "No nodes"
+	}
+
+	out := make([]string, 0, len(nodes)+1)
+	out = append(out, "ID|Name|DC|Class|Status|Simulated|Allocs|CPU|Memory|Disk")
+	for _, node := range nodes {
+		out = append(out, fmt.Sprintf("%s|%s|%s|%s|%s|%t|%d|%d/%d MHz|%s/%s|%s/%s",
+			limit(node.ID, shortId),
+			node.Name,
+			node.Datacenter,
+			node.NodeClass,
+			node.Status,
+			node.Simulated,
+			node.Allocs,
+			node.CPU,
+			node.CPUCapacity,
+			humanize.IBytes(uint64(node.MemoryMB*bytesPerMegabyte)),
+			humanize.IBytes(uint64(node.MemoryCapacityMB*bytesPerMegabyte)),
+			humanize.IBytes(uint64(node.DiskMB*bytesPerMegabyte)),
+			humanize.IBytes(uint64(node.DiskCapacityMB*bytesPerMegabyte))))
+	}
+	return formatList(out)
+}
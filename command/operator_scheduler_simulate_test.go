@@ -0,0 +1,105 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSchedulerSimulateCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorSchedulerSimulateCommand{}
+}
+
+func TestOperatorSchedulerSimulateCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+
+	// Fails without anything to simulate
+	if code := cmd.Run(nil); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on invalid node counts
+	if code := cmd.Run([]string{"-address=nope", "-add-node=abcd:0"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Invalid node count") {
+		t.Fatalf("expected node count error, got: %s", out)
+	}
+}
+
+func TestOperatorSchedulerSimulateCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait for a node to appear
+	var nodeID string
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		nodeID = nodes[0].ID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	fh, err := ioutil.TempFile("", "nomad")
+	require.NoError(err)
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(`
+job "job1" {
+  datacenters = ["dc1"]
+  group "group1" {
+    count = 2
+    task "task1" {
+      driver = "exec"
+      resources {
+        cpu    = 100
+        memory = 64
+      }
+    }
+  }
+}`)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-add-node=" + nodeID[:8] + ":2", fh.Name()})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Evaluations")
+	require.Contains(out, "job1")
+	require.Contains(out, "Node Utilization")
+	require.Contains(out, "-simulated-2")
+
+	// The job is not registered
+	jobs, _, err := client.Jobs().List(nil)
+	require.NoError(err)
+	require.Empty(jobs)
+	ui.OutputWriter.Reset()
+
+	// JSON output
+	code = cmd.Run([]string{"-address=" + url, "-json", fh.Name()})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), `"Simulated": false`)
+}
@@ -0,0 +1,626 @@
+// Package filter implements boolean expressions over the fields of objects,
+// used to filter the results of list endpoints.
+//
+// An expression is made of matches combined with "and", "or", "not" and
+// parentheses. A match compares the value of a selector, the dotted path of
+// struct fields and map keys from the object, with a value:
+//
+//	Status == "running"
+//	Meta.rack != r1
+//	Meta["my key"] is not empty
+//	Drivers contains docker
+//	"web" in Tags
+//	Name matches "^cache-[0-9]+$"
+//
+// Values may be quoted with double quotes or backticks, and must be quoted
+// if they contain spaces or parentheses. Values are converted to the type of
+// the field they are compared with.
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expression is a parsed filter expression.
+type Expression struct {
+	expr string
+	root node
+}
+
+// Parse parses the given filter expression.
+func Parse(expr string) (*Expression, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+	}
+
+	return &Expression{expr: expr, root: root}, nil
+}
+
+// String returns the expression as it was parsed.
+func (e *Expression) String() string {
+	return e.expr
+}
+
+// Evaluate returns whether the given object matches the expression. An error
+// is returned if a selector doesn't exist in the object or if a value can't be
+// compared with the field it selects.
+func (e *Expression) Evaluate(obj interface{}) (bool, error) {
+	return e.root.eval(reflect.ValueOf(obj))
+}
+
+// node is a node of the parsed expression
+type node interface {
+	eval(obj reflect.Value) (bool, error)
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n *andNode) eval(obj reflect.Value) (bool, error) {
+	ok, err := n.left.eval(obj)
+	if err != nil || !ok {
+		return false, err
+	}
+	return n.right.eval(obj)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n *orNode) eval(obj reflect.Value) (bool, error) {
+	ok, err := n.left.eval(obj)
+	if err != nil || ok {
+		return ok, err
+	}
+	return n.right.eval(obj)
+}
+
+type notNode struct {
+	node node
+}
+
+func (n *notNode) eval(obj reflect.Value) (bool, error) {
+	ok, err := n.node.eval(obj)
+	return !ok, err
+}
+
+// matchOperator is the operator of a match
+type matchOperator int
+
+const (
+	matchEqual matchOperator = iota
+	matchNotEqual
+	matchIsEmpty
+	matchIsNotEmpty
+	matchContains
+	matchNotContains
+	matchMatches
+	matchNotMatches
+)
+
+type matchNode struct {
+	selector []string
+	op       matchOperator
+	value    string
+	re       *regexp.Regexp
+}
+
+func (n *matchNode) eval(obj reflect.Value) (bool, error) {
+	v, found, err := selectValue(obj, n.selector)
+	if err != nil {
+		return false, err
+	}
+
+	switch n.op {
+	case matchEqual, matchNotEqual:
+		equal := false
+		if found {
+			if equal, err = valueEqual(v, n.value); err != nil {
+				return false, n.errorf("%v", err)
+			}
+		}
+		return equal == (n.op == matchEqual), nil
+
+	case matchIsEmpty, matchIsNotEmpty:
+		empty := true
+		if found {
+			if empty, err = valueEmpty(v); err != nil {
+				return false, n.errorf("%v", err)
+			}
+		}
+		return empty == (n.op == matchIsEmpty), nil
+
+	case matchContains, matchNotContains:
+		contains := false
+		if found {
+			if contains, err = valueContains(v, n.value); err != nil {
+				return false, n.errorf("%v", err)
+			}
+		}
+		return contains == (n.op == matchContains), nil
+
+	case matchMatches, matchNotMatches:
+		matches := false
+		if found {
+			if v.Kind() != reflect.String {
+				return false, n.errorf("can't match %s against a regular expression", v.Kind())
+			}
+			matches = n.re.MatchString(v.String())
+		}
+		return matches == (n.op == matchMatches), nil
+	}
+
+	return false, fmt.Errorf("unknown match operator %d", n.op)
+}
+
+func (n *matchNode) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("selector %q: %s", strings.Join(n.selector, "."), fmt.Sprintf(format, args...))
+}
+
+// selectValue returns the value at the given path of struct fields and map
+// keys. Missing map keys and nil pointers are returned as not found, while
+// unknown fields are an error.
+func selectValue(obj reflect.Value, path []string) (reflect.Value, bool, error) {
+	v := obj
+	for i, name := range path {
+		v = indirect(v)
+		if !v.IsValid() {
+			return v, false, nil
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			field, ok := structField(v, name)
+			if !ok {
+				return v, false, fmt.Errorf("selector %q: unknown field %q", strings.Join(path, "."), name)
+			}
+			v = field
+
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return v, false, fmt.Errorf("selector %q: can't select from a map with %s keys", strings.Join(path, "."), v.Type().Key().Kind())
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return v, false, nil
+			}
+
+		default:
+			return v, false, fmt.Errorf("selector %q: can't select %q from %s", strings.Join(path[:i+1], "."), name, v.Kind())
+		}
+	}
+
+	v = indirect(v)
+	return v, v.IsValid(), nil
+}
+
+// structField returns the exported field of the struct with the given name.
+// The name is matched case insensitively if there is no exact match.
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	if f, ok := v.Type().FieldByName(name); ok && f.PkgPath == "" {
+		return v.FieldByIndex(f.Index), true
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.PkgPath == "" && strings.EqualFold(f.Name, name) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid value
+// for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// valueEqual returns whether the value is equal to the given string converted
+// to the type of the value.
+func valueEqual(v reflect.Value, s string) (bool, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return false, nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String() == s, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return false, fmt.Errorf("can't compare bool with %q", s)
+		}
+		return v.Bool() == b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("can't compare integer with %q", s)
+		}
+		return v.Int() == i, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("can't compare unsigned integer with %q", s)
+		}
+		return v.Uint() == u, nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return false, fmt.Errorf("can't compare float with %q", s)
+		}
+		return v.Float() == f, nil
+	}
+
+	return false, fmt.Errorf("can't compare %s with a value", v.Kind())
+}
+
+// valueEmpty returns whether the value is an empty string, slice or map.
+func valueEmpty(v reflect.Value) (bool, error) {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0, nil
+	}
+	return false, fmt.Errorf("can't check if %s is empty", v.Kind())
+}
+
+// valueContains returns whether the string contains the substring, the slice
+// contains the element or the map contains the key.
+func valueContains(v reflect.Value, s string) (bool, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.Contains(v.String(), s), nil
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			equal, err := valueEqual(v.Index(i), s)
+			if err != nil {
+				return false, err
+			}
+			if equal {
+				return true, nil
+			}
+		}
+		return false, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return false, fmt.Errorf("can't check if a map with %s keys contains a value", v.Type().Key().Kind())
+		}
+		return v.MapIndex(reflect.ValueOf(s).Convert(v.Type().Key())).IsValid(), nil
+	}
+	return false, fmt.Errorf("can't check if %s contains a value", v.Kind())
+}
+
+// parser is a recursive descent parser of the tokens of an expression
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword returns whether the next token is the given keyword and consumes
+// it if so.
+func (p *parser) keyword(word string) bool {
+	if tok := p.peek(); tok.kind == tokenWord && tok.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.keyword("not") {
+		n, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: n}, nil
+	}
+
+	if tok := p.peek(); tok.kind == tokenLParen {
+		p.next()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d, got %s", tok.pos, tok)
+		}
+		return n, nil
+	}
+
+	return p.parseMatch()
+}
+
+func (p *parser) parseMatch() (node, error) {
+	first := p.next()
+	if first.kind != tokenWord && first.kind != tokenString {
+		return nil, fmt.Errorf("expected a selector or value at position %d, got %s", first.pos, first)
+	}
+
+	// The "in" operators take the value first
+	if p.keyword("in") {
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		return &matchNode{selector: selector, op: matchContains, value: first.text}, nil
+	}
+	if tok := p.peek(); tok.kind == tokenWord && tok.text == "not" &&
+		p.tokens[p.pos+1].kind == tokenWord && p.tokens[p.pos+1].text == "in" {
+		p.pos += 2
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		return &matchNode{selector: selector, op: matchNotContains, value: first.text}, nil
+	}
+
+	p.pos--
+	selector, err := p.parseSelector()
+	if err != nil {
+		return nil, err
+	}
+
+	n := &matchNode{selector: selector}
+	opTok := p.next()
+	switch {
+	case opTok.kind == tokenEqual:
+		n.op = matchEqual
+	case opTok.kind == tokenNotEqual:
+		n.op = matchNotEqual
+	case opTok.kind == tokenWord && opTok.text == "is":
+		n.op = matchIsEmpty
+		if p.keyword("not") {
+			n.op = matchIsNotEmpty
+		}
+		if !p.keyword("empty") {
+			return nil, fmt.Errorf("expected \"empty\" at position %d, got %s", p.peek().pos, p.peek())
+		}
+		return n, nil
+	case opTok.kind == tokenWord && opTok.text == "contains":
+		n.op = matchContains
+	case opTok.kind == tokenWord && opTok.text == "matches":
+		n.op = matchMatches
+	case opTok.kind == tokenWord && opTok.text == "not":
+		switch {
+		case p.keyword("contains"):
+			n.op = matchNotContains
+		case p.keyword("matches"):
+			n.op = matchNotMatches
+		default:
+			return nil, fmt.Errorf("expected \"contains\" or \"matches\" at position %d, got %s", p.peek().pos, p.peek())
+		}
+	default:
+		return nil, fmt.Errorf("expected an operator at position %d, got %s", opTok.pos, opTok)
+	}
+
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, fmt.Errorf("expected a value at position %d, got %s", value.pos, value)
+	}
+	n.value = value.text
+
+	if n.op == matchMatches || n.op == matchNotMatches {
+		re, err := regexp.Compile(n.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %v", n.value, err)
+		}
+		n.re = re
+	}
+
+	return n, nil
+}
+
+// parseSelector parses a selector made of dotted field names, optionally
+// followed by quoted map keys in brackets.
+func (p *parser) parseSelector() ([]string, error) {
+	tok := p.next()
+	if tok.kind != tokenWord || isKeyword(tok.text) {
+		return nil, fmt.Errorf("expected a selector at position %d, got %s", tok.pos, tok)
+	}
+
+	var selector []string
+	addPath := func(tok token) {
+		for _, part := range strings.Split(tok.text, ".") {
+			if part != "" {
+				selector = append(selector, part)
+			}
+		}
+	}
+	addPath(tok)
+
+	for p.peek().kind == tokenLBracket {
+		p.next()
+		key := p.next()
+		if key.kind != tokenString {
+			return nil, fmt.Errorf("expected a quoted key at position %d, got %s", key.pos, key)
+		}
+		selector = append(selector, key.text)
+		if tok := p.next(); tok.kind != tokenRBracket {
+			return nil, fmt.Errorf("expected \"]\" at position %d, got %s", tok.pos, tok)
+		}
+
+		// Fields may follow the key, such as Meta["key"].Field
+		if tok := p.peek(); tok.kind == tokenWord && strings.HasPrefix(tok.text, ".") {
+			addPath(p.next())
+		}
+	}
+
+	return selector, nil
+}
+
+func isKeyword(word string) bool {
+	switch word {
+	case "and", "or", "not", "in", "is", "empty", "contains", "matches":
+		return true
+	}
+	return false
+}
+
+// tokenKind is the kind of a lexed token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenEqual
+	tokenNotEqual
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lex splits the expression into tokens
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == '[':
+			tokens = append(tokens, token{kind: tokenLBracket, text: "[", pos: i})
+			i++
+		case c == ']':
+			tokens = append(tokens, token{kind: tokenRBracket, text: "]", pos: i})
+			i++
+		case strings.HasPrefix(expr[i:], "=="):
+			tokens = append(tokens, token{kind: tokenEqual, text: "==", pos: i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "!="):
+			tokens = append(tokens, token{kind: tokenNotEqual, text: "!=", pos: i})
+			i += 2
+		case c == '"':
+			end := i + 1
+			for ; end < len(expr) && expr[end] != '"'; end++ {
+				if expr[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			s, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d: %v", i, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s, pos: i})
+			i = end + 1
+		case c == '`':
+			end := strings.IndexByte(expr[i+1:], '`')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: expr[i+1 : i+1+end], pos: i})
+			i += end + 2
+		default:
+			end := i
+			for end < len(expr) && isWordChar(expr[end]) {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: expr[i:end], pos: i})
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+func isWordChar(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '(', ')', '[', ']', '"', '`', '=', '!':
+		return false
+	}
+	return true
+}
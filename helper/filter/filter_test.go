@@ -0,0 +1,160 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testNested struct {
+	Name  string
+	Count int
+}
+
+type testObject struct {
+	ID       string
+	Status   string
+	Priority int
+	Ready    bool
+	Weight   float64
+	Tags     []string
+	Meta     map[string]string
+	Nested   *testNested
+	Missing  *testNested
+	internal string
+}
+
+func testObj() *testObject {
+	return &testObject{
+		ID:       "abc-123",
+		Status:   "running",
+		Priority: 50,
+		Ready:    true,
+		Weight:   0.5,
+		Tags:     []string{"web", "cache"},
+		Meta: map[string]string{
+			"rack":   "r1",
+			"my key": "value",
+		},
+		Nested: &testNested{
+			Name:  "nested",
+			Count: 3,
+		},
+		internal: "hidden",
+	}
+}
+
+func TestExpression_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr     string
+		expected bool
+	}{
+		{`Status == running`, true},
+		{`Status == "running"`, true},
+		{`Status == ` + "`running`", true},
+		{`Status != running`, false},
+		{`status == running`, true},
+		{`Priority == 50`, true},
+		{`Priority != 50`, false},
+		{`Ready == true`, true},
+		{`Weight == 0.5`, true},
+		{`Tags contains web`, true},
+		{`Tags contains api`, false},
+		{`Tags not contains api`, true},
+		{`web in Tags`, true},
+		{`"api" not in Tags`, true},
+		{`Tags is empty`, false},
+		{`Tags is not empty`, true},
+		{`Meta.rack == r1`, true},
+		{`Meta["my key"] == value`, true},
+		{`Meta.unknown == r1`, false},
+		{`Meta.unknown is empty`, true},
+		{`Meta contains rack`, true},
+		{`Nested.Name == nested`, true},
+		{`Nested.Count == 3`, true},
+		{`Missing.Name == nested`, false},
+		{`Missing is empty`, true},
+		{`ID contains "123"`, true},
+		{`ID matches "^abc-[0-9]+$"`, true},
+		{`ID not matches "^xyz"`, true},
+		{`Status == running and Priority == 50`, true},
+		{`Status == running and Priority == 10`, false},
+		{`Status == pending or Priority == 50`, true},
+		{`not Status == pending`, true},
+		{`not (Status == running and Ready == true)`, false},
+		{`(Status == pending or Status == running) and web in Tags`, true},
+	}
+
+	obj := testObj()
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			expr, err := Parse(tc.expr)
+			require.NoError(t, err)
+
+			out, err := expr.Evaluate(obj)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestExpression_EvaluateErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr string
+		err  string
+	}{
+		{`Unknown == foo`, `unknown field "Unknown"`},
+		{`internal == hidden`, `unknown field "internal"`},
+		{`Priority == high`, `can't compare integer with "high"`},
+		{`Ready == maybe`, `can't compare bool with "maybe"`},
+		{`Priority matches "5"`, `can't match int`},
+		{`Status.Name == foo`, `can't select "Name" from string`},
+		{`Nested is empty`, `can't check if struct is empty`},
+	}
+
+	obj := testObj()
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			expr, err := Parse(tc.expr)
+			require.NoError(t, err)
+
+			_, err = expr.Evaluate(obj)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr string
+		err  string
+	}{
+		{``, `expected a selector or value`},
+		{`Status`, `expected an operator`},
+		{`Status ==`, `expected a value`},
+		{`Status == "running`, `unterminated string`},
+		{`(Status == running`, `expected ")"`},
+		{`Status == running)`, `unexpected ")"`},
+		{`Status is full`, `expected "empty"`},
+		{`Status not equals running`, `expected "contains" or "matches"`},
+		{`Status matches "("`, `invalid regular expression`},
+		{`"Status" == running`, `expected a selector`},
+		{`Status == running and`, `expected a selector or value`},
+		{`Meta[rack] == r1`, `expected a quoted key`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := Parse(tc.expr)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
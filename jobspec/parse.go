@@ -432,11 +432,16 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 		"auto_revert",
 		"auto_promote",
 		"canary",
+		"canary_percent",
+		"rollout_steps",
+		"health_gate",
 	}
 	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
 		return err
 	}
 
+	delete(m, "health_gate")
+
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
 		WeaklyTypedInput: true,
@@ -445,7 +450,59 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 	if err != nil {
 		return err
 	}
-	return dec.Decode(m)
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	// Parse the health gate
+	ot, ok := o.Val.(*ast.ObjectType)
+	if !ok {
+		return fmt.Errorf("update: should be an object")
+	}
+	if gl := ot.List.Filter("health_gate"); len(gl.Items) > 0 {
+		if len(gl.Items) > 1 {
+			return fmt.Errorf("update: cannot have more than 1 health_gate")
+		}
+		gate, err := parseHealthGate(gl.Items[0])
+		if err != nil {
+			return err
+		}
+		(*result).HealthGate = gate
+	}
+
+	return nil
+}
+
+func parseHealthGate(o *ast.ObjectItem) (*api.HealthGate, error) {
+	valid := []string{
+		"url",
+		"timeout",
+		"attempts",
+		"retry_interval",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return nil, multierror.Prefix(err, "health_gate ->")
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return nil, err
+	}
+
+	var gate api.HealthGate
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &gate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := dec.Decode(m); err != nil {
+		return nil, err
+	}
+
+	return &gate, nil
 }
 
 func parseMigrate(result **api.MigrateStrategy, list *ast.ObjectList) error {
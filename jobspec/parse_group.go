@@ -52,9 +52,11 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"migrate",
 			"spread",
 			"shutdown_delay",
+			"max_run_duration",
 			"network",
 			"service",
 			"volume",
+			"archive",
 		}
 		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -78,6 +80,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "network")
 		delete(m, "service")
 		delete(m, "volume")
+		delete(m, "archive")
 
 		// Build the group with the basic decode
 		var g api.TaskGroup
@@ -181,6 +184,13 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// Parse the archive
+		if o := listVal.Filter("archive"); len(o.Items) > 0 {
+			if err := parseArchive(&g.Archive, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', archive ->", n))
+			}
+		}
+
 		// Parse tasks
 		if o := listVal.Filter("task"); len(o.Items) > 0 {
 			if err := parseTasks(&g.Tasks, o); err != nil {
@@ -252,6 +262,38 @@ func parseEphemeralDisk(result **api.EphemeralDisk, list *ast.ObjectList) error
 	return nil
 }
 
+func parseArchive(result **api.AllocArchive, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'archive' block allowed")
+	}
+
+	// Get our archive object
+	obj := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"paths",
+		"volume",
+	}
+	if err := helper.CheckHCLKeys(obj.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, obj.Val); err != nil {
+		return err
+	}
+
+	var archive api.AllocArchive
+	if err := mapstructure.WeakDecode(m, &archive); err != nil {
+		return err
+	}
+	*result = &archive
+
+	return nil
+}
+
 func parseRestartPolicy(final **api.RestartPolicy, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
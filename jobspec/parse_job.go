@@ -30,6 +30,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	}
 	delete(m, "constraint")
 	delete(m, "affinity")
+	delete(m, "gc")
 	delete(m, "meta")
 	delete(m, "migrate")
 	delete(m, "parameterized")
@@ -38,6 +39,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	delete(m, "update")
 	delete(m, "vault")
 	delete(m, "spread")
+	delete(m, "depends_on")
 
 	// Set the ID and name to the object key
 	result.ID = helper.StringToPtr(obj.Keys[0].Token.Value().(string))
@@ -63,6 +65,8 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"affinity",
 		"spread",
 		"datacenters",
+		"depends_on",
+		"gc",
 		"group",
 		"id",
 		"meta",
@@ -126,6 +130,20 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the jobs the job depends on
+	if o := listVal.Filter("depends_on"); len(o.Items) > 0 {
+		if err := parseJobDependencies(&result.DependsOn, o); err != nil {
+			return multierror.Prefix(err, "depends_on ->")
+		}
+	}
+
+	// If we have a gc stanza, then parse that
+	if o := listVal.Filter("gc"); len(o.Items) > 0 {
+		if err := parseJobGC(&result.GC, o); err != nil {
+			return multierror.Prefix(err, "gc ->")
+		}
+	}
+
 	// If we have a reschedule stanza, then parse that
 	if o := listVal.Filter("reschedule"); len(o.Items) > 0 {
 		if err := parseReschedulePolicy(&result.Reschedule, o); err != nil {
@@ -282,3 +300,81 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 	*result = &d
 	return nil
 }
+
+func parseJobGC(result **api.JobGCConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'gc' block allowed per job")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"min_retention",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	// Build the gc block
+	var g api.JobGCConfig
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &g,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	*result = &g
+	return nil
+}
+
+func parseJobDependencies(result *[]*api.JobDependency, list *ast.ObjectList) error {
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("missing dependency job ID")
+		}
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("dependency on job '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// Check for invalid keys
+		valid := []string{
+			"status",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		// Build the dependency
+		var d api.JobDependency
+		d.JobID = n
+		if err := mapstructure.WeakDecode(m, &d); err != nil {
+			return err
+		}
+		*result = append(*result, &d)
+	}
+	return nil
+}
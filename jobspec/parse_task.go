@@ -63,6 +63,7 @@ func parseTask(item *ast.ObjectItem) (*api.Task, error) {
 		"kill_timeout",
 		"leader",
 		"logs",
+		"max_run_duration",
 		"meta",
 		"resources",
 		"service",
@@ -204,6 +205,10 @@ func parseTask(item *ast.ObjectItem) (*api.Task, error) {
 		valid := []string{
 			"max_files",
 			"max_file_size",
+			"compress",
+			"rotate_interval",
+			"max_age",
+			"sink",
 		}
 		if err := helper.CheckHCLKeys(logsBlock.Val, valid); err != nil {
 			return nil, multierror.Prefix(err, "logs ->")
@@ -212,12 +217,29 @@ func parseTask(item *ast.ObjectItem) (*api.Task, error) {
 		if err := hcl.DecodeObject(&m, logsBlock.Val); err != nil {
 			return nil, err
 		}
+		delete(m, "sink")
 
 		var log api.LogConfig
-		if err := mapstructure.WeakDecode(m, &log); err != nil {
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &log,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := dec.Decode(m); err != nil {
 			return nil, err
 		}
 
+		if ot, ok := logsBlock.Val.(*ast.ObjectType); ok {
+			if so := ot.List.Filter("sink"); len(so.Items) > 0 {
+				if err := parseLogSinks(&log.Sinks, so); err != nil {
+					return nil, multierror.Prefix(err, "logs -> sink ->")
+				}
+			}
+		}
+
 		t.LogConfig = &log
 	}
 
@@ -278,6 +300,34 @@ func parseTask(item *ast.ObjectItem) (*api.Task, error) {
 	return &t, nil
 }
 
+func parseLogSinks(result *[]*api.LogSink, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"type",
+			"address",
+			"buffer_lines",
+		}
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		var s api.LogSink
+		if err := mapstructure.WeakDecode(m, &s); err != nil {
+			return err
+		}
+
+		*result = append(*result, &s)
+	}
+
+	return nil
+}
+
 func parseArtifacts(result *[]*api.TaskArtifact, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -682,6 +682,87 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"job-gc.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				Type: helper.StringToPtr("batch"),
+				GC: &api.JobGCConfig{
+					MinRetention: helper.TimeToPtr(6 * time.Hour),
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "raw_exec",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"job-depends-on.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				DependsOn: []*api.JobDependency{
+					{
+						JobID:  "db",
+						Status: helper.StringToPtr("healthy"),
+					},
+					{
+						JobID:  "migrate",
+						Status: helper.StringToPtr("complete"),
+					},
+					{
+						JobID: "cache",
+					},
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "raw_exec",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"max-run-duration.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				Type: helper.StringToPtr("batch"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name:           helper.StringToPtr("bar"),
+						MaxRunDuration: helper.TimeToPtr(time.Hour),
+						Tasks: []*api.Task{
+							{
+								Name:           "bar",
+								Driver:         "raw_exec",
+								MaxRunDuration: 30 * time.Minute,
+							},
+							{
+								Name:   "baz",
+								Driver: "raw_exec",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"job-with-kill-signal.hcl",
 			&api.Job{
@@ -1039,6 +1120,141 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"tg-archive.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("group_archive"),
+				Name: helper.StringToPtr("group_archive"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("group"),
+						Volumes: map[string]*api.VolumeRequest{
+							"results": {
+								Name:   "results",
+								Type:   "host",
+								Source: "results",
+							},
+						},
+						Archive: &api.AllocArchive{
+							Paths:  []string{"alloc/data", "foo/local/output"},
+							Volume: "results",
+						},
+						Tasks: []*api.Task{{Name: "foo"}},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"update-rollout-steps.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name:  helper.StringToPtr("bar"),
+						Count: helper.IntToPtr(10),
+						Update: &api.UpdateStrategy{
+							CanaryPercent: helper.IntToPtr(10),
+							RolloutSteps:  []int{25, 50},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "raw_exec",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"update-health-gate.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				Update: &api.UpdateStrategy{
+					HealthGate: &api.HealthGate{
+						URL:      helper.StringToPtr("http://127.0.0.1:8080/gate"),
+						Timeout:  helper.TimeToPtr(5 * time.Second),
+						Attempts: helper.IntToPtr(2),
+					},
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "raw_exec",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"logs-sinks.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "raw_exec",
+								LogConfig: &api.LogConfig{
+									MaxFiles:      helper.IntToPtr(5),
+									MaxFileSizeMB: helper.IntToPtr(20),
+									Sinks: []*api.LogSink{
+										{
+											Type:    "syslog",
+											Address: "udp://127.0.0.1:514",
+										},
+										{
+											Type:        "json",
+											Address:     "app.json",
+											BufferLines: 512,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"logs-rotation.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "raw_exec",
+								LogConfig: &api.LogConfig{
+									MaxFiles:       helper.IntToPtr(20),
+									MaxFileSizeMB:  helper.IntToPtr(5),
+									Compress:       helper.BoolToPtr(true),
+									RotateInterval: helper.TimeToPtr(time.Hour),
+									MaxAge:         helper.TimeToPtr(72 * time.Hour),
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 	}
 
 	for _, tc := range cases {
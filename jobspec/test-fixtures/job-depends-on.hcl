@@ -0,0 +1,17 @@
+job "foo" {
+  depends_on "db" {
+    status = "healthy"
+  }
+
+  depends_on "migrate" {
+    status = "complete"
+  }
+
+  depends_on "cache" {}
+
+  group "bar" {
+    task "bar" {
+      driver = "raw_exec"
+    }
+  }
+}
@@ -0,0 +1,13 @@
+job "foo" {
+  type = "batch"
+
+  gc {
+    min_retention = "6h"
+  }
+
+  group "bar" {
+    task "bar" {
+      driver = "raw_exec"
+    }
+  }
+}
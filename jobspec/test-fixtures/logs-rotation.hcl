@@ -0,0 +1,15 @@
+job "foo" {
+  group "bar" {
+    task "bar" {
+      driver = "raw_exec"
+
+      logs {
+        max_files       = 20
+        max_file_size   = 5
+        compress        = true
+        rotate_interval = "1h"
+        max_age         = "72h"
+      }
+    }
+  }
+}
@@ -0,0 +1,23 @@
+job "foo" {
+  group "bar" {
+    task "bar" {
+      driver = "raw_exec"
+
+      logs {
+        max_files     = 5
+        max_file_size = 20
+
+        sink {
+          type    = "syslog"
+          address = "udp://127.0.0.1:514"
+        }
+
+        sink {
+          type         = "json"
+          address      = "app.json"
+          buffer_lines = 512
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,16 @@
+job "foo" {
+  type = "batch"
+
+  group "bar" {
+    max_run_duration = "1h"
+
+    task "bar" {
+      driver           = "raw_exec"
+      max_run_duration = "30m"
+    }
+
+    task "baz" {
+      driver = "raw_exec"
+    }
+  }
+}
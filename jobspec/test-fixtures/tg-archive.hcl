@@ -0,0 +1,15 @@
+job "group_archive" {
+  group "group" {
+    volume "results" {
+      type   = "host"
+      source = "results"
+    }
+
+    archive {
+      paths  = ["alloc/data", "foo/local/output"]
+      volume = "results"
+    }
+
+    task "foo" {}
+  }
+}
@@ -0,0 +1,15 @@
+job "foo" {
+  update {
+    health_gate {
+      url      = "http://127.0.0.1:8080/gate"
+      timeout  = "5s"
+      attempts = 2
+    }
+  }
+
+  group "bar" {
+    task "bar" {
+      driver = "raw_exec"
+    }
+  }
+}
@@ -0,0 +1,14 @@
+job "foo" {
+  group "bar" {
+    count = 10
+
+    update {
+      canary_percent = 10
+      rollout_steps  = [25, 50]
+    }
+
+    task "bar" {
+      driver = "raw_exec"
+    }
+  }
+}
@@ -41,26 +41,29 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Capture all the allocations
-			var err error
-			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.AllocsByIDPrefix(ws, args.RequestNamespace(), prefix)
-			} else {
-				iter, err = state.AllocsByNamespace(ws, args.RequestNamespace())
-			}
+			// Capture the allocations, starting at the page of the next token
+			iter, err := state.AllocsByIDPrefixFrom(ws, args.RequestNamespace(), args.Prefix, args.NextToken)
 			if err != nil {
 				return err
 			}
 
 			var allocs []*structs.AllocListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				alloc := raw.(*structs.Allocation)
-				allocs = append(allocs, alloc.Stub())
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Allocation).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					return raw.(*structs.Allocation).Stub(), nil
+				},
+				func(stub interface{}) {
+					allocs = append(allocs, stub.(*structs.AllocListStub))
+				})
+			if err != nil {
+				return err
+			}
+			reply.NextToken, err = paginator.page()
+			if err != nil {
+				return err
 			}
 			reply.Allocations = allocs
 
@@ -47,6 +47,11 @@ type BlockedEvals struct {
 	// classes.
 	escaped map[string]wrappedEval
 
+	// dependencies is the set of evaluations waiting for the jobs their job
+	// depends on to reach their required status. They are only unblocked by
+	// changes of those jobs.
+	dependencies map[string]wrappedEval
+
 	// system is the set of system evaluations that failed to start on nodes because of
 	// resource constraints.
 	system *systemEvals
@@ -64,6 +69,11 @@ type BlockedEvals struct {
 	// time they are being blocked.
 	unblockIndexes map[string]uint64
 
+	// dependencyIndexes maps jobs to the index in which the evaluations
+	// depending on them were last unblocked. It is used like unblockIndexes
+	// for evaluations waiting on job dependencies.
+	dependencyIndexes map[structs.NamespacedID]uint64
+
 	// duplicates is the set of evaluations for jobs that had pre-existing
 	// blocked evaluations. These should be marked as cancelled since only one
 	// blocked eval is needed per job.
@@ -113,17 +123,19 @@ type BlockedStats struct {
 // unblocked evals into the passed broker.
 func NewBlockedEvals(evalBroker *EvalBroker, logger log.Logger) *BlockedEvals {
 	return &BlockedEvals{
-		logger:           logger.Named("blocked_evals"),
-		evalBroker:       evalBroker,
-		captured:         make(map[string]wrappedEval),
-		escaped:          make(map[string]wrappedEval),
-		system:           newSystemEvals(),
-		jobs:             make(map[structs.NamespacedID]string),
-		unblockIndexes:   make(map[string]uint64),
-		capacityChangeCh: make(chan *capacityUpdate, unblockBuffer),
-		duplicateCh:      make(chan struct{}, 1),
-		stopCh:           make(chan struct{}),
-		stats:            new(BlockedStats),
+		logger:            logger.Named("blocked_evals"),
+		evalBroker:        evalBroker,
+		captured:          make(map[string]wrappedEval),
+		escaped:           make(map[string]wrappedEval),
+		dependencies:      make(map[string]wrappedEval),
+		system:            newSystemEvals(),
+		jobs:              make(map[structs.NamespacedID]string),
+		unblockIndexes:    make(map[string]uint64),
+		dependencyIndexes: make(map[structs.NamespacedID]uint64),
+		capacityChangeCh:  make(chan *capacityUpdate, unblockBuffer),
+		duplicateCh:       make(chan struct{}, 1),
+		stopCh:            make(chan struct{}),
+		stats:             new(BlockedStats),
 	}
 }
 
@@ -222,6 +234,13 @@ func (b *BlockedEvals) processBlock(eval *structs.Evaluation, token string) {
 		token: token,
 	}
 
+	// Evaluations waiting on the dependencies of their job are only unblocked
+	// when the jobs they depend on change, not by capacity changes.
+	if len(eval.UnmetDependencies) != 0 {
+		b.dependencies[eval.ID] = wrapped
+		return
+	}
+
 	// If the eval has escaped, meaning computed node classes could not capture
 	// the constraints of the job, we store the eval separately as we have to
 	// unblock it whenever node capacity changes. This is because we don't know
@@ -269,11 +288,20 @@ func (b *BlockedEvals) processBlockJobDuplicate(eval *structs.Evaluation) (newCa
 			dup = eval
 			newCancelled = true
 		}
+	} else if existingW, ok = b.dependencies[existingID]; ok {
+		if latestEvalIndex(existingW.eval) <= latestEvalIndex(eval) {
+			delete(b.dependencies, existingID)
+			b.stats.TotalBlocked--
+			dup = existingW.eval
+		} else {
+			dup = eval
+			newCancelled = true
+		}
 	} else {
 		existingW, ok = b.escaped[existingID]
 		if !ok {
 			// This is a programming error
-			b.logger.Error("existing blocked evaluation is neither tracked as captured or escaped", "existing_id", existingID)
+			b.logger.Error("existing blocked evaluation is not tracked as captured, escaped or waiting on dependencies", "existing_id", existingID)
 			delete(b.jobs, structs.NewNamespacedID(eval.JobID, eval.Namespace))
 			return
 		}
@@ -314,6 +342,18 @@ func latestEvalIndex(eval *structs.Evaluation) uint64 {
 // complete. This method returns if that is the case and should be called with
 // the lock held.
 func (b *BlockedEvals) missedUnblock(eval *structs.Evaluation) bool {
+	// The evaluation is waiting on job dependencies, so only changes of the
+	// jobs it depends on could have unblocked it.
+	if len(eval.UnmetDependencies) != 0 {
+		for _, jobID := range eval.UnmetDependencies {
+			index, ok := b.dependencyIndexes[structs.NewNamespacedID(jobID, eval.Namespace)]
+			if ok && eval.SnapshotIndex < index {
+				return true
+			}
+		}
+		return false
+	}
+
 	var max uint64 = 0
 	for id, index := range b.unblockIndexes {
 		// Calculate the max unblock index
@@ -410,6 +450,12 @@ func (b *BlockedEvals) Untrack(jobID, namespace string) {
 			b.stats.TotalQuotaLimit--
 		}
 	}
+
+	if _, ok := b.dependencies[evalID]; ok {
+		delete(b.jobs, nsID)
+		delete(b.dependencies, evalID)
+		b.stats.TotalBlocked--
+	}
 }
 
 // Unblock causes any evaluation that could potentially make progress on a
@@ -496,6 +542,43 @@ func (b *BlockedEvals) UnblockClassAndQuota(class, quota string, index uint64) {
 	}
 }
 
+// UnblockDependents causes any evaluation waiting on the passed job, because
+// it is a dependency of its job, to be enqueued into the eval broker. It is
+// called when the job may have reached the status its dependents require.
+func (b *BlockedEvals) UnblockDependents(jobID, namespace string, index uint64) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	// Do nothing if not enabled
+	if !b.enabled {
+		return
+	}
+
+	// Store the index in which the unblock happened. We use this on subsequent
+	// block calls in case the evaluation was in the scheduler when the job
+	// changed.
+	b.dependencyIndexes[structs.NewNamespacedID(jobID, namespace)] = index
+
+	unblocked := make(map[*structs.Evaluation]string)
+	for id, wrapped := range b.dependencies {
+		if wrapped.eval.Namespace != namespace {
+			continue
+		}
+		if ok, _ := helper.SliceStringIsSubset(wrapped.eval.UnmetDependencies, []string{jobID}); !ok {
+			continue
+		}
+
+		unblocked[wrapped.eval] = wrapped.token
+		delete(b.jobs, structs.NewNamespacedID(wrapped.eval.JobID, wrapped.eval.Namespace))
+		delete(b.dependencies, id)
+	}
+
+	if l := len(unblocked); l != 0 {
+		b.stats.TotalBlocked -= l
+		b.evalBroker.EnqueueAll(unblocked)
+	}
+}
+
 // UnblockNode finds any blocked evalution that's node specific (system jobs) and enqueues
 // it on the eval broker
 func (b *BlockedEvals) UnblockNode(nodeID string, index uint64) {
@@ -685,8 +768,10 @@ func (b *BlockedEvals) Flush() {
 	b.stats.TotalQuotaLimit = 0
 	b.captured = make(map[string]wrappedEval)
 	b.escaped = make(map[string]wrappedEval)
+	b.dependencies = make(map[string]wrappedEval)
 	b.jobs = make(map[structs.NamespacedID]string)
 	b.unblockIndexes = make(map[string]uint64)
+	b.dependencyIndexes = make(map[structs.NamespacedID]uint64)
 	b.timetable = nil
 	b.duplicates = nil
 	b.capacityChangeCh = make(chan *capacityUpdate, unblockBuffer)
@@ -758,4 +843,9 @@ func (b *BlockedEvals) pruneUnblockIndexes() {
 			delete(b.unblockIndexes, key)
 		}
 	}
+	for key, index := range b.dependencyIndexes {
+		if index < oldThreshold {
+			delete(b.dependencyIndexes, key)
+		}
+	}
 }
@@ -621,6 +621,71 @@ func TestBlockedEvals_UnblockNode(t *testing.T) {
 	require.Equal(t, 0, bs.TotalBlocked)
 }
 
+func TestBlockedEvals_UnblockDependents(t *testing.T) {
+	t.Parallel()
+	blocked, broker := testBlockedEvals(t)
+
+	// Create a blocked eval waiting on two jobs and add it to the blocked
+	// tracker.
+	e := mock.Eval()
+	e.Status = structs.EvalStatusBlocked
+	e.UnmetDependencies = []string{"db", "queue"}
+	e.SnapshotIndex = 999
+	blocked.Block(e)
+
+	// Verify block did track
+	bs := blocked.Stats()
+	require.Equal(t, 1, bs.TotalBlocked)
+	require.Len(t, blocked.dependencies, 1)
+
+	// Capacity changes and unrelated jobs don't unblock it
+	blocked.Unblock("v1:123", 1000)
+	blocked.UnblockDependents("cache", e.Namespace, 1001)
+	blocked.UnblockDependents("db", "other", 1002)
+	require.Equal(t, 1, blocked.Stats().TotalBlocked)
+	require.Equal(t, 0, broker.Stats().TotalReady)
+
+	blocked.UnblockDependents("queue", e.Namespace, 1003)
+	requireBlockedEvalsEnqueued(t, blocked, broker, 1)
+	require.Empty(t, blocked.dependencies)
+}
+
+func TestBlockedEvals_Block_ImmediateUnblock_Dependents(t *testing.T) {
+	t.Parallel()
+	blocked, broker := testBlockedEvals(t)
+
+	// Do an unblock prior to blocking
+	blocked.UnblockDependents("db", structs.DefaultNamespace, 1000)
+
+	// Create a blocked eval that was created with a snapshot from before the
+	// dependency changed.
+	e := mock.Eval()
+	e.Status = structs.EvalStatusBlocked
+	e.UnmetDependencies = []string{"db"}
+	e.SnapshotIndex = 900
+	blocked.Block(e)
+
+	// Verify block caused the eval to be immediately unblocked
+	requireBlockedEvalsEnqueued(t, blocked, broker, 1)
+}
+
+func TestBlockedEvals_Untrack_Dependents(t *testing.T) {
+	t.Parallel()
+	blocked, _ := testBlockedEvals(t)
+
+	e := mock.Eval()
+	e.Status = structs.EvalStatusBlocked
+	e.UnmetDependencies = []string{"db"}
+	e.SnapshotIndex = 1000
+	blocked.Block(e)
+	require.Equal(t, 1, blocked.Stats().TotalBlocked)
+
+	// Untrack and verify
+	blocked.Untrack(e.JobID, e.Namespace)
+	require.Equal(t, 0, blocked.Stats().TotalBlocked)
+	require.Empty(t, blocked.dependencies)
+}
+
 func TestBlockedEvals_SystemUntrack(t *testing.T) {
 	t.Parallel()
 	blocked, _ := testBlockedEvals(t)
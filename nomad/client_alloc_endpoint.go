@@ -2,9 +2,7 @@ package nomad
 
 import (
 	"errors"
-	"fmt"
 	"io"
-	"net"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -26,6 +24,7 @@ type ClientAllocations struct {
 
 func (a *ClientAllocations) register() {
 	a.srv.streamingRpcs.Register("Allocations.Exec", a.exec)
+	a.srv.streamingRpcs.Register("Allocations.PortForward", a.portForward)
 }
 
 // GarbageCollectAll is used to garbage collect all allocations on a client.
@@ -74,6 +73,63 @@ func (a *ClientAllocations) GarbageCollectAll(args *structs.NodeSpecificRequest,
 	return NodeRpc(state.Session, "Allocations.GarbageCollectAll", args, reply)
 }
 
+// GCStatus is used to retrieve the allocations marked for garbage collection
+// on a client.
+func (a *ClientAllocations) GCStatus(args *structs.NodeSpecificRequest, reply *cstructs.GCStatusResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hop
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := a.srv.forward("ClientAllocations.GCStatus", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_allocations", "gc_status"}, time.Now())
+
+	// Check node read permissions
+	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments.
+	if args.NodeID == "" {
+		return errors.New("missing NodeID")
+	}
+
+	// Make sure Node is valid and new enough to support RPC
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	_, err = getNodeForRpc(snap, args.NodeID)
+	if err != nil {
+		return err
+	}
+
+	// Get the connection to the client and make the RPC
+	if state, ok := a.srv.getNodeConn(args.NodeID); ok {
+		err = NodeRpc(state.Session, "Allocations.GCStatus", args, reply)
+	} else {
+		err = findNodeConnAndForward(a.srv, args.NodeID, "ClientAllocations.GCStatus", args, reply)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Only return the allocations of the namespaces the token can read jobs in
+	if aclObj != nil {
+		reply.FilterAllocs(func(namespace string) bool {
+			return aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
+		})
+	}
+	return nil
+}
+
 // Signal is used to send a signal to an allocation on a client.
 func (a *ClientAllocations) Signal(args *structs.AllocSignalRequest, reply *structs.GenericResponse) error {
 	// We only allow stale reads since the only potentially stale information is
@@ -327,67 +383,63 @@ func (a *ClientAllocations) exec(conn io.ReadWriteCloser) {
 		return
 	}
 
-	nodeID := alloc.NodeID
+	forwardStreamingRpcToNode(a.srv, conn, encoder, snap, alloc.NodeID, "Allocations.Exec", &args)
+}
 
-	// Make sure Node is valid and new enough to support RPC
-	node, err := snap.NodeByID(nil, nodeID)
-	if err != nil {
+// portForward is used to forward a connection to a port of a running
+// allocation
+func (a *ClientAllocations) portForward(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "port_forward"}, time.Now())
+
+	// Decode the arguments
+	var args cstructs.AllocPortForwardRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
 		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
-	if node == nil {
-		err := fmt.Errorf("Unknown node %q", nodeID)
-		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != a.srv.Region() {
+		forwardRegionStreamingRpc(a.srv, conn, encoder, &args, "Allocations.PortForward",
+			args.AllocID, &args.QueryOptions)
 		return
 	}
 
-	if err := nodeSupportsRpc(node); err != nil {
-		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+	// Verify the arguments.
+	if args.AllocID == "" {
+		handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
 		return
 	}
 
-	// Get the connection to the client either by forwarding to another server
-	// or creating a direct stream
-	var clientConn net.Conn
-	state, ok := a.srv.getNodeConn(nodeID)
-	if !ok {
-		// Determine the Server that has a connection to the node.
-		srv, err := a.srv.serverWithNodeConn(nodeID, a.srv.Region())
-		if err != nil {
-			var code *int64
-			if structs.IsErrNoNodeConn(err) {
-				code = helper.Int64ToPtr(404)
-			}
-			handleStreamResultError(err, code, encoder)
-			return
-		}
-
-		// Get a connection to the server
-		conn, err := a.srv.streamingRpc(srv, "Allocations.Exec")
-		if err != nil {
-			handleStreamResultError(err, nil, encoder)
-			return
-		}
-
-		clientConn = conn
-	} else {
-		stream, err := NodeStreamingRpc(state.Session, "Allocations.Exec")
-		if err != nil {
-			handleStreamResultError(err, nil, encoder)
-			return
-		}
-		clientConn = stream
-	}
-	defer clientConn.Close()
-
-	// Send the request.
-	outEncoder := codec.NewEncoder(clientConn, structs.MsgpackHandle)
-	if err := outEncoder.Encode(args); err != nil {
+	// Retrieve the allocation
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
 		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
-	structs.Bridge(conn, clientConn)
-	return
+	alloc, err := getAlloc(snap, args.AllocID)
+	if structs.IsErrUnknownAllocation(err) {
+		handleStreamResultError(err, helper.Int64ToPtr(404), encoder)
+		return
+	}
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	// Check alloc-port-forward permissions
+	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocPortForward) {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	forwardStreamingRpcToNode(a.srv, conn, encoder, snap, alloc.NodeID, "Allocations.PortForward", &args)
 }
@@ -65,6 +65,136 @@ func TestClientAllocations_GarbageCollectAll_Local(t *testing.T) {
 	require.Nil(err)
 }
 
+func TestClientAllocations_GCStatus_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+		c.GCMaxAllocs = 20
+	})
+	defer cleanupC()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	req := &structs.NodeSpecificRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Fetch the response
+	var resp cstructs.GCStatusResponse
+	err := msgpackrpc.CallWithCodec(codec, "ClientAllocations.GCStatus", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "missing")
+
+	// Fetch the response setting the node id
+	req.NodeID = c.NodeID()
+	var resp2 cstructs.GCStatusResponse
+	err = msgpackrpc.CallWithCodec(codec, "ClientAllocations.GCStatus", req, &resp2)
+	require.Nil(err)
+	require.Equal(20, resp2.MaxAllocs)
+}
+
+func TestClientAllocations_GCStatus_Local_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+		c.GCDiskUsageThreshold = 100.0
+		c.GCInodeUsageThreshold = 100.0
+	})
+	defer cleanupC()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Run an allocation to completion so it is marked for collection
+	a := mock.Alloc()
+	a.Job.Type = structs.JobTypeBatch
+	a.NodeID = c.NodeID()
+	a.Job.TaskGroups[0].Count = 1
+	a.Job.TaskGroups[0].Tasks[0] = &structs.Task{
+		Name:   "web",
+		Driver: "mock_driver",
+		Config: map[string]interface{}{
+			"run_for": "10ms",
+		},
+		LogConfig: structs.DefaultLogConfig(),
+		Resources: &structs.Resources{
+			CPU:      500,
+			MemoryMB: 256,
+		},
+	}
+
+	state := s.State()
+	require.Nil(state.UpsertJob(999, a.Job))
+	require.Nil(state.UpsertAllocs(1003, []*structs.Allocation{a}))
+
+	// Create the tokens
+	tokenNode := mock.CreatePolicyAndToken(t, state, 1005, "node", mock.NodePolicy(acl.PolicyRead))
+	policyJob := mock.NodePolicy(acl.PolicyRead) +
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	tokenJob := mock.CreatePolicyAndToken(t, state, 1007, "job", policyJob)
+
+	req := &structs.NodeSpecificRequest{
+		NodeID: c.NodeID(),
+		QueryOptions: structs.QueryOptions{
+			AuthToken: root.SecretID,
+			Region:    "global",
+		},
+	}
+
+	// Wait for the allocation to be marked for collection
+	testutil.WaitForResult(func() (bool, error) {
+		var resp cstructs.GCStatusResponse
+		if err := msgpackrpc.CallWithCodec(codec, "ClientAllocations.GCStatus", req, &resp); err != nil {
+			return false, err
+		}
+		if len(resp.Allocs) != 1 {
+			return false, fmt.Errorf("expected 1 alloc marked for collection; got %d", len(resp.Allocs))
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// A token that can't read jobs in the namespace doesn't see the allocation
+	req.AuthToken = tokenNode.SecretID
+	var resp cstructs.GCStatusResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "ClientAllocations.GCStatus", req, &resp))
+	require.Empty(resp.Allocs)
+
+	// A token that can read jobs in the namespace sees it
+	req.AuthToken = tokenJob.SecretID
+	var resp2 cstructs.GCStatusResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "ClientAllocations.GCStatus", req, &resp2))
+	require.Len(resp2.Allocs, 1)
+	require.Equal(a.ID, resp2.Allocs[0].AllocID)
+}
+
 func TestClientAllocations_GarbageCollectAll_Local_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -1212,6 +1342,271 @@ func TestAlloc_ExecStreaming(t *testing.T) {
 	}
 }
 
+func TestAlloc_PortForward(t *testing.T) {
+	t.Parallel()
+
+	localServer, cleanupLS := TestServer(t, nil)
+	defer cleanupLS()
+
+	remoteServer, cleanupRS := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+	})
+	defer cleanupRS()
+
+	remoteRegionServer, cleanupRRS := TestServer(t, func(c *Config) {
+		c.Region = "two"
+	})
+	defer cleanupRRS()
+
+	TestJoin(t, localServer, remoteServer)
+	TestJoin(t, localServer, remoteRegionServer)
+	testutil.WaitForLeader(t, localServer.RPC)
+	testutil.WaitForLeader(t, remoteServer.RPC)
+	testutil.WaitForLeader(t, remoteRegionServer.RPC)
+
+	c, cleanup := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{localServer.config.RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// Wait for the client to connect
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := remoteServer.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		require.NoError(t, err, "failed to have a client")
+	})
+
+	// Force remove the connection locally in case it exists
+	remoteServer.nodeConnsLock.Lock()
+	delete(remoteServer.nodeConns, c.NodeID())
+	remoteServer.nodeConnsLock.Unlock()
+
+	// Start an echo server standing in for the task
+	ln, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	// Start task
+	a := mock.BatchAlloc()
+	a.NodeID = c.NodeID()
+	a.Job.Type = structs.JobTypeBatch
+	a.Job.TaskGroups[0].Count = 1
+	a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Allocate the echo server's port on the loopback address
+	for _, tr := range a.AllocatedResources.Tasks {
+		tr.Networks = []*structs.NetworkResource{{
+			IP:            "127.0.0.1",
+			ReservedPorts: []structs.Port{{Label: "echo", Value: ln.Addr().(*net.TCPAddr).Port}},
+		}}
+	}
+
+	// Upsert the allocation
+	localState := localServer.State()
+	require.Nil(t, localState.UpsertJob(999, a.Job))
+	require.Nil(t, localState.UpsertAllocs(1003, []*structs.Allocation{a}))
+	remoteState := remoteServer.State()
+	require.Nil(t, remoteState.UpsertJob(999, a.Job))
+	require.Nil(t, remoteState.UpsertAllocs(1003, []*structs.Allocation{a}))
+
+	// Wait for the client to run the allocation
+	testutil.WaitForResult(func() (bool, error) {
+		alloc, err := localState.AllocByID(nil, a.ID)
+		if err != nil {
+			return false, err
+		}
+		if alloc == nil {
+			return false, fmt.Errorf("unknown alloc")
+		}
+		if alloc.ClientStatus != structs.AllocClientStatusRunning {
+			return false, fmt.Errorf("alloc client status: %v", alloc.ClientStatus)
+		}
+
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err, "task didn't start yet")
+	})
+
+	cases := []struct {
+		name string
+		rpc  func(string) (structs.StreamingRpcHandler, error)
+	}{
+		{"client", c.StreamingRpcHandler},
+		{"local_server", localServer.StreamingRpcHandler},
+		{"remote_server", remoteServer.StreamingRpcHandler},
+		{"remote_region", remoteRegionServer.StreamingRpcHandler},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// Make the request
+			req := &cstructs.AllocPortForwardRequest{
+				AllocID:      a.ID,
+				Port:         ln.Addr().(*net.TCPAddr).Port,
+				QueryOptions: nstructs.QueryOptions{Region: "global"},
+			}
+
+			// Get the handler
+			handler, err := tc.rpc("Allocations.PortForward")
+			require.Nil(t, err)
+
+			// Create a pipe
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			errCh := make(chan error)
+			payloads := make(chan []byte)
+
+			// Start the handler
+			go handler(p2)
+			go func() {
+				decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+				for {
+					var msg cstructs.StreamErrWrapper
+					if err := decoder.Decode(&msg); err != nil {
+						return
+					}
+					if msg.Error != nil {
+						errCh <- msg.Error
+						continue
+					}
+					payloads <- msg.Payload
+				}
+			}()
+
+			// Send the request and data
+			encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+			require.Nil(t, encoder.Encode(req))
+			require.Nil(t, encoder.Encode(&cstructs.PortForwardFrame{Data: []byte("ping"), Close: true}))
+
+			timeout := time.After(3 * time.Second)
+			received := ""
+
+			for received != "ping" {
+				select {
+				case <-timeout:
+					require.FailNow(t, "timed out", "received %q", received)
+				case err := <-errCh:
+					require.NoError(t, err)
+				case p := <-payloads:
+					received += string(p)
+				}
+			}
+		})
+	}
+}
+
+func TestAlloc_PortForward_ACL(t *testing.T) {
+	t.Parallel()
+
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create the alloc
+	a := mock.Alloc()
+	state := s.State()
+	require.Nil(t, state.UpsertJob(999, a.Job))
+	require.Nil(t, state.UpsertAllocs(1003, []*structs.Allocation{a}))
+
+	policyBad := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocExec})
+	tokenBad := mock.CreatePolicyAndToken(t, state, 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocPortForward})
+	tokenGood := mock.CreatePolicyAndToken(t, state, 1009, "valid", policyGood)
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: structs.ErrUnknownNodePrefix,
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: structs.ErrUnknownNodePrefix,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+
+			// Make the request
+			req := &cstructs.AllocPortForwardRequest{
+				AllocID: a.ID,
+				Port:    8080,
+				QueryOptions: nstructs.QueryOptions{
+					Region:    "global",
+					AuthToken: c.Token,
+					Namespace: nstructs.DefaultNamespace,
+				},
+			}
+
+			// Get the handler
+			handler, err := s.StreamingRpcHandler("Allocations.PortForward")
+			require.Nil(t, err)
+
+			// Create a pipe
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			// Start the handler
+			go handler(p2)
+
+			// Send the request
+			encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+			require.Nil(t, encoder.Encode(req))
+
+			errCh := make(chan error, 1)
+			go func() {
+				var msg cstructs.StreamErrWrapper
+				decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+				if err := decoder.Decode(&msg); err != nil {
+					errCh <- err
+				} else if msg.Error != nil {
+					errCh <- msg.Error
+				} else {
+					errCh <- fmt.Errorf("received unexpected payload: %q", msg.Payload)
+				}
+			}()
+
+			select {
+			case <-time.After(3 * time.Second):
+				require.FailNow(t, "timed out")
+			case err := <-errCh:
+				require.Contains(t, err.Error(), c.ExpectedError)
+			}
+		})
+	}
+}
+
 func decodeFrames(t *testing.T, p1 net.Conn, frames chan<- *drivers.ExecTaskStreamingResponseMsg, errCh chan<- error) {
 	// Start the decoder
 	decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
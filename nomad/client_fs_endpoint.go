@@ -28,6 +28,8 @@ type FileSystem struct {
 func (f *FileSystem) register() {
 	f.srv.streamingRpcs.Register("FileSystem.Logs", f.logs)
 	f.srv.streamingRpcs.Register("FileSystem.Stream", f.stream)
+	f.srv.streamingRpcs.Register("FileSystem.Upload", f.upload)
+	f.srv.streamingRpcs.Register("FileSystem.Archive", f.archive)
 }
 
 // handleStreamResultError is a helper for sending an error with a potential
@@ -321,6 +323,122 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	return
 }
 
+// upload is used to upload a file into an allocation's directory on the
+// client.
+func (f *FileSystem) upload(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "file_system", "upload"}, time.Now())
+
+	// Decode the arguments
+	var args cstructs.FsUploadRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != f.srv.Region() {
+		forwardRegionStreamingRpc(f.srv, conn, encoder, &args, "FileSystem.Upload",
+			args.AllocID, &args.QueryOptions)
+		return
+	}
+
+	// Verify the arguments.
+	if args.AllocID == "" {
+		handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Retrieve the allocation
+	snap, err := f.srv.State().Snapshot()
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	alloc, err := getAlloc(snap, args.AllocID)
+	if structs.IsErrUnknownAllocation(err) {
+		handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
+		return
+	}
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	// Check namespace write-fs permissions.
+	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityWriteFS) {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	forwardStreamingRpcToNode(f.srv, conn, encoder, snap, alloc.NodeID, "FileSystem.Upload", &args)
+}
+
+// archive is used to stream a tar archive of a path in an allocation's
+// directory on the client.
+func (f *FileSystem) archive(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "file_system", "archive"}, time.Now())
+
+	// Decode the arguments
+	var args cstructs.FsArchiveRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != f.srv.Region() {
+		forwardRegionStreamingRpc(f.srv, conn, encoder, &args, "FileSystem.Archive",
+			args.AllocID, &args.QueryOptions)
+		return
+	}
+
+	// Verify the arguments.
+	if args.AllocID == "" {
+		handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Retrieve the allocation
+	snap, err := f.srv.State().Snapshot()
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	alloc, err := getAlloc(snap, args.AllocID)
+	if structs.IsErrUnknownAllocation(err) {
+		handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
+		return
+	}
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	// Check namespace read-fs permissions.
+	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	forwardStreamingRpcToNode(f.srv, conn, encoder, snap, alloc.NodeID, "FileSystem.Archive", &args)
+}
+
 // logs is used to access an task's logs for a given allocation
 func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	defer conn.Close()
@@ -1285,6 +1285,320 @@ OUTER:
 	}
 }
 
+// uploadFile sends the upload request and file data to the handler and
+// returns the handler's reply.
+func uploadFile(t *testing.T, handler structs.StreamingRpcHandler,
+	req *cstructs.FsUploadRequest, data string) *cstructs.StreamErrWrapper {
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	// Start the handler
+	go handler(p2)
+
+	// Start the decoder
+	replyCh := make(chan *cstructs.StreamErrWrapper, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		var msg cstructs.StreamErrWrapper
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		if err := decoder.Decode(&msg); err != nil {
+			errCh <- err
+			return
+		}
+		replyCh <- &msg
+	}()
+
+	// Send the request and the file, which is not read on errors
+	go func() {
+		encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+		if err := encoder.Encode(req); err != nil {
+			return
+		}
+		encoder.Encode(&cstructs.FsUploadFrame{Data: []byte(data), EOF: true})
+	}()
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	case err := <-errCh:
+		t.Fatalf("error decoding: %v", err)
+	case msg := <-replyCh:
+		return msg
+	}
+	return nil
+}
+
+func TestClientFS_Upload_ACL(t *testing.T) {
+	t.Parallel()
+
+	// Start a server
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityWriteFS})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	// Upsert the allocation
+	state := s.State()
+	alloc := mock.Alloc()
+	require.NoError(t, state.UpsertJob(1010, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(1011, []*structs.Allocation{alloc}))
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: structs.ErrUnknownNodePrefix,
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: structs.ErrUnknownNodePrefix,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.FsUploadRequest{
+				AllocID: alloc.ID,
+				Path:    "alloc/foo",
+				QueryOptions: structs.QueryOptions{
+					Namespace: structs.DefaultNamespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			// Get the handler
+			handler, err := s.StreamingRpcHandler("FileSystem.Upload")
+			require.NoError(t, err)
+
+			msg := uploadFile(t, handler, req, "foo")
+			require.NotNil(t, msg.Error)
+			require.Contains(t, msg.Error.Error(), c.ExpectedError)
+		})
+	}
+}
+
+func TestClientFS_Upload_Remote_Server(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s2.config.RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Force an allocation onto the node
+	a := mock.Alloc()
+	a.Job.Type = structs.JobTypeBatch
+	a.NodeID = c.NodeID()
+	a.Job.TaskGroups[0].Count = 1
+	a.Job.TaskGroups[0].Tasks[0] = &structs.Task{
+		Name:   "web",
+		Driver: "mock_driver",
+		Config: map[string]interface{}{
+			"run_for": "20s",
+		},
+		LogConfig: structs.DefaultLogConfig(),
+		Resources: &structs.Resources{
+			CPU:      500,
+			MemoryMB: 256,
+		},
+	}
+
+	// Wait for the client to connect
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s2.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Upsert the allocation
+	state1 := s1.State()
+	state2 := s2.State()
+	require.Nil(state1.UpsertJob(999, a.Job))
+	require.Nil(state1.UpsertAllocs(1003, []*structs.Allocation{a}))
+	require.Nil(state2.UpsertJob(999, a.Job))
+	require.Nil(state2.UpsertAllocs(1003, []*structs.Allocation{a}))
+
+	// Wait for the client to run the allocation
+	testutil.WaitForResult(func() (bool, error) {
+		alloc, err := state2.AllocByID(nil, a.ID)
+		if err != nil {
+			return false, err
+		}
+		if alloc == nil {
+			return false, fmt.Errorf("unknown alloc")
+		}
+		if alloc.ClientStatus != structs.AllocClientStatusRunning {
+			return false, fmt.Errorf("alloc client status: %v", alloc.ClientStatus)
+		}
+
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("Alloc on node %q not running: %v", c.NodeID(), err)
+	})
+
+	// Force remove the connection locally in case it exists
+	s1.nodeConnsLock.Lock()
+	delete(s1.nodeConns, c.NodeID())
+	s1.nodeConnsLock.Unlock()
+
+	// Make the request
+	req := &cstructs.FsUploadRequest{
+		AllocID:      a.ID,
+		Path:         "alloc/data/foo",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := s1.StreamingRpcHandler("FileSystem.Upload")
+	require.Nil(err)
+
+	msg := uploadFile(t, handler, req, "hello")
+	require.Nil(msg.Error)
+
+	var info cstructs.AllocFileInfo
+	require.NoError(codec.NewDecoderBytes(msg.Payload, structs.JsonHandle).Decode(&info))
+	require.Equal("foo", info.Name)
+	require.EqualValues(5, info.Size)
+}
+
+func TestClientFS_Archive_ACL(t *testing.T) {
+	t.Parallel()
+
+	// Start a server
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityReadFS})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	// Upsert the allocation
+	state := s.State()
+	alloc := mock.Alloc()
+	require.NoError(t, state.UpsertJob(1010, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(1011, []*structs.Allocation{alloc}))
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: structs.ErrUnknownNodePrefix,
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: structs.ErrUnknownNodePrefix,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.FsArchiveRequest{
+				AllocID: alloc.ID,
+				Path:    "alloc",
+				QueryOptions: structs.QueryOptions{
+					Namespace: structs.DefaultNamespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			// Get the handler
+			handler, err := s.StreamingRpcHandler("FileSystem.Archive")
+			require.Nil(t, err)
+
+			// Create a pipe
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			errCh := make(chan error)
+			streamMsg := make(chan *cstructs.StreamErrWrapper)
+
+			// Start the handler
+			go handler(p2)
+
+			// Start the decoder
+			go func() {
+				decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+				for {
+					var msg cstructs.StreamErrWrapper
+					if err := decoder.Decode(&msg); err != nil {
+						if err == io.EOF || strings.Contains(err.Error(), "closed") {
+							return
+						}
+						errCh <- fmt.Errorf("error decoding: %v", err)
+					}
+
+					streamMsg <- &msg
+				}
+			}()
+
+			// Send the request
+			encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+			require.Nil(t, encoder.Encode(req))
+
+			select {
+			case <-time.After(3 * time.Second):
+				t.Fatal("timeout")
+			case err := <-errCh:
+				t.Fatal(err)
+			case msg := <-streamMsg:
+				require.NotNil(t, msg.Error)
+				require.Contains(t, msg.Error.Error(), c.ExpectedError)
+			}
+		})
+	}
+}
+
 func TestClientFS_Logs_NoAlloc(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -0,0 +1,94 @@
+package nomad
+
+import (
+	"errors"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+
+	"github.com/hashicorp/nomad/client/structs"
+)
+
+// NodeMeta is used to forward RPC requests to the targed Nomad client's
+// NodeMeta endpoint.
+type NodeMeta struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Apply is used to update the metadata of a client at runtime.
+func (n *NodeMeta) Apply(args *structs.NodeMetaApplyRequest, reply *structs.NodeMetaResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hop
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := n.srv.forward("NodeMeta.Apply", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_meta", "apply"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return n.forwardToNode(args.NodeID, "NodeMeta.Apply", args, reply)
+}
+
+// Read is used to retrieve the metadata of a client.
+func (n *NodeMeta) Read(args *nstructs.NodeSpecificRequest, reply *structs.NodeMetaResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hop
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := n.srv.forward("NodeMeta.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_meta", "read"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return n.forwardToNode(args.NodeID, "NodeMeta.Read", args, reply)
+}
+
+// forwardToNode makes the RPC to the given node, going through the server
+// holding a connection to it if necessary.
+func (n *NodeMeta) forwardToNode(nodeID, method string, args, reply interface{}) error {
+	// Verify the arguments.
+	if nodeID == "" {
+		return errors.New("missing NodeID")
+	}
+
+	// Check if the node even exists and is compatible with NodeRpc
+	snap, err := n.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Make sure Node is new enough to support RPC
+	if _, err := getNodeForRpc(snap, nodeID); err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := n.srv.getNodeConn(nodeID)
+	if !ok {
+		return findNodeConnAndForward(n.srv, nodeID, method, args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, method, args, reply)
+}
@@ -0,0 +1,280 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/kr/pretty"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMeta_Apply_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	req := &cstructs.NodeMetaApplyRequest{
+		Meta:         map[string]*string{"foo": helper.StringToPtr("bar")},
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Fetch the response
+	var resp cstructs.NodeMetaResponse
+	err := msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "missing")
+
+	// Fetch the response setting the node id
+	req.NodeID = c.NodeID()
+	var resp2 cstructs.NodeMetaResponse
+	err = msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp2)
+	require.Nil(err)
+	require.Equal("bar", resp2.Meta["foo"])
+	require.Equal(req.Meta, resp2.Dynamic)
+
+	// Read the metadata back
+	readReq := &structs.NodeSpecificRequest{
+		NodeID:       c.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp3 cstructs.NodeMetaResponse
+	err = msgpackrpc.CallWithCodec(codec, "NodeMeta.Read", readReq, &resp3)
+	require.Nil(err)
+	require.Equal("bar", resp3.Meta["foo"])
+}
+
+func TestNodeMeta_Apply_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create a bad token
+	policyBad := mock.NodePolicy(acl.PolicyRead)
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NodePolicy(acl.PolicyWrite)
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: "Unknown node",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: "Unknown node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+
+			// Make the request for an unknown node
+			req := &cstructs.NodeMetaApplyRequest{
+				NodeID: uuid.Generate(),
+				Meta:   map[string]*string{"foo": helper.StringToPtr("bar")},
+				QueryOptions: structs.QueryOptions{
+					AuthToken: c.Token,
+					Region:    "global",
+				},
+			}
+
+			// Fetch the response
+			var resp cstructs.NodeMetaResponse
+			err := msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp)
+			require.NotNil(err)
+			require.Contains(err.Error(), c.ExpectedError)
+		})
+	}
+}
+
+func TestNodeMeta_Read_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityReadJob})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NodePolicy(acl.PolicyRead)
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: "Unknown node",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: "Unknown node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+
+			// Make the request for an unknown node
+			req := &structs.NodeSpecificRequest{
+				NodeID: uuid.Generate(),
+				QueryOptions: structs.QueryOptions{
+					AuthToken: c.Token,
+					Region:    "global",
+				},
+			}
+
+			// Fetch the response
+			var resp cstructs.NodeMetaResponse
+			err := msgpackrpc.CallWithCodec(codec, "NodeMeta.Read", req, &resp)
+			require.NotNil(err)
+			require.Contains(err.Error(), c.ExpectedError)
+		})
+	}
+}
+
+func TestNodeMeta_Apply_OldNode(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and fake an old client
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	state := s.State()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Test for an old version error
+	node := mock.Node()
+	node.Attributes["nomad.version"] = "0.7.1"
+	require.Nil(state.UpsertNode(1005, node))
+
+	req := &cstructs.NodeMetaApplyRequest{
+		NodeID:       node.ID,
+		Meta:         map[string]*string{"foo": helper.StringToPtr("bar")},
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	var resp cstructs.NodeMetaResponse
+	err := msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp)
+	require.True(structs.IsErrNodeLacksRpc(err))
+}
+
+func TestNodeMeta_Apply_Remote(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+	codec := rpcClient(t, s2)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s2.config.RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s2.connectedNodes()
+		if len(nodes) != 1 {
+			return false, fmt.Errorf("should have 1 client. found %d", len(nodes))
+		}
+		req := &structs.NodeSpecificRequest{
+			NodeID:       c.NodeID(),
+			QueryOptions: structs.QueryOptions{Region: "global"},
+		}
+		resp := structs.SingleNodeResponse{}
+		if err := msgpackrpc.CallWithCodec(codec, "Node.GetNode", req, &resp); err != nil {
+			return false, err
+		}
+		return resp.Node != nil && resp.Node.Status == structs.NodeStatusReady, fmt.Errorf(
+			"expected ready but found %s", pretty.Sprint(resp.Node))
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Force remove the connection locally in case it exists
+	s1.nodeConnsLock.Lock()
+	delete(s1.nodeConns, c.NodeID())
+	s1.nodeConnsLock.Unlock()
+
+	// Make the request through the server without the connection
+	codec = rpcClient(t, s1)
+	req := &cstructs.NodeMetaApplyRequest{
+		NodeID:       c.NodeID(),
+		Meta:         map[string]*string{"foo": helper.StringToPtr("bar")},
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Fetch the response
+	var resp cstructs.NodeMetaResponse
+	err := msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp)
+	require.Nil(err)
+	require.Equal("bar", resp.Meta["foo"])
+}
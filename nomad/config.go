@@ -119,6 +119,11 @@ type Config struct {
 	// performing upgrade migrations.
 	UpgradeVersion string
 
+	// HealthGateHosts are the hosts the health gates of deployments may send
+	// requests to. A health gate whose URL host is not listed is rejected when
+	// the job is registered.
+	HealthGateHosts []string
+
 	// SerfConfig is the configuration for the serf cluster
 	SerfConfig *serf.Config
 
@@ -274,26 +274,29 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Capture all the deployments
-			var err error
-			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.DeploymentsByIDPrefix(ws, args.RequestNamespace(), prefix)
-			} else {
-				iter, err = state.DeploymentsByNamespace(ws, args.RequestNamespace())
-			}
+			// Capture the deployments, starting at the page of the next token
+			iter, err := state.DeploymentsByIDPrefixFrom(ws, args.RequestNamespace(), args.Prefix, args.NextToken)
 			if err != nil {
 				return err
 			}
 
 			var deploys []*structs.Deployment
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				deploy := raw.(*structs.Deployment)
-				deploys = append(deploys, deploy)
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Deployment).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					return raw, nil
+				},
+				func(stub interface{}) {
+					deploys = append(deploys, stub.(*structs.Deployment))
+				})
+			if err != nil {
+				return err
+			}
+			reply.NextToken, err = paginator.page()
+			if err != nil {
+				return err
 			}
 			reply.Deployments = deploys
 
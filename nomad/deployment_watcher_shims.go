@@ -44,6 +44,16 @@ func (d *deploymentWatcherRaftShim) UpdateDeploymentPromotion(req *structs.Apply
 	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
 }
 
+func (d *deploymentWatcherRaftShim) UpdateDeploymentStep(req *structs.ApplyDeploymentStepRequest) (uint64, error) {
+	fsmErrIntf, index, raftErr := d.apply(structs.DeploymentStepRequestType, req)
+	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
+}
+
+func (d *deploymentWatcherRaftShim) UpdateDeploymentHealthGate(req *structs.ApplyDeploymentHealthGateRequest) (uint64, error) {
+	fsmErrIntf, index, raftErr := d.apply(structs.DeploymentHealthGateRequestType, req)
+	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
+}
+
 func (d *deploymentWatcherRaftShim) UpdateDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error) {
 	fsmErrIntf, index, raftErr := d.apply(structs.DeploymentAllocHealthRequestType, req)
 	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
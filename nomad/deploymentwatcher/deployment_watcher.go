@@ -47,6 +47,14 @@ type deploymentTriggers interface {
 	// upsertDeploymentPromotion is used to promote canaries in a deployment
 	upsertDeploymentPromotion(req *structs.ApplyDeploymentPromoteRequest) (uint64, error)
 
+	// upsertDeploymentStep is used to advance the rollout steps of a
+	// deployment
+	upsertDeploymentStep(req *structs.ApplyDeploymentStepRequest) (uint64, error)
+
+	// upsertDeploymentHealthGate is used to record the result of a task
+	// group's health gate on a deployment
+	upsertDeploymentHealthGate(req *structs.ApplyDeploymentHealthGateRequest) (uint64, error)
+
 	// upsertDeploymentAllocHealth is used to set the health of allocations in a
 	// deployment
 	upsertDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error)
@@ -77,6 +85,15 @@ type deploymentWatcher struct {
 	// j is the job the deployment is for
 	j *structs.Job
 
+	// healthGateCh receives the results of the health gates checked for the
+	// task groups of the deployment
+	healthGateCh chan *healthGateResponse
+
+	// healthGatesStarted is the set of health gates that have been started,
+	// keyed by task group and whether it was for the canaries. It is only
+	// accessed by the watch loop.
+	healthGatesStarted map[string]struct{}
+
 	// outstandingBatch marks whether an outstanding function exists to create
 	// the evaluation. Access should be done through the lock.
 	outstandingBatch bool
@@ -109,6 +126,8 @@ func newDeploymentWatcher(parent context.Context, queryLimiter *rate.Limiter,
 		deploymentUpdateCh: make(chan struct{}, 1),
 		d:                  d,
 		j:                  j,
+		healthGateCh:       make(chan *healthGateResponse),
+		healthGatesStarted: make(map[string]struct{}),
 		state:              state,
 		deploymentTriggers: triggers,
 		logger:             logger.With("deployment_id", d.ID, "job", j.NamespacedID()),
@@ -246,6 +265,23 @@ func (w *deploymentWatcher) PromoteDeployment(
 	req *structs.DeploymentPromoteRequest,
 	resp *structs.DeploymentUpdateResponse) error {
 
+	// Canaries may only be promoted once their health gate has passed
+	groups := make(map[string]struct{}, len(req.Groups))
+	for _, g := range req.Groups {
+		groups[g] = struct{}{}
+	}
+	for name, state := range w.getDeployment().TaskGroups {
+		if _, ok := groups[name]; !req.All && !ok {
+			continue
+		}
+		if state.DesiredCanaries == 0 || w.healthGate(name) == nil {
+			continue
+		}
+		if !state.HealthGatePassed() {
+			return fmt.Errorf("Task group %q has not passed its health gate", name)
+		}
+	}
+
 	// Create the request
 	areq := &structs.ApplyDeploymentPromoteRequest{
 		DeploymentPromoteRequest: *req,
@@ -267,18 +303,26 @@ func (w *deploymentWatcher) PromoteDeployment(
 
 // autoPromoteDeployment creates a synthetic promotion request, and upserts it for processing
 func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStub) error {
-	d := w.getDeployment()
-	if !d.HasPlacedCanaries() || !d.RequiresPromotion() {
+	d, err := w.latestDeployment()
+	if err != nil {
+		return err
+	}
+	if d == nil || !d.HasPlacedCanaries() || !d.RequiresPromotion() {
 		return nil
 	}
 
 	// AutoPromote iff every task group is marked auto_promote and is healthy. The whole
 	// job version has been incremented, so we promote together. See also AutoRevert
-	for _, tv := range d.TaskGroups {
+	for name, tv := range d.TaskGroups {
 		if !tv.AutoPromote || tv.DesiredCanaries != len(tv.PlacedCanaries) {
 			return nil
 		}
 
+		// Wait for the health gate of the canaries to pass
+		if w.healthGate(name) != nil && !tv.HealthGatePassed() {
+			return nil
+		}
+
 		// Find the health status of each canary
 		for _, c := range tv.PlacedCanaries {
 			for _, a := range allocs {
@@ -290,13 +334,156 @@ func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStu
 	}
 
 	// Send the request
-	_, err := w.upsertDeploymentPromotion(&structs.ApplyDeploymentPromoteRequest{
+	_, err = w.upsertDeploymentPromotion(&structs.ApplyDeploymentPromoteRequest{
 		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{DeploymentID: d.GetID(), All: true},
 		Eval:                     w.getEval(),
 	})
 	return err
 }
 
+// advanceRolloutSteps moves the task groups whose allocations for the current
+// rollout step are all healthy to their next step. It returns whether any
+// group advanced, in which case the caller must create an evaluation to place
+// the next step. Steps advance on the health of the allocations alone; the
+// min_healthy_time of the update strategy is the only pause between them.
+func (w *deploymentWatcher) advanceRolloutSteps() (bool, error) {
+	d, err := w.latestDeployment()
+	if err != nil {
+		return false, err
+	}
+	if d == nil || d.Status != structs.DeploymentStatusRunning {
+		return false, nil
+	}
+
+	steps := make(map[string]int)
+	for name, state := range d.TaskGroups {
+		// Steps only start once the canaries are promoted
+		if state.DesiredCanaries > 0 && !state.Promoted {
+			continue
+		}
+
+		if state.HasNextStep() && state.HealthyAllocs >= state.StepTarget() {
+			steps[name] = state.CurrentStep + 1
+		}
+	}
+
+	if len(steps) == 0 {
+		return false, nil
+	}
+
+	w.logger.Debug("advancing rollout steps", "steps", steps)
+	_, err = w.upsertDeploymentStep(&structs.ApplyDeploymentStepRequest{
+		DeploymentID: d.ID,
+		Steps:        steps,
+	})
+	return err == nil, err
+}
+
+// healthGate returns the health gate of the given task group or nil if it
+// doesn't have one.
+func (w *deploymentWatcher) healthGate(group string) *structs.HealthGate {
+	tg := w.j.LookupTaskGroup(group)
+	if tg == nil || tg.Update == nil {
+		return nil
+	}
+	return tg.Update.HealthGate
+}
+
+// startHealthGates starts checking the health gates of the task groups whose
+// allocations are healthy for the current phase of the deployment. The
+// canaries of a group are gated before they can be promoted, and the whole
+// group before the deployment can complete.
+func (w *deploymentWatcher) startHealthGates() {
+	d := w.getDeployment()
+	if d.Status != structs.DeploymentStatusRunning {
+		return
+	}
+
+	for name, state := range d.TaskGroups {
+		gate := w.healthGate(name)
+		if gate == nil || state.HealthGate != nil {
+			continue
+		}
+
+		canary := state.DesiredCanaries > 0 && !state.Promoted
+		if canary {
+			if len(state.PlacedCanaries) < state.DesiredCanaries || state.HealthyAllocs < state.DesiredCanaries {
+				continue
+			}
+		} else if state.HealthyAllocs < state.DesiredTotal {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%v", name, canary)
+		if _, ok := w.healthGatesStarted[key]; ok {
+			continue
+		}
+		w.healthGatesStarted[key] = struct{}{}
+
+		req := &healthGateRequest{
+			DeploymentID: d.ID,
+			Namespace:    d.Namespace,
+			JobID:        d.JobID,
+			JobVersion:   d.JobVersion,
+			TaskGroup:    name,
+			Canary:       canary,
+		}
+
+		w.logger.Debug("checking health gate", "task_group", name, "canary", canary)
+		go func(group string, gate *structs.HealthGate) {
+			result := checkHealthGate(w.ctx, gate, req)
+			if result == nil {
+				return
+			}
+
+			select {
+			case w.healthGateCh <- &healthGateResponse{group: group, result: result}:
+			case <-w.ctx.Done():
+			}
+		}(name, gate)
+	}
+}
+
+// handleHealthGate records the result of a task group's health gate and
+// returns whether the deployment should be failed and rolled back.
+func (w *deploymentWatcher) handleHealthGate(res *healthGateResponse) (fail, rollback bool) {
+	req := &structs.ApplyDeploymentHealthGateRequest{
+		DeploymentID: w.deploymentID,
+		TaskGroup:    res.group,
+		Result:       res.result,
+	}
+
+	// Create an eval so the scheduler can complete the deployment
+	if res.result.Passed {
+		req.Eval = w.getEval()
+	}
+
+	if _, err := w.upsertDeploymentHealthGate(req); err != nil {
+		w.logger.Error("failed to record health gate result", "task_group", res.group, "error", err)
+	}
+
+	if res.result.Passed {
+		w.logger.Debug("health gate passed", "task_group", res.group)
+		return false, false
+	}
+
+	w.logger.Warn("health gate failed", "task_group", res.group, "attempts", res.result.Attempts,
+		"description", res.result.Description)
+	state, ok := w.getDeployment().TaskGroups[res.group]
+	return true, ok && state.AutoRevert
+}
+
+// latestDeployment returns the deployment from the state store, which may be
+// more recent than the tracked deployment.
+func (w *deploymentWatcher) latestDeployment() (*structs.Deployment, error) {
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return snap.DeploymentByID(nil, w.deploymentID)
+}
+
 func (w *deploymentWatcher) PauseDeployment(
 	req *structs.DeploymentPauseRequest,
 	resp *structs.DeploymentUpdateResponse) error {
@@ -407,7 +594,7 @@ func (w *deploymentWatcher) watch() {
 	allocIndex := uint64(1)
 	var updates *allocUpdates
 
-	rollback, deadlineHit := false, false
+	rollback, deadlineHit, healthGateFailed := false, false, false
 
 FAIL:
 	for {
@@ -463,6 +650,24 @@ FAIL:
 				}
 			}
 
+			// Promotion resets the health gates of the groups
+			w.startHealthGates()
+
+		case res := <-w.healthGateCh:
+			// A failed health gate fails the deployment
+			if fail, rback := w.handleHealthGate(res); fail {
+				healthGateFailed = true
+				rollback = rback
+				break FAIL
+			}
+
+			// A passed health gate may allow the canaries to be promoted
+			if updates != nil {
+				if err := w.autoPromoteDeployment(updates.allocs); err != nil {
+					w.logger.Error("failed to auto promote deployment", "error", err)
+				}
+			}
+
 		case updates = <-w.getAllocsCh(allocIndex):
 			if err := updates.err; err != nil {
 				if err == context.Canceled || w.ctx.Err() == context.Canceled {
@@ -499,8 +704,18 @@ FAIL:
 				w.logger.Error("failed to auto promote deployment", "error", err)
 			}
 
+			// Move on to the next rollout step of the groups whose current
+			// step is healthy
+			advanced, err := w.advanceRolloutSteps()
+			if err != nil {
+				w.logger.Error("failed to advance deployment rollout steps", "error", err)
+			}
+
+			// Check the health gates of the groups that are healthy
+			w.startHealthGates()
+
 			// Create an eval to push the deployment along
-			if res.createEval || len(res.allowReplacements) != 0 {
+			if res.createEval || advanced || len(res.allowReplacements) != 0 {
 				w.createBatchedUpdate(res.allowReplacements, allocIndex)
 			}
 		}
@@ -510,6 +725,8 @@ FAIL:
 	desc := structs.DeploymentStatusDescriptionFailedAllocations
 	if deadlineHit {
 		desc = structs.DeploymentStatusDescriptionProgressDeadline
+	} else if healthGateFailed {
+		desc = structs.DeploymentStatusDescriptionFailedHealthGate
 	}
 
 	// Rollback to the old job if necessary
@@ -573,8 +790,9 @@ func (w *deploymentWatcher) handleAllocUpdate(allocs []*structs.AllocListStub) (
 		progressBased := dstate.ProgressDeadline != 0
 
 		// Check if the allocation has failed and we need to mark it for allow
-		// replacements
-		if progressBased && alloc.DeploymentStatus.IsUnhealthy() &&
+		// replacements. System jobs are never rescheduled so their unhealthy
+		// allocations are left for the progress deadline to handle.
+		if progressBased && alloc.DeploymentStatus.IsUnhealthy() && w.j.Type != structs.JobTypeSystem &&
 			deployment.Active() && !alloc.DesiredTransition.ShouldReschedule() {
 			res.allowReplacements = append(res.allowReplacements, alloc.ID)
 			continue
@@ -49,6 +49,14 @@ type DeploymentRaftEndpoints interface {
 	// deployment
 	UpdateDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error)
 
+	// UpdateDeploymentStep is used to advance the rollout steps of a
+	// deployment
+	UpdateDeploymentStep(req *structs.ApplyDeploymentStepRequest) (uint64, error)
+
+	// UpdateDeploymentHealthGate is used to record the result of a task
+	// group's health gate on a deployment
+	UpdateDeploymentHealthGate(req *structs.ApplyDeploymentHealthGateRequest) (uint64, error)
+
 	// UpdateAllocDesiredTransition is used to update the desired transition
 	// for allocations.
 	UpdateAllocDesiredTransition(req *structs.AllocUpdateDesiredTransitionRequest) (uint64, error)
@@ -387,6 +395,16 @@ func (w *Watcher) upsertDeploymentPromotion(req *structs.ApplyDeploymentPromoteR
 	return w.raft.UpdateDeploymentPromotion(req)
 }
 
+// upsertDeploymentStep commits the given rollout step changes to Raft
+func (w *Watcher) upsertDeploymentStep(req *structs.ApplyDeploymentStepRequest) (uint64, error) {
+	return w.raft.UpdateDeploymentStep(req)
+}
+
+// upsertDeploymentHealthGate commits the given health gate result to Raft
+func (w *Watcher) upsertDeploymentHealthGate(req *structs.ApplyDeploymentHealthGateRequest) (uint64, error) {
+	return w.raft.UpdateDeploymentHealthGate(req)
+}
+
 // upsertDeploymentAllocHealth commits the given allocation health changes to
 // Raft
 func (w *Watcher) upsertDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error) {
@@ -2,6 +2,9 @@ package deploymentwatcher
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -614,6 +617,245 @@ func TestWatcher_AutoPromoteDeployment(t *testing.T) {
 	require.False(t, b1.DeploymentStatus.Canary)
 }
 
+// Test that a deployment with rollout steps advances once the current step is
+// healthy
+func TestWatcher_RolloutSteps_Advance(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	upd := structs.DefaultUpdateStrategy.Copy()
+	upd.MaxParallel = 4
+	upd.RolloutSteps = []int{50}
+
+	j := mock.Job()
+	j.TaskGroups[0].Count = 4
+	j.TaskGroups[0].Update = upd
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups["web"].DesiredTotal = 4
+	d.TaskGroups["web"].RolloutSteps = upd.RolloutSteps
+
+	a := mock.Alloc()
+	a.DeploymentID = d.ID
+	b := mock.Alloc()
+	b.DeploymentID = d.ID
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+	require.Nil(m.state.UpsertAllocs(m.nextIndex(), []*structs.Allocation{a, b}), "UpsertAllocs")
+
+	// Assert that we get a call to advance the rollout step
+	matchConfig := &matchDeploymentAllocHealthRequestConfig{
+		DeploymentID: d.ID,
+		Healthy:      []string{a.ID, b.ID},
+		Eval:         true,
+	}
+	m.On("UpdateDeploymentAllocHealth", mocker.MatchedBy(matchDeploymentAllocHealthRequest(matchConfig))).Return(nil)
+	matcher := matchDeploymentStepRequest(d.ID, map[string]int{"web": 1})
+	m.On("UpdateDeploymentStep", mocker.MatchedBy(matcher)).Return(nil)
+
+	// Assert that the next step is placed by the batched eval
+	m1 := matchUpdateAllocDesiredTransitions([]string{d.ID})
+	m.On("UpdateAllocDesiredTransition", mocker.MatchedBy(m1)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == len(w.watchers), nil },
+		func(err error) { require.Equal(1, len(w.watchers), "Should have 1 deployment") })
+
+	// Mark the first step of allocations healthy
+	req := &structs.DeploymentAllocHealthRequest{
+		DeploymentID:         d.ID,
+		HealthyAllocationIDs: []string{a.ID, b.ID},
+	}
+	var resp structs.DeploymentUpdateResponse
+	require.Nil(w.SetAllocHealth(req, &resp), "SetAllocHealth")
+
+	testutil.WaitForResult(func() (bool, error) {
+		ws := memdb.NewWatchSet()
+		dout, err := m.state.DeploymentByID(ws, d.ID)
+		if err != nil {
+			return false, err
+		}
+		step := dout.TaskGroups["web"].CurrentStep
+		return step == 1, fmt.Errorf("expected rollout step 1; got %d", step)
+	}, func(err error) { t.Fatal(err) })
+
+	testutil.WaitForResult(func() (bool, error) {
+		ws := memdb.NewWatchSet()
+		evals, err := m.state.EvalsByJob(ws, j.Namespace, j.ID)
+		if err != nil {
+			return false, err
+		}
+
+		if l := len(evals); l != 2 {
+			return false, fmt.Errorf("Got %d evals; want 2", l)
+		}
+		return true, nil
+	}, func(err error) { t.Fatal(err) })
+
+	m.AssertCalled(t, "UpdateDeploymentStep", mocker.MatchedBy(matcher))
+	m.AssertCalled(t, "UpdateAllocDesiredTransition", mocker.MatchedBy(m1))
+}
+
+// Test that a failed health gate fails the deployment and rolls back the job
+func TestWatcher_HealthGate_Fail_Rollback(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	defer ts.Close()
+
+	// Create a job, alloc, and a deployment
+	j := mock.Job()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.AutoRevert = true
+	j.TaskGroups[0].Update.ProgressDeadline = 0
+	j.TaskGroups[0].Update.HealthGate = &structs.HealthGate{
+		URL:      ts.URL,
+		Timeout:  time.Second,
+		Attempts: 2,
+	}
+	j.Stable = true
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups["web"].AutoRevert = true
+	d.TaskGroups["web"].DesiredTotal = 1
+	a := mock.Alloc()
+	a.DeploymentID = d.ID
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+	require.Nil(m.state.UpsertAllocs(m.nextIndex(), []*structs.Allocation{a}), "UpsertAllocs")
+
+	// Upsert the job again to get a new version
+	j2 := j.Copy()
+	j2.Stable = false
+	j2.Meta["foo"] = "bar"
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j2), "UpsertJob2")
+
+	m.On("UpdateDeploymentAllocHealth", mocker.Anything).Return(nil)
+	m.On("UpdateAllocDesiredTransition", mocker.Anything).Return(nil)
+	m.On("UpdateDeploymentHealthGate", mocker.MatchedBy(func(req *structs.ApplyDeploymentHealthGateRequest) bool {
+		return req.TaskGroup == "web" && !req.Result.Passed && req.Result.Attempts == 2 && req.Eval == nil
+	})).Return(nil)
+
+	// Assert that the deployment is failed and rolled back
+	matchConfig := &matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusFailed,
+		StatusDescription: structs.DeploymentStatusDescriptionRollback(structs.DeploymentStatusDescriptionFailedHealthGate, 0),
+		JobVersion:        helper.Uint64ToPtr(0),
+		Eval:              true,
+	}
+	matcher := matchDeploymentStatusUpdateRequest(matchConfig)
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == watchersCount(w), nil },
+		func(err error) { require.Equal(1, watchersCount(w), "Should have 1 deployment") })
+
+	// Mark the allocation healthy to start the health gate
+	req := &structs.DeploymentAllocHealthRequest{
+		DeploymentID:         d.ID,
+		HealthyAllocationIDs: []string{a.ID},
+	}
+	var resp structs.DeploymentUpdateResponse
+	require.Nil(w.SetAllocHealth(req, &resp), "SetAllocHealth")
+
+	testutil.WaitForResult(func() (bool, error) { return 0 == watchersCount(w), nil },
+		func(err error) { require.Equal(0, watchersCount(w), "Should have no deployment") })
+	m.AssertNumberOfCalls(t, "UpdateDeploymentHealthGate", 1)
+
+	ws := memdb.NewWatchSet()
+	dout, err := m.state.DeploymentByID(ws, d.ID)
+	require.Nil(err)
+	require.NotNil(dout.TaskGroups["web"].HealthGate)
+	require.False(dout.TaskGroups["web"].HealthGatePassed())
+	require.Equal(structs.DeploymentStatusFailed, dout.Status)
+	require.Equal(matchConfig.StatusDescription, dout.StatusDescription)
+}
+
+// Test that canaries are only auto promoted once their health gate passed
+func TestWatcher_HealthGate_AutoPromote(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer ts.Close()
+
+	upd := structs.DefaultUpdateStrategy.Copy()
+	upd.AutoPromote = true
+	upd.Canary = 1
+	upd.HealthGate = &structs.HealthGate{
+		URL:      ts.URL,
+		Timeout:  time.Second,
+		Attempts: 1,
+	}
+
+	j := mock.Job()
+	j.TaskGroups[0].Update = upd
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups["web"].AutoPromote = true
+	d.TaskGroups["web"].DesiredCanaries = 1
+
+	a := mock.Alloc()
+	a.DeploymentID = d.ID
+	a.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Canary: true,
+	}
+	d.TaskGroups["web"].PlacedCanaries = []string{a.ID}
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+	require.Nil(m.state.UpsertAllocs(m.nextIndex(), []*structs.Allocation{a}), "UpsertAllocs")
+
+	m.On("UpdateDeploymentAllocHealth", mocker.Anything).Return(nil)
+	m.On("UpdateAllocDesiredTransition", mocker.Anything).Return(nil)
+	m.On("UpdateDeploymentHealthGate", mocker.MatchedBy(func(req *structs.ApplyDeploymentHealthGateRequest) bool {
+		return req.Result.Passed && req.Result.Canary && req.Eval != nil
+	})).Return(nil)
+	m.On("UpdateDeploymentPromotion", mocker.Anything).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == watchersCount(w), nil },
+		func(err error) { require.Equal(1, watchersCount(w), "Should have 1 deployment") })
+
+	// Mark the canary healthy
+	req := &structs.DeploymentAllocHealthRequest{
+		DeploymentID:         d.ID,
+		HealthyAllocationIDs: []string{a.ID},
+	}
+	var resp structs.DeploymentUpdateResponse
+	require.Nil(w.SetAllocHealth(req, &resp), "SetAllocHealth")
+
+	testutil.WaitForResult(func() (bool, error) {
+		ws := memdb.NewWatchSet()
+		dout, err := m.state.DeploymentByID(ws, d.ID)
+		if err != nil {
+			return false, err
+		}
+		state := dout.TaskGroups["web"]
+		if !state.Promoted {
+			return false, fmt.Errorf("deployment not promoted")
+		}
+		if state.HealthGate != nil {
+			return false, fmt.Errorf("health gate should be reset by promotion")
+		}
+		return true, nil
+	}, func(err error) { t.Fatal(err) })
+
+	require.EqualValues(1, atomic.LoadInt32(&calls))
+	m.AssertNumberOfCalls(t, "UpdateDeploymentHealthGate", 1)
+}
+
 // Test pausing a deployment that is running
 func TestWatcher_PauseDeployment_Pause_Running(t *testing.T) {
 	t.Parallel()
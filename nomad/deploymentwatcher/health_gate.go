@@ -0,0 +1,112 @@
+package deploymentwatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// healthGateMaxDrain is the maximum number of bytes of a health gate
+	// response that are read so the connection can be reused
+	healthGateMaxDrain = 4096
+)
+
+// healthGateRequest is the body POSTed to a health gate endpoint
+type healthGateRequest struct {
+	DeploymentID string
+	Namespace    string
+	JobID        string
+	JobVersion   uint64
+	TaskGroup    string
+	Canary       bool
+}
+
+// healthGateResponse carries the result of a task group's health gate back to
+// the watch loop
+type healthGateResponse struct {
+	group  string
+	result *structs.HealthGateResult
+}
+
+// healthGateClient is the HTTP client used to check health gates. Timeouts
+// are set per attempt from the gate's configuration.
+var healthGateClient = cleanhttp.DefaultClient()
+
+// checkHealthGate checks the health gate until it passes or runs out of
+// attempts. A nil result is returned if the context is cancelled.
+func checkHealthGate(ctx context.Context, gate *structs.HealthGate, req *healthGateRequest) *structs.HealthGateResult {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return &structs.HealthGateResult{
+			Canary:      req.Canary,
+			Description: fmt.Sprintf("failed to encode health gate request: %v", err),
+			Timestamp:   time.Now(),
+		}
+	}
+
+	result := &structs.HealthGateResult{Canary: req.Canary}
+	for {
+		result.Attempts++
+		err := checkHealthGateOnce(ctx, gate, body)
+		if err == nil {
+			result.Passed = true
+			result.Description = "Health gate passed"
+			break
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		result.Description = err.Error()
+		if result.Attempts >= gate.Attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(gate.RetryInterval):
+		}
+	}
+
+	result.Timestamp = time.Now()
+	return result
+}
+
+// checkHealthGateOnce makes a single request to the health gate and returns
+// an error if it did not pass.
+func checkHealthGateOnce(ctx context.Context, gate *structs.HealthGate, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, gate.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequest("POST", gate.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create health gate request: %v", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := healthGateClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health gate request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The response body is never kept since it would be shown to anyone who
+	// can read the deployment
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, healthGateMaxDrain))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return fmt.Errorf("health gate returned status %d", resp.StatusCode)
+}
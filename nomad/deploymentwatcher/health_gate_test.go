@@ -0,0 +1,123 @@
+package deploymentwatcher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testHealthGate(url string) *structs.HealthGate {
+	return &structs.HealthGate{
+		URL:           url,
+		Timeout:       time.Second,
+		Attempts:      3,
+		RetryInterval: 10 * time.Millisecond,
+	}
+}
+
+func TestCheckHealthGate_Pass(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var received healthGateRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal("POST", r.Method)
+		require.NoError(json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer ts.Close()
+
+	req := &healthGateRequest{
+		DeploymentID: "foo",
+		JobID:        "bar",
+		TaskGroup:    "web",
+		Canary:       true,
+	}
+	result := checkHealthGate(context.Background(), testHealthGate(ts.URL), req)
+	require.NotNil(result)
+	require.True(result.Passed)
+	require.True(result.Canary)
+	require.Equal(1, result.Attempts)
+	require.False(result.Timestamp.IsZero())
+	require.Equal(*req, received)
+}
+
+func TestCheckHealthGate_Retry(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	result := checkHealthGate(context.Background(), testHealthGate(ts.URL), &healthGateRequest{})
+	require.NotNil(result)
+	require.True(result.Passed)
+	require.Equal(2, result.Attempts)
+}
+
+func TestCheckHealthGate_Fail(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusPreconditionFailed)
+		w.Write([]byte("error rate too high\n"))
+	}))
+	defer ts.Close()
+
+	result := checkHealthGate(context.Background(), testHealthGate(ts.URL), &healthGateRequest{})
+	require.NotNil(result)
+	require.False(result.Passed)
+	require.Equal(3, result.Attempts)
+	require.EqualValues(3, atomic.LoadInt32(&calls))
+	require.Equal("health gate returned status 412", result.Description)
+}
+
+func TestCheckHealthGate_Timeout(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	gate := testHealthGate(ts.URL)
+	gate.Timeout = 50 * time.Millisecond
+	gate.Attempts = 1
+	result := checkHealthGate(context.Background(), gate, &healthGateRequest{})
+	require.NotNil(result)
+	require.False(result.Passed)
+	require.Contains(result.Description, "health gate request failed")
+}
+
+func TestCheckHealthGate_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	gate := testHealthGate(ts.URL)
+	gate.RetryInterval = time.Minute
+	require.Nil(t, checkHealthGate(ctx, gate, &healthGateRequest{}))
+}
@@ -174,6 +174,30 @@ func matchDeploymentPromoteRequest(c *matchDeploymentPromoteRequestConfig) func(
 		return true
 	}
 }
+
+func (m *mockBackend) UpdateDeploymentStep(req *structs.ApplyDeploymentStepRequest) (uint64, error) {
+	m.Called(req)
+	i := m.nextIndex()
+	return i, m.state.UpdateDeploymentStep(i, req)
+}
+
+func (m *mockBackend) UpdateDeploymentHealthGate(req *structs.ApplyDeploymentHealthGateRequest) (uint64, error) {
+	m.Called(req)
+	i := m.nextIndex()
+	return i, m.state.UpdateDeploymentHealthGate(i, req)
+}
+
+// matchDeploymentStepRequest is used to match a rollout step request
+func matchDeploymentStepRequest(deploymentID string, steps map[string]int) func(args *structs.ApplyDeploymentStepRequest) bool {
+	return func(args *structs.ApplyDeploymentStepRequest) bool {
+		if args.DeploymentID != deploymentID || args.Eval != nil {
+			return false
+		}
+
+		return reflect.DeepEqual(steps, args.Steps)
+	}
+}
+
 func (m *mockBackend) UpdateDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error) {
 	m.Called(req)
 	i := m.nextIndex()
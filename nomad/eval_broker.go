@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +22,10 @@ const (
 	// they've reached the deliveryLimit. This allows the leader to
 	// set the status to failed.
 	failedQueue = "_failed"
+
+	// defaultNamespaceWeight is the weight of namespaces that don't have a
+	// weight set in the scheduler configuration.
+	defaultNamespaceWeight = 1
 )
 
 var (
@@ -38,8 +43,10 @@ var (
 // created, due to a change in a job specification or a node, we put it into the
 // broker. The broker sorts by evaluations by priority and scheduler type. This
 // allows us to dequeue the highest priority work first, while also allowing sub-schedulers
-// to only dequeue work they know how to handle. The broker is designed to be entirely
-// in-memory and is managed by the leader node.
+// to only dequeue work they know how to handle. Evaluations of the same priority
+// are shared between namespaces by weighted fair queuing, and between the jobs
+// launched from the same job and the other jobs of a namespace by fair queuing.
+// The broker is designed to be entirely in-memory and is managed by the leader node.
 //
 // The broker must provide at-least-once delivery semantics. It relies on explicit
 // Ack/Nack messages to handle this. If a delivery is not Ack'd in a sufficient time
@@ -63,7 +70,12 @@ type EvalBroker struct {
 	blocked map[structs.NamespacedID]PendingEvaluations
 
 	// ready tracks the ready jobs by scheduler in a priority queue
-	ready map[string]PendingEvaluations
+	ready map[string]*ReadyEvaluations
+
+	// weights is the weight of each namespace when dequeuing evaluations of
+	// the same priority. Namespaces without a weight have a weight of
+	// defaultNamespaceWeight.
+	weights map[string]int
 
 	// unack is a map of evalID to an un-acknowledged evaluation
 	unack map[string]*unackEval
@@ -80,6 +92,14 @@ type EvalBroker struct {
 	// timeWait has evaluations that are waiting for time to elapse
 	timeWait map[string]*time.Timer
 
+	// coalesced is the set of blocked evaluations that were canceled in
+	// favor of a more recent evaluation of the same job. The leader updates
+	// their status via Raft.
+	coalesced []*structs.Evaluation
+
+	// coalescedCh is used to signal that evaluations were coalesced
+	coalescedCh chan struct{}
+
 	// delayedEvalCancelFunc is used to stop the long running go routine
 	// that processes delayed evaluations
 	delayedEvalCancelFunc context.CancelFunc
@@ -135,17 +155,20 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		evals:                make(map[string]int),
 		jobEvals:             make(map[structs.NamespacedID]string),
 		blocked:              make(map[structs.NamespacedID]PendingEvaluations),
-		ready:                make(map[string]PendingEvaluations),
+		ready:                make(map[string]*ReadyEvaluations),
+		weights:              make(map[string]int),
 		unack:                make(map[string]*unackEval),
 		waiting:              make(map[string]chan struct{}),
 		requeue:              make(map[string]*structs.Evaluation),
 		timeWait:             make(map[string]*time.Timer),
+		coalescedCh:          make(chan struct{}, 1),
 		initialNackDelay:     initialNackDelay,
 		subsequentNackDelay:  subsequentNackDelay,
 		delayHeap:            delayheap.NewDelayHeap(),
 		delayedEvalsUpdateCh: make(chan struct{}, 1),
 	}
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 
 	return b, nil
 }
@@ -177,6 +200,28 @@ func (b *EvalBroker) SetEnabled(enabled bool) {
 	}
 }
 
+// SetNamespaceWeights is used to set the weight of each namespace when
+// dequeuing evaluations of the same priority. A namespace with twice the weight
+// of another is dequeued twice as often while both have evaluations waiting.
+func (b *EvalBroker) SetNamespaceWeights(weights map[string]int) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.weights = make(map[string]int, len(weights))
+	for namespace, weight := range weights {
+		b.weights[namespace] = weight
+	}
+}
+
+// namespaceWeight returns the weight of the given namespace. This assumes the
+// lock is held.
+func (b *EvalBroker) namespaceWeight(namespace string) int {
+	if weight, ok := b.weights[namespace]; ok && weight > 0 {
+		return weight
+	}
+	return defaultNamespaceWeight
+}
+
 // Enqueue is used to enqueue a new evaluation
 func (b *EvalBroker) Enqueue(eval *structs.Evaluation) {
 	b.l.Lock()
@@ -299,15 +344,15 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, queue string) {
 	// Find the pending by scheduler class
 	pending, ok := b.ready[queue]
 	if !ok {
-		pending = make([]*structs.Evaluation, 0, 16)
+		pending = NewReadyEvaluations(b.namespaceWeight)
+		b.ready[queue] = pending
 		if _, ok := b.waiting[queue]; !ok {
 			b.waiting[queue] = make(chan struct{}, 1)
 		}
 	}
 
-	// Push onto the heap
-	heap.Push(&pending, eval)
-	b.ready[queue] = pending
+	// Push onto the queue of the namespace
+	pending.Push(eval)
 
 	// Update the stats
 	b.stats.TotalReady += 1
@@ -317,6 +362,12 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, queue string) {
 		b.stats.ByScheduler[queue] = bySched
 	}
 	bySched.Ready += 1
+	byNamespace, ok := b.stats.ByNamespace[eval.Namespace]
+	if !ok {
+		byNamespace = &NamespaceStats{}
+		b.stats.ByNamespace[eval.Namespace] = byNamespace
+	}
+	byNamespace.Ready += 1
 
 	// Unblock any blocked dequeues
 	select {
@@ -424,9 +475,7 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string, error) {
 	// Get the pending queue
 	pending := b.ready[sched]
-	raw := heap.Pop(&pending)
-	b.ready[sched] = pending
-	eval := raw.(*structs.Evaluation)
+	eval := pending.Pop()
 
 	// Generate a UUID for the token
 	token := uuid.Generate()
@@ -452,6 +501,7 @@ func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string,
 	bySched := b.stats.ByScheduler[sched]
 	bySched.Ready -= 1
 	bySched.Unacked += 1
+	b.stats.ByNamespace[eval.Namespace].Ready -= 1
 
 	return eval, token, nil
 }
@@ -570,16 +620,13 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	}
 	delete(b.jobEvals, namespacedID)
 
-	// Check if there are any blocked evaluations
+	// Check if there are any blocked evaluations. Only the most recent one
+	// needs to be processed since the scheduler always reconciles the job
+	// as a whole, so the others are coalesced into it.
 	if blocked := b.blocked[namespacedID]; len(blocked) != 0 {
-		raw := heap.Pop(&blocked)
-		if len(blocked) > 0 {
-			b.blocked[namespacedID] = blocked
-		} else {
-			delete(b.blocked, namespacedID)
-		}
-		eval := raw.(*structs.Evaluation)
-		b.stats.TotalBlocked -= 1
+		delete(b.blocked, namespacedID)
+		eval := b.coalesceLocked(blocked)
+		b.stats.TotalBlocked -= len(blocked)
 		b.enqueueLocked(eval, eval.Type)
 	}
 
@@ -639,6 +686,78 @@ func (b *EvalBroker) Nack(evalID, token string) error {
 	return nil
 }
 
+// coalesceLocked returns the most recent of the given blocked evaluations of a
+// job and marks the others as canceled in favor of it. This assumes the lock
+// is held.
+func (b *EvalBroker) coalesceLocked(blocked PendingEvaluations) *structs.Evaluation {
+	latest := blocked[0]
+	for _, eval := range blocked[1:] {
+		if eval.CreateIndex > latest.CreateIndex {
+			latest = eval
+		}
+	}
+	if len(blocked) == 1 {
+		return latest
+	}
+
+	for _, eval := range blocked {
+		if eval == latest {
+			continue
+		}
+		delete(b.evals, eval.ID)
+
+		canceled := eval.Copy()
+		canceled.Status = structs.EvalStatusCancelled
+		canceled.StatusDescription = fmt.Sprintf("coalesced into more recent evaluation %q", latest.ID)
+		canceled.CoalescedInto = latest.ID
+		b.coalesced = append(b.coalesced, canceled)
+	}
+
+	// Signal that there are coalesced evaluations
+	select {
+	case b.coalescedCh <- struct{}{}:
+	default:
+	}
+
+	return latest
+}
+
+// Coalesced returns the evaluations that were canceled in favor of a more
+// recent evaluation of the same job since the last call, blocking until there
+// are some or the timeout is reached. The returned evaluations are copies
+// with their status already updated.
+func (b *EvalBroker) Coalesced(timeout time.Duration) []*structs.Evaluation {
+	var timeoutTimer *time.Timer
+	var timeoutCh <-chan time.Time
+SCAN:
+	b.l.Lock()
+	if len(b.coalesced) != 0 {
+		coalesced := b.coalesced
+		b.coalesced = nil
+		b.l.Unlock()
+		return coalesced
+	}
+
+	// Capture the chan inside the lock to prevent a race with it getting
+	// reset in flush
+	coalescedCh := b.coalescedCh
+	b.l.Unlock()
+
+	// Create the timer
+	if timeoutTimer == nil && timeout != 0 {
+		timeoutTimer = time.NewTimer(timeout)
+		timeoutCh = timeoutTimer.C
+		defer timeoutTimer.Stop()
+	}
+
+	select {
+	case <-timeoutCh:
+		return nil
+	case <-coalescedCh:
+		goto SCAN
+	}
+}
+
 // nackReenqueueDelay is used to determine the delay that should be applied on
 // the evaluation given the number of previous attempts
 func (b *EvalBroker) nackReenqueueDelay(eval *structs.Evaluation, prevDequeues int) time.Duration {
@@ -714,16 +833,22 @@ func (b *EvalBroker) flush() {
 	// Clear out the update channel for delayed evaluations
 	b.delayedEvalsUpdateCh = make(chan struct{}, 1)
 
+	// Drop the coalesced evaluations, the next leader coalesces them again
+	// once they are restored
+	b.coalesced = nil
+	b.coalescedCh = make(chan struct{}, 1)
+
 	// Reset the broker
 	b.stats.TotalReady = 0
 	b.stats.TotalUnacked = 0
 	b.stats.TotalBlocked = 0
 	b.stats.TotalWaiting = 0
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 	b.evals = make(map[string]int)
 	b.jobEvals = make(map[structs.NamespacedID]string)
 	b.blocked = make(map[structs.NamespacedID]PendingEvaluations)
-	b.ready = make(map[string]PendingEvaluations)
+	b.ready = make(map[string]*ReadyEvaluations)
 	b.unack = make(map[string]*unackEval)
 	b.timeWait = make(map[string]*time.Timer)
 	b.delayHeap = delayheap.NewDelayHeap()
@@ -804,6 +929,7 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	// Allocate a new stats struct
 	stats := new(BrokerStats)
 	stats.ByScheduler = make(map[string]*SchedulerStats)
+	stats.ByNamespace = make(map[string]*NamespaceStats)
 
 	b.l.RLock()
 	defer b.l.RUnlock()
@@ -818,6 +944,11 @@ func (b *EvalBroker) Stats() *BrokerStats {
 		*subStatCopy = *subStat
 		stats.ByScheduler[sched] = subStatCopy
 	}
+	for namespace, subStat := range b.stats.ByNamespace {
+		subStatCopy := new(NamespaceStats)
+		*subStatCopy = *subStat
+		stats.ByNamespace[namespace] = subStatCopy
+	}
 	return stats
 }
 
@@ -835,6 +966,10 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 				metrics.SetGauge([]string{"nomad", "broker", sched, "ready"}, float32(schedStats.Ready))
 				metrics.SetGauge([]string{"nomad", "broker", sched, "unacked"}, float32(schedStats.Unacked))
 			}
+			for namespace, nsStats := range stats.ByNamespace {
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace_ready"}, float32(nsStats.Ready),
+					[]metrics.Label{{Name: "namespace", Value: namespace}})
+			}
 
 		case <-stopCh:
 			return
@@ -849,6 +984,7 @@ type BrokerStats struct {
 	TotalBlocked int
 	TotalWaiting int
 	ByScheduler  map[string]*SchedulerStats
+	ByNamespace  map[string]*NamespaceStats
 }
 
 // SchedulerStats returns the stats per scheduler
@@ -857,6 +993,11 @@ type SchedulerStats struct {
 	Unacked int
 }
 
+// NamespaceStats returns the stats per namespace
+type NamespaceStats struct {
+	Ready int
+}
+
 // Len is for the sorting interface
 func (p PendingEvaluations) Len() int {
 	return len(p)
@@ -893,9 +1034,272 @@ func (p *PendingEvaluations) Pop() interface{} {
 
 // Peek is used to peek at the next element that would be popped
 func (p PendingEvaluations) Peek() *structs.Evaluation {
-	n := len(p)
-	if n == 0 {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[0]
+}
+
+// ReadyEvaluations is the queue of ready evaluations of a scheduler. When
+// several namespaces have evaluations of the highest priority waiting, the
+// namespace is chosen by weighted fair queuing so that a namespace enqueuing
+// many evaluations can't starve the others. Within a namespace, the jobs
+// launched from the same parameterized or periodic job share a queue, and the
+// queues are chosen by fair queuing so that dispatching many jobs can't starve
+// the other jobs of the namespace. Since the broker only has one ready
+// evaluation per job, the other jobs of a namespace are dequeued in FIFO order.
+type ReadyEvaluations struct {
+	namespaces *fairQueue
+}
+
+// NewReadyEvaluations returns an empty queue of ready evaluations, sharing
+// evaluations between namespaces by the weights returned by the given
+// function.
+func NewReadyEvaluations(weight func(namespace string) int) *ReadyEvaluations {
+	return &ReadyEvaluations{
+		namespaces: newFairQueue(
+			func(eval *structs.Evaluation) string {
+				return eval.Namespace
+			},
+			weight,
+			func() evalQueue {
+				return newFairQueue(
+					func(eval *structs.Evaluation) string {
+						return parentJobID(eval.JobID)
+					},
+					nil,
+					func() evalQueue {
+						return &pendingQueue{}
+					})
+			}),
+	}
+}
+
+// Push is used to add an evaluation to the queue
+func (r *ReadyEvaluations) Push(eval *structs.Evaluation) {
+	r.namespaces.push(eval)
+}
+
+// Pop is used to remove the next evaluation
+func (r *ReadyEvaluations) Pop() *structs.Evaluation {
+	return r.namespaces.pop()
+}
+
+// Peek is used to peek at the next evaluation that would be popped
+func (r *ReadyEvaluations) Peek() *structs.Evaluation {
+	return r.namespaces.peek()
+}
+
+// parentJobID returns the ID of the parameterized or periodic job the job was
+// launched from, or the ID of the job if it wasn't launched from another job.
+func parentJobID(jobID string) string {
+	for _, suffix := range []string{structs.DispatchLaunchSuffix, structs.PeriodicLaunchSuffix} {
+		if index := strings.LastIndex(jobID, suffix); index > 0 {
+			return jobID[:index]
+		}
+	}
+	return jobID
+}
+
+// evalQueue is a queue of evaluations
+type evalQueue interface {
+	push(eval *structs.Evaluation)
+	pop() *structs.Evaluation
+	peek() *structs.Evaluation
+	len() int
+}
+
+// pendingQueue is an evalQueue ordered by priority and create index
+type pendingQueue struct {
+	pending PendingEvaluations
+}
+
+func (q *pendingQueue) push(eval *structs.Evaluation) {
+	heap.Push(&q.pending, eval)
+}
+
+func (q *pendingQueue) pop() *structs.Evaluation {
+	return heap.Pop(&q.pending).(*structs.Evaluation)
+}
+
+func (q *pendingQueue) peek() *structs.Evaluation {
+	return q.pending.Peek()
+}
+
+func (q *pendingQueue) len() int {
+	return len(q.pending)
+}
+
+// fairQueue is an evalQueue which keeps the evaluations of each key in a
+// separate queue. Evaluations of higher priority always go first, and the
+// queues with evaluations of the same priority are dequeued by weighted fair
+// queuing.
+type fairQueue struct {
+	// key returns the key of the queue of an evaluation
+	key func(eval *structs.Evaluation) string
+
+	// weight returns the weight of a key. All keys have a weight of 1 if it
+	// is nil.
+	weight func(key string) int
+
+	// newQueue returns an empty queue for a key
+	newQueue func() evalQueue
+
+	// keys is the heap of active keys, ordered by the key to dequeue next
+	keys fairKeys
+
+	// active is the active key by its name
+	active map[string]*fairKey
+
+	// vtime is the virtual time of the queue. Keys that become active start
+	// at this time, so they can't make up for the time they were idle by
+	// starving the others.
+	vtime float64
+
+	// seq is the number of keys that have become active
+	seq uint64
+
+	// size is the number of evaluations in the queues
+	size int
+}
+
+// fairKey is a key of a fairQueue with evaluations waiting
+type fairKey struct {
+	// name is the key returned for the evaluations
+	name string
+
+	// queue is the queue of evaluations of the key
+	queue evalQueue
+
+	// finish is the virtual time of the key, which advances by the inverse
+	// of the key's weight for each dequeued evaluation. The key with the
+	// lowest virtual time is dequeued first.
+	finish float64
+
+	// activated is the order in which the key became active and is used to
+	// break ties between keys at the same virtual time with evaluations of
+	// the same priority and create index.
+	activated uint64
+
+	// index is the index of the key in the heap
+	index int
+}
+
+func newFairQueue(key func(*structs.Evaluation) string, weight func(string) int, newQueue func() evalQueue) *fairQueue {
+	return &fairQueue{
+		key:      key,
+		weight:   weight,
+		newQueue: newQueue,
+		active:   make(map[string]*fairKey),
+	}
+}
+
+func (q *fairQueue) push(eval *structs.Evaluation) {
+	name := q.key(eval)
+	key, ok := q.active[name]
+	if !ok {
+		q.seq += 1
+		key = &fairKey{
+			name:      name,
+			queue:     q.newQueue(),
+			finish:    q.vtime,
+			activated: q.seq,
+		}
+		key.queue.push(eval)
+		q.active[name] = key
+		heap.Push(&q.keys, key)
+	} else {
+		// The next evaluation of the key may have changed
+		key.queue.push(eval)
+		heap.Fix(&q.keys, key.index)
+	}
+	q.size += 1
+}
+
+// pop removes the next evaluation, charging its key the inverse of the key's
+// weight.
+func (q *fairQueue) pop() *structs.Evaluation {
+	if len(q.keys) == 0 {
 		return nil
 	}
-	return p[n-1]
+
+	key := q.keys[0]
+	eval := key.queue.pop()
+	q.size -= 1
+
+	// Advance the virtual time of the queue to the start of this evaluation
+	// and the virtual time of the key past it
+	weight := 1
+	if q.weight != nil {
+		weight = q.weight(key.name)
+	}
+	if key.finish > q.vtime {
+		q.vtime = key.finish
+	}
+	key.finish += 1 / float64(weight)
+
+	if key.queue.len() != 0 {
+		heap.Fix(&q.keys, key.index)
+		return eval
+	}
+
+	// The key is idle
+	heap.Pop(&q.keys)
+	delete(q.active, key.name)
+	return eval
+}
+
+func (q *fairQueue) peek() *structs.Evaluation {
+	if len(q.keys) == 0 {
+		return nil
+	}
+	return q.keys[0].queue.peek()
+}
+
+func (q *fairQueue) len() int {
+	return q.size
+}
+
+// fairKeys is a heap of the active keys of a fairQueue. Evaluations of higher
+// priority always go first, followed by the key with the lowest virtual time,
+// then the evaluation created first and then the key activated first.
+type fairKeys []*fairKey
+
+func (k fairKeys) Len() int {
+	return len(k)
+}
+
+func (k fairKeys) Less(i, j int) bool {
+	ei, ej := k[i].queue.peek(), k[j].queue.peek()
+	switch {
+	case ei.Priority != ej.Priority:
+		return ei.Priority > ej.Priority
+	case k[i].finish != k[j].finish:
+		return k[i].finish < k[j].finish
+	case ei.CreateIndex != ej.CreateIndex:
+		return ei.CreateIndex < ej.CreateIndex
+	default:
+		return k[i].activated < k[j].activated
+	}
+}
+
+func (k fairKeys) Swap(i, j int) {
+	k[i], k[j] = k[j], k[i]
+	k[i].index = i
+	k[j].index = j
+}
+
+func (k *fairKeys) Push(x interface{}) {
+	key := x.(*fairKey)
+	key.index = len(*k)
+	*k = append(*k, key)
+}
+
+func (k *fairKeys) Pop() interface{} {
+	old := *k
+	n := len(old)
+	key := old[n-1]
+	old[n-1] = nil
+	*k = old[:n-1]
+	return key
 }
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -455,7 +458,7 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	// Check the stats
+	// Check the stats, eval2 is coalesced into eval3
 	stats = b.Stats()
 	if stats.TotalReady != 2 {
 		t.Fatalf("bad: %#v", stats)
@@ -463,47 +466,13 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 	if stats.TotalUnacked != 0 {
 		t.Fatalf("bad: %#v", stats)
 	}
-	if stats.TotalBlocked != 2 {
-		t.Fatalf("bad: %#v", stats)
-	}
-
-	// Dequeue should work
-	out, token, err = b.Dequeue(defaultSched, time.Second)
-	if err != nil {
-		t.Fatalf("err: %v", err)
-	}
-	if out != eval2 {
-		t.Fatalf("bad : %#v", out)
-	}
-
-	// Check the stats
-	stats = b.Stats()
-	if stats.TotalReady != 1 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalUnacked != 1 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalBlocked != 2 {
+	if stats.TotalBlocked != 1 {
 		t.Fatalf("bad: %#v", stats)
 	}
 
-	// Ack out
-	err = b.Ack(eval2.ID, token)
-	if err != nil {
-		t.Fatalf("err: %v", err)
-	}
-
-	// Check the stats
-	stats = b.Stats()
-	if stats.TotalReady != 2 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalUnacked != 0 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalBlocked != 1 {
-		t.Fatalf("bad: %#v", stats)
+	coalesced := b.Coalesced(time.Second)
+	if len(coalesced) != 1 || coalesced[0].ID != eval2.ID || coalesced[0].CoalescedInto != eval3.ID {
+		t.Fatalf("bad: %#v", coalesced)
 	}
 
 	// Dequeue should work
@@ -624,6 +593,71 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 	}
 }
 
+func TestEvalBroker_Coalesce(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	// Nothing is coalesced yet
+	require.Nil(b.Coalesced(10 * time.Millisecond))
+
+	eval := mock.Eval()
+	eval.CreateIndex = 10
+	b.Enqueue(eval)
+
+	// Blocked behind the first eval, out of order
+	eval3 := mock.Eval()
+	eval3.JobID = eval.JobID
+	eval3.CreateIndex = 13
+	b.Enqueue(eval3)
+
+	eval2 := mock.Eval()
+	eval2.JobID = eval.JobID
+	eval2.CreateIndex = 12
+	b.Enqueue(eval2)
+
+	eval4 := mock.Eval()
+	eval4.JobID = eval.JobID
+	eval4.CreateIndex = 11
+	b.Enqueue(eval4)
+
+	require.Equal(3, b.Stats().TotalBlocked)
+
+	out, token, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(eval, out)
+	require.NoError(b.Ack(eval.ID, token))
+
+	// Only the most recent eval is ready
+	stats := b.Stats()
+	require.Equal(1, stats.TotalReady)
+	require.Equal(0, stats.TotalBlocked)
+
+	out, token, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(eval3, out)
+	require.NoError(b.Ack(eval3.ID, token))
+
+	// The others are canceled with a link to it
+	coalesced := b.Coalesced(time.Second)
+	require.Len(coalesced, 2)
+	ids := []string{coalesced[0].ID, coalesced[1].ID}
+	require.ElementsMatch([]string{eval2.ID, eval4.ID}, ids)
+	for _, c := range coalesced {
+		require.Equal(structs.EvalStatusCancelled, c.Status)
+		require.Equal(eval3.ID, c.CoalescedInto)
+		require.Contains(c.StatusDescription, eval3.ID)
+	}
+
+	// The broker evals aren't modified
+	require.Equal(structs.EvalStatusPending, eval2.Status)
+	require.Empty(eval2.CoalescedInto)
+
+	// The list is drained
+	require.Nil(b.Coalesced(10 * time.Millisecond))
+}
+
 func TestEvalBroker_Enqueue_Disable(t *testing.T) {
 	t.Parallel()
 	b := testBroker(t, 0)
@@ -869,6 +903,184 @@ func TestEvalBroker_Dequeue_Fairness(t *testing.T) {
 	}
 }
 
+// Ensure weighted fairness between namespaces at the same priority
+func TestEvalBroker_Dequeue_NamespaceFairness(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+	b.SetNamespaceWeights(map[string]int{"n2": 2})
+
+	// A namespace with many evaluations enqueued first
+	for i := 0; i < 100; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "n1"
+		eval.CreateIndex = uint64(i)
+		b.Enqueue(eval)
+	}
+	for i := 100; i < 110; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "n2"
+		eval.CreateIndex = uint64(i)
+		b.Enqueue(eval)
+	}
+
+	// A higher priority evaluation always goes first
+	high := mock.Eval()
+	high.Namespace = "n1"
+	high.Priority = 80
+	b.Enqueue(high)
+
+	stats := b.Stats()
+	require.Equal(101, stats.ByNamespace["n1"].Ready)
+	require.Equal(10, stats.ByNamespace["n2"].Ready)
+
+	out, _, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(high, out)
+
+	// The namespace with twice the weight is dequeued twice as often
+	counts := make(map[string]int)
+	for i := 0; i < 15; i++ {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(err)
+		counts[out.Namespace]++
+	}
+	require.Equal(5, counts["n1"])
+	require.Equal(10, counts["n2"])
+
+	// The evaluations of each namespace stay in FIFO order
+	out, _, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal("n1", out.Namespace)
+	require.Equal(uint64(5), out.CreateIndex)
+
+	stats = b.Stats()
+	require.Equal(94, stats.ByNamespace["n1"].Ready)
+	require.Equal(0, stats.ByNamespace["n2"].Ready)
+}
+
+// Ensure a namespace becoming active doesn't starve the others
+func TestEvalBroker_Dequeue_NamespaceFairness_Idle(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	for i := 0; i < 20; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "n1"
+		b.Enqueue(eval)
+	}
+	for i := 0; i < 10; i++ {
+		_, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(err)
+	}
+
+	// The idle namespace doesn't get the share it didn't use
+	for i := 0; i < 10; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "n2"
+		b.Enqueue(eval)
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 10; i++ {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(err)
+		counts[out.Namespace]++
+	}
+	require.Equal(5, counts["n1"])
+	require.Equal(5, counts["n2"])
+}
+
+// Ensure jobs launched from the same job don't starve the other jobs of the
+// namespace
+func TestEvalBroker_Dequeue_ParentJobFairness(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	// Many dispatched jobs enqueued first
+	for i := 0; i < 100; i++ {
+		eval := mock.Eval()
+		eval.JobID = fmt.Sprintf("batch%s%d-%s", structs.DispatchLaunchSuffix, i, uuid.Generate()[:8])
+		eval.CreateIndex = uint64(i)
+		b.Enqueue(eval)
+	}
+	var others []*structs.Evaluation
+	for i := 100; i < 105; i++ {
+		eval := mock.Eval()
+		eval.CreateIndex = uint64(i)
+		b.Enqueue(eval)
+		others = append(others, eval)
+	}
+
+	// The dispatched jobs share a queue with the same share as each other job
+	var out []*structs.Evaluation
+	for i := 0; i < 10; i++ {
+		eval, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(err)
+		out = append(out, eval)
+	}
+
+	dispatched := 0
+	for _, eval := range out {
+		if parentJobID(eval.JobID) == "batch" {
+			dispatched++
+		}
+	}
+	require.Equal(5, dispatched)
+	for _, eval := range others {
+		require.Contains(out, eval)
+	}
+}
+
+func TestReadyEvaluations_Order(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ready := NewReadyEvaluations(nil)
+
+	// Evaluations of many jobs of different priorities, pushed out of order
+	var evals []*structs.Evaluation
+	for i := 0; i < 1000; i++ {
+		eval := mock.Eval()
+		eval.Priority = 10 * (i % 5)
+		eval.CreateIndex = uint64(i)
+		evals = append(evals, eval)
+	}
+	for _, i := range rand.Perm(len(evals)) {
+		ready.Push(evals[i])
+	}
+
+	// Each job has its own key, so the evaluations are dequeued by priority
+	// and then create index
+	sort.Slice(evals, func(i, j int) bool {
+		if evals[i].Priority != evals[j].Priority {
+			return evals[i].Priority > evals[j].Priority
+		}
+		return evals[i].CreateIndex < evals[j].CreateIndex
+	})
+	for _, eval := range evals {
+		require.Equal(eval, ready.Peek())
+		require.Equal(eval, ready.Pop())
+	}
+	require.Nil(ready.Peek())
+	require.Nil(ready.Pop())
+}
+
+func TestEvalBroker_parentJobID(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	require.Equal("example", parentJobID("example"))
+	require.Equal("example", parentJobID("example/dispatch-1573067564-8e3b4a1c"))
+	require.Equal("example", parentJobID("example/periodic-1573067564"))
+	require.Equal("example/periodic-1", parentJobID("example/periodic-1/dispatch-1573067564-8e3b4a1c"))
+	require.Equal("/dispatch-1", parentJobID("/dispatch-1"))
+}
+
 // Ensure we get unblocked
 func TestEvalBroker_Dequeue_Blocked(t *testing.T) {
 	t.Parallel()
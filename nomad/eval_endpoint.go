@@ -330,6 +330,65 @@ func (e *Eval) Reap(args *structs.EvalDeleteRequest,
 	return nil
 }
 
+// Delete is used to delete evaluations, which is only allowed while the eval
+// broker is paused. It is meant to recover from a backlog of evaluations that
+// can't be processed.
+func (e *Eval) Delete(args *structs.EvalDeleteRequest,
+	reply *structs.GenericResponse) error {
+	if done, err := e.srv.forward("Eval.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "delete"}, time.Now())
+
+	// Deleting evaluations requires a management token
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.Evals) == 0 {
+		return fmt.Errorf("missing evaluation IDs")
+	}
+	if len(args.Allocs) != 0 {
+		return fmt.Errorf("allocations can't be deleted with evaluations")
+	}
+
+	// The broker must be paused so the evaluations aren't being processed
+	snap, err := e.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	_, schedConfig, err := snap.SchedulerConfig()
+	if err != nil {
+		return err
+	}
+	if schedConfig == nil || !schedConfig.PauseEvalBroker {
+		return fmt.Errorf("eval broker is enabled; the eval broker must be paused to delete evaluations")
+	}
+
+	ws := memdb.NewWatchSet()
+	for _, evalID := range args.Evals {
+		eval, err := snap.EvalByID(ws, evalID)
+		if err != nil {
+			return err
+		}
+		if eval == nil {
+			return fmt.Errorf("evaluation %q not found", evalID)
+		}
+	}
+
+	// Update via Raft
+	_, index, err := e.srv.raftApply(structs.EvalDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
 // List is used to get a list of the evaluations in the system
 func (e *Eval) List(args *structs.EvalListRequest,
 	reply *structs.EvalListResponse) error {
@@ -350,26 +409,33 @@ func (e *Eval) List(args *structs.EvalListRequest,
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Scan all the evaluations
-			var err error
-			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.EvalsByIDPrefix(ws, args.RequestNamespace(), prefix)
-			} else {
-				iter, err = state.EvalsByNamespace(ws, args.RequestNamespace())
-			}
+			// Scan the evaluations, starting at the page of the next token
+			iter, err := state.EvalsByIDPrefixFrom(ws, args.RequestNamespace(), args.Prefix, args.NextToken)
 			if err != nil {
 				return err
 			}
 
+			iter = memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+				return args.ShouldBeFiltered(raw.(*structs.Evaluation))
+			})
+
 			var evals []*structs.Evaluation
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				eval := raw.(*structs.Evaluation)
-				evals = append(evals, eval)
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Evaluation).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					return raw, nil
+				},
+				func(stub interface{}) {
+					evals = append(evals, stub.(*structs.Evaluation))
+				})
+			if err != nil {
+				return err
+			}
+			reply.NextToken, err = paginator.page()
+			if err != nil {
+				return err
 			}
 			reply.Evaluations = evals
 
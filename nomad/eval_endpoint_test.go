@@ -662,6 +662,97 @@ func TestEvalEndpoint_Reap(t *testing.T) {
 	}
 }
 
+func TestEvalEndpoint_Delete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	eval1 := mock.Eval()
+	require.NoError(s1.fsm.State().UpsertEvals(1000, []*structs.Evaluation{eval1}))
+
+	// Deleting fails while the eval broker is enabled
+	del := &structs.EvalDeleteRequest{
+		Evals:        []string{eval1.ID},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Delete", del, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "eval broker must be paused")
+
+	// Pause the eval broker
+	config := &structs.SchedulerSetConfigRequest{
+		Config:       structs.SchedulerConfiguration{PauseEvalBroker: true},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var configResp structs.SchedulerSetConfigurationResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", config, &configResp))
+	require.False(s1.evalBroker.Enabled())
+	require.False(s1.blockedEvals.Enabled())
+
+	// Deleting an unknown evaluation fails
+	del.Evals = []string{eval1.ID, uuid.Generate()}
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Delete", del, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+
+	del.Evals = []string{eval1.ID}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.Delete", del, &resp))
+	require.NotZero(resp.Index)
+
+	// Ensure deleted
+	ws := memdb.NewWatchSet()
+	out, err := s1.fsm.State().EvalByID(ws, eval1.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	// Resuming the eval broker enables it again
+	config.Config.PauseEvalBroker = false
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", config, &configResp))
+	require.True(s1.evalBroker.Enabled())
+	require.True(s1.blockedEvals.Enabled())
+}
+
+func TestEvalEndpoint_Delete_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	eval1 := mock.Eval()
+	require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{eval1}))
+
+	// Tokens that aren't management tokens are denied
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+
+	del := &structs.EvalDeleteRequest{
+		Evals: []string{eval1.ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Delete", del, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// A management token passes the ACL check
+	del.AuthToken = root.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Delete", del, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "eval broker must be paused")
+}
+
 func TestEvalEndpoint_List(t *testing.T) {
 	t.Parallel()
 
@@ -718,6 +809,97 @@ func TestEvalEndpoint_List(t *testing.T) {
 
 }
 
+func TestEvalEndpoint_List_FilterPaginate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the evaluations
+	eval1 := mock.Eval()
+	eval1.ID = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
+	eval1.JobID = "example"
+	eval2 := mock.Eval()
+	eval2.ID = "aaaabbbb-3350-4b4b-d185-0e1992ed43e9"
+	eval2.JobID = "example"
+	eval2.Status = structs.EvalStatusBlocked
+	eval3 := mock.Eval()
+	eval3.ID = "aaaacccc-3350-4b4b-d185-0e1992ed43e9"
+	eval3.JobID = "example"
+	eval3.TriggeredBy = structs.EvalTriggerNodeUpdate
+	eval3.NodeID = "node1"
+	eval4 := mock.Eval()
+	eval4.ID = "aaaadddd-3350-4b4b-d185-0e1992ed43e9"
+	require.NoError(s1.fsm.State().UpsertEvals(1000, []*structs.Evaluation{eval1, eval2, eval3, eval4}))
+
+	cases := []struct {
+		name      string
+		req       *structs.EvalListRequest
+		expected  []string
+		nextToken string
+	}{
+		{
+			name:     "by job",
+			req:      &structs.EvalListRequest{FilterJobID: "example"},
+			expected: []string{eval1.ID, eval2.ID, eval3.ID},
+		},
+		{
+			name:     "by status",
+			req:      &structs.EvalListRequest{FilterEvalStatus: structs.EvalStatusBlocked},
+			expected: []string{eval2.ID},
+		},
+		{
+			name:     "by trigger and node",
+			req:      &structs.EvalListRequest{FilterTriggeredBy: structs.EvalTriggerNodeUpdate, FilterNodeID: "node1"},
+			expected: []string{eval3.ID},
+		},
+		{
+			name: "first page",
+			req: &structs.EvalListRequest{
+				QueryOptions: structs.QueryOptions{PerPage: 2},
+			},
+			expected:  []string{eval1.ID, eval2.ID},
+			nextToken: eval3.ID,
+		},
+		{
+			name: "last page",
+			req: &structs.EvalListRequest{
+				QueryOptions: structs.QueryOptions{PerPage: 2, NextToken: eval3.ID},
+			},
+			expected: []string{eval3.ID, eval4.ID},
+		},
+		{
+			name: "filtered page",
+			req: &structs.EvalListRequest{
+				FilterJobID:  "example",
+				QueryOptions: structs.QueryOptions{PerPage: 1, NextToken: eval2.ID},
+			},
+			expected:  []string{eval2.ID},
+			nextToken: eval3.ID,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.req.Region = "global"
+			tc.req.Namespace = structs.DefaultNamespace
+
+			var resp structs.EvalListResponse
+			require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.List", tc.req, &resp))
+
+			var ids []string
+			for _, eval := range resp.Evaluations {
+				ids = append(ids, eval.ID)
+			}
+			require.Equal(tc.expected, ids)
+			require.Equal(tc.nextToken, resp.NextToken)
+		})
+	}
+}
+
 func TestEvalEndpoint_List_ACL(t *testing.T) {
 	t.Parallel()
 
@@ -251,6 +251,12 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applySchedulerConfigUpdate(buf[1:], log.Index)
 	case structs.NodeBatchDeregisterRequestType:
 		return n.applyDeregisterNodeBatch(buf[1:], log.Index)
+	case structs.BatchNodeUpdateEligibilityRequestType:
+		return n.applyBatchNodeEligibilityUpdate(buf[1:], log.Index)
+	case structs.DeploymentStepRequestType:
+		return n.applyDeploymentStep(buf[1:], log.Index)
+	case structs.DeploymentHealthGateRequestType:
+		return n.applyDeploymentHealthGate(buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -422,6 +428,43 @@ func (n *nomadFSM) applyNodeEligibilityUpdate(buf []byte, index uint64) interfac
 	return nil
 }
 
+func (n *nomadFSM) applyBatchNodeEligibilityUpdate(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "batch_node_eligibility_update"}, time.Now())
+	var req structs.BatchNodeUpdateEligibilityRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	// Lookup the existing nodes so we can detect which become eligible
+	nodes := make(map[string]*structs.Node, len(req.Updates))
+	for nodeID := range req.Updates {
+		node, err := n.state.NodeByID(nil, nodeID)
+		if err != nil {
+			n.logger.Error("BatchUpdateNodeEligibility failed to lookup node", "node_id", nodeID, "error", err)
+			return err
+		}
+		nodes[nodeID] = node
+	}
+
+	if err := n.state.BatchUpdateNodeEligibility(index, req.UpdatedAt, req.Updates, req.NodeEvents); err != nil {
+		n.logger.Error("BatchUpdateNodeEligibility failed", "error", err)
+		return err
+	}
+
+	// Unblock evals for the nodes computed node class if they are
+	// transitioning to eligible.
+	for nodeID, eligibility := range req.Updates {
+		node := nodes[nodeID]
+		if node != nil && node.SchedulingEligibility == structs.NodeSchedulingIneligible &&
+			eligibility == structs.NodeSchedulingEligible {
+			n.blockedEvals.Unblock(node.ComputedClass, index)
+			n.blockedEvals.UnblockNode(nodeID, index)
+		}
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyUpsertJob(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_job"}, time.Now())
 	var req structs.JobRegisterRequest
@@ -522,7 +565,7 @@ func (n *nomadFSM) applyDeregisterJob(buf []byte, index uint64) interface{} {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	return n.state.WithWriteTransaction(func(tx state.Txn) error {
+	err := n.state.WithWriteTransaction(func(tx state.Txn) error {
 		if err := n.handleJobDeregister(index, req.JobID, req.Namespace, req.Purge, tx); err != nil {
 			n.logger.Error("deregistering job failed", "error", err)
 			return err
@@ -530,6 +573,15 @@ func (n *nomadFSM) applyDeregisterJob(buf []byte, index uint64) interface{} {
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	// Unblock evals waiting on the job as a dependency so they can report
+	// that it was stopped
+	n.blockedEvals.UnblockDependents(req.JobID, req.Namespace, index)
+	return nil
 }
 
 func (n *nomadFSM) applyBatchDeregisterJob(buf []byte, index uint64) interface{} {
@@ -564,6 +616,9 @@ func (n *nomadFSM) applyBatchDeregisterJob(buf []byte, index uint64) interface{}
 
 	// perform the side effects outside the transactions
 	n.handleUpsertedEvals(req.Evals)
+	for jobNS := range req.Jobs {
+		n.blockedEvals.UnblockDependents(jobNS.ID, jobNS.Namespace, index)
+	}
 	return nil
 }
 
@@ -648,9 +703,11 @@ func (n *nomadFSM) handleUpsertedEval(eval *structs.Evaluation) {
 	} else if eval.ShouldBlock() {
 		n.blockedEvals.Block(eval)
 	} else if eval.Status == structs.EvalStatusComplete &&
-		len(eval.FailedTGAllocs) == 0 {
+		len(eval.FailedTGAllocs) == 0 && eval.BlockedEval == "" {
 		// If we have a successful evaluation for a node, untrack any
-		// blocked evaluation
+		// blocked evaluation. Evaluations which completed by creating a
+		// blocked evaluation, to wait for the dependencies of their job,
+		// must not untrack it.
 		n.blockedEvals.Untrack(eval.JobID, eval.Namespace)
 	}
 }
@@ -719,11 +776,19 @@ func (n *nomadFSM) applyAllocClientUpdate(buf []byte, index uint64) interface{}
 	// Create a watch set
 	ws := memdb.NewWatchSet()
 
-	// Updating the allocs with the job id and task group name
+	// Updating the allocs with the job id and task group name, and collect the
+	// jobs whose allocations started or completed as their dependents may be
+	// able to make progress
+	progressed := make(map[structs.NamespacedID]struct{})
 	for _, alloc := range req.Alloc {
 		if existing, _ := n.state.AllocByID(ws, alloc.ID); existing != nil {
 			alloc.JobID = existing.JobID
 			alloc.TaskGroup = existing.TaskGroup
+
+			if alloc.ClientStatus == structs.AllocClientStatusRunning ||
+				alloc.ClientStatus == structs.AllocClientStatusComplete {
+				progressed[structs.NewNamespacedID(existing.JobID, existing.Namespace)] = struct{}{}
+			}
 		}
 	}
 
@@ -766,6 +831,11 @@ func (n *nomadFSM) applyAllocClientUpdate(buf []byte, index uint64) interface{}
 		}
 	}
 
+	// Unblock evals waiting on the jobs as a dependency
+	for job := range progressed {
+		n.blockedEvals.UnblockDependents(job.ID, job.Namespace, index)
+	}
+
 	return nil
 }
 
@@ -876,6 +946,20 @@ func (n *nomadFSM) applyDeploymentStatusUpdate(buf []byte, index uint64) interfa
 		return err
 	}
 
+	// Unblock evals waiting on the job of a successful deployment as a
+	// dependency
+	if req.DeploymentUpdate != nil && req.DeploymentUpdate.Status == structs.DeploymentStatusSuccessful {
+		ws := memdb.NewWatchSet()
+		d, err := n.state.DeploymentByID(ws, req.DeploymentUpdate.DeploymentID)
+		if err != nil {
+			n.logger.Error("looking up deployment failed", "deployment_id", req.DeploymentUpdate.DeploymentID, "error", err)
+			return err
+		}
+		if d != nil {
+			n.blockedEvals.UnblockDependents(d.JobID, d.Namespace, index)
+		}
+	}
+
 	n.handleUpsertedEval(req.Eval)
 	return nil
 }
@@ -897,6 +981,41 @@ func (n *nomadFSM) applyDeploymentPromotion(buf []byte, index uint64) interface{
 	return nil
 }
 
+// applyDeploymentStep is used to advance the rollout steps of a deployment
+func (n *nomadFSM) applyDeploymentStep(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_deployment_step"}, time.Now())
+	var req structs.ApplyDeploymentStepRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateDeploymentStep(index, &req); err != nil {
+		n.logger.Error("UpdateDeploymentStep failed", "error", err)
+		return err
+	}
+
+	n.handleUpsertedEval(req.Eval)
+	return nil
+}
+
+// applyDeploymentHealthGate is used to record the result of a task group's
+// health gate on a deployment
+func (n *nomadFSM) applyDeploymentHealthGate(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_deployment_health_gate"}, time.Now())
+	var req structs.ApplyDeploymentHealthGateRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateDeploymentHealthGate(index, &req); err != nil {
+		n.logger.Error("UpdateDeploymentHealthGate failed", "error", err)
+		return err
+	}
+
+	n.handleUpsertedEval(req.Eval)
+	return nil
+}
+
 // applyDeploymentAllocHealth is used to set the health of allocations as part
 // of a deployment
 func (n *nomadFSM) applyDeploymentAllocHealth(buf []byte, index uint64) interface{} {
@@ -1051,9 +1170,18 @@ func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interfac
 		if err != nil {
 			return err
 		}
+		if applied {
+			n.evalBroker.SetNamespaceWeights(req.Config.NamespaceWeights)
+		}
 		return applied
 	}
-	return n.state.SchedulerSetConfig(index, &req.Config)
+	if err := n.state.SchedulerSetConfig(index, &req.Config); err != nil {
+		return err
+	}
+
+	// Update the weights of the namespaces in the eval broker
+	n.evalBroker.SetNamespaceWeights(req.Config.NamespaceWeights)
+	return nil
 }
 
 func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
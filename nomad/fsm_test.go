@@ -1464,6 +1464,104 @@ func TestFSM_UpdateAllocFromClient_Unblock(t *testing.T) {
 	})
 }
 
+func TestFSM_UpdateAllocFromClient_UnblockDependents(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+	fsm.blockedEvals.SetEnabled(true)
+	state := fsm.State()
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(state.UpsertJobSummary(8, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(10, []*structs.Allocation{alloc}))
+
+	// Mark an eval waiting on the job of the alloc as blocked.
+	eval := mock.Eval()
+	eval.UnmetDependencies = []string{alloc.JobID}
+	eval.SnapshotIndex = 10
+	fsm.blockedEvals.Block(eval)
+	require.Equal(1, fsm.blockedEvals.Stats().TotalBlocked)
+
+	// A pending alloc doesn't unblock it
+	update := &structs.Allocation{
+		ID:           alloc.ID,
+		ClientStatus: structs.AllocClientStatusPending,
+	}
+	req := structs.AllocUpdateRequest{
+		Alloc: []*structs.Allocation{update},
+	}
+	buf, err := structs.Encode(structs.AllocClientUpdateRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+	require.Equal(1, fsm.blockedEvals.Stats().TotalBlocked)
+
+	// A running alloc does
+	update.ClientStatus = structs.AllocClientStatusRunning
+	buf, err = structs.Encode(structs.AllocClientUpdateRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	testutil.WaitForResult(func() (bool, error) {
+		bStats := fsm.blockedEvals.Stats()
+		if bStats.TotalBlocked != 0 {
+			return false, fmt.Errorf("bad: %#v", bStats)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+}
+
+func TestFSM_DeregisterJob_UnblockDependents(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+	fsm.blockedEvals.SetEnabled(true)
+
+	job := mock.Job()
+	req := structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.JobRegisterRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Mark an eval waiting on the job as blocked.
+	eval := mock.Eval()
+	eval.UnmetDependencies = []string{job.ID}
+	eval.SnapshotIndex = 1
+	fsm.blockedEvals.Block(eval)
+	require.Equal(1, fsm.blockedEvals.Stats().TotalBlocked)
+
+	// Stopping the job unblocks it
+	req2 := structs.JobDeregisterRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err = structs.Encode(structs.JobDeregisterRequestType, req2)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	testutil.WaitForResult(func() (bool, error) {
+		bStats := fsm.blockedEvals.Stats()
+		if bStats.TotalBlocked != 0 {
+			return false, fmt.Errorf("bad: %#v", bStats)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+}
+
 func TestFSM_UpdateAllocFromClient(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2937,6 +3035,7 @@ func TestFSM_SchedulerConfig(t *testing.T) {
 				SystemSchedulerEnabled: true,
 				BatchSchedulerEnabled:  true,
 			},
+			NamespaceWeights: map[string]int{"n1": 3},
 		},
 	}
 	buf, err := structs.Encode(structs.SchedulerConfigRequestType, req)
@@ -2954,9 +3053,14 @@ func TestFSM_SchedulerConfig(t *testing.T) {
 	require.Equal(config.PreemptionConfig.SystemSchedulerEnabled, req.Config.PreemptionConfig.SystemSchedulerEnabled)
 	require.Equal(config.PreemptionConfig.BatchSchedulerEnabled, req.Config.PreemptionConfig.BatchSchedulerEnabled)
 
+	// Verify the eval broker uses the namespace weights
+	require.Equal(3, fsm.evalBroker.namespaceWeight("n1"))
+	require.Equal(defaultNamespaceWeight, fsm.evalBroker.namespaceWeight("n2"))
+
 	// Now use CAS and provide an old index
 	req.CAS = true
 	req.Config.PreemptionConfig = structs.PreemptionConfig{SystemSchedulerEnabled: false, BatchSchedulerEnabled: false}
+	req.Config.NamespaceWeights = nil
 	req.Config.ModifyIndex = config.ModifyIndex - 1
 	buf, err = structs.Encode(structs.SchedulerConfigRequestType, req)
 	require.Nil(err)
@@ -2971,4 +3075,5 @@ func TestFSM_SchedulerConfig(t *testing.T) {
 	// Verify that preemption is still enabled
 	require.True(config.PreemptionConfig.SystemSchedulerEnabled)
 	require.True(config.PreemptionConfig.BatchSchedulerEnabled)
+	require.Equal(3, fsm.evalBroker.namespaceWeight("n1"))
 }
@@ -65,6 +65,7 @@ func NewJobEndpoints(s *Server) *Job {
 		validators: []jobValidator{
 			jobConnectHook{},
 			jobValidate{},
+			jobHealthGateValidator{srv: s},
 		},
 	}
 }
@@ -178,6 +179,11 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Validate the jobs the job depends on
+	if err := validateJobDependencies(snap, args.Job); err != nil {
+		return err
+	}
+
 	// Ensure that the job has permissions for the requested Vault tokens
 	policies := args.Job.VaultPolicies()
 	if len(policies) != 0 {
@@ -891,30 +897,34 @@ func (j *Job) List(args *structs.JobListRequest,
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Capture all the jobs
-			var err error
-			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.JobsByIDPrefix(ws, args.RequestNamespace(), prefix)
-			} else {
-				iter, err = state.JobsByNamespace(ws, args.RequestNamespace())
-			}
+			// Capture the jobs, starting at the page of the next token
+			iter, err := state.JobsByIDPrefixFrom(ws, args.RequestNamespace(), args.Prefix, args.NextToken)
 			if err != nil {
 				return err
 			}
 
 			var jobs []*structs.JobListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				job := raw.(*structs.Job)
-				summary, err := state.JobSummaryByID(ws, args.RequestNamespace(), job.ID)
-				if err != nil {
-					return fmt.Errorf("unable to look up summary for job: %v", job.ID)
-				}
-				jobs = append(jobs, job.Stub(summary))
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Job).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					job := raw.(*structs.Job)
+					summary, err := state.JobSummaryByID(ws, args.RequestNamespace(), job.ID)
+					if err != nil {
+						return nil, fmt.Errorf("unable to look up summary for job: %v", job.ID)
+					}
+					return job.Stub(summary), nil
+				},
+				func(stub interface{}) {
+					jobs = append(jobs, stub.(*structs.JobListStub))
+				})
+			if err != nil {
+				return err
+			}
+			reply.NextToken, err = paginator.page()
+			if err != nil {
+				return err
 			}
 			reply.Jobs = jobs
 
@@ -1213,6 +1223,7 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		JobModifyIndex: updatedIndex,
 		Status:         structs.EvalStatusPending,
 		AnnotatePlan:   true,
+		PlacementTrace: args.Explain,
 		// Timestamps are added for consistency but this eval is never persisted
 		CreateTime: now,
 		ModifyTime: now,
@@ -1313,6 +1324,83 @@ func validateJobUpdate(old, new *structs.Job) error {
 	return nil
 }
 
+// validateJobDependencies validates that the jobs the job depends on exist and
+// can reach their required status, and that they don't depend on the job
+// themselves.
+func validateJobDependencies(snap *state.StateSnapshot, job *structs.Job) error {
+	if len(job.DependsOn) == 0 {
+		return nil
+	}
+
+	var mErr multierror.Error
+	ws := memdb.NewWatchSet()
+	for _, dep := range job.DependsOn {
+		depJob, err := snap.JobByID(ws, job.Namespace, dep.JobID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case depJob == nil:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("dependency job %q not found", dep.JobID))
+		case depJob.IsPeriodic() || depJob.IsParameterized():
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("dependency job %q is periodic or parameterized and never runs itself", dep.JobID))
+		case dep.Status == structs.JobDependencyStatusComplete && depJob.Type != structs.JobTypeBatch:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("dependency job %q can't complete since it is a %q job", dep.JobID, depJob.Type))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	cycle, err := jobDependencyCycle(snap, job)
+	if err != nil {
+		return err
+	}
+	if cycle != nil {
+		return fmt.Errorf("job dependencies form a cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// jobDependencyCycle returns the IDs of the jobs forming a cycle if the job
+// transitively depends on itself, or nil otherwise. The dependencies of other
+// jobs are read from the snapshot.
+func jobDependencyCycle(snap *state.StateSnapshot, job *structs.Job) ([]string, error) {
+	ws := memdb.NewWatchSet()
+	path := []string{job.ID}
+	visited := make(map[string]struct{})
+
+	var visit func(deps []*structs.JobDependency) ([]string, error)
+	visit = func(deps []*structs.JobDependency) ([]string, error) {
+		for _, dep := range deps {
+			if dep.JobID == job.ID {
+				return append(path, dep.JobID), nil
+			}
+			if _, ok := visited[dep.JobID]; ok {
+				continue
+			}
+			visited[dep.JobID] = struct{}{}
+
+			depJob, err := snap.JobByID(ws, job.Namespace, dep.JobID)
+			if err != nil {
+				return nil, err
+			}
+			if depJob == nil {
+				continue
+			}
+
+			path = append(path, dep.JobID)
+			if cycle, err := visit(depJob.DependsOn); err != nil || cycle != nil {
+				return cycle, err
+			}
+			path = path[:len(path)-1]
+		}
+		return nil, nil
+	}
+	return visit(job.DependsOn)
+}
+
 // Dispatch a parameterized job.
 func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) error {
 	if done, err := j.srv.forward("Job.Dispatch", args, args, reply); done {
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"fmt"
+	"net/url"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/helper"
@@ -217,3 +218,47 @@ func (jobValidate) Validate(job *structs.Job) (warnings []error, err error) {
 
 	return warnings, validationErrors.ErrorOrNil()
 }
+
+// jobHealthGateValidator rejects jobs with health gates whose URL host is not
+// in the server's list of allowed health gate hosts. Health gates are checked
+// by the servers, so without the allowlist any job could make the servers send
+// requests to internal addresses.
+type jobHealthGateValidator struct {
+	srv *Server
+}
+
+func (jobHealthGateValidator) Name() string {
+	return "health_gate"
+}
+
+func (v jobHealthGateValidator) Validate(job *structs.Job) (warnings []error, err error) {
+	var mErr multierror.Error
+	for _, tg := range job.TaskGroups {
+		if tg.Update == nil || tg.Update.HealthGate == nil {
+			continue
+		}
+
+		gateURL := tg.Update.HealthGate.URL
+		if !healthGateHostAllowed(gateURL, v.srv.config.HealthGateHosts) {
+			multierror.Append(&mErr, fmt.Errorf("Task group %q health gate URL %q is not allowed by the servers", tg.Name, gateURL))
+		}
+	}
+	return nil, mErr.ErrorOrNil()
+}
+
+// healthGateHostAllowed returns whether the host of the health gate URL is in
+// the allowed hosts. A host is allowed if either its host:port or its hostname
+// is listed.
+func healthGateHostAllowed(gateURL string, allowed []string) bool {
+	u, err := url.Parse(gateURL)
+	if err != nil {
+		return false
+	}
+
+	for _, host := range allowed {
+		if host == u.Host || host == u.Hostname() {
+			return true
+		}
+	}
+	return false
+}
@@ -275,6 +275,52 @@ func TestJobEndpoint_Register_ConnectWithSidecarTask(t *testing.T) {
 
 }
 
+func TestJobEndpoint_Register_HealthGateHosts(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.HealthGateHosts = []string{"gate.example.com", "127.0.0.1:8080"}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	register := func(gateURL string) error {
+		job := mock.Job()
+		job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+		job.TaskGroups[0].Update.HealthGate = &structs.HealthGate{
+			URL:      gateURL,
+			Timeout:  time.Second,
+			Attempts: 1,
+		}
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+
+	// Gates to allowed hosts are registered
+	require.NoError(register("https://gate.example.com/check"))
+	require.NoError(register("http://gate.example.com:9000/check"))
+	require.NoError(register("http://127.0.0.1:8080/check"))
+
+	// Gates to any other host are rejected
+	err := register("http://127.0.0.1:4646/v1/acl/tokens")
+	require.Error(err)
+	require.Contains(err.Error(), "is not allowed by the servers")
+
+	err = register("http://gate.example.com.evil.com/check")
+	require.Error(err)
+	require.Contains(err.Error(), "is not allowed by the servers")
+}
+
 func TestJobEndpoint_Register_ACL(t *testing.T) {
 	t.Parallel()
 
@@ -728,6 +774,97 @@ func TestJobEndpoint_Register_Dispatched(t *testing.T) {
 	require.Contains(err.Error(), "job can't be submitted with 'Dispatched'")
 }
 
+func TestJobEndpoint_Register_Dependencies(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the jobs to depend on
+	state := s1.fsm.State()
+	service := mock.Job()
+	service.ID = "service"
+	batch := mock.Job()
+	batch.ID = "batch"
+	batch.Type = structs.JobTypeBatch
+	periodic := mock.PeriodicJob()
+	periodic.ID = "periodic"
+	cyclic := mock.Job()
+	cyclic.ID = "cyclic"
+	cyclic.DependsOn = []*structs.JobDependency{{JobID: "web", Status: structs.JobDependencyStatusRunning}}
+	for i, job := range []*structs.Job{service, batch, periodic, cyclic} {
+		require.NoError(state.UpsertJob(uint64(100+i), job))
+	}
+
+	cases := []struct {
+		name string
+		deps []*structs.JobDependency
+		err  string
+	}{
+		{
+			name: "valid",
+			deps: []*structs.JobDependency{
+				{JobID: "service", Status: structs.JobDependencyStatusHealthy},
+				{JobID: "batch", Status: structs.JobDependencyStatusComplete},
+			},
+		},
+		{
+			name: "missing",
+			deps: []*structs.JobDependency{{JobID: "missing", Status: structs.JobDependencyStatusRunning}},
+			err:  `dependency job "missing" not found`,
+		},
+		{
+			name: "periodic",
+			deps: []*structs.JobDependency{{JobID: "periodic", Status: structs.JobDependencyStatusRunning}},
+			err:  `dependency job "periodic" is periodic or parameterized`,
+		},
+		{
+			name: "complete service",
+			deps: []*structs.JobDependency{{JobID: "service", Status: structs.JobDependencyStatusComplete}},
+			err:  `dependency job "service" can't complete`,
+		},
+		{
+			name: "self",
+			deps: []*structs.JobDependency{{JobID: "web", Status: structs.JobDependencyStatusRunning}},
+			err:  "Job can't depend on itself",
+		},
+		{
+			name: "cycle",
+			deps: []*structs.JobDependency{{JobID: "cyclic", Status: structs.JobDependencyStatusRunning}},
+			err:  "job dependencies form a cycle: web -> cyclic -> web",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.Job()
+			job.ID = "web"
+			job.DependsOn = tc.deps
+			req := &structs.JobRegisterRequest{
+				Job: job,
+				WriteRequest: structs.WriteRequest{
+					Region:    "global",
+					Namespace: job.Namespace,
+				},
+			}
+
+			var resp structs.JobRegisterResponse
+			err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+			if tc.err == "" {
+				require.NoError(err)
+				return
+			}
+			require.Error(err)
+			require.Contains(err.Error(), tc.err)
+		})
+	}
+}
+
 func TestJobEndpoint_Register_EnforceIndex(t *testing.T) {
 	t.Parallel()
 
@@ -3482,6 +3619,69 @@ func TestJobEndpoint_GetJobSummary_Blocking(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_ListJobs_FilterPaginate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the jobs
+	state := s1.fsm.State()
+	for i, id := range []string{"job-a", "job-b", "job-c"} {
+		job := mock.Job()
+		job.ID = id
+		if i == 1 {
+			job.Type = structs.JobTypeBatch
+		}
+		require.NoError(state.UpsertJob(uint64(1000+i), job))
+	}
+
+	// Page through the jobs
+	get := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			PerPage:   2,
+		},
+	}
+	var resp structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp))
+	require.Len(resp.Jobs, 2)
+	require.Equal("job-a", resp.Jobs[0].ID)
+	require.Equal("job-b", resp.Jobs[1].ID)
+	require.Equal("job-c", resp.NextToken)
+
+	get.NextToken = resp.NextToken
+	var resp2 structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp2))
+	require.Len(resp2.Jobs, 1)
+	require.Equal("job-c", resp2.Jobs[0].ID)
+	require.Empty(resp2.NextToken)
+
+	// Filter the jobs
+	get = &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			Filter:    `Type == batch`,
+		},
+	}
+	var resp3 structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp3))
+	require.Len(resp3.Jobs, 1)
+	require.Equal("job-b", resp3.Jobs[0].ID)
+
+	// Invalid filters are rejected
+	get.Filter = `Type ==`
+	var resp4 structs.JobListResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp4)
+	require.Error(err)
+	require.Contains(err.Error(), "failed to parse filter")
+}
+
 func TestJobEndpoint_ListJobs(t *testing.T) {
 	t.Parallel()
 
@@ -4413,6 +4613,50 @@ func TestJobEndpoint_Plan_WithDiff(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Plan_Explain(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a node that doesn't meet the job constraint
+	node := mock.Node()
+	node.Attributes["kernel.name"] = "freebsd"
+	require.NoError(node.ComputeClass())
+	require.NoError(state.UpsertNode(1000, node))
+
+	job := mock.Job()
+	planReq := &structs.JobPlanRequest{
+		Job:     job,
+		Explain: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var planResp structs.JobPlanResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+
+	metrics := planResp.FailedTGAllocs[job.TaskGroups[0].Name]
+	require.NotNil(metrics)
+	require.Len(metrics.PlacementTrace, 1)
+	require.Equal(node.ID, metrics.PlacementTrace[0].NodeID)
+	require.Equal("constraint", metrics.PlacementTrace[0].Checker)
+
+	// The trace is only recorded when explaining
+	planReq.Explain = false
+	planResp = structs.JobPlanResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+	require.Empty(planResp.FailedTGAllocs[job.TaskGroups[0].Name].PlacementTrace)
+}
+
 func TestJobEndpoint_Plan_NoDiff(t *testing.T) {
 	t.Parallel()
 
@@ -199,7 +199,10 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	s.autopilot.Start()
 
 	// Initialize scheduler configuration
-	s.getOrCreateSchedulerConfig()
+	schedConfig := s.getOrCreateSchedulerConfig()
+	if schedConfig != nil {
+		s.evalBroker.SetNamespaceWeights(schedConfig.NamespaceWeights)
+	}
 
 	// Enable the plan queue, since we are now the leader
 	s.planQueue.SetEnabled(true)
@@ -207,12 +210,13 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Start the plan evaluator
 	go s.planApply()
 
-	// Enable the eval broker, since we are now the leader
-	s.evalBroker.SetEnabled(true)
-
-	// Enable the blocked eval tracker, since we are now the leader
-	s.blockedEvals.SetEnabled(true)
-	s.blockedEvals.SetTimetable(s.fsm.TimeTable())
+	// Enable the eval broker and the blocked eval tracker, since we are now
+	// the leader, unless the eval broker is paused
+	if schedConfig == nil || !schedConfig.PauseEvalBroker {
+		s.evalBroker.SetEnabled(true)
+		s.blockedEvals.SetEnabled(true)
+		s.blockedEvals.SetTimetable(s.fsm.TimeTable())
+	}
 
 	// Enable the deployment watcher, since we are now the leader
 	s.deploymentWatcher.SetEnabled(true, s.State())
@@ -248,6 +252,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Reap any duplicate blocked evaluations
 	go s.reapDupBlockedEvaluations(stopCh)
 
+	// Reap any evaluations coalesced by the eval broker
+	go s.reapCoalescedEvaluations(stopCh)
+
 	// Periodically unblock failed allocations
 	go s.periodicUnblockFailedEvals(stopCh)
 
@@ -316,6 +323,32 @@ func (s *Server) restoreEvals() error {
 	return nil
 }
 
+// handleEvalBrokerStateChange pauses or resumes the eval broker and the
+// blocked eval tracker according to the scheduler configuration. When they are
+// resumed, the evaluations are restored from the state store since they were
+// flushed while paused.
+func (s *Server) handleEvalBrokerStateChange(schedConfig *structs.SchedulerConfiguration) error {
+	if !s.IsLeader() {
+		return nil
+	}
+
+	enabled := s.evalBroker.Enabled()
+	switch {
+	case schedConfig.PauseEvalBroker && enabled:
+		s.logger.Named("core").Info("pausing eval broker")
+		s.evalBroker.SetEnabled(false)
+		s.blockedEvals.SetEnabled(false)
+
+	case !schedConfig.PauseEvalBroker && !enabled:
+		s.logger.Named("core").Info("resuming eval broker")
+		s.evalBroker.SetEnabled(true)
+		s.blockedEvals.SetEnabled(true)
+		s.blockedEvals.SetTimetable(s.fsm.TimeTable())
+		return s.restoreEvals()
+	}
+	return nil
+}
+
 // restoreRevokingAccessors is used to restore Vault accessors that should be
 // revoked.
 func (s *Server) restoreRevokingAccessors() error {
@@ -511,7 +544,13 @@ func (s *Server) reapFailedEvaluations(stopCh chan struct{}) {
 			// Scan for a failed evaluation
 			eval, token, err := s.evalBroker.Dequeue([]string{failedQueue}, time.Second)
 			if err != nil {
-				return
+				// The broker is disabled while it is paused
+				select {
+				case <-stopCh:
+					return
+				case <-time.After(time.Second):
+					continue
+				}
 			}
 			if eval == nil {
 				continue
@@ -584,6 +623,36 @@ func (s *Server) reapDupBlockedEvaluations(stopCh chan struct{}) {
 	}
 }
 
+// reapCoalescedEvaluations is used to cancel the evaluations that the eval
+// broker coalesced into a more recent evaluation of the same job.
+func (s *Server) reapCoalescedEvaluations(stopCh chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Scan for coalesced evals. The broker already updated their
+			// status.
+			cancel := s.evalBroker.Coalesced(time.Second)
+			if cancel == nil {
+				continue
+			}
+			for _, eval := range cancel {
+				eval.UpdateModifyTime()
+			}
+
+			// Update via Raft
+			req := structs.EvalUpdateRequest{
+				Evals: cancel,
+			}
+			if _, _, err := s.raftApply(structs.EvalUpdateRequestType, &req); err != nil {
+				s.logger.Error("failed to update coalesced evals", "evals", log.Fmt("%#v", cancel), "error", err)
+				continue
+			}
+		}
+	}
+}
+
 // periodicUnblockFailedEvals periodically unblocks failed, blocked evaluations.
 func (s *Server) periodicUnblockFailedEvals(stopCh chan struct{}) {
 	ticker := time.NewTicker(failedEvalUnblockInterval)
@@ -601,6 +601,59 @@ func TestLeader_ReapDuplicateEval(t *testing.T) {
 	})
 }
 
+func TestLeader_ReapCoalescedEval(t *testing.T) {
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create an eval with two more evals blocked behind it
+	eval := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval.JobID
+	eval3 := mock.Eval()
+	eval3.JobID = eval.JobID
+	state := s1.fsm.State()
+	for i, e := range []*structs.Evaluation{eval, eval2, eval3} {
+		if err := state.UpsertEvals(uint64(100+i), []*structs.Evaluation{e}); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+	s1.evalBroker.Enqueue(eval)
+	s1.evalBroker.Enqueue(eval2)
+	s1.evalBroker.Enqueue(eval3)
+
+	out, token, err := s1.evalBroker.Dequeue(defaultSched, time.Second)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out.ID != eval.ID {
+		t.Fatalf("bad: %#v", out)
+	}
+	if err := s1.evalBroker.Ack(eval.ID, token); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Wait for the oldest blocked evaluation to be marked as cancelled
+	testutil.WaitForResult(func() (bool, error) {
+		ws := memdb.NewWatchSet()
+		out, err := state.EvalByID(ws, eval2.ID)
+		if err != nil {
+			return false, err
+		}
+		if out == nil || out.Status != structs.EvalStatusCancelled {
+			return false, fmt.Errorf("eval not canceled: %#v", out)
+		}
+		if out.CoalescedInto != eval3.ID {
+			return false, fmt.Errorf("bad coalesced into: %q", out.CoalescedInto)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
 func TestLeader_RestoreVaultAccessors(t *testing.T) {
 	s1, cleanupS1 := TestServer(t, func(c *Config) {
 		c.NumSchedulers = 0
@@ -16,9 +16,11 @@ import (
 	vapi "github.com/hashicorp/vault/api"
 
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
 )
 
@@ -172,7 +174,12 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 		originalStatus = originalNode.Status
 	}
 	transitionToReady := transitionedToReady(args.Node.Status, originalStatus)
-	if structs.ShouldDrainNode(args.Node.Status) || transitionToReady {
+
+	// Node metadata can be updated at runtime, which may make the node
+	// feasible for system jobs it was previously filtered out of.
+	metaChanged := originalNode != nil && args.Node.Status == structs.NodeStatusReady &&
+		!helper.CompareMapStringString(originalNode.Meta, args.Node.Meta)
+	if structs.ShouldDrainNode(args.Node.Status) || transitionToReady || metaChanged {
 		evalIDs, evalIndex, err := n.createNodeEvals(args.Node.ID, index)
 		if err != nil {
 			n.logger.Error("eval creation failed", "error", err)
@@ -539,31 +546,10 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	}
 
 	// Setup drain strategy
-	if args.DrainStrategy != nil {
-		// Mark start time for the drain
-		if node.DrainStrategy == nil {
-			args.DrainStrategy.StartedAt = now
-		} else {
-			args.DrainStrategy.StartedAt = node.DrainStrategy.StartedAt
-		}
-
-		// Mark the deadline time
-		if args.DrainStrategy.Deadline.Nanoseconds() > 0 {
-			args.DrainStrategy.ForceDeadline = now.Add(args.DrainStrategy.Deadline)
-		}
-	}
+	setupDrainStrategy(node, args.DrainStrategy, now)
 
 	// Construct the node event
-	args.NodeEvent = structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemDrain)
-	if node.DrainStrategy == nil && args.DrainStrategy != nil {
-		args.NodeEvent.SetMessage(NodeDrainEventDrainSet)
-	} else if node.DrainStrategy != nil && args.DrainStrategy != nil {
-		args.NodeEvent.SetMessage(NodeDrainEventDrainUpdated)
-	} else if node.DrainStrategy != nil && args.DrainStrategy == nil {
-		args.NodeEvent.SetMessage(NodeDrainEventDrainDisabled)
-	} else {
-		args.NodeEvent = nil
-	}
+	args.NodeEvent = drainNodeEvent(node, args.DrainStrategy)
 
 	// Commit this update via Raft
 	_, index, err := n.srv.raftApply(structs.NodeUpdateDrainRequestType, args)
@@ -590,6 +576,282 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	return nil
 }
 
+// setupDrainStrategy marks the start time and force deadline of a drain
+// strategy being applied to the given node. A nil strategy is left untouched.
+func setupDrainStrategy(node *structs.Node, strategy *structs.DrainStrategy, now time.Time) {
+	if strategy == nil {
+		return
+	}
+
+	// Mark start time for the drain
+	if node.DrainStrategy == nil {
+		strategy.StartedAt = now
+	} else {
+		strategy.StartedAt = node.DrainStrategy.StartedAt
+	}
+
+	// Mark the deadline time
+	if strategy.Deadline.Nanoseconds() > 0 {
+		strategy.ForceDeadline = now.Add(strategy.Deadline)
+	}
+}
+
+// drainNodeEvent returns the node event describing the transition of the node
+// to the given drain strategy or nil if the node is not and will not be
+// draining.
+func drainNodeEvent(node *structs.Node, strategy *structs.DrainStrategy) *structs.NodeEvent {
+	event := structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemDrain)
+	if node.DrainStrategy == nil && strategy != nil {
+		event.SetMessage(NodeDrainEventDrainSet)
+	} else if node.DrainStrategy != nil && strategy != nil {
+		event.SetMessage(NodeDrainEventDrainUpdated)
+	} else if node.DrainStrategy != nil && strategy == nil {
+		event.SetMessage(NodeDrainEventDrainDisabled)
+	} else {
+		return nil
+	}
+	return event
+}
+
+// BatchUpdateDrain is used to update the drain mode of all client nodes
+// matching a selector. The nodes are resolved on the server and updated in a
+// single Raft transaction.
+func (n *Node) BatchUpdateDrain(args *structs.NodeBatchUpdateDrainRequest,
+	reply *structs.NodeBatchDrainUpdateResponse) error {
+	if done, err := n.srv.forward("Node.BatchUpdateDrain", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "batch_update_drain"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments
+	if err := args.Selector.Validate(); err != nil {
+		return err
+	}
+
+	// Resolve the selected nodes
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	nodes, err := n.nodesBySelector(snap, args.Selector)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes matched the selector")
+	}
+
+	now := time.Now().UTC()
+	req := &structs.BatchNodeUpdateDrainRequest{
+		Updates:      make(map[string]*structs.DrainUpdate, len(nodes)),
+		NodeEvents:   make(map[string]*structs.NodeEvent, len(nodes)),
+		UpdatedAt:    now.Unix(),
+		WriteRequest: args.WriteRequest,
+	}
+
+	for _, node := range nodes {
+		// Each node tracks its own drain start time and deadline
+		strategy := args.DrainStrategy.Copy()
+		setupDrainStrategy(node, strategy, now)
+
+		req.Updates[node.ID] = &structs.DrainUpdate{
+			DrainStrategy: strategy,
+			MarkEligible:  args.MarkEligible,
+		}
+		if event := drainNodeEvent(node, strategy); event != nil {
+			req.NodeEvents[node.ID] = event
+		}
+		reply.NodeIDs = append(reply.NodeIDs, node.ID)
+	}
+
+	// Commit this update via Raft
+	outErr, index, err := n.srv.raftApply(structs.BatchNodeUpdateDrainRequestType, req)
+	if err != nil {
+		n.logger.Error("batch drain update failed", "error", err)
+		return err
+	}
+	if outErr != nil {
+		if err, ok := outErr.(error); ok && err != nil {
+			n.logger.Error("batch drain update failed", "error", err)
+			return err
+		}
+	}
+	reply.NodeModifyIndex = index
+
+	// If the nodes are transitioning to be eligible, create Node evaluations
+	// because there may be a System job registered that should be evaluated.
+	if args.MarkEligible && args.DrainStrategy == nil {
+		for _, node := range nodes {
+			if node.SchedulingEligibility != structs.NodeSchedulingIneligible {
+				continue
+			}
+
+			evalIDs, evalIndex, err := n.createNodeEvals(node.ID, index)
+			if err != nil {
+				n.logger.Error("eval creation failed", "error", err)
+				return err
+			}
+			reply.EvalIDs = append(reply.EvalIDs, evalIDs...)
+			reply.EvalCreateIndex = evalIndex
+		}
+	}
+
+	// Set the reply index
+	reply.Index = index
+	return nil
+}
+
+// BatchUpdateEligibility is used to update the scheduling eligibility of all
+// client nodes matching a selector. The nodes are resolved on the server and
+// updated in a single Raft transaction.
+func (n *Node) BatchUpdateEligibility(args *structs.NodeBatchUpdateEligibilityRequest,
+	reply *structs.NodeBatchEligibilityUpdateResponse) error {
+	if done, err := n.srv.forward("Node.BatchUpdateEligibility", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "batch_update_eligibility"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments
+	if err := args.Selector.Validate(); err != nil {
+		return err
+	}
+	switch args.Eligibility {
+	case structs.NodeSchedulingEligible, structs.NodeSchedulingIneligible:
+	default:
+		return fmt.Errorf("invalid scheduling eligibility %q", args.Eligibility)
+	}
+
+	// Resolve the selected nodes
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	nodes, err := n.nodesBySelector(snap, args.Selector)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes matched the selector")
+	}
+
+	req := &structs.BatchNodeUpdateEligibilityRequest{
+		Updates:      make(map[string]string, len(nodes)),
+		NodeEvents:   make(map[string]*structs.NodeEvent, len(nodes)),
+		UpdatedAt:    time.Now().Unix(),
+		WriteRequest: args.WriteRequest,
+	}
+
+	var draining []string
+	for _, node := range nodes {
+		reply.NodeIDs = append(reply.NodeIDs, node.ID)
+		if node.SchedulingEligibility == args.Eligibility {
+			continue // Nothing to do
+		}
+		if node.DrainStrategy != nil && args.Eligibility == structs.NodeSchedulingEligible {
+			draining = append(draining, node.ID)
+			continue
+		}
+
+		event := structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemCluster)
+		if args.Eligibility == structs.NodeSchedulingEligible {
+			event.SetMessage(NodeEligibilityEventEligible)
+		} else {
+			event.SetMessage(NodeEligibilityEventIneligible)
+		}
+		req.Updates[node.ID] = args.Eligibility
+		req.NodeEvents[node.ID] = event
+	}
+
+	if len(draining) != 0 {
+		return fmt.Errorf("can not set scheduling eligibility to eligible while nodes are draining: %s",
+			strings.Join(draining, ", "))
+	}
+	if len(req.Updates) == 0 {
+		return nil
+	}
+
+	// Commit this update via Raft
+	outErr, index, err := n.srv.raftApply(structs.BatchNodeUpdateEligibilityRequestType, req)
+	if err != nil {
+		n.logger.Error("batch eligibility update failed", "error", err)
+		return err
+	}
+	if outErr != nil {
+		if err, ok := outErr.(error); ok && err != nil {
+			n.logger.Error("batch eligibility update failed", "error", err)
+			return err
+		}
+	}
+	reply.NodeModifyIndex = index
+
+	// If the nodes are transitioning to be eligible, create Node evaluations
+	// because there may be a System job registered that should be evaluated.
+	if args.Eligibility == structs.NodeSchedulingEligible {
+		for nodeID := range req.Updates {
+			evalIDs, evalIndex, err := n.createNodeEvals(nodeID, index)
+			if err != nil {
+				n.logger.Error("eval creation failed", "error", err)
+				return err
+			}
+			reply.EvalIDs = append(reply.EvalIDs, evalIDs...)
+			reply.EvalCreateIndex = evalIndex
+		}
+	}
+
+	// Set the reply index
+	reply.Index = index
+	return nil
+}
+
+// nodesBySelector returns the nodes in the snapshot that match the selector.
+// Filters are evaluated the same way the scheduler evaluates job constraints.
+func (n *Node) nodesBySelector(snap *state.StateSnapshot, selector *structs.NodeSelector) ([]*structs.Node, error) {
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var checker *scheduler.ConstraintChecker
+	if len(selector.Filters) != 0 {
+		ctx := scheduler.NewEvalContext(snap, nil, n.logger)
+		checker = scheduler.NewConstraintChecker(ctx, selector.Filters)
+	}
+
+	var nodes []*structs.Node
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		node := raw.(*structs.Node)
+		if selector.Datacenter != "" && node.Datacenter != selector.Datacenter {
+			continue
+		}
+		if selector.NodeClass != "" && node.NodeClass != selector.NodeClass {
+			continue
+		}
+		if checker != nil && !checker.Feasible(node) {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
 // UpdateEligibility is used to update the scheduling eligibility of a node
 func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	reply *structs.NodeEligibilityUpdateResponse) error {
@@ -1226,26 +1488,29 @@ func (n *Node) List(args *structs.NodeListRequest,
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Capture all the nodes
-			var err error
-			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.NodesByIDPrefix(ws, prefix)
-			} else {
-				iter, err = state.Nodes(ws)
-			}
+			// Capture the nodes, starting at the page of the next token
+			iter, err := state.NodesByIDPrefixFrom(ws, args.Prefix, args.NextToken)
 			if err != nil {
 				return err
 			}
 
 			var nodes []*structs.NodeListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				node := raw.(*structs.Node)
-				nodes = append(nodes, node.Stub())
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Node).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					return raw.(*structs.Node).Stub(), nil
+				},
+				func(stub interface{}) {
+					nodes = append(nodes, stub.(*structs.NodeListStub))
+				})
+			if err != nil {
+				return err
+			}
+			reply.NextToken, err = paginator.page()
+			if err != nil {
+				return err
 			}
 			reply.Nodes = nodes
 
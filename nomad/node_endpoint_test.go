@@ -1210,6 +1210,171 @@ func TestClientEndpoint_UpdateEligibility(t *testing.T) {
 	require.Equal(NodeEligibilityEventEligible, out.Events[2].Message)
 }
 
+func TestClientEndpoint_BatchUpdateDrain(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Disable drainer to prevent drain from completing during test
+	s1.nodeDrainer.SetEnabled(false, nil)
+
+	// Register three nodes, one of which is in a different datacenter and
+	// one of which has different meta
+	n1, n2, n3 := mock.Node(), mock.Node(), mock.Node()
+	n2.Meta["rack"] = "r2"
+	n3.Datacenter = "dc2"
+	for _, node := range []*structs.Node{n1, n2, n3} {
+		reg := &structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.NodeUpdateResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+	}
+
+	// An empty selector is rejected
+	req := &structs.NodeBatchUpdateDrainRequest{
+		Selector: &structs.NodeSelector{},
+		DrainStrategy: &structs.DrainStrategy{
+			DrainSpec: structs.DrainSpec{
+				Deadline: 10 * time.Second,
+			},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeBatchDrainUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateDrain", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "must specify")
+
+	// A selector matching no nodes is rejected
+	req.Selector = &structs.NodeSelector{Datacenter: "dc3"}
+	err = msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateDrain", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "no nodes matched")
+
+	// Drain the nodes in dc1 that are not on rack r2
+	req.Selector = &structs.NodeSelector{
+		Datacenter: "dc1",
+		Filters: []*structs.Constraint{
+			{
+				LTarget: "${meta.rack}",
+				Operand: "!=",
+				RTarget: "r2",
+			},
+		},
+	}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateDrain", req, &resp))
+	require.NotZero(resp.Index)
+	require.Equal([]string{n1.ID}, resp.NodeIDs)
+
+	state := s1.fsm.State()
+	out, err := state.NodeByID(nil, n1.ID)
+	require.Nil(err)
+	require.NotNil(out.DrainStrategy)
+	require.False(out.DrainStrategy.StartedAt.IsZero())
+	require.False(out.DrainStrategy.ForceDeadline.IsZero())
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	require.Len(out.Events, 2)
+	require.Equal(NodeDrainEventDrainSet, out.Events[1].Message)
+
+	for _, id := range []string{n2.ID, n3.ID} {
+		out, err := state.NodeByID(nil, id)
+		require.Nil(err)
+		require.Nil(out.DrainStrategy)
+	}
+
+	// Register a system job
+	job := mock.SystemJob()
+	require.Nil(s1.State().UpsertJob(10, job))
+
+	// Disable the drain by node class and expect evals for the node that
+	// becomes eligible
+	req = &structs.NodeBatchUpdateDrainRequest{
+		Selector:     &structs.NodeSelector{NodeClass: n1.NodeClass},
+		MarkEligible: true,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp2 structs.NodeBatchDrainUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateDrain", req, &resp2))
+	require.Len(resp2.NodeIDs, 3)
+	require.Len(resp2.EvalIDs, 1)
+	require.NotZero(resp2.EvalCreateIndex)
+
+	out, err = state.NodeByID(nil, n1.ID)
+	require.Nil(err)
+	require.Nil(out.DrainStrategy)
+	require.Equal(structs.NodeSchedulingEligible, out.SchedulingEligibility)
+	require.Len(out.Events, 3)
+	require.Equal(NodeDrainEventDrainDisabled, out.Events[2].Message)
+}
+
+func TestClientEndpoint_BatchUpdateEligibility(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register two nodes in different datacenters
+	n1, n2 := mock.Node(), mock.Node()
+	n2.Datacenter = "dc2"
+	for _, node := range []*structs.Node{n1, n2} {
+		reg := &structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.NodeUpdateResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+	}
+
+	// Mark the nodes in dc1 ineligible
+	req := &structs.NodeBatchUpdateEligibilityRequest{
+		Selector:     &structs.NodeSelector{Datacenter: "dc1"},
+		Eligibility:  structs.NodeSchedulingIneligible,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeBatchEligibilityUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateEligibility", req, &resp))
+	require.NotZero(resp.Index)
+	require.Equal([]string{n1.ID}, resp.NodeIDs)
+	require.Empty(resp.EvalIDs)
+
+	state := s1.fsm.State()
+	out, err := state.NodeByID(nil, n1.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	require.Len(out.Events, 2)
+	require.Equal(NodeEligibilityEventIneligible, out.Events[1].Message)
+
+	out, err = state.NodeByID(nil, n2.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingEligible, out.SchedulingEligibility)
+
+	// Register a system job
+	job := mock.SystemJob()
+	require.Nil(s1.State().UpsertJob(10, job))
+
+	// Mark the nodes eligible again and expect evals
+	req.Eligibility = structs.NodeSchedulingEligible
+	var resp2 structs.NodeBatchEligibilityUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateEligibility", req, &resp2))
+	require.NotZero(resp2.EvalCreateIndex)
+	require.Len(resp2.EvalIDs, 1)
+
+	out, err = state.NodeByID(nil, n1.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingEligible, out.SchedulingEligibility)
+	require.Len(out.Events, 3)
+	require.Equal(NodeEligibilityEventEligible, out.Events[2].Message)
+}
+
 func TestClientEndpoint_UpdateEligibility_ACL(t *testing.T) {
 	t.Parallel()
 
@@ -2666,6 +2831,41 @@ func TestClientEndpoint_ListNodes_ACL(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_ListNodes_FilterSecretID(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the node
+	node := mock.Node()
+	state := s1.fsm.State()
+	require.NoError(state.UpsertNode(1000, node))
+
+	// Fields of the returned stubs can be filtered on
+	req := &structs.NodeListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+			Filter: fmt.Sprintf("ID == %s", node.ID),
+		},
+	}
+	var resp structs.NodeListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.List", req, &resp))
+	require.Len(resp.Nodes, 1)
+
+	// The secret ID isn't part of the stub, so it can't be matched to
+	// recover it
+	req.Filter = fmt.Sprintf("SecretID matches ^%s", node.SecretID[:1])
+	var resp2 structs.NodeListResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.List", req, &resp2)
+	require.Error(err)
+	require.Contains(err.Error(), "failed to evaluate filter")
+	require.Empty(resp2.Nodes)
+}
+
 func TestClientEndpoint_ListNodes_Blocking(t *testing.T) {
 	t.Parallel()
 
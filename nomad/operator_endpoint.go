@@ -3,11 +3,15 @@ package nomad
 import (
 	"fmt"
 	"net"
+	"time"
 
+	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/consul/agent/consul/autopilot"
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
 )
@@ -308,6 +312,11 @@ func (op *Operator) SchedulerSetConfiguration(args *structs.SchedulerSetConfigRe
 	if !ServersMeetMinimumVersion(op.srv.Members(), minSchedulerConfigVersion, false) {
 		return fmt.Errorf("All servers should be running version %v to update scheduler config", minSchedulerConfigVersion)
 	}
+
+	if err := args.Config.Validate(); err != nil {
+		return err
+	}
+
 	// Apply the update
 	resp, index, err := op.srv.raftApply(structs.SchedulerConfigRequestType, args)
 	if err != nil {
@@ -323,6 +332,13 @@ func (op *Operator) SchedulerSetConfiguration(args *structs.SchedulerSetConfigRe
 		reply.Updated = respBool
 	}
 	reply.Index = index
+
+	// Pause or resume the eval broker
+	if !args.CAS || reply.Updated {
+		if err := op.srv.handleEvalBrokerStateChange(&args.Config); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -355,3 +371,151 @@ func (op *Operator) SchedulerGetConfiguration(args *structs.GenericRequest, repl
 
 	return nil
 }
+
+const (
+	// maxSimulatedNodes is the maximum number of nodes a scheduler simulation
+	// may add to the cluster
+	maxSimulatedNodes = 1000
+
+	// maxSimulatedJobs is the maximum number of jobs a scheduler simulation
+	// may register
+	maxSimulatedJobs = 100
+)
+
+// SchedulerSimulate is used to replay hypothetical jobs, node additions and
+// node removals against a snapshot of the cluster state. The resulting plans
+// are only applied to the snapshot and are never committed.
+func (op *Operator) SchedulerSimulate(args *structs.SchedulerSimulateRequest, reply *structs.SchedulerSimulateResponse) error {
+	if done, err := op.srv.forward("Operator.SchedulerSimulate", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "operator", "scheduler_simulate"}, time.Now())
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if len(args.Jobs) == 0 && len(args.AddNodes) == 0 && len(args.RemoveNodes) == 0 {
+		return fmt.Errorf("simulation requires jobs or node changes")
+	}
+	if len(args.Jobs) > maxSimulatedJobs {
+		return fmt.Errorf("simulation may register at most %d jobs", maxSimulatedJobs)
+	}
+	added := 0
+	for _, add := range args.AddNodes {
+		if add == nil || add.NodeID == "" {
+			return fmt.Errorf("missing node ID for added node")
+		}
+		if add.Count < 1 {
+			return fmt.Errorf("count for added node %q must be positive", add.NodeID)
+		}
+		if add.Count > maxSimulatedNodes-added {
+			return fmt.Errorf("simulation may add at most %d nodes", maxSimulatedNodes)
+		}
+		added += add.Count
+	}
+
+	// Run admission controllers
+	var warnings []error
+	jobs := make([]*structs.Job, 0, len(args.Jobs))
+	for _, job := range args.Jobs {
+		if job == nil {
+			return fmt.Errorf("missing job for simulation")
+		}
+		job, jobWarnings, err := op.srv.staticEndpoints.Job.admissionControllers(job)
+		if err != nil {
+			return err
+		}
+		warnings = append(warnings, jobWarnings...)
+		jobs = append(jobs, job)
+	}
+	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+
+	// Acquire a snapshot of the state to run the simulation against
+	snap, err := op.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	index, err := snap.LatestIndex()
+	if err != nil {
+		return err
+	}
+
+	sim, err := scheduler.NewSimulation(op.logger, &snap.StateStore)
+	if err != nil {
+		return err
+	}
+
+	// Apply the node changes before registering the jobs
+	for _, nodeID := range args.RemoveNodes {
+		evals, err := sim.RemoveNode(nodeID)
+		if err != nil {
+			return err
+		}
+		reply.Evals = append(reply.Evals, evals...)
+	}
+	for _, add := range args.AddNodes {
+		evals, err := sim.AddNode(add.NodeID, add.Count)
+		if err != nil {
+			return err
+		}
+		reply.Evals = append(reply.Evals, evals...)
+	}
+	for _, job := range jobs {
+		eval, err := sim.RegisterJob(job)
+		if err != nil {
+			return err
+		}
+		reply.Evals = append(reply.Evals, eval)
+	}
+
+	// Only return the evaluations and allocations of namespaces the token
+	// can read jobs of
+	if rule != nil {
+		reply.Evals = filterSimulatedEvals(reply.Evals, rule)
+	}
+
+	nodes, err := sim.Nodes()
+	if err != nil {
+		return err
+	}
+	reply.Nodes = nodes
+
+	reply.Index = index
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// filterSimulatedEvals removes the evaluations and allocations of namespaces
+// the ACL object isn't allowed to read jobs of.
+func filterSimulatedEvals(evals []*structs.SimulatedEval, aclObj *acl.ACL) []*structs.SimulatedEval {
+	allowed := func(ns string) bool {
+		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob)
+	}
+	filterAllocs := func(allocs []*structs.SimulatedAlloc) []*structs.SimulatedAlloc {
+		var out []*structs.SimulatedAlloc
+		for _, alloc := range allocs {
+			if allowed(alloc.Namespace) {
+				out = append(out, alloc)
+			}
+		}
+		return out
+	}
+
+	var out []*structs.SimulatedEval
+	for _, eval := range evals {
+		if !allowed(eval.Namespace) {
+			continue
+		}
+		eval.Placed = filterAllocs(eval.Placed)
+		eval.Stopped = filterAllocs(eval.Stopped)
+		eval.Preempted = filterAllocs(eval.Preempted)
+		out = append(out, eval)
+	}
+	return out
+}
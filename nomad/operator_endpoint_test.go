@@ -9,6 +9,7 @@ import (
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper/freeport"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -422,6 +423,12 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 
 	require.NotZero(reply.Index)
 	require.False(reply.SchedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
+
+	// Invalid namespace weights are rejected
+	arg.Config.NamespaceWeights = map[string]int{"default": 0}
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", &arg, &setResponse)
+	require.Error(err)
+	require.Contains(err.Error(), "must be at least 1")
 }
 
 func TestOperator_SchedulerGetConfiguration_ACL(t *testing.T) {
@@ -521,3 +528,186 @@ func TestOperator_SchedulerSetConfiguration_ACL(t *testing.T) {
 	}
 
 }
+
+func TestOperator_SchedulerSimulate(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+	require := require.New(t)
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	arg := structs.SchedulerSimulateRequest{
+		Jobs: []*structs.Job{job},
+		AddNodes: []*structs.SimulateAddNode{
+			{NodeID: node.ID, Count: 2},
+		},
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+	var reply structs.SchedulerSimulateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply))
+	require.NotZero(reply.Index)
+	require.Len(reply.Evals, 1)
+	require.Equal(job.ID, reply.Evals[0].JobID)
+	require.Len(reply.Evals[0].Placed, 2)
+	require.Len(reply.Nodes, 3)
+
+	// Nothing is committed to the cluster state
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Nil(out)
+	nodes, err := state.Nodes(nil)
+	require.NoError(err)
+	count := 0
+	for raw := nodes.Next(); raw != nil; raw = nodes.Next() {
+		count++
+	}
+	require.Equal(1, count)
+
+	// Unknown nodes are rejected
+	arg.AddNodes = nil
+	arg.RemoveNodes = []string{uuid.Generate()}
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+
+	// The number of added nodes is capped
+	arg.RemoveNodes = nil
+	arg.AddNodes = []*structs.SimulateAddNode{
+		{NodeID: node.ID, Count: maxSimulatedNodes},
+		{NodeID: node.ID, Count: 1},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply)
+	require.Error(err)
+	require.Contains(err.Error(), "at most 1000 nodes")
+
+	// The number of jobs is capped
+	arg.AddNodes = nil
+	arg.Jobs = make([]*structs.Job, maxSimulatedJobs+1)
+	for i := range arg.Jobs {
+		arg.Jobs[i] = mock.Job()
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply)
+	require.Error(err)
+	require.Contains(err.Error(), "at most 100 jobs")
+}
+
+func TestOperator_SchedulerSimulate_ACL(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create ACL tokens
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyWrite))
+	validToken := mock.CreatePolicyAndToken(t, state, 1003, "test-valid", `operator { policy = "read" }`)
+
+	arg := structs.SchedulerSimulateRequest{
+		Jobs: []*structs.Job{mock.Job()},
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+	require := require.New(t)
+	var reply structs.SchedulerSimulateResponse
+
+	// Try with no token and expect permission denied
+	{
+		err := msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply)
+		require.NotNil(err)
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with an invalid token and expect permission denied
+	{
+		arg.AuthToken = invalidToken.SecretID
+		err := msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply)
+		require.NotNil(err)
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a valid token, should succeed
+	{
+		arg.AuthToken = validToken.SecretID
+		err := msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply)
+		require.Nil(err)
+	}
+
+	// Try with root token, should succeed
+	{
+		arg.AuthToken = root.SecretID
+		err := msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply)
+		require.Nil(err)
+		require.Len(reply.Evals, 1)
+	}
+
+	// Evaluations and allocations of namespaces the token can't read jobs
+	// of are dropped
+	{
+		arg.AuthToken = validToken.SecretID
+		var reply structs.SchedulerSimulateResponse
+		err := msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply)
+		require.Nil(err)
+		require.Empty(reply.Evals)
+
+		readToken := mock.CreatePolicyAndToken(t, state, 1005, "test-read-job",
+			`operator { policy = "read" }`+mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		arg.AuthToken = readToken.SecretID
+		var reply2 structs.SchedulerSimulateResponse
+		err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSimulate", &arg, &reply2)
+		require.Nil(err)
+		require.Len(reply2.Evals, 1)
+	}
+}
+
+func TestOperator_filterSimulatedEvals(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	policy, err := acl.Parse(mock.NamespacePolicy("web", "", []string{acl.NamespaceCapabilityReadJob}))
+	require.NoError(err)
+	aclObj, err := acl.NewACL(false, []*acl.Policy{policy})
+	require.NoError(err)
+
+	evals := []*structs.SimulatedEval{
+		{
+			Namespace: "web",
+			JobID:     "web",
+			Placed:    []*structs.SimulatedAlloc{{ID: "placed", Namespace: "web"}},
+			Stopped:   []*structs.SimulatedAlloc{{ID: "stopped", Namespace: "web"}},
+			Preempted: []*structs.SimulatedAlloc{
+				{ID: "preempted-web", Namespace: "web"},
+				{ID: "preempted-db", Namespace: "db"},
+			},
+		},
+		{
+			Namespace: "db",
+			JobID:     "db",
+			Placed:    []*structs.SimulatedAlloc{{ID: "placed-db", Namespace: "db"}},
+		},
+	}
+
+	out := filterSimulatedEvals(evals, aclObj)
+	require.Len(out, 1)
+	require.Equal("web", out[0].JobID)
+	require.Len(out[0].Placed, 1)
+	require.Len(out[0].Stopped, 1)
+	require.Len(out[0].Preempted, 1)
+	require.Equal("preempted-web", out[0].Preempted[0].ID)
+}
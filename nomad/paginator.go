@@ -0,0 +1,85 @@
+package nomad
+
+import (
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/filter"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// paginator wraps an iterator of the state store to return the page of
+// results requested by the pagination and filter options of a query. The
+// iterator must start at the result of the query's next token.
+type paginator struct {
+	iter    memdb.ResultIterator
+	perPage int32
+	filter  *filter.Expression
+
+	// tokenFunc returns the token of a result, which is the next token
+	// returned for the result that starts the next page
+	tokenFunc func(raw interface{}) string
+
+	// stubFunc returns the object returned for a result. The filter is
+	// evaluated against it rather than the result, so only the fields
+	// returned to the caller can be matched
+	stubFunc func(raw interface{}) (interface{}, error)
+
+	// appendFunc is called with the object of each result of the page
+	appendFunc func(stub interface{})
+}
+
+// newPaginator returns a paginator over the results of the iterator, which
+// must start at the result of the next token of the query. An error is
+// returned if the filter expression of the query is invalid.
+func newPaginator(iter memdb.ResultIterator, opts structs.QueryOptions,
+	tokenFunc func(raw interface{}) string,
+	stubFunc func(raw interface{}) (interface{}, error),
+	appendFunc func(stub interface{})) (*paginator, error) {
+
+	p := &paginator{
+		iter:       iter,
+		perPage:    opts.PerPage,
+		tokenFunc:  tokenFunc,
+		stubFunc:   stubFunc,
+		appendFunc: appendFunc,
+	}
+
+	if opts.Filter != "" {
+		expr, err := filter.Parse(opts.Filter)
+		if err != nil {
+			return nil, structs.NewErrRPCCodedf(400, "failed to parse filter: %v", err)
+		}
+		p.filter = expr
+	}
+
+	return p, nil
+}
+
+// page calls appendFunc with the object of each result of the requested page
+// and returns the token of the next page, which is empty on the last page.
+func (p *paginator) page() (string, error) {
+	count := 0
+	for raw := p.iter.Next(); raw != nil; raw = p.iter.Next() {
+		stub, err := p.stubFunc(raw)
+		if err != nil {
+			return "", err
+		}
+
+		if p.filter != nil {
+			match, err := p.filter.Evaluate(stub)
+			if err != nil {
+				return "", structs.NewErrRPCCodedf(400, "failed to evaluate filter: %v", err)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		if p.perPage > 0 && count == int(p.perPage) {
+			return p.tokenFunc(raw), nil
+		}
+		p.appendFunc(stub)
+		count++
+	}
+
+	return "", nil
+}
@@ -0,0 +1,119 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginator(t *testing.T) {
+	t.Parallel()
+
+	ids := []string{
+		"aaaaaaaa-3350-4b4b-d185-0e1992ed43e9",
+		"aaaabbbb-3350-4b4b-d185-0e1992ed43e9",
+		"aaaacccc-3350-4b4b-d185-0e1992ed43e9",
+		"aaaadddd-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaeeee-3350-4b4b-d185-0e1992ed43e9",
+	}
+
+	store := state.TestStateStore(t)
+	var evals []*structs.Evaluation
+	for i, id := range ids {
+		eval := mock.Eval()
+		eval.ID = id
+		if i%2 == 0 {
+			eval.Status = structs.EvalStatusBlocked
+		}
+		evals = append(evals, eval)
+	}
+	require.NoError(t, store.UpsertEvals(1000, evals))
+
+	cases := []struct {
+		name      string
+		opts      structs.QueryOptions
+		expected  []string
+		nextToken string
+		err       string
+	}{
+		{
+			name:     "all",
+			expected: ids,
+		},
+		{
+			name:      "first page",
+			opts:      structs.QueryOptions{PerPage: 2},
+			expected:  ids[:2],
+			nextToken: ids[2],
+		},
+		{
+			name:      "middle page",
+			opts:      structs.QueryOptions{PerPage: 2, NextToken: ids[2]},
+			expected:  ids[2:4],
+			nextToken: ids[4],
+		},
+		{
+			name:     "last page",
+			opts:     structs.QueryOptions{PerPage: 2, NextToken: ids[4]},
+			expected: ids[4:],
+		},
+		{
+			name:     "filter",
+			opts:     structs.QueryOptions{Filter: `Status == blocked`},
+			expected: []string{ids[0], ids[2], ids[4]},
+		},
+		{
+			name:      "filtered page",
+			opts:      structs.QueryOptions{Filter: `Status == blocked`, PerPage: 1, NextToken: ids[1]},
+			expected:  []string{ids[2]},
+			nextToken: ids[4],
+		},
+		{
+			name: "invalid filter",
+			opts: structs.QueryOptions{Filter: `Status ==`},
+			err:  "failed to parse filter",
+		},
+		{
+			name: "unknown field",
+			opts: structs.QueryOptions{Filter: `Unknown == blocked`},
+			err:  "failed to evaluate filter",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			iter, err := store.EvalsByIDPrefixFrom(nil, structs.DefaultNamespace, "", tc.opts.NextToken)
+			require.NoError(t, err)
+
+			var out []string
+			paginator, err := newPaginator(iter, tc.opts,
+				func(raw interface{}) string {
+					return raw.(*structs.Evaluation).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					return raw, nil
+				},
+				func(stub interface{}) {
+					out = append(out, stub.(*structs.Evaluation).ID)
+				})
+			if err == nil {
+				var nextToken string
+				nextToken, err = paginator.page()
+				if err == nil {
+					require.Equal(t, tc.expected, out)
+					require.Equal(t, tc.nextToken, nextToken)
+				}
+			}
+
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,6 +19,9 @@ const (
 	// truncateLimit is the maximum number of matches that will be returned for a
 	// prefix for a specific context
 	truncateLimit = 20
+
+	// fuzzyMinTextLength is the minimum length of the text of a fuzzy search
+	fuzzyMinTextLength = 2
 )
 
 var (
@@ -30,6 +34,17 @@ var (
 		structs.Evals,
 		structs.Deployments,
 	}
+
+	// fuzzyContexts are the contexts which are searched to find fuzzy matches
+	// for a given text
+	fuzzyContexts = []structs.Context{
+		structs.Jobs,
+		structs.Groups,
+		structs.Tasks,
+		structs.Services,
+		structs.Images,
+		structs.Nodes,
+	}
 )
 
 // Search endpoint is used to look up matches for a given prefix and context
@@ -194,3 +209,223 @@ func (s *Search) PrefixSearch(args *structs.SearchRequest, reply *structs.Search
 		}}
 	return s.srv.blockingRPC(&opts)
 }
+
+// FuzzySearch is used to list the jobs, task groups, tasks, services, images
+// and nodes whose names contain the given text, ignoring case. The components
+// of jobs are scoped by the namespace, job ID and task group and task names of
+// their parents.
+func (s *Search) FuzzySearch(args *structs.FuzzySearchRequest, reply *structs.FuzzySearchResponse) error {
+	if done, err := s.srv.forward("Search.FuzzySearch", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "search", "fuzzy_search"}, time.Now())
+
+	aclObj, err := s.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	namespace := args.RequestNamespace()
+
+	// Require either node:read or namespace:read-job
+	if !anySearchPerms(aclObj, namespace, args.Context) {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.Text) < fuzzyMinTextLength {
+		return structs.NewErrRPCCodedf(400, "fuzzy search text must be at least %d characters", fuzzyMinTextLength)
+	}
+
+	contexts, err := fuzzySearchContexts(aclObj, namespace, args.Context)
+	if err != nil {
+		return structs.NewErrRPCCoded(400, err.Error())
+	}
+
+	text := strings.ToLower(args.Text)
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryMeta: &reply.QueryMeta,
+		queryOpts: &structs.QueryOptions{},
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			matches := make(map[structs.Context][]fuzzyMatch)
+			for _, ctx := range contexts {
+				matches[ctx] = nil
+			}
+
+			searchJobs, searchNodes := false, false
+			for ctx := range matches {
+				if ctx == structs.Nodes {
+					searchNodes = true
+				} else {
+					searchJobs = true
+				}
+			}
+
+			if searchJobs {
+				iter, err := state.JobsByNamespace(ws, namespace)
+				if err != nil {
+					return err
+				}
+				for raw := iter.Next(); raw != nil; raw = iter.Next() {
+					fuzzyMatchJob(raw.(*structs.Job), text, matches)
+				}
+
+				index, err := state.Index("jobs")
+				if err != nil {
+					return err
+				}
+				if index > reply.Index {
+					reply.Index = index
+				}
+			}
+
+			if searchNodes {
+				iter, err := state.Nodes(ws)
+				if err != nil {
+					return err
+				}
+				for raw := iter.Next(); raw != nil; raw = iter.Next() {
+					node := raw.(*structs.Node)
+					if idx := fuzzyIndex(node.Name, text); idx != -1 {
+						matches[structs.Nodes] = append(matches[structs.Nodes], fuzzyMatch{
+							FuzzyMatch: structs.FuzzyMatch{ID: node.Name, Scope: []string{node.ID}},
+							index:      idx,
+						})
+					}
+				}
+
+				index, err := state.Index("nodes")
+				if err != nil {
+					return err
+				}
+				if index > reply.Index {
+					reply.Index = index
+				}
+			}
+
+			// Sort the matches of each context so the best ones are kept
+			reply.Matches = make(map[structs.Context][]structs.FuzzyMatch, len(matches))
+			reply.Truncations = make(map[structs.Context]bool, len(matches))
+			for ctx, ctxMatches := range matches {
+				sortFuzzyMatches(ctxMatches)
+
+				out := make([]structs.FuzzyMatch, 0, len(ctxMatches))
+				for i, m := range ctxMatches {
+					if i == truncateLimit {
+						break
+					}
+					out = append(out, m.FuzzyMatch)
+				}
+				reply.Matches[ctx] = out
+				reply.Truncations[ctx] = len(ctxMatches) > truncateLimit
+			}
+
+			s.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// fuzzyMatch is a fuzzy match along with the index of the text in its name,
+// used to sort the matches.
+type fuzzyMatch struct {
+	structs.FuzzyMatch
+	index int
+}
+
+// fuzzyMatchJob appends the matches of the job and its components to the
+// matches of the contexts being searched. Child jobs of periodic and
+// parameterized jobs are skipped, since their components are those of their
+// parent.
+func fuzzyMatchJob(job *structs.Job, text string, matches map[structs.Context][]fuzzyMatch) {
+	if job.ParentID != "" {
+		return
+	}
+
+	add := func(ctx structs.Context, name string, scope ...string) {
+		if _, ok := matches[ctx]; !ok {
+			return
+		}
+		if idx := fuzzyIndex(name, text); idx != -1 {
+			matches[ctx] = append(matches[ctx], fuzzyMatch{
+				FuzzyMatch: structs.FuzzyMatch{ID: name, Scope: scope},
+				index:      idx,
+			})
+		}
+	}
+
+	add(structs.Jobs, job.Name, job.Namespace, job.ID)
+	for _, tg := range job.TaskGroups {
+		add(structs.Groups, tg.Name, job.Namespace, job.ID)
+		for _, service := range tg.Services {
+			add(structs.Services, service.Name, job.Namespace, job.ID, tg.Name)
+		}
+
+		for _, task := range tg.Tasks {
+			add(structs.Tasks, task.Name, job.Namespace, job.ID, tg.Name)
+			for _, service := range task.Services {
+				add(structs.Services, service.Name, job.Namespace, job.ID, tg.Name, task.Name)
+			}
+			if image, ok := task.Config["image"].(string); ok {
+				add(structs.Images, image, job.Namespace, job.ID, tg.Name, task.Name)
+			}
+		}
+	}
+}
+
+// fuzzyIndex returns the index of the lower case text in the name, ignoring
+// case, or -1 if the name doesn't contain the text.
+func fuzzyIndex(name, text string) int {
+	return strings.Index(strings.ToLower(name), text)
+}
+
+// sortFuzzyMatches sorts the matches so that names containing the text closer
+// to their start come first, and then by name and scope.
+func sortFuzzyMatches(matches []fuzzyMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.index != b.index {
+			return a.index < b.index
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return strings.Join(a.Scope, "/") < strings.Join(b.Scope, "/")
+	})
+}
+
+// fuzzySearchContexts returns the fuzzy search contexts the aclObj is valid
+// for. If aclObj is nil all contexts are returned.
+func fuzzySearchContexts(aclObj *acl.ACL, namespace string, context structs.Context) ([]structs.Context, error) {
+	var all []structs.Context
+	switch context {
+	case structs.All, "":
+		all = fuzzyContexts
+	case structs.Jobs, structs.Groups, structs.Tasks, structs.Services, structs.Images, structs.Nodes:
+		all = []structs.Context{context}
+	default:
+		return nil, fmt.Errorf("context must be one of %v or 'all' for all contexts; got %q", fuzzyContexts, context)
+	}
+
+	// If ACLs aren't enabled return all contexts
+	if aclObj == nil {
+		return all, nil
+	}
+
+	jobRead := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
+	nodeRead := aclObj.AllowNodeRead()
+
+	// Filter contexts down to those the ACL grants access to
+	available := make([]structs.Context, 0, len(all))
+	for _, c := range all {
+		if c == structs.Nodes {
+			if nodeRead {
+				available = append(available, c)
+			}
+		} else if jobRead {
+			available = append(available, c)
+		}
+	}
+	return available, nil
+}
@@ -56,7 +56,8 @@ func anySearchPerms(aclObj *acl.ACL, namespace string, context structs.Context)
 	}
 	if !jobRead {
 		switch context {
-		case structs.Allocs, structs.Deployments, structs.Evals, structs.Jobs:
+		case structs.Allocs, structs.Deployments, structs.Evals, structs.Jobs,
+			structs.Groups, structs.Tasks, structs.Services, structs.Images:
 			return false
 		}
 	}
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const jobIndex = 1000
@@ -746,3 +748,241 @@ func TestSearch_PrefixSearch_MultiRegion(t *testing.T) {
 	assert.Equal(job.ID, resp.Matches[structs.Jobs][0])
 	assert.Equal(uint64(jobIndex), resp.Index)
 }
+
+func TestSearch_FuzzySearch_Job(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	job := mock.Job()
+	job.Name = "my-web-job"
+	job.TaskGroups[0].Services = []*structs.Service{{Name: "web-group-service"}}
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{"image": "redis:web"}
+	job.TaskGroups[0].Tasks[0].Services = []*structs.Service{{Name: "cache-web"}}
+	require.NoError(s.fsm.State().UpsertJob(jobIndex, job))
+
+	// Child jobs are ignored
+	child := mock.Job()
+	child.Name = "my-web-job/periodic-1"
+	child.ParentID = job.ID
+	require.NoError(s.fsm.State().UpsertJob(jobIndex, child))
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "WEB",
+		Context: structs.All,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var resp structs.FuzzySearchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+
+	ns, tg, task := job.Namespace, job.TaskGroups[0].Name, job.TaskGroups[0].Tasks[0].Name
+	require.Equal([]structs.FuzzyMatch{{ID: job.Name, Scope: []string{ns, job.ID}}},
+		resp.Matches[structs.Jobs])
+	require.Equal([]structs.FuzzyMatch{{ID: tg, Scope: []string{ns, job.ID}}},
+		resp.Matches[structs.Groups])
+	require.Equal([]structs.FuzzyMatch{{ID: task, Scope: []string{ns, job.ID, tg}}},
+		resp.Matches[structs.Tasks])
+	require.Equal([]structs.FuzzyMatch{{ID: "redis:web", Scope: []string{ns, job.ID, tg, task}}},
+		resp.Matches[structs.Images])
+
+	// Matches at the start of the name come first
+	require.Equal([]structs.FuzzyMatch{
+		{ID: "web-group-service", Scope: []string{ns, job.ID, tg}},
+		{ID: "cache-web", Scope: []string{ns, job.ID, tg, task}},
+	}, resp.Matches[structs.Services])
+	require.Len(resp.Matches[structs.Nodes], 0)
+	require.Equal(uint64(jobIndex), resp.Index)
+
+	// Only the requested context is searched
+	req.Context = structs.Tasks
+	var resp2 structs.FuzzySearchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp2))
+	require.Len(resp2.Matches, 1)
+	require.Len(resp2.Matches[structs.Tasks], 1)
+}
+
+func TestSearch_FuzzySearch_Node(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	node := mock.Node()
+	node.Name = "client-east-1"
+	require.NoError(s.fsm.State().UpsertNode(1001, node))
+
+	other := mock.Node()
+	other.Name = "client-west-1"
+	require.NoError(s.fsm.State().UpsertNode(1002, other))
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "east",
+		Context: structs.Nodes,
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+
+	var resp structs.FuzzySearchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+	require.Equal([]structs.FuzzyMatch{{ID: node.Name, Scope: []string{node.ID}}},
+		resp.Matches[structs.Nodes])
+	require.False(resp.Truncations[structs.Nodes])
+	require.Equal(uint64(1002), resp.Index)
+}
+
+func TestSearch_FuzzySearch_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, root, cleanupS := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	state := s.fsm.State()
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(jobIndex, job))
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1001, node))
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "ob",
+		Context: structs.All,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Try without a token and expect failure
+	{
+		var resp structs.FuzzySearchResponse
+		err := msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp)
+		require.EqualError(err, structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a node:read token and expect failure due to Groups being the context
+	{
+		validToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid", mock.NodePolicy(acl.PolicyRead))
+		req.Context = structs.Groups
+		req.AuthToken = validToken.SecretID
+		var resp structs.FuzzySearchResponse
+		err := msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp)
+		require.EqualError(err, structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a node:read token and expect only nodes for the All context
+	{
+		validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid", mock.NodePolicy(acl.PolicyRead))
+		req.Context = structs.All
+		req.AuthToken = validToken.SecretID
+		var resp structs.FuzzySearchResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+		require.Len(resp.Matches[structs.Nodes], 1)
+		require.Len(resp.Matches[structs.Jobs], 0)
+		require.Equal(uint64(1001), resp.Index)
+	}
+
+	// Try with a namespace:read-job token and expect only job contexts
+	{
+		validToken := mock.CreatePolicyAndToken(t, state, 1007, "test-valid2",
+			mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req.AuthToken = validToken.SecretID
+		var resp structs.FuzzySearchResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+		require.Len(resp.Matches[structs.Jobs], 1)
+		require.Len(resp.Matches[structs.Nodes], 0)
+		require.Equal(uint64(jobIndex), resp.Index)
+	}
+
+	// Try with a management token
+	{
+		req.AuthToken = root.SecretID
+		var resp structs.FuzzySearchResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+		require.Len(resp.Matches[structs.Jobs], 1)
+		require.Len(resp.Matches[structs.Nodes], 1)
+	}
+}
+
+func TestSearch_FuzzySearch_Errors(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "a",
+		Context: structs.All,
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+
+	var resp structs.FuzzySearchResponse
+	err := msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "at least 2 characters")
+
+	req.Text = "ab"
+	req.Context = structs.Evals
+	err = msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "context must be one of")
+}
+
+func TestSearch_FuzzySearch_Truncate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	for i := 0; i < 25; i++ {
+		job := mock.Job()
+		job.Name = fmt.Sprintf("job-%02d", i)
+		require.NoError(s.fsm.State().UpsertJob(uint64(jobIndex+i), job))
+	}
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "job",
+		Context: structs.Jobs,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+
+	var resp structs.FuzzySearchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+	require.Len(resp.Matches[structs.Jobs], 20)
+	require.Equal("job-00", resp.Matches[structs.Jobs][0].ID)
+	require.True(resp.Truncations[structs.Jobs])
+}
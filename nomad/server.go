@@ -251,6 +251,7 @@ type endpoints struct {
 
 	// Client endpoints
 	ClientStats       *ClientStats
+	NodeMeta          *NodeMeta
 	FileSystem        *FileSystem
 	Agent             *Agent
 	ClientAllocations *ClientAllocations
@@ -1039,6 +1040,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 
 		// Client endpoints
 		s.staticEndpoints.ClientStats = &ClientStats{srv: s, logger: s.logger.Named("client_stats")}
+		s.staticEndpoints.NodeMeta = &NodeMeta{srv: s, logger: s.logger.Named("client_meta")}
 		s.staticEndpoints.ClientAllocations = &ClientAllocations{srv: s, logger: s.logger.Named("client_allocs")}
 		s.staticEndpoints.ClientAllocations.register()
 
@@ -1065,6 +1067,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.Search)
 	s.staticEndpoints.Enterprise.Register(server)
 	server.Register(s.staticEndpoints.ClientStats)
+	server.Register(s.staticEndpoints.NodeMeta)
 	server.Register(s.staticEndpoints.ClientAllocations)
 	server.Register(s.staticEndpoints.FileSystem)
 	server.Register(s.staticEndpoints.Agent)
@@ -0,0 +1,183 @@
+package state
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+
+	memdb "github.com/hashicorp/go-memdb"
+)
+
+// seekIterator iterates over the objects of an index whose keys start with a
+// prefix and are at least a bound, in key order. It is used to start a page
+// of a list at the object of the next token without walking the objects
+// before it.
+//
+// The index is walked by a chain of prefix scans. The first scan is of the
+// bound itself, followed by the prefixes branching off the bound to a larger
+// byte, from the last byte of the bound back to the end of the prefix. Most
+// of these prefixes match no keys and only cost a lookup in the index.
+type seekIterator struct {
+	txn   *memdb.Txn
+	table string
+	index string
+
+	// args returns the arguments of a prefix scan of the index for the raw
+	// key prefix
+	args func(key []byte) []interface{}
+
+	// bound is the smallest key returned and min is the length of the
+	// prefix all keys start with
+	bound []byte
+	min   int
+
+	// pos is the byte of the bound being branched off and next is the byte
+	// value of the next branch
+	pos  int
+	next int
+
+	iter    memdb.ResultIterator
+	watchCh <-chan struct{}
+}
+
+// newSeekIterator returns an iterator over the objects of the index of the
+// table whose keys start with prefix and are at least bound. The watch
+// channel of the iterator fires on changes to any key with the prefix.
+func newSeekIterator(txn *memdb.Txn, table, index string, args func([]byte) []interface{}, prefix, bound []byte) (*seekIterator, error) {
+	watchIter, err := txn.Get(table, index+"_prefix", args(prefix)...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &seekIterator{
+		txn:     txn,
+		table:   table,
+		index:   index,
+		args:    args,
+		min:     len(prefix),
+		watchCh: watchIter.WatchCh(),
+	}
+
+	switch {
+	case bytes.HasPrefix(bound, prefix):
+		s.bound = bound
+	case bytes.Compare(bound, prefix) < 0:
+		// All keys with the prefix are past the bound
+		s.bound = prefix
+	default:
+		// No key with the prefix reaches the bound
+		s.pos = -1
+		return s, nil
+	}
+
+	if s.iter, err = txn.Get(table, index+"_prefix", args(s.bound)...); err != nil {
+		return nil, err
+	}
+	s.pos = len(s.bound) - 1
+	if s.pos >= s.min {
+		s.next = int(s.bound[s.pos]) + 1
+	}
+	return s, nil
+}
+
+func (s *seekIterator) WatchCh() <-chan struct{} {
+	return s.watchCh
+}
+
+func (s *seekIterator) Next() interface{} {
+	for {
+		if s.iter != nil {
+			if raw := s.iter.Next(); raw != nil {
+				return raw
+			}
+		}
+
+		key, ok := s.nextPrefix()
+		if !ok {
+			return nil
+		}
+
+		// The arguments only differ from those of the first scan by the key,
+		// so the scan can't fail
+		iter, err := s.txn.Get(s.table, s.index+"_prefix", s.args(key)...)
+		if err != nil {
+			return nil
+		}
+		s.iter = iter
+	}
+}
+
+// nextPrefix returns the next key prefix to scan, or false once the keys
+// past the bound have all been scanned.
+func (s *seekIterator) nextPrefix() ([]byte, bool) {
+	for s.pos >= s.min {
+		if s.next <= math.MaxUint8 {
+			key := make([]byte, s.pos+1)
+			copy(key, s.bound[:s.pos])
+			key[s.pos] = byte(s.next)
+			s.next++
+			return key, true
+		}
+
+		s.pos--
+		if s.pos >= s.min {
+			s.next = int(s.bound[s.pos]) + 1
+		}
+	}
+	return nil, false
+}
+
+// uuidKey returns the key of a UUID or a prefix of one in a UUID index. Like
+// the prefix scans of UUID indexes, the UUID must have an even number of
+// hexadecimal digits.
+func uuidKey(id string) ([]byte, error) {
+	if len(id) > 36 {
+		return nil, fmt.Errorf("Invalid UUID length. UUID have 36 characters; got %d", len(id))
+	}
+
+	sanitized := strings.Replace(id, "-", "", -1)
+	if len(sanitized)%2 != 0 {
+		return nil, fmt.Errorf("Input (without hyphens) must be even length")
+	}
+
+	key, err := hex.DecodeString(sanitized)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid UUID: %v", err)
+	}
+	return key, nil
+}
+
+// uuidKeyRange returns the keys of a UUID prefix and of the UUID to start
+// from, which defaults to the prefix.
+func uuidKeyRange(prefix, fromID string) (prefixKey, fromKey []byte, err error) {
+	if prefixKey, err = uuidKey(prefix); err != nil {
+		return nil, nil, err
+	}
+	if fromID == "" {
+		return prefixKey, prefixKey, nil
+	}
+	if fromKey, err = uuidKey(fromID); err != nil {
+		return nil, nil, err
+	}
+	return prefixKey, fromKey, nil
+}
+
+// namespaceIDSeek returns a seekIterator over the objects of the table in the
+// namespace whose ID has the prefix, starting at the given ID. The keys of the
+// namespace index of a table are the namespace followed by the UUID of the
+// object, so the objects of a namespace are in ID order.
+func namespaceIDSeek(txn *memdb.Txn, table, namespace, prefix, fromID string) (*seekIterator, error) {
+	prefixKey, fromKey, err := uuidKeyRange(prefix, fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := namespace + "\x00"
+	args := func(key []byte) []interface{} {
+		return []interface{}{string(key)}
+	}
+	return newSeekIterator(txn, table, "namespace", args,
+		append([]byte(ns), prefixKey...), append([]byte(ns), fromKey...))
+}
@@ -251,7 +251,11 @@ func (s *StateStore) UpsertPlanResults(index uint64, results *structs.ApplyPlanR
 
 	// Upsert the newly created or updated deployment
 	if results.Deployment != nil {
-		if err := s.upsertDeploymentImpl(index, results.Deployment, txn); err != nil {
+		deployment, err := s.planDeployment(results.Deployment, txn)
+		if err != nil {
+			return err
+		}
+		if err := s.upsertDeploymentImpl(index, deployment, txn); err != nil {
 			return err
 		}
 	}
@@ -333,6 +337,28 @@ func addComputedAllocAttrs(allocs []*structs.Allocation, job *structs.Job) {
 	}
 }
 
+// planDeployment returns the deployment to upsert for the deployment of a plan.
+// A plan only updates an existing deployment to grow the desired totals of its
+// task groups, so the rest of the deployment is taken from the state store
+// rather than from the scheduler's possibly stale copy.
+func (s *StateStore) planDeployment(deployment *structs.Deployment, txn *memdb.Txn) (*structs.Deployment, error) {
+	existing, err := txn.First("deployment", "id", deployment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("deployment lookup failed: %v", err)
+	}
+	if existing == nil {
+		return deployment, nil
+	}
+
+	copy := existing.(*structs.Deployment).Copy()
+	for name, dstate := range deployment.TaskGroups {
+		if state, ok := copy.TaskGroups[name]; ok {
+			state.DesiredTotal = dstate.DesiredTotal
+		}
+	}
+	return copy, nil
+}
+
 // upsertDeploymentUpdates updates the deployments given the passed status
 // updates.
 func (s *StateStore) upsertDeploymentUpdates(index uint64, updates []*structs.DeploymentStatusUpdate, txn *memdb.Txn) error {
@@ -486,6 +512,22 @@ func (s *StateStore) DeploymentsByIDPrefix(ws memdb.WatchSet, namespace, deploym
 	return wrap, nil
 }
 
+// DeploymentsByIDPrefixFrom returns an iterator over the deployments of the
+// namespace whose ID has the prefix, in ID order, starting at the deployment
+// with the given ID or the next one after it. The iterator seeks directly to
+// the deployment, so it can be used to return a page of deployments.
+func (s *StateStore) DeploymentsByIDPrefixFrom(ws memdb.WatchSet, namespace, prefix, fromID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := namespaceIDSeek(txn, "deployment", namespace, prefix, fromID)
+	if err != nil {
+		return nil, fmt.Errorf("deployment lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // deploymentNamespaceFilter returns a filter function that filters all
 // deployment not in the given namespace.
 func deploymentNamespaceFilter(namespace string) func(interface{}) bool {
@@ -819,11 +861,34 @@ func (s *StateStore) updateNodeDrainImpl(txn *memdb.Txn, index uint64, nodeID st
 	return nil
 }
 
+// BatchUpdateNodeEligibility is used to update the scheduling eligibility of
+// a batch of nodes
+func (s *StateStore) BatchUpdateNodeEligibility(index uint64, updatedAt int64, updates map[string]string, events map[string]*structs.NodeEvent) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+	for node, eligibility := range updates {
+		if err := s.updateNodeEligibilityImpl(txn, index, node, eligibility, updatedAt, events[node]); err != nil {
+			return err
+		}
+	}
+	txn.Commit()
+	return nil
+}
+
 // UpdateNodeEligibility is used to update the scheduling eligibility of a node
 func (s *StateStore) UpdateNodeEligibility(index uint64, nodeID string, eligibility string, updatedAt int64, event *structs.NodeEvent) error {
 
 	txn := s.db.Txn(true)
 	defer txn.Abort()
+	if err := s.updateNodeEligibilityImpl(txn, index, nodeID, eligibility, updatedAt, event); err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func (s *StateStore) updateNodeEligibilityImpl(txn *memdb.Txn, index uint64, nodeID string,
+	eligibility string, updatedAt int64, event *structs.NodeEvent) error {
 
 	// Lookup the node
 	existing, err := txn.First("nodes", "id", nodeID)
@@ -861,7 +926,6 @@ func (s *StateStore) UpdateNodeEligibility(index uint64, nodeID string, eligibil
 		return fmt.Errorf("index update failed: %v", err)
 	}
 
-	txn.Commit()
 	return nil
 }
 
@@ -955,6 +1019,30 @@ func (s *StateStore) NodesByIDPrefix(ws memdb.WatchSet, nodeID string) (memdb.Re
 	return iter, nil
 }
 
+// NodesByIDPrefixFrom returns an iterator over the nodes whose ID has the
+// prefix, in ID order, starting at the node with the given ID or the next one
+// after it. The iterator seeks directly to the node, so it can be used to
+// return a page of nodes.
+func (s *StateStore) NodesByIDPrefixFrom(ws memdb.WatchSet, prefix, fromID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	prefixKey, fromKey, err := uuidKeyRange(prefix, fromID)
+	if err != nil {
+		return nil, fmt.Errorf("node lookup failed: %v", err)
+	}
+
+	args := func(key []byte) []interface{} {
+		return []interface{}{key}
+	}
+	iter, err := newSeekIterator(txn, "nodes", "id", args, prefixKey, fromKey)
+	if err != nil {
+		return nil, fmt.Errorf("node lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // NodeBySecretID is used to lookup a node by SecretID
 func (s *StateStore) NodeBySecretID(ws memdb.WatchSet, secretID string) (*structs.Node, error) {
 	txn := s.db.Txn(false)
@@ -1292,6 +1380,25 @@ func (s *StateStore) JobsByIDPrefix(ws memdb.WatchSet, namespace, id string) (me
 	return iter, nil
 }
 
+// JobsByIDPrefixFrom returns an iterator over the jobs of the namespace whose
+// ID has the prefix, in ID order, starting at the job with the given ID or the
+// next one after it. The iterator seeks directly to the job, so it can be used
+// to return a page of jobs.
+func (s *StateStore) JobsByIDPrefixFrom(ws memdb.WatchSet, namespace, prefix, fromID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	args := func(key []byte) []interface{} {
+		return []interface{}{namespace, string(key)}
+	}
+	iter, err := newSeekIterator(txn, "jobs", "id", args, []byte(prefix), []byte(fromID))
+	if err != nil {
+		return nil, fmt.Errorf("job lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // JobVersionsByID returns all the tracked versions of a job.
 func (s *StateStore) JobVersionsByID(ws memdb.WatchSet, namespace, id string) ([]*structs.Job, error) {
 	txn := s.db.Txn(false)
@@ -1850,6 +1957,22 @@ func (s *StateStore) EvalsByIDPrefix(ws memdb.WatchSet, namespace, id string) (m
 	return wrap, nil
 }
 
+// EvalsByIDPrefixFrom returns an iterator over the evaluations of the
+// namespace whose ID has the prefix, in ID order, starting at the evaluation
+// with the given ID or the next one after it. The iterator seeks directly to
+// the evaluation, so it can be used to return a page of evaluations.
+func (s *StateStore) EvalsByIDPrefixFrom(ws memdb.WatchSet, namespace, prefix, fromID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := namespaceIDSeek(txn, "evals", namespace, prefix, fromID)
+	if err != nil {
+		return nil, fmt.Errorf("eval lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // evalNamespaceFilter returns a filter function that filters all evaluations
 // not in the given namespace.
 func evalNamespaceFilter(namespace string) func(interface{}) bool {
@@ -1992,6 +2115,11 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *memdb.Txn, index uint64, a
 		copyAlloc.DeploymentStatus.ModifyIndex = index
 	}
 
+	// The client archives the alloc dir before destroying it
+	if alloc.ArchiveStatus != nil {
+		copyAlloc.ArchiveStatus = alloc.ArchiveStatus.Copy()
+	}
+
 	// Update the modify index
 	copyAlloc.ModifyIndex = index
 
@@ -2260,6 +2388,22 @@ func (s *StateStore) AllocsByIDPrefix(ws memdb.WatchSet, namespace, id string) (
 	return wrap, nil
 }
 
+// AllocsByIDPrefixFrom returns an iterator over the allocations of the
+// namespace whose ID has the prefix, in ID order, starting at the allocation
+// with the given ID or the next one after it. The iterator seeks directly to
+// the allocation, so it can be used to return a page of allocations.
+func (s *StateStore) AllocsByIDPrefixFrom(ws memdb.WatchSet, namespace, prefix, fromID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := namespaceIDSeek(txn, "allocs", namespace, prefix, fromID)
+	if err != nil {
+		return nil, fmt.Errorf("alloc lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // allocNamespaceFilter returns a filter function that filters all allocations
 // not in the given namespace.
 func allocNamespaceFilter(namespace string) func(interface{}) bool {
@@ -2770,7 +2914,10 @@ func (s *StateStore) UpdateDeploymentPromotion(index uint64, req *structs.ApplyD
 			continue
 		}
 
+		// The health gate of the canaries has been consumed, so the group
+		// is gated again once the rest of the allocations are healthy
 		status.Promoted = true
+		status.HealthGate = nil
 	}
 
 	// If the deployment no longer needs promotion, update its status
@@ -2812,6 +2959,95 @@ func (s *StateStore) UpdateDeploymentPromotion(index uint64, req *structs.ApplyD
 	return nil
 }
 
+// UpdateDeploymentStep is used to advance the rollout steps of the task groups
+// of a deployment and potentially make a evaluation
+func (s *StateStore) UpdateDeploymentStep(index uint64, req *structs.ApplyDeploymentStepRequest) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Retrieve deployment and ensure it is not terminal and is active
+	ws := memdb.NewWatchSet()
+	deployment, err := s.deploymentByIDImpl(ws, req.DeploymentID, txn)
+	if err != nil {
+		return err
+	} else if deployment == nil {
+		return fmt.Errorf("Deployment ID %q couldn't be updated as it does not exist", req.DeploymentID)
+	} else if !deployment.Active() {
+		return fmt.Errorf("Deployment %q has terminal status %q:", deployment.ID, deployment.Status)
+	}
+
+	// Update the steps of the groups
+	copy := deployment.Copy()
+	copy.ModifyIndex = index
+	for tg, step := range req.Steps {
+		state, ok := copy.TaskGroups[tg]
+		if !ok {
+			return fmt.Errorf("Deployment %q does not have task group %q", deployment.ID, tg)
+		}
+		if step < state.CurrentStep || step > len(state.RolloutSteps) {
+			return fmt.Errorf("Task group %q can not move to rollout step %d", tg, step)
+		}
+		state.CurrentStep = step
+	}
+
+	// Insert the deployment
+	if err := s.upsertDeploymentImpl(index, copy, txn); err != nil {
+		return err
+	}
+
+	// Upsert the optional eval
+	if req.Eval != nil {
+		if err := s.nestedUpsertEval(txn, index, req.Eval); err != nil {
+			return err
+		}
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// UpdateDeploymentHealthGate is used to record the result of a task group's
+// health gate on a deployment and potentially make a evaluation
+func (s *StateStore) UpdateDeploymentHealthGate(index uint64, req *structs.ApplyDeploymentHealthGateRequest) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Retrieve deployment and ensure it is not terminal and is active
+	ws := memdb.NewWatchSet()
+	deployment, err := s.deploymentByIDImpl(ws, req.DeploymentID, txn)
+	if err != nil {
+		return err
+	} else if deployment == nil {
+		return fmt.Errorf("Deployment ID %q couldn't be updated as it does not exist", req.DeploymentID)
+	} else if !deployment.Active() {
+		return fmt.Errorf("Deployment %q has terminal status %q:", deployment.ID, deployment.Status)
+	}
+
+	// Record the result on the group
+	copy := deployment.Copy()
+	copy.ModifyIndex = index
+	state, ok := copy.TaskGroups[req.TaskGroup]
+	if !ok {
+		return fmt.Errorf("Deployment %q does not have task group %q", deployment.ID, req.TaskGroup)
+	}
+	state.HealthGate = req.Result.Copy()
+
+	// Insert the deployment
+	if err := s.upsertDeploymentImpl(index, copy, txn); err != nil {
+		return err
+	}
+
+	// Upsert the optional eval
+	if req.Eval != nil {
+		if err := s.nestedUpsertEval(txn, index, req.Eval); err != nil {
+			return err
+		}
+	}
+
+	txn.Commit()
+	return nil
+}
+
 // UpdateDeploymentAllocHealth is used to update the health of allocations as
 // part of the deployment and potentially make a evaluation
 func (s *StateStore) UpdateDeploymentAllocHealth(index uint64, req *structs.ApplyDeploymentAllocHealthRequest) error {
@@ -350,6 +350,54 @@ func TestStateStore_UpsertPlanResults_Deployment(t *testing.T) {
 	assert.EqualValues(1001, evalOut.ModifyIndex)
 }
 
+// This test checks that a plan updating an existing deployment only changes the
+// desired totals of its task groups
+func TestStateStore_UpsertPlanResults_DeploymentDesiredTotal(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	alloc := mock.Alloc()
+	job := alloc.Job
+	alloc.Job = nil
+
+	d := mock.Deployment()
+	d.JobID = job.ID
+	d.TaskGroups["web"].DesiredTotal = 2
+	require.NoError(state.UpsertJob(999, job))
+	require.NoError(state.UpsertDeployment(1000, d))
+
+	// Mark allocations of the deployment healthy after the scheduler's copy
+	// was taken
+	stale := d.Copy()
+	healthy := d.Copy()
+	healthy.TaskGroups["web"].HealthyAllocs = 2
+	require.NoError(state.UpsertDeployment(1001, healthy))
+
+	// Grow the deployment with a stale copy
+	stale.TaskGroups["web"].DesiredTotal = 3
+	alloc.DeploymentID = d.ID
+	res := structs.ApplyPlanResultsRequest{
+		AllocUpdateRequest: structs.AllocUpdateRequest{
+			Alloc: []*structs.Allocation{alloc},
+			Job:   job,
+		},
+		Deployment: stale,
+	}
+	require.NoError(state.UpsertPlanResults(1002, &res))
+
+	ws := memdb.NewWatchSet()
+	dout, err := state.DeploymentByID(ws, d.ID)
+	require.NoError(err)
+	require.EqualValues(1000, dout.CreateIndex)
+	require.EqualValues(1002, dout.ModifyIndex)
+
+	tg := dout.TaskGroups["web"]
+	require.Equal(3, tg.DesiredTotal)
+	require.Equal(2, tg.HealthyAllocs)
+	require.Equal(1, tg.PlacedAllocs)
+}
+
 // This test checks that:
 // 1) Preempted allocations in plan results are updated
 // 2) Evals are inserted for preempted jobs
@@ -1219,6 +1267,74 @@ func TestStateStore_UpdateNodeEligibility(t *testing.T) {
 	require.Contains(err.Error(), "while it is draining")
 }
 
+func TestStateStore_BatchUpdateNodeEligibility(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+
+	n1, n2 := mock.Node(), mock.Node()
+	require.Nil(state.UpsertNode(1000, n1))
+	require.Nil(state.UpsertNode(1001, n2))
+
+	// Create a watchset so we can test that the update fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := state.NodeByID(ws, n1.ID)
+	require.Nil(err)
+
+	updates := map[string]string{
+		n1.ID: structs.NodeSchedulingIneligible,
+		n2.ID: structs.NodeSchedulingIneligible,
+	}
+	event := &structs.NodeEvent{
+		Message:   "Node marked as ineligible",
+		Subsystem: structs.NodeEventSubsystemCluster,
+		Timestamp: time.Now(),
+	}
+	events := map[string]*structs.NodeEvent{
+		n1.ID: event,
+		n2.ID: event,
+	}
+
+	require.Nil(state.BatchUpdateNodeEligibility(1002, 7, updates, events))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	for _, id := range []string{n1.ID, n2.ID} {
+		out, err := state.NodeByID(ws, id)
+		require.Nil(err)
+		require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+		require.Len(out.Events, 2)
+		require.EqualValues(1002, out.ModifyIndex)
+		require.EqualValues(7, out.StatusUpdatedAt)
+	}
+
+	index, err := state.Index("nodes")
+	require.Nil(err)
+	require.EqualValues(1002, index)
+	require.False(watchFired(ws))
+
+	// Drain one node and ensure the whole batch is rejected
+	expectedDrain := &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{
+			Deadline: -1 * time.Second,
+		},
+	}
+	require.Nil(state.UpdateNodeDrain(1003, n1.ID, expectedDrain, false, 8, nil))
+
+	updates = map[string]string{
+		n1.ID: structs.NodeSchedulingEligible,
+		n2.ID: structs.NodeSchedulingEligible,
+	}
+	err = state.BatchUpdateNodeEligibility(1004, 9, updates, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "while it is draining")
+
+	out, err := state.NodeByID(nil, n2.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+}
+
 func TestStateStore_Nodes(t *testing.T) {
 	t.Parallel()
 
@@ -2204,6 +2320,41 @@ func TestStateStore_JobsByIDPrefix(t *testing.T) {
 	}
 }
 
+func TestStateStore_JobsByIDPrefixFrom(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	ids := []string{"example", "redis", "redis-2", "redis-20", "riak", "zookeeper"}
+	for i, id := range ids {
+		job := mock.Job()
+		job.ID = id
+		require.NoError(state.UpsertJob(uint64(1000+i), job))
+	}
+
+	gatherIDs := func(prefix, fromID string) []string {
+		iter, err := state.JobsByIDPrefixFrom(nil, structs.DefaultNamespace, prefix, fromID)
+		require.NoError(err)
+
+		var out []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			out = append(out, raw.(*structs.Job).ID)
+		}
+		return out
+	}
+
+	require.Equal(ids, gatherIDs("", ""))
+	require.Equal(ids[1:], gatherIDs("", "redis"))
+	require.Equal(ids[3:], gatherIDs("", "redis-20"))
+	require.Equal(ids[4:], gatherIDs("", "redis-3"))
+	require.Equal(ids[1:5], gatherIDs("r", ""))
+	require.Equal(ids[2:4], gatherIDs("redis-", "redis-2"))
+	require.Equal(ids[1:5], gatherIDs("r", "a"))
+	require.Empty(gatherIDs("r", "s"))
+	require.Empty(gatherIDs("", "zz"))
+	require.Empty(gatherIDs("", "\xff"))
+}
+
 func TestStateStore_JobsByPeriodic(t *testing.T) {
 	t.Parallel()
 
@@ -5097,6 +5248,75 @@ func TestStateStore_AllocsByIDPrefix(t *testing.T) {
 	}
 }
 
+func TestStateStore_AllocsByIDPrefixFrom(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	ids := []string{
+		"aaaaaaaa-7bfb-395d-eb95-0685af2176b2",
+		"aaaaaaab-7bfb-395d-eb95-0685af2176b2",
+		"aaaaaabb-7bfb-395d-eb95-0685af2176b2",
+		"aaaaabbb-7bfb-395d-eb95-0685af2176b2",
+		"aaaabbbb-7bfb-395d-eb95-0685af2176b2",
+		"aaabbbbb-7bfb-395d-eb95-0685af2176b2",
+		"aabbbbbb-7bfb-395d-eb95-0685af2176b2",
+		"abbbbbbb-7bfb-395d-eb95-0685af2176b2",
+		"bbbbbbbb-7bfb-395d-eb95-0685af2176b2",
+	}
+	var allocs []*structs.Allocation
+	for _, id := range ids {
+		alloc := mock.Alloc()
+		alloc.ID = id
+		allocs = append(allocs, alloc)
+	}
+
+	// An allocation of another namespace within the range
+	other := mock.Alloc()
+	other.ID = "aaaaaaac-7bfb-395d-eb95-0685af2176b2"
+	other.Namespace = "other"
+	allocs = append(allocs, other)
+	require.NoError(state.UpsertAllocs(1000, allocs))
+
+	gatherIDs := func(prefix, fromID string) []string {
+		iter, err := state.AllocsByIDPrefixFrom(nil, structs.DefaultNamespace, prefix, fromID)
+		require.NoError(err)
+
+		var out []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			out = append(out, raw.(*structs.Allocation).ID)
+		}
+		return out
+	}
+
+	require.Equal(ids, gatherIDs("", ""))
+	require.Equal(ids[3:], gatherIDs("", ids[3]))
+	require.Equal(ids[:5], gatherIDs("aaaa", ""))
+	require.Equal(ids[2:5], gatherIDs("aaaa", ids[2]))
+	require.Equal([]string{ids[8]}, gatherIDs("", ids[8]))
+
+	// The iterator starts after an ID that doesn't exist
+	require.Equal(ids[2:], gatherIDs("", "aaaaaaab-ffff-395d-eb95-0685af2176b2"))
+	require.Empty(gatherIDs("", "cccccccc-7bfb-395d-eb95-0685af2176b2"))
+
+	// IDs outside of the prefix
+	require.Equal(ids[:5], gatherIDs("aaaa", "00000000-7bfb-395d-eb95-0685af2176b2"))
+	require.Empty(gatherIDs("aaaa", ids[5]))
+
+	_, err := state.AllocsByIDPrefixFrom(nil, structs.DefaultNamespace, "aaa", "")
+	require.Error(err)
+
+	// The watch fires on new allocations of the prefix
+	ws := memdb.NewWatchSet()
+	_, err = state.AllocsByIDPrefixFrom(ws, structs.DefaultNamespace, "", ids[4])
+	require.NoError(err)
+	require.False(watchFired(ws))
+
+	alloc := mock.Alloc()
+	require.NoError(state.UpsertAllocs(1001, []*structs.Allocation{alloc}))
+	require.True(watchFired(ws))
+}
+
 func TestStateStore_Allocs(t *testing.T) {
 	t.Parallel()
 
@@ -6031,6 +6251,106 @@ func TestStateStore_UpsertDeploymentPromotion_Terminal(t *testing.T) {
 	}
 }
 
+// Test advancing the rollout step of a deployment.
+func TestStateStore_UpdateDeploymentStep(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	require := require.New(t)
+
+	d := mock.Deployment()
+	d.TaskGroups["web"].DesiredTotal = 10
+	d.TaskGroups["web"].RolloutSteps = []int{20, 50}
+	require.Nil(state.UpsertDeployment(1, d))
+
+	// Moving past the last step should fail
+	req := &structs.ApplyDeploymentStepRequest{
+		DeploymentID: d.ID,
+		Steps:        map[string]int{"web": 3},
+	}
+	err := state.UpdateDeploymentStep(2, req)
+	require.Error(err)
+	require.Contains(err.Error(), "can not move to rollout step")
+
+	// Unknown groups should fail
+	req.Steps = map[string]int{"foo": 1}
+	err = state.UpdateDeploymentStep(2, req)
+	require.Error(err)
+	require.Contains(err.Error(), "does not have task group")
+
+	// Advance the step and create an eval
+	e := mock.Eval()
+	req.Steps = map[string]int{"web": 1}
+	req.Eval = e
+	require.Nil(state.UpdateDeploymentStep(3, req))
+
+	ws := memdb.NewWatchSet()
+	dout, err := state.DeploymentByID(ws, d.ID)
+	require.Nil(err)
+	require.Equal(1, dout.TaskGroups["web"].CurrentStep)
+	require.EqualValues(3, dout.ModifyIndex)
+
+	eout, err := state.EvalByID(ws, e.ID)
+	require.Nil(err)
+	require.NotNil(eout)
+}
+
+// Test recording a health gate result on a deployment and resetting it when
+// the deployment is promoted.
+func TestStateStore_UpdateDeploymentHealthGate(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	require := require.New(t)
+
+	j := mock.Job()
+	require.Nil(state.UpsertJob(1, j))
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	require.Nil(state.UpsertDeployment(2, d))
+
+	// Unknown groups should fail
+	req := &structs.ApplyDeploymentHealthGateRequest{
+		DeploymentID: d.ID,
+		TaskGroup:    "foo",
+		Result:       &structs.HealthGateResult{Passed: true},
+	}
+	err := state.UpdateDeploymentHealthGate(3, req)
+	require.Error(err)
+	require.Contains(err.Error(), "does not have task group")
+
+	// Record the result and create an eval
+	e := mock.Eval()
+	req.TaskGroup = "web"
+	req.Eval = e
+	require.Nil(state.UpdateDeploymentHealthGate(4, req))
+
+	ws := memdb.NewWatchSet()
+	dout, err := state.DeploymentByID(ws, d.ID)
+	require.Nil(err)
+	require.True(dout.TaskGroups["web"].HealthGatePassed())
+	require.EqualValues(4, dout.ModifyIndex)
+
+	eout, err := state.EvalByID(ws, e.ID)
+	require.Nil(err)
+	require.NotNil(eout)
+
+	// Promoting resets the result
+	promote := &structs.ApplyDeploymentPromoteRequest{
+		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			All:          true,
+		},
+	}
+	require.Nil(state.UpdateDeploymentPromotion(5, promote))
+
+	dout, err = state.DeploymentByID(ws, d.ID)
+	require.Nil(err)
+	require.True(dout.TaskGroups["web"].Promoted)
+	require.Nil(dout.TaskGroups["web"].HealthGate)
+}
+
 // Test promoting unhealthy canaries in a deployment.
 func TestStateStore_UpsertDeploymentPromotion_Unhealthy(t *testing.T) {
 	t.Parallel()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/nomad/helper/flatmap"
@@ -115,6 +116,17 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Dependencies diff
+	dependsOnDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if dependsOnDiff != nil {
+		diff.Objects = append(diff.Objects, dependsOnDiff...)
+	}
+
 	// Task groups diff
 	tgs, err := taskGroupDiffs(j.TaskGroups, other.TaskGroups, contextual)
 	if err != nil {
@@ -132,6 +144,11 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, cDiff)
 	}
 
+	// GC diff
+	if gDiff := primitiveObjectDiff(j.GC, other.GC, nil, "GC", contextual); gDiff != nil {
+		diff.Objects = append(diff.Objects, gDiff)
+	}
+
 	// Check to see if there is a diff. We don't use reflect because we are
 	// filtering quite a few fields that will change on each diff.
 	if diff.Type == DiffTypeNone {
@@ -268,11 +285,15 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 	}
 
 	// Update diff
-	// COMPAT: Remove "Stagger" in 0.7.0.
-	if uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual); uDiff != nil {
+	if uDiff := updateStrategyDiff(tg.Update, other.Update, contextual); uDiff != nil {
 		diff.Objects = append(diff.Objects, uDiff)
 	}
 
+	// Archive diff
+	if aDiff := allocArchiveDiff(tg.Archive, other.Archive, contextual); aDiff != nil {
+		diff.Objects = append(diff.Objects, aDiff)
+	}
+
 	// Network Resources diff
 	if nDiffs := networkResourceDiffs(tg.Networks, other.Networks, contextual); nDiffs != nil {
 		diff.Objects = append(diff.Objects, nDiffs...)
@@ -440,7 +461,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(t.LogConfig, other.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -827,7 +848,7 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(old.LogConfig, new.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(old.LogConfig, new.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -835,6 +856,36 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// logConfigDiff returns the diff of two LogConfig objects, including the
+// set of log sinks. If contextual diff is enabled, all fields will be
+// returned, even if no diff occurred.
+func logConfigDiff(old, new *LogConfig, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "LogConfig", contextual)
+
+	var oldSinks, newSinks []*LogSink
+	if old != nil {
+		oldSinks = old.Sinks
+	}
+	if new != nil {
+		newSinks = new.Sinks
+	}
+	sDiffs := primitiveObjectSetDiff(
+		interfaceSlice(oldSinks),
+		interfaceSlice(newSinks),
+		nil,
+		"Sink",
+		contextual)
+	if len(sDiffs) == 0 {
+		return diff
+	}
+
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "LogConfig"}
+	}
+	diff.Objects = append(diff.Objects, sDiffs...)
+	return diff
+}
+
 // consulProxyDiff returns the diff of two ConsulProxy objects.
 // If contextual diff is enabled, all fields will be returned, even if no diff occurred.
 func consulProxyDiff(old, new *ConsulProxy, contextual bool) *ObjectDiff {
@@ -1401,6 +1452,72 @@ func fieldDiffs(old, new map[string]string, contextual bool) []*FieldDiff {
 	return diffs
 }
 
+// updateStrategyDiff returns the diff of two update strategies. If contextual
+// diff is enabled, all fields will be returned, even if no diff occurred.
+func updateStrategyDiff(old, new *UpdateStrategy, contextual bool) *ObjectDiff {
+	// COMPAT: Remove "Stagger" in 0.7.0.
+	diff := primitiveObjectDiff(old, new, []string{"Stagger"}, "Update", contextual)
+
+	// Diff the rollout steps
+	var oldSteps, newSteps []string
+	if old != nil {
+		for _, step := range old.RolloutSteps {
+			oldSteps = append(oldSteps, strconv.Itoa(step))
+		}
+	}
+	if new != nil {
+		for _, step := range new.RolloutSteps {
+			newSteps = append(newSteps, strconv.Itoa(step))
+		}
+	}
+	if sDiff := stringSetDiff(oldSteps, newSteps, "RolloutSteps", contextual); sDiff != nil {
+		if diff == nil {
+			diff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+		}
+		diff.Objects = append(diff.Objects, sDiff)
+	}
+
+	// Diff the health gate
+	var oldGate, newGate *HealthGate
+	if old != nil {
+		oldGate = old.HealthGate
+	}
+	if new != nil {
+		newGate = new.HealthGate
+	}
+	if gDiff := primitiveObjectDiff(oldGate, newGate, nil, "HealthGate", contextual); gDiff != nil {
+		if diff == nil {
+			diff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+		}
+		diff.Objects = append(diff.Objects, gDiff)
+	}
+
+	return diff
+}
+
+// allocArchiveDiff returns the diff of two archive stanzas. If contextual diff
+// is enabled, all fields will be returned, even if no diff occurred.
+func allocArchiveDiff(old, new *AllocArchive, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "Archive", contextual)
+
+	// Diff the paths
+	var oldPaths, newPaths []string
+	if old != nil {
+		oldPaths = old.Paths
+	}
+	if new != nil {
+		newPaths = new.Paths
+	}
+	if pDiff := stringSetDiff(oldPaths, newPaths, "Paths", contextual); pDiff != nil {
+		if diff == nil {
+			diff = &ObjectDiff{Type: DiffTypeEdited, Name: "Archive"}
+		}
+		diff.Objects = append(diff.Objects, pDiff)
+	}
+
+	return diff
+}
+
 // stringSetDiff diffs two sets of strings with the given name.
 func stringSetDiff(old, new []string, name string, contextual bool) *ObjectDiff {
 	oldMap := make(map[string]struct{}, len(old))
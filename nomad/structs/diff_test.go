@@ -602,6 +602,88 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// GC added
+			Old: &Job{},
+			New: &Job{
+				GC: &JobGCConfig{
+					MinRetention: time.Hour,
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "GC",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "MinRetention",
+								Old:  "",
+								New:  "3600000000000",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			// DependsOn edited
+			Old: &Job{
+				DependsOn: []*JobDependency{
+					{JobID: "db", Status: JobDependencyStatusRunning},
+					{JobID: "queue", Status: JobDependencyStatusRunning},
+				},
+			},
+			New: &Job{
+				DependsOn: []*JobDependency{
+					{JobID: "db", Status: JobDependencyStatusHealthy},
+					{JobID: "queue", Status: JobDependencyStatusRunning},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "DependsOn",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "JobID",
+								Old:  "",
+								New:  "db",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Status",
+								Old:  "",
+								New:  "healthy",
+							},
+						},
+					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "DependsOn",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "JobID",
+								Old:  "db",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Status",
+								Old:  "running",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Periodic edited with context
 			Contextual: true,
@@ -1995,6 +2077,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "0",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "CanaryPercent",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "HealthyDeadline",
@@ -2057,6 +2145,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "0",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "CanaryPercent",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "HealthyDeadline",
@@ -2224,6 +2318,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "2",
 								New:  "2",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "CanaryPercent",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "HealthCheck",
@@ -2339,6 +2439,58 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Archive edited
+			Old: &TaskGroup{
+				Archive: &AllocArchive{
+					Paths:  []string{"alloc/data", "web/local"},
+					Volume: "results",
+				},
+			},
+			New: &TaskGroup{
+				Archive: &AllocArchive{
+					Paths:  []string{"alloc/data", "web/output"},
+					Volume: "outputs",
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Archive",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Volume",
+								Old:  "results",
+								New:  "outputs",
+							},
+						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Paths",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Paths",
+										Old:  "",
+										New:  "web/output",
+									},
+									{
+										Type: DiffTypeDeleted,
+										Name: "Paths",
+										Old:  "web/local",
+										New:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// EphemeralDisk edited
 			Old: &TaskGroup{
@@ -2952,6 +3104,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "false",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxRunDuration",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "ShutdownDelay",
@@ -2998,6 +3156,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "false",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxRunDuration",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "ShutdownDelay",
@@ -3426,6 +3590,18 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Compress",
+								Old:  "",
+								New:  "false",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxAge",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "MaxFileSizeMB",
@@ -3438,6 +3614,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "RotateInterval",
+								Old:  "",
+								New:  "0",
+							},
 						},
 					},
 				},
@@ -3459,6 +3641,18 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Compress",
+								Old:  "false",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxAge",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "MaxFileSizeMB",
@@ -3471,6 +3665,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "RotateInterval",
+								Old:  "0",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -3536,6 +3736,18 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "Compress",
+								Old:  "false",
+								New:  "false",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "MaxAge",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "MaxFileSizeMB",
@@ -3548,6 +3760,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "RotateInterval",
+								Old:  "0",
+								New:  "0",
+							},
 						},
 					},
 				},
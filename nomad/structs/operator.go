@@ -1,8 +1,10 @@
 package structs
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/raft"
 )
 
@@ -126,11 +128,35 @@ type SchedulerConfiguration struct {
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig
 
+	// PlacementTraceEnabled specifies whether the schedulers record why each
+	// node was rejected in the metrics of the placements of all evaluations.
+	PlacementTraceEnabled bool
+
+	// NamespaceWeights specifies the share of the eval broker each namespace
+	// receives while several namespaces have evaluations of the same priority
+	// waiting. Namespaces without a weight have a weight of 1.
+	NamespaceWeights map[string]int
+
+	// PauseEvalBroker specifies whether the eval broker is paused. While it
+	// is paused, no evaluations are processed and evaluations may be deleted.
+	PauseEvalBroker bool
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// Validate returns an error if the scheduler configuration is invalid
+func (s *SchedulerConfiguration) Validate() error {
+	var mErr multierror.Error
+	for namespace, weight := range s.NamespaceWeights {
+		if weight < 1 {
+			multierror.Append(&mErr, fmt.Errorf("Weight of namespace %q must be at least 1, got %d", namespace, weight))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
 // SchedulerConfigurationResponse is the response object that wraps SchedulerConfiguration
 type SchedulerConfigurationResponse struct {
 	// SchedulerConfig contains scheduler config options
@@ -173,3 +199,92 @@ type SchedulerSetConfigRequest struct {
 	// WriteRequest holds the ACL token to go along with this request.
 	WriteRequest
 }
+
+// SchedulerSimulateRequest is used by the Operator endpoint to replay
+// hypothetical jobs, node additions and node removals against a snapshot of
+// the cluster state without committing any plan.
+type SchedulerSimulateRequest struct {
+	// Jobs are registered in order after the node changes are applied.
+	Jobs []*Job
+
+	// AddNodes are the nodes added to the cluster, as clones of existing
+	// nodes.
+	AddNodes []*SimulateAddNode
+
+	// RemoveNodes are the IDs of the nodes removed from the cluster.
+	RemoveNodes []string
+
+	QueryOptions
+}
+
+// SimulateAddNode adds Count clones of an existing node to a simulation.
+type SimulateAddNode struct {
+	NodeID string
+	Count  int
+}
+
+// SchedulerSimulateResponse is the result of a scheduler simulation.
+type SchedulerSimulateResponse struct {
+	// Evals are the results of the evaluations processed by the simulation in
+	// order, including the evaluations created by the node changes.
+	Evals []*SimulatedEval
+
+	// Nodes is the utilization of the nodes at the end of the simulation.
+	Nodes []*SimulatedNode
+
+	// Warnings are the warnings returned when validating the jobs.
+	Warnings string
+
+	QueryMeta
+}
+
+// SimulatedEval is the result of an evaluation processed by a simulation.
+type SimulatedEval struct {
+	Namespace   string
+	JobID       string
+	TriggeredBy string
+
+	// Placed, Stopped and Preempted are the allocations placed, stopped and
+	// preempted by the evaluation.
+	Placed    []*SimulatedAlloc
+	Stopped   []*SimulatedAlloc
+	Preempted []*SimulatedAlloc
+
+	// FailedTGAllocs are the metrics of the task groups that couldn't be
+	// placed.
+	FailedTGAllocs map[string]*AllocMetric
+}
+
+// SimulatedAlloc describes an allocation changed by a simulated evaluation.
+type SimulatedAlloc struct {
+	ID        string
+	Name      string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	NodeID    string
+}
+
+// SimulatedNode is the utilization of a node at the end of a simulation.
+type SimulatedNode struct {
+	ID         string
+	Name       string
+	Datacenter string
+	NodeClass  string
+	Status     string
+
+	// Simulated is true if the node was added by the simulation.
+	Simulated bool
+
+	// Allocs is the number of non-terminal allocations on the node.
+	Allocs int
+
+	// The resources allocated on the node and the resources available to
+	// allocations.
+	CPU              int64
+	CPUCapacity      int64
+	MemoryMB         int64
+	MemoryCapacityMB int64
+	DiskMB           int64
+	DiskCapacityMB   int64
+}
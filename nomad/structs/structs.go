@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -83,6 +84,9 @@ const (
 	BatchNodeUpdateDrainRequestType
 	SchedulerConfigRequestType
 	NodeBatchDeregisterRequestType
+	BatchNodeUpdateEligibilityRequestType
+	DeploymentStepRequestType
+	DeploymentHealthGateRequestType
 )
 
 const (
@@ -140,6 +144,10 @@ const (
 	// retain scoring metadata
 	MaxRetainedNodeScores = 5
 
+	// MaxPlacementTraceEntries is the number of rejected nodes for which we
+	// retain the reason in the placement trace of an allocation metric
+	MaxPlacementTraceEntries = 50
+
 	// Normalized scorer name
 	NormScorerName = "normalized-score"
 )
@@ -157,6 +165,13 @@ const (
 	Namespaces  Context = "namespaces"
 	Quotas      Context = "quotas"
 	All         Context = "all"
+
+	// Groups, Tasks, Services and Images are only used by fuzzy searches,
+	// and match the components of jobs.
+	Groups   Context = "groups"
+	Tasks    Context = "tasks"
+	Services Context = "services"
+	Images   Context = "images"
 )
 
 // NamespacedID is a tuple of an ID and a namespace
@@ -235,6 +250,20 @@ type QueryOptions struct {
 	// AuthToken is secret portion of the ACL token used for the request
 	AuthToken string
 
+	// PerPage is the number of entries to be returned in queries that support
+	// paginated lists.
+	PerPage int32
+
+	// NextToken is the token used to indicate where to start paging for
+	// queries that support paginated lists. This token should be the ID of
+	// the next object after the last one seen in the previous response.
+	NextToken string
+
+	// Filter is a boolean expression over the fields of the listed objects,
+	// used to only return the objects matching it in queries that support
+	// filtered lists.
+	Filter string
+
 	InternalRpcInfo
 }
 
@@ -362,6 +391,11 @@ type QueryMeta struct {
 
 	// Used to indicate if there is a known leader node
 	KnownLeader bool
+
+	// NextToken is the token returned with queries that support paginated
+	// lists. To resume paging from this point, pass this token in the next
+	// request's QueryOptions.
+	NextToken string
 }
 
 // WriteMeta allows a write response to include potentially
@@ -483,6 +517,83 @@ type NodeUpdateEligibilityRequest struct {
 	WriteRequest
 }
 
+// BatchNodeUpdateEligibilityRequest is used for updating the scheduling
+// eligibility of a batch of nodes
+type BatchNodeUpdateEligibilityRequest struct {
+	// Updates is a mapping of nodes to their updated scheduling eligibility
+	Updates map[string]string
+
+	// NodeEvents is a mapping of the node to the event to add to the node
+	NodeEvents map[string]*NodeEvent
+
+	// UpdatedAt represents server time of receiving request
+	UpdatedAt int64
+
+	WriteRequest
+}
+
+// NodeSelector is used to select a set of nodes by their properties. A node
+// is selected only if it matches every non-empty field of the selector.
+type NodeSelector struct {
+	// Datacenter restricts the selection to nodes in the given datacenter.
+	Datacenter string
+
+	// NodeClass restricts the selection to nodes of the given class.
+	NodeClass string
+
+	// Filters are constraints, evaluated like job constraints, that the node
+	// must satisfy. They can be used to select nodes by attribute or meta.
+	Filters []*Constraint
+}
+
+// IsEmpty returns whether the selector has no selection criteria and would
+// therefore match every node.
+func (s *NodeSelector) IsEmpty() bool {
+	return s == nil || (s.Datacenter == "" && s.NodeClass == "" && len(s.Filters) == 0)
+}
+
+// Validate returns an error if the selector is empty or any of its filters
+// are invalid.
+func (s *NodeSelector) Validate() error {
+	if s.IsEmpty() {
+		return fmt.Errorf("node selector must specify a datacenter, node class or filter")
+	}
+
+	var mErr multierror.Error
+	for idx, c := range s.Filters {
+		if c == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Filter %d is nil", idx+1))
+			continue
+		}
+		if err := c.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Filter %d validation failed: %s", idx+1, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// NodeBatchUpdateDrainRequest is used for Node.BatchUpdateDrain endpoint to
+// update the drain strategy of all nodes matching a selector.
+type NodeBatchUpdateDrainRequest struct {
+	Selector      *NodeSelector
+	DrainStrategy *DrainStrategy
+
+	// MarkEligible marks the nodes as eligible if removing the drain strategy.
+	MarkEligible bool
+
+	WriteRequest
+}
+
+// NodeBatchUpdateEligibilityRequest is used for Node.BatchUpdateEligibility
+// endpoint to update the scheduling eligibility of all nodes matching a
+// selector.
+type NodeBatchUpdateEligibilityRequest struct {
+	Selector    *NodeSelector
+	Eligibility string
+
+	WriteRequest
+}
+
 // NodeEvaluateRequest is used to re-evaluate the node
 type NodeEvaluateRequest struct {
 	NodeID string
@@ -525,6 +636,44 @@ type SearchRequest struct {
 	QueryOptions
 }
 
+// FuzzySearchRequest is used to parameterize a fuzzy search, which matches
+// the names of jobs and their components and of nodes containing the text.
+type FuzzySearchRequest struct {
+	// Text is the case insensitive substring the names are matched to
+	Text string
+
+	// Context is the type that can be matched against. A context can be jobs,
+	// groups, tasks, services, images, nodes, or all to match every context.
+	Context Context
+
+	QueryOptions
+}
+
+// FuzzyMatch is the name of a matching object. If the object is a component
+// of a job, Scope holds the IDs of its parents starting with the namespace,
+// so that the object can be found.
+type FuzzyMatch struct {
+	// ID is the name of the matching object
+	ID string
+
+	// Scope is the IDs of the parents of the object, such as the namespace,
+	// job ID and task group name of a task.
+	Scope []string `json:",omitempty"`
+}
+
+// FuzzySearchResponse is used to return the matches of a fuzzy search and
+// whether the matches of each context have been truncated.
+type FuzzySearchResponse struct {
+	// Matches is the matching objects of each context
+	Matches map[Context][]FuzzyMatch
+
+	// Truncations indicates whether the matches for a particular context have
+	// been truncated
+	Truncations map[Context]bool
+
+	QueryMeta
+}
+
 // JobRegisterRequest is used for Job.Register endpoint
 // to register a job as being a schedulable entity.
 type JobRegisterRequest struct {
@@ -606,6 +755,9 @@ type JobPlanRequest struct {
 	Diff bool // Toggles an annotated diff
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
+	// Explain records why each node was rejected in the placement trace of
+	// the failed allocation metrics
+	Explain bool
 	WriteRequest
 }
 
@@ -678,7 +830,8 @@ type EvalUpdateRequest struct {
 	WriteRequest
 }
 
-// EvalDeleteRequest is used for deleting an evaluation.
+// EvalDeleteRequest is used for deleting an evaluation. Allocs is only used
+// by the garbage collector.
 type EvalDeleteRequest struct {
 	Evals  []string
 	Allocs []string
@@ -708,9 +861,34 @@ type EvalDequeueRequest struct {
 
 // EvalListRequest is used to list the evaluations
 type EvalListRequest struct {
+	// FilterJobID, FilterEvalStatus, FilterTriggeredBy and FilterNodeID
+	// restrict the evaluations to those with the given field values.
+	FilterJobID       string
+	FilterEvalStatus  string
+	FilterTriggeredBy string
+	FilterNodeID      string
+
 	QueryOptions
 }
 
+// ShouldBeFiltered returns true if the evaluation doesn't match the filters
+// of the request.
+func (req *EvalListRequest) ShouldBeFiltered(e *Evaluation) bool {
+	if req.FilterJobID != "" && req.FilterJobID != e.JobID {
+		return true
+	}
+	if req.FilterEvalStatus != "" && req.FilterEvalStatus != e.Status {
+		return true
+	}
+	if req.FilterTriggeredBy != "" && req.FilterTriggeredBy != e.TriggeredBy {
+		return true
+	}
+	if req.FilterNodeID != "" && req.FilterNodeID != e.NodeID {
+		return true
+	}
+	return false
+}
+
 // PlanRequest is used to submit an allocation plan to the leader
 type PlanRequest struct {
 	Plan *Plan
@@ -1002,6 +1180,37 @@ type ApplyDeploymentPromoteRequest struct {
 	Eval *Evaluation
 }
 
+// ApplyDeploymentStepRequest is used to advance the rollout steps of the task
+// groups of a deployment via Raft
+type ApplyDeploymentStepRequest struct {
+	DeploymentID string
+
+	// Steps maps the task groups to the rollout step they advance to
+	Steps map[string]int
+
+	// An optional evaluation to create after advancing the steps
+	Eval *Evaluation
+
+	WriteRequest
+}
+
+// ApplyDeploymentHealthGateRequest is used to record the result of a task
+// group's health gate on a deployment via Raft
+type ApplyDeploymentHealthGateRequest struct {
+	DeploymentID string
+
+	// TaskGroup is the task group the health gate was run for
+	TaskGroup string
+
+	// Result is the result of the health gate
+	Result *HealthGateResult
+
+	// An optional evaluation to create after recording the result
+	Eval *Evaluation
+
+	WriteRequest
+}
+
 // DeploymentPauseRequest is used to pause a deployment
 type DeploymentPauseRequest struct {
 	DeploymentID string
@@ -1128,6 +1337,26 @@ type NodeEligibilityUpdateResponse struct {
 	WriteMeta
 }
 
+// NodeBatchDrainUpdateResponse is used to respond to a drain update of the
+// nodes matching a selector
+type NodeBatchDrainUpdateResponse struct {
+	NodeIDs         []string
+	NodeModifyIndex uint64
+	EvalIDs         []string
+	EvalCreateIndex uint64
+	WriteMeta
+}
+
+// NodeBatchEligibilityUpdateResponse is used to respond to an eligibility
+// update of the nodes matching a selector
+type NodeBatchEligibilityUpdateResponse struct {
+	NodeIDs         []string
+	NodeModifyIndex uint64
+	EvalIDs         []string
+	EvalCreateIndex uint64
+	WriteMeta
+}
+
 // NodeAllocsResponse is used to return allocs for a single node
 type NodeAllocsResponse struct {
 	Allocs []*Allocation
@@ -3381,6 +3610,10 @@ type Job struct {
 	// to run. Each task group is an atomic unit of scheduling and placement.
 	TaskGroups []*TaskGroup
 
+	// DependsOn is the set of jobs, in the job's namespace, which must reach
+	// a given status before the allocations of this job are placed.
+	DependsOn []*JobDependency
+
 	// See agent.ApiJobToStructJob
 	// Update provides defaults for the TaskGroup Update stanzas
 	Update UpdateStrategy
@@ -3392,6 +3625,10 @@ type Job struct {
 	// for dispatching.
 	ParameterizedJob *ParameterizedJobConfig
 
+	// GC is used to hint clients how long to keep the terminal allocations
+	// of the job before garbage collecting them.
+	GC *JobGCConfig
+
 	// Dispatched is used to identify if the Job has been dispatched from a
 	// parameterized job.
 	Dispatched bool
@@ -3476,6 +3713,10 @@ func (j *Job) Canonicalize() (warnings error) {
 		j.Periodic.Canonicalize()
 	}
 
+	for _, dep := range j.DependsOn {
+		dep.Canonicalize()
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -3502,6 +3743,15 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = nj.Periodic.Copy()
 	nj.Meta = helper.CopyMapStringString(nj.Meta)
 	nj.ParameterizedJob = nj.ParameterizedJob.Copy()
+	nj.GC = nj.GC.Copy()
+
+	if j.DependsOn != nil {
+		deps := make([]*JobDependency, len(j.DependsOn))
+		for i, dep := range j.DependsOn {
+			deps[i] = dep.Copy()
+		}
+		nj.DependsOn = deps
+	}
 	return nj
 }
 
@@ -3592,6 +3842,10 @@ func (j *Job) Validate() error {
 			mErr.Errors = append(mErr.Errors, errors.New("ShutdownDelay must be a positive value"))
 		}
 
+		if tg.MaxRunDuration != nil && *tg.MaxRunDuration < 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("MaxRunDuration must be a positive value"))
+		}
+
 		if j.Type == "system" && tg.Count > 1 {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("Job task group %s has count %d. Count cannot exceed 1 with system scheduler",
@@ -3630,6 +3884,29 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.GC != nil {
+		if err := j.GC.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
+	// Check the dependencies
+	dependencies := make(map[string]struct{}, len(j.DependsOn))
+	for idx, dep := range j.DependsOn {
+		if err := dep.Validate(); err != nil {
+			outer := fmt.Errorf("Dependency %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+			continue
+		}
+		if dep.JobID == j.ID {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Job can't depend on itself"))
+		}
+		if _, ok := dependencies[dep.JobID]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Dependency on job %q is defined more than once", dep.JobID))
+		}
+		dependencies[dep.JobID] = struct{}{}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -4019,6 +4296,21 @@ type UpdateStrategy struct {
 	// Canary is the number of canaries to deploy when a change to the task
 	// group is detected.
 	Canary int
+
+	// CanaryPercent is the number of canaries to deploy as a percentage of
+	// the task group count. It is exclusive with Canary.
+	CanaryPercent int
+
+	// RolloutSteps is an increasing list of percentages of the task group
+	// count the rolling update progresses through. A step is started as
+	// soon as all the allocations of the previous step are healthy, and the
+	// rollout completes to 100% after the last step.
+	RolloutSteps []int
+
+	// HealthGate is an optional external check that must pass before the
+	// canaries of the group are promoted or the group's deployment is
+	// considered successful.
+	HealthGate *HealthGate
 }
 
 func (u *UpdateStrategy) Copy() *UpdateStrategy {
@@ -4028,9 +4320,29 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 
 	copy := new(UpdateStrategy)
 	*copy = *u
+	copy.RolloutSteps = helper.CopySliceInt(u.RolloutSteps)
+	copy.HealthGate = u.HealthGate.Copy()
 	return copy
 }
 
+// DesiredCanaries returns the number of canaries to deploy for a task group
+// with the given count.
+func (u *UpdateStrategy) DesiredCanaries(count int) int {
+	if u == nil {
+		return 0
+	}
+	if u.CanaryPercent == 0 {
+		return u.Canary
+	}
+	return percentOf(count, u.CanaryPercent)
+}
+
+// percentOf returns the given percentage of total, rounded up so that any
+// non-zero percentage of a non-zero total is at least one.
+func percentOf(total, percent int) int {
+	return (total*percent + 99) / 100
+}
+
 func (u *UpdateStrategy) Validate() error {
 	if u == nil {
 		return nil
@@ -4049,9 +4361,22 @@ func (u *UpdateStrategy) Validate() error {
 	if u.Canary < 0 {
 		multierror.Append(&mErr, fmt.Errorf("Canary count can not be less than zero: %d < 0", u.Canary))
 	}
-	if u.Canary == 0 && u.AutoPromote {
+	if u.CanaryPercent < 0 || u.CanaryPercent > 100 {
+		multierror.Append(&mErr, fmt.Errorf("Canary percent must be between 0 and 100: %d", u.CanaryPercent))
+	}
+	if u.Canary != 0 && u.CanaryPercent != 0 {
+		multierror.Append(&mErr, fmt.Errorf("Canary count and canary percent can not both be set"))
+	}
+	if u.Canary == 0 && u.CanaryPercent == 0 && u.AutoPromote {
 		multierror.Append(&mErr, fmt.Errorf("Auto Promote requires a Canary count greater than zero"))
 	}
+	for i, step := range u.RolloutSteps {
+		if step <= 0 || step > 100 {
+			multierror.Append(&mErr, fmt.Errorf("Rollout step %d must be between 1 and 100: %d", i+1, step))
+		} else if i > 0 && step <= u.RolloutSteps[i-1] {
+			multierror.Append(&mErr, fmt.Errorf("Rollout steps must be increasing: %d <= %d", step, u.RolloutSteps[i-1]))
+		}
+	}
 	if u.MinHealthyTime < 0 {
 		multierror.Append(&mErr, fmt.Errorf("Minimum healthy time may not be less than zero: %v", u.MinHealthyTime))
 	}
@@ -4070,6 +4395,9 @@ func (u *UpdateStrategy) Validate() error {
 	if u.Stagger <= 0 {
 		multierror.Append(&mErr, fmt.Errorf("Stagger must be greater than zero: %v", u.Stagger))
 	}
+	if err := u.HealthGate.Validate(); err != nil {
+		multierror.Append(&mErr, fmt.Errorf("Health gate validation failed: %v", err))
+	}
 
 	return mErr.ErrorOrNil()
 }
@@ -4082,6 +4410,60 @@ func (u *UpdateStrategy) IsEmpty() bool {
 	return u.MaxParallel == 0
 }
 
+// HealthGate is an HTTP endpoint that is consulted by the deployment watcher
+// once the allocations of a task group are healthy. A 2xx response passes the
+// gate while any other response or error fails the attempt.
+type HealthGate struct {
+	// URL is the HTTP(S) endpoint the deployment is POSTed to
+	URL string
+
+	// Timeout is the maximum time a single attempt may take
+	Timeout time.Duration
+
+	// Attempts is the number of times the gate is tried before it is
+	// considered failed
+	Attempts int
+
+	// RetryInterval is the time to wait between failed attempts
+	RetryInterval time.Duration
+}
+
+func (h *HealthGate) Copy() *HealthGate {
+	if h == nil {
+		return nil
+	}
+
+	nh := new(HealthGate)
+	*nh = *h
+	return nh
+}
+
+func (h *HealthGate) Validate() error {
+	if h == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if h.URL == "" {
+		multierror.Append(&mErr, fmt.Errorf("Missing URL"))
+	} else if u, err := url.Parse(h.URL); err != nil {
+		multierror.Append(&mErr, fmt.Errorf("Invalid URL %q: %v", h.URL, err))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		multierror.Append(&mErr, fmt.Errorf("URL must use http or https: %q", h.URL))
+	}
+	if h.Timeout <= 0 {
+		multierror.Append(&mErr, fmt.Errorf("Timeout must be greater than zero: %v", h.Timeout))
+	}
+	if h.Attempts < 1 {
+		multierror.Append(&mErr, fmt.Errorf("Attempts must be at least one: %d", h.Attempts))
+	}
+	if h.RetryInterval < 0 {
+		multierror.Append(&mErr, fmt.Errorf("Retry interval may not be less than zero: %v", h.RetryInterval))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 // TODO(alexdadgar): Remove once no longer used by the scheduler.
 // Rolling returns if a rolling strategy should be used
 func (u *UpdateStrategy) Rolling() bool {
@@ -4309,6 +4691,88 @@ func (d *ParameterizedJobConfig) Copy() *ParameterizedJobConfig {
 	return nd
 }
 
+// JobGCConfig is used to hint clients how to garbage collect the terminal
+// allocations of a job
+type JobGCConfig struct {
+	// MinRetention is the minimum duration clients keep the terminal
+	// allocations of the job. Clients still collect them earlier when they
+	// must free disk space.
+	MinRetention time.Duration
+}
+
+func (g *JobGCConfig) Copy() *JobGCConfig {
+	if g == nil {
+		return nil
+	}
+	ng := new(JobGCConfig)
+	*ng = *g
+	return ng
+}
+
+func (g *JobGCConfig) Validate() error {
+	if g.MinRetention < 0 {
+		return fmt.Errorf("GC min retention must be a positive value")
+	}
+	return nil
+}
+
+const (
+	// JobDependencyStatusRunning requires every task group of the job to have
+	// a running allocation.
+	JobDependencyStatusRunning = "running"
+
+	// JobDependencyStatusHealthy requires the latest deployment of the job to
+	// be successful. For jobs without deployments it's the same as running.
+	JobDependencyStatusHealthy = "healthy"
+
+	// JobDependencyStatusComplete requires the job to be dead with every task
+	// group having completed allocations.
+	JobDependencyStatusComplete = "complete"
+)
+
+// JobDependency is a job another job depends on, along with the status it
+// must reach before the allocations of the dependent job are placed.
+type JobDependency struct {
+	// JobID is the ID of the job, in the namespace of the dependent job.
+	JobID string
+
+	// Status is the status the job must reach.
+	Status string
+}
+
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(JobDependency)
+	*nd = *d
+	return nd
+}
+
+func (d *JobDependency) Canonicalize() {
+	if d.Status == "" {
+		d.Status = JobDependencyStatusRunning
+	}
+}
+
+func (d *JobDependency) Validate() error {
+	var mErr multierror.Error
+	if d.JobID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing dependency job ID"))
+	}
+	switch d.Status {
+	case JobDependencyStatusRunning, JobDependencyStatusHealthy, JobDependencyStatusComplete:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid dependency status %q; must be one of %q, %q or %q",
+			d.Status, JobDependencyStatusRunning, JobDependencyStatusHealthy, JobDependencyStatusComplete))
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (d *JobDependency) String() string {
+	return fmt.Sprintf("%s (%s)", d.JobID, d.Status)
+}
+
 // DispatchedID returns an ID appropriate for a job dispatched against a
 // particular parameterized job
 func DispatchedID(templateID string, t time.Time) string {
@@ -4726,6 +5190,54 @@ func (m *MigrateStrategy) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// AllocArchive is used to archive paths of the allocation directory into a
+// host volume before the allocation directory is destroyed, so that outputs of
+// the allocation outlive its garbage collection.
+type AllocArchive struct {
+	// Paths are the paths relative to the allocation directory to archive
+	Paths []string
+
+	// Volume is the name of the task group's host volume to write the
+	// archive to
+	Volume string
+}
+
+func (a *AllocArchive) Copy() *AllocArchive {
+	if a == nil {
+		return nil
+	}
+
+	na := new(AllocArchive)
+	*na = *a
+	na.Paths = helper.CopySliceString(a.Paths)
+	return na
+}
+
+// Validate validates the archive against the volumes of the task group
+func (a *AllocArchive) Validate(volumes map[string]*VolumeRequest) error {
+	var mErr multierror.Error
+	if len(a.Paths) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Archive must have at least one path"))
+	}
+	for _, path := range a.Paths {
+		if escapes, err := PathEscapesAllocDir("", path); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid path %q: %v", path, err))
+		} else if escapes {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("path %q escapes the allocation directory", path))
+		}
+	}
+
+	if a.Volume == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Archive must have a volume"))
+	} else if v, ok := volumes[a.Volume]; !ok {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Archive references undefined volume %s", a.Volume))
+	} else if v.ReadOnly {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Archive references read only volume %s", a.Volume))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 // TaskGroup is an atomic unit of placement. Each task group belongs to
 // a job and may contain any number of tasks. A task group support running
 // in many replicas using the same configuration..
@@ -4785,6 +5297,14 @@ type TaskGroup struct {
 	// ShutdownDelay is the amount of time to wait between deregistering
 	// group services in consul and stopping tasks.
 	ShutdownDelay *time.Duration
+
+	// MaxRunDuration is the maximum duration the tasks of the group may run
+	// for. It is used by the tasks which don't set their own.
+	MaxRunDuration *time.Duration
+
+	// Archive is used to archive paths of the allocation directory into a
+	// host volume before the allocation is garbage collected.
+	Archive *AllocArchive
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -4800,6 +5320,7 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg.Affinities = CopySliceAffinities(ntg.Affinities)
 	ntg.Spreads = CopySliceSpreads(ntg.Spreads)
 	ntg.Volumes = CopyMapVolumeRequest(ntg.Volumes)
+	ntg.Archive = ntg.Archive.Copy()
 
 	// Copy the network objects
 	if tg.Networks != nil {
@@ -4835,6 +5356,10 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 		ntg.ShutdownDelay = tg.ShutdownDelay
 	}
 
+	if tg.MaxRunDuration != nil {
+		ntg.MaxRunDuration = tg.MaxRunDuration
+	}
+
 	return ntg
 }
 
@@ -4959,6 +5484,12 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		default:
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Job type %q does not allow update block", j.Type))
 		}
+		if j.Type == JobTypeSystem && (u.Canary != 0 || u.CanaryPercent != 0) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have canaries"))
+		}
+		if j.Type == JobTypeSystem && len(u.RolloutSteps) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have rollout steps"))
+		}
 		if err := u.Validate(); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
 		}
@@ -5012,6 +5543,14 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	// Validate the archive
+	if tg.Archive != nil {
+		if err := tg.Archive.Validate(tg.Volumes); err != nil {
+			outer := fmt.Errorf("Archive validation failed: %v", err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
 	// Validate task group and task network resources
 	if err := tg.validateNetworks(); err != nil {
 		outer := fmt.Errorf("Task group network validation failed: %v", err)
@@ -5279,16 +5818,42 @@ const (
 type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
+
+	// Compress gzips log files once they are rotated
+	Compress bool
+
+	// RotateInterval rotates log files after this duration even if they
+	// haven't reached the maximum size
+	RotateInterval time.Duration
+
+	// MaxAge is the duration after which rotated log files are removed even
+	// if there are fewer than MaxFiles
+	MaxAge time.Duration
+
+	// Sinks are additional destinations the task's logs are shipped to
+	// alongside the rotated log files
+	Sinks []*LogSink
 }
 
 func (l *LogConfig) Copy() *LogConfig {
 	if l == nil {
 		return nil
 	}
-	return &LogConfig{
-		MaxFiles:      l.MaxFiles,
-		MaxFileSizeMB: l.MaxFileSizeMB,
+
+	nl := &LogConfig{
+		MaxFiles:       l.MaxFiles,
+		MaxFileSizeMB:  l.MaxFileSizeMB,
+		Compress:       l.Compress,
+		RotateInterval: l.RotateInterval,
+		MaxAge:         l.MaxAge,
 	}
+	if l.Sinks != nil {
+		nl.Sinks = make([]*LogSink, len(l.Sinks))
+		for i, s := range l.Sinks {
+			nl.Sinks[i] = s.Copy()
+		}
+	}
+	return nl
 }
 
 // DefaultLogConfig returns the default LogConfig values.
@@ -5309,6 +5874,101 @@ func (l *LogConfig) Validate() error {
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
 	}
+	if l.RotateInterval != 0 && l.RotateInterval < time.Second {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum rotate interval is 1s; got %v", l.RotateInterval))
+	}
+	if l.MaxAge < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max age may not be negative; got %v", l.MaxAge))
+	}
+	for i, s := range l.Sinks {
+		if err := s.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("sink %d validation failed: %v", i+1, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// jsonSinks returns the number of sinks that write JSON log files to the
+// task's log directory.
+func (l *LogConfig) jsonSinks() int {
+	n := 0
+	for _, s := range l.Sinks {
+		if s.Type == LogSinkTypeJSON {
+			n++
+		}
+	}
+	return n
+}
+
+const (
+	// LogSinkTypeSyslog ships log lines in the RFC5424 format to a syslog
+	// server over TCP, UDP or a Unix socket.
+	LogSinkTypeSyslog = "syslog"
+
+	// LogSinkTypeJSON writes log lines along with the alloc, job and task
+	// metadata as JSON lines to a file in the task's log directory.
+	LogSinkTypeJSON = "json"
+)
+
+// LogSink is a destination the logs of a task are shipped to
+type LogSink struct {
+	// Type is the type of the sink
+	Type string
+
+	// Address is the URL of the syslog server for syslog sinks, with a tcp,
+	// udp or unix scheme, and the name of the file in the task's log
+	// directory for JSON sinks.
+	Address string
+
+	// BufferLines is the number of lines buffered for the sink before new
+	// lines are dropped. A zero value uses the default buffer size.
+	BufferLines int
+}
+
+func (s *LogSink) Copy() *LogSink {
+	if s == nil {
+		return nil
+	}
+	ns := new(LogSink)
+	*ns = *s
+	return ns
+}
+
+func (s *LogSink) Validate() error {
+	var mErr multierror.Error
+	switch s.Type {
+	case LogSinkTypeSyslog:
+		u, err := url.Parse(s.Address)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid address %q: %v", s.Address, err))
+			break
+		}
+		switch u.Scheme {
+		case "tcp", "udp":
+			if u.Host == "" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("address %q is missing a host", s.Address))
+			}
+		case "unix":
+			if u.Path == "" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("address %q is missing a socket path", s.Address))
+			}
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("address %q must use the tcp, udp or unix scheme", s.Address))
+		}
+	case LogSinkTypeJSON:
+		if s.Address == "" || s.Address != filepath.Base(s.Address) || s.Address == "." || s.Address == ".." {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("address %q must be a file name", s.Address))
+		} else if strings.HasPrefix(s.Address, "stdout") || strings.HasPrefix(s.Address, "stderr") {
+			// The file is prefixed with the task name and must not collide
+			// with the task's rotated stdout and stderr files
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("address %q may not start with stdout or stderr", s.Address))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid type %q", s.Type))
+	}
+	if s.BufferLines < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("buffer lines may not be negative; got %d", s.BufferLines))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -5377,6 +6037,11 @@ type Task struct {
 	// task from Consul and sending it a signal to shutdown. See #2441
 	ShutdownDelay time.Duration
 
+	// MaxRunDuration is the maximum duration the task may run for before it
+	// is killed and considered failed. Zero means the task may run
+	// indefinitely.
+	MaxRunDuration time.Duration
+
 	// VolumeMounts is a list of Volume name <-> mount configurations that will be
 	// attached to this task.
 	VolumeMounts []*VolumeMount
@@ -5473,6 +6138,11 @@ func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 		t.KillTimeout = DefaultKillTimeout
 	}
 
+	// Inherit the max run duration of the group if it is not specified.
+	if t.MaxRunDuration == 0 && tg.MaxRunDuration != nil {
+		t.MaxRunDuration = *tg.MaxRunDuration
+	}
+
 	if t.Vault != nil {
 		t.Vault.Canonicalize()
 	}
@@ -5506,6 +6176,11 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 	if t.ShutdownDelay < 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("ShutdownDelay must be a positive value"))
 	}
+	if t.MaxRunDuration < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("MaxRunDuration must be a positive value"))
+	} else if t.MaxRunDuration > 0 && jobType != JobTypeBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("MaxRunDuration is only supported for %q jobs", JobTypeBatch))
+	}
 
 	// Validate the resources.
 	if t.Resources == nil {
@@ -5553,7 +6228,8 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 	}
 
 	if t.LogConfig != nil && ephemeralDisk != nil {
-		logUsage := (t.LogConfig.MaxFiles * t.LogConfig.MaxFileSizeMB)
+		// JSON sinks are rotated like the task's log files
+		logUsage := (t.LogConfig.MaxFiles * t.LogConfig.MaxFileSizeMB) * (1 + t.LogConfig.jsonSinks())
 		if ephemeralDisk.SizeMB <= logUsage {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("log storage (%d MB) must be less than requested disk capacity (%d MB)",
@@ -6101,6 +6777,14 @@ const (
 	// failed.
 	TaskArtifactDownloadFailed = "Failed Artifact Download"
 
+	// TaskArtifactCacheHit indicates that an artifact was copied from the
+	// client's artifact cache.
+	TaskArtifactCacheHit = "Artifact Cache Hit"
+
+	// TaskArtifactCacheMiss indicates that an artifact was downloaded into
+	// the client's artifact cache.
+	TaskArtifactCacheMiss = "Artifact Cache Miss"
+
 	// TaskBuildingTaskDir indicates that the task directory/chroot is being
 	// built.
 	TaskBuildingTaskDir = "Building Task Directory"
@@ -6130,6 +6814,10 @@ const (
 	// TaskRestoreFailed indicates Nomad was unable to reattach to a
 	// restored task.
 	TaskRestoreFailed = "Failed Restoring Task"
+
+	// TaskMaxRunDurationExceeded indicates that the task ran for longer than
+	// its max run duration and is being killed.
+	TaskMaxRunDurationExceeded = "Max Run Duration Exceeded"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -6339,6 +7027,12 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 		desc = event.DriverMessage
 	case TaskLeaderDead:
 		desc = "Leader Task in Group dead"
+	case TaskMaxRunDurationExceeded:
+		if event.KillReason != "" {
+			desc = event.KillReason
+		} else {
+			desc = "Task exceeded its max run duration"
+		}
 	default:
 		desc = event.Message
 	}
@@ -6970,6 +7664,10 @@ func (s *Spread) Validate() error {
 	var mErr multierror.Error
 	if s.Attribute == "" {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing spread attribute"))
+	} else if s.Attribute == "${node.host_volumes}" {
+		// The list of host volumes has a single value only for nodes with one
+		// host volume
+		mErr.Errors = append(mErr.Errors, errors.New("Spread attribute ${node.host_volumes} is not supported; use ${node.host_volume.<name>}"))
 	}
 	if s.Weight <= 0 || s.Weight > 100 {
 		mErr.Errors = append(mErr.Errors, errors.New("Spread stanza must have a positive weight from 0 to 100"))
@@ -7172,6 +7870,7 @@ const (
 	DeploymentStatusDescriptionFailedAllocations     = "Failed due to unhealthy allocations"
 	DeploymentStatusDescriptionProgressDeadline      = "Failed due to progress deadline"
 	DeploymentStatusDescriptionFailedByUser          = "Deployment marked as failed"
+	DeploymentStatusDescriptionFailedHealthGate      = "Failed due to health gate"
 )
 
 // DeploymentStatusDescriptionRollback is used to get the status description of
@@ -7375,6 +8074,41 @@ type DeploymentState struct {
 
 	// UnhealthyAllocs are allocations that have been marked as unhealthy.
 	UnhealthyAllocs int
+
+	// RolloutSteps is the list of percentages of the desired total the
+	// rollout progresses through, copied from the task group's update
+	// strategy.
+	RolloutSteps []int
+
+	// CurrentStep is the index of the rollout step in progress. It is equal
+	// to the number of rollout steps once the last step is completed.
+	CurrentStep int
+
+	// HealthGate is the result of the task group's health gate for the
+	// current phase of the deployment. It is reset when the canaries are
+	// promoted.
+	HealthGate *HealthGateResult
+}
+
+// HealthGatePassed returns whether the health gate of the current phase of
+// the deployment has passed.
+func (d *DeploymentState) HealthGatePassed() bool {
+	return d.HealthGate != nil && d.HealthGate.Passed
+}
+
+// StepTarget returns the number of allocations the deployment may place for
+// the current rollout step.
+func (d *DeploymentState) StepTarget() int {
+	if d.CurrentStep >= len(d.RolloutSteps) {
+		return d.DesiredTotal
+	}
+	return percentOf(d.DesiredTotal, d.RolloutSteps[d.CurrentStep])
+}
+
+// HasNextStep returns whether the rollout has steps left after the current
+// one.
+func (d *DeploymentState) HasNextStep() bool {
+	return d.CurrentStep < len(d.RolloutSteps) && d.StepTarget() < d.DesiredTotal
 }
 
 func (d *DeploymentState) GoString() string {
@@ -7387,6 +8121,13 @@ func (d *DeploymentState) GoString() string {
 	base += fmt.Sprintf("\n\tUnhealthy: %d", d.UnhealthyAllocs)
 	base += fmt.Sprintf("\n\tAutoRevert: %v", d.AutoRevert)
 	base += fmt.Sprintf("\n\tAutoPromote: %v", d.AutoPromote)
+	if len(d.RolloutSteps) != 0 {
+		base += fmt.Sprintf("\n\tRollout Steps: %v", d.RolloutSteps)
+		base += fmt.Sprintf("\n\tCurrent Step: %d", d.CurrentStep)
+	}
+	if d.HealthGate != nil {
+		base += fmt.Sprintf("\n\tHealth Gate: %v (%s)", d.HealthGate.Passed, d.HealthGate.Description)
+	}
 	return base
 }
 
@@ -7394,9 +8135,39 @@ func (d *DeploymentState) Copy() *DeploymentState {
 	c := &DeploymentState{}
 	*c = *d
 	c.PlacedCanaries = helper.CopySliceString(d.PlacedCanaries)
+	c.RolloutSteps = helper.CopySliceInt(d.RolloutSteps)
+	c.HealthGate = d.HealthGate.Copy()
 	return c
 }
 
+// HealthGateResult is the result of checking a task group's health gate
+type HealthGateResult struct {
+	// Passed is whether the health gate passed
+	Passed bool
+
+	// Canary is whether the gate was checked for the canaries of the group
+	Canary bool
+
+	// Attempts is the number of attempts made
+	Attempts int
+
+	// Description is a human readable description of the result
+	Description string
+
+	// Timestamp is the time the result was recorded
+	Timestamp time.Time
+}
+
+func (r *HealthGateResult) Copy() *HealthGateResult {
+	if r == nil {
+		return nil
+	}
+
+	nr := new(HealthGateResult)
+	*nr = *r
+	return nr
+}
+
 // DeploymentStatusUpdate is used to update the status of a given deployment
 type DeploymentStatusUpdate struct {
 	// DeploymentID is the ID of the deployment to update
@@ -7618,6 +8389,10 @@ type Allocation struct {
 	// given deployment
 	DeploymentStatus *AllocDeploymentStatus
 
+	// ArchiveStatus captures where the client archived the allocation
+	// directory to before destroying it
+	ArchiveStatus *AllocArchiveStatus
+
 	// RescheduleTrackers captures details of previous reschedule attempts of the allocation
 	RescheduleTracker *RescheduleTracker
 
@@ -7698,6 +8473,7 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 
 	na.Metrics = na.Metrics.Copy()
 	na.DeploymentStatus = na.DeploymentStatus.Copy()
+	na.ArchiveStatus = na.ArchiveStatus.Copy()
 
 	if a.TaskStates != nil {
 		ts := make(map[string]*TaskState, len(na.TaskStates))
@@ -8154,6 +8930,22 @@ type AllocMetric struct {
 	// This is to prevent creating many failed allocations for a
 	// single task group.
 	CoalescedFailures int
+
+	// PlacementTrace records why nodes were rejected for the placement. It
+	// is only populated when the placement trace is enabled for the
+	// evaluation and is bounded to MaxPlacementTraceEntries entries.
+	PlacementTrace []*NodePlacementTrace
+
+	// PlacementTraceDropped is the number of rejected nodes that were not
+	// recorded in the placement trace because it was full.
+	PlacementTraceDropped int
+
+	// tracePlacement enables recording the placement trace
+	tracePlacement bool
+
+	// lastTraceReason is the reason of the last rejected node, even if it
+	// was dropped from the placement trace
+	lastTraceReason string
 }
 
 func (a *AllocMetric) Copy() *AllocMetric {
@@ -8170,6 +8962,12 @@ func (a *AllocMetric) Copy() *AllocMetric {
 	na.QuotaExhausted = helper.CopySliceString(na.QuotaExhausted)
 	na.Scores = helper.CopyMapStringFloat64(na.Scores)
 	na.ScoreMetaData = CopySliceNodeScoreMeta(na.ScoreMetaData)
+	if a.PlacementTrace != nil {
+		na.PlacementTrace = make([]*NodePlacementTrace, len(a.PlacementTrace))
+		for i, t := range a.PlacementTrace {
+			na.PlacementTrace[i] = t.Copy()
+		}
+	}
 	return na
 }
 
@@ -8209,6 +9007,51 @@ func (a *AllocMetric) ExhaustedNode(node *Node, dimension string) {
 	}
 }
 
+// EnablePlacementTrace enables recording why nodes were rejected.
+func (a *AllocMetric) EnablePlacementTrace() {
+	a.tracePlacement = true
+}
+
+// TracingPlacement returns whether the placement trace is enabled. Callers
+// can use it to avoid building the reasons when it is disabled.
+func (a *AllocMetric) TracingPlacement() bool {
+	return a.tracePlacement
+}
+
+// TraceNode records why the node was rejected by the given checker in the
+// placement trace, if it is enabled.
+func (a *AllocMetric) TraceNode(node *Node, checker, reason string) {
+	if !a.tracePlacement || node == nil {
+		return
+	}
+
+	a.lastTraceReason = reason
+	if len(a.PlacementTrace) >= MaxPlacementTraceEntries {
+		a.PlacementTraceDropped += 1
+		return
+	}
+
+	a.PlacementTrace = append(a.PlacementTrace, &NodePlacementTrace{
+		NodeID:   node.ID,
+		NodeName: node.Name,
+		Checker:  checker,
+		Reason:   reason,
+	})
+}
+
+// ClearPlacementTrace drops the placement trace. The trace is only kept for
+// failed placements, so it isn't carried by placed allocations.
+func (a *AllocMetric) ClearPlacementTrace() {
+	a.PlacementTrace = nil
+	a.PlacementTraceDropped = 0
+}
+
+// LastTraceReason returns the reason of the last node recorded in the
+// placement trace.
+func (a *AllocMetric) LastTraceReason() string {
+	return a.lastTraceReason
+}
+
 func (a *AllocMetric) ExhaustQuota(dimensions []string) {
 	if a.QuotaExhausted == nil {
 		a.QuotaExhausted = make([]string, 0, len(dimensions))
@@ -8261,6 +9104,27 @@ func (a *AllocMetric) PopulateScoreMetaData() {
 	}
 }
 
+// NodePlacementTrace records why a node was rejected for a placement.
+type NodePlacementTrace struct {
+	NodeID   string
+	NodeName string
+
+	// Checker is the part of the scheduler that rejected the node
+	Checker string
+
+	// Reason is why the checker rejected the node
+	Reason string
+}
+
+func (t *NodePlacementTrace) Copy() *NodePlacementTrace {
+	if t == nil {
+		return nil
+	}
+	nt := new(NodePlacementTrace)
+	*nt = *t
+	return nt
+}
+
 // NodeScoreMeta captures scoring meta data derived from
 // different scoring factors.
 type NodeScoreMeta struct {
@@ -8360,6 +9224,33 @@ func (a *AllocDeploymentStatus) Copy() *AllocDeploymentStatus {
 	return c
 }
 
+// AllocArchiveStatus captures where the allocation directory was archived to
+// by the task group's archive stanza.
+type AllocArchiveStatus struct {
+	// Volume is the name of the client's host volume the archive was written
+	// to
+	Volume string
+
+	// Path is the path of the archive relative to the host volume
+	Path string
+
+	// Timestamp is the time at which the archive was written
+	Timestamp time.Time
+
+	// Error is set if archiving the allocation directory failed
+	Error string
+}
+
+func (a *AllocArchiveStatus) Copy() *AllocArchiveStatus {
+	if a == nil {
+		return nil
+	}
+
+	c := new(AllocArchiveStatus)
+	*c = *a
+	return c
+}
+
 const (
 	EvalStatusBlocked   = "blocked"
 	EvalStatusPending   = "pending"
@@ -8488,6 +9379,10 @@ type Evaluation struct {
 	// to constraints or lacking resources.
 	BlockedEval string
 
+	// CoalescedInto is the ID of the more recent evaluation of the job this
+	// evaluation was canceled in favor of while it was waiting in the broker.
+	CoalescedInto string
+
 	// FailedTGAllocs are task groups which have allocations that could not be
 	// made, but the metrics are persisted so that the user can use the feedback
 	// to determine the cause.
@@ -8501,6 +9396,15 @@ type Evaluation struct {
 	// evaluation.
 	QuotaLimitReached string
 
+	// UnmetDependencies is the set of IDs of the jobs the job depends on which
+	// hadn't reached their required status when the evaluation was blocked.
+	// The evaluation is unblocked when any of them changes.
+	UnmetDependencies []string
+
+	// UnmetDependencyReasons maps the ID of each unmet dependency to the
+	// reason it isn't met.
+	UnmetDependencyReasons map[string]string
+
 	// EscapedComputedClass marks whether the job has constraints that are not
 	// captured by computed node classes.
 	EscapedComputedClass bool
@@ -8509,6 +9413,10 @@ type Evaluation struct {
 	// during the evaluation. This should not be set during normal operations.
 	AnnotatePlan bool
 
+	// PlacementTrace triggers the scheduler to record why each node was
+	// rejected in the metrics of the placements.
+	PlacementTrace bool
+
 	// QueuedAllocations is the number of unplaced allocations at the time the
 	// evaluation was processed. The map is keyed by Task Group names.
 	QueuedAllocations map[string]int
@@ -8565,6 +9473,9 @@ func (e *Evaluation) Copy() *Evaluation {
 		ne.ClassEligibility = classes
 	}
 
+	ne.UnmetDependencies = helper.CopySliceString(e.UnmetDependencies)
+	ne.UnmetDependencyReasons = helper.CopyMapStringString(e.UnmetDependencyReasons)
+
 	// Copy FailedTGAllocs
 	if e.FailedTGAllocs != nil {
 		failedTGs := make(map[string]*AllocMetric, len(e.FailedTGAllocs))
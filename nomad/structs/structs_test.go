@@ -452,6 +452,32 @@ func TestJob_SystemJob_Validate(t *testing.T) {
 
 }
 
+func TestJob_DependsOn_Validate(t *testing.T) {
+	j := testJob()
+	j.DependsOn = []*JobDependency{
+		{JobID: "db"},
+		{JobID: "queue", Status: JobDependencyStatusHealthy},
+	}
+	j.Canonicalize()
+	require.NoError(t, j.Validate())
+	require.Equal(t, JobDependencyStatusRunning, j.DependsOn[0].Status)
+
+	j.DependsOn = []*JobDependency{
+		{JobID: j.ID, Status: JobDependencyStatusRunning},
+		{JobID: "db", Status: JobDependencyStatusRunning},
+		{JobID: "db", Status: JobDependencyStatusComplete},
+		{JobID: "", Status: "started"},
+	}
+	err := j.Validate()
+	require.Error(t, err)
+	mErr := err.(*multierror.Error)
+	require.Len(t, mErr.Errors, 3)
+	require.Contains(t, mErr.Errors[0].Error(), "Job can't depend on itself")
+	require.Contains(t, mErr.Errors[1].Error(), `Dependency on job "db" is defined more than once`)
+	require.Contains(t, mErr.Errors[2].Error(), "Missing dependency job ID")
+	require.Contains(t, mErr.Errors[2].Error(), `Invalid dependency status "started"`)
+}
+
 func TestJob_VaultPolicies(t *testing.T) {
 	j0 := &Job{}
 	e0 := make(map[string]map[string]*Vault, 0)
@@ -827,6 +853,21 @@ func TestTaskGroup_Validate(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 
+	tg = &TaskGroup{
+		Name:  "web",
+		Count: 1,
+		Tasks: []*Task{
+			{Name: "web", Leader: true},
+		},
+		Update: DefaultUpdateStrategy.Copy(),
+	}
+	tg.Update.Canary = 1
+	j.Type = JobTypeSystem
+	err = tg.Validate(j)
+	if !strings.Contains(err.Error(), "System jobs may not have canaries") {
+		t.Fatalf("err: %s", err)
+	}
+
 	tg = &TaskGroup{
 		Count: -1,
 		RestartPolicy: &RestartPolicy{
@@ -965,6 +1006,83 @@ func TestTaskGroup_Validate(t *testing.T) {
 
 }
 
+func TestAllocArchive_Validate(t *testing.T) {
+	volumes := map[string]*VolumeRequest{
+		"results": {
+			Name:   "results",
+			Type:   VolumeTypeHost,
+			Source: "results",
+		},
+		"config": {
+			Name:     "config",
+			Type:     VolumeTypeHost,
+			Source:   "config",
+			ReadOnly: true,
+		},
+	}
+
+	cases := []struct {
+		Name     string
+		Archive  *AllocArchive
+		Expected []string
+	}{
+		{
+			Name: "valid",
+			Archive: &AllocArchive{
+				Paths:  []string{"alloc/data", "web/local/results"},
+				Volume: "results",
+			},
+		},
+		{
+			Name:    "empty",
+			Archive: &AllocArchive{},
+			Expected: []string{
+				"at least one path",
+				"must have a volume",
+			},
+		},
+		{
+			Name: "escaping path",
+			Archive: &AllocArchive{
+				Paths:  []string{"../../etc"},
+				Volume: "results",
+			},
+			Expected: []string{`path "../../etc" escapes`},
+		},
+		{
+			Name: "undefined volume",
+			Archive: &AllocArchive{
+				Paths:  []string{"alloc/data"},
+				Volume: "foo",
+			},
+			Expected: []string{"undefined volume foo"},
+		},
+		{
+			Name: "read only volume",
+			Archive: &AllocArchive{
+				Paths:  []string{"alloc/data"},
+				Volume: "config",
+			},
+			Expected: []string{"read only volume config"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			err := c.Archive.Validate(volumes)
+			if len(c.Expected) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, e := range c.Expected {
+				require.Contains(t, err.Error(), e)
+			}
+		})
+	}
+}
+
 func TestTask_Validate(t *testing.T) {
 	task := &Task{}
 	ephemeralDisk := DefaultEphemeralDisk()
@@ -1021,6 +1139,38 @@ func TestTask_Validate(t *testing.T) {
 	}
 }
 
+func TestTask_MaxRunDuration(t *testing.T) {
+	job := testJob()
+	job.Type = JobTypeBatch
+	tg := job.TaskGroups[0]
+	maxRunDuration := time.Hour
+	tg.MaxRunDuration = &maxRunDuration
+	task := tg.Tasks[0]
+	task.MaxRunDuration = 0
+
+	// Tasks inherit the max run duration of their group
+	job.Canonicalize()
+	require.Equal(t, time.Hour, task.MaxRunDuration)
+	require.NoError(t, job.Validate())
+
+	// The task can override it
+	task.MaxRunDuration = time.Minute
+	job.Canonicalize()
+	require.Equal(t, time.Minute, task.MaxRunDuration)
+
+	ephemeralDisk := DefaultEphemeralDisk()
+	task.MaxRunDuration = -time.Minute
+	err := task.Validate(ephemeralDisk, JobTypeBatch, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "MaxRunDuration must be a positive value")
+
+	// Only batch jobs may set it
+	task.MaxRunDuration = time.Minute
+	err = task.Validate(ephemeralDisk, JobTypeService, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `MaxRunDuration is only supported for "batch" jobs`)
+}
+
 func TestTask_Validate_Services(t *testing.T) {
 	s1 := &Service{
 		Name:      "service-name",
@@ -1661,6 +1811,66 @@ func TestTask_Validate_LogConfig(t *testing.T) {
 	}
 }
 
+func TestLogConfig_Validate_Sinks(t *testing.T) {
+	l := DefaultLogConfig()
+	l.Sinks = []*LogSink{
+		{Type: LogSinkTypeSyslog, Address: "tcp://127.0.0.1:514"},
+		{Type: LogSinkTypeSyslog, Address: "unix:///dev/log", BufferLines: 100},
+		{Type: LogSinkTypeJSON, Address: "app.json"},
+	}
+	require.NoError(t, l.Validate())
+
+	l.Sinks = []*LogSink{
+		{Type: "kafka", Address: "tcp://127.0.0.1:9092"},
+		{Type: LogSinkTypeSyslog, Address: "http://127.0.0.1:514"},
+		{Type: LogSinkTypeSyslog, Address: "udp://"},
+		{Type: LogSinkTypeJSON, Address: "../app.json"},
+		{Type: LogSinkTypeJSON, Address: "stdout.json"},
+		{Type: LogSinkTypeJSON, Address: "app.json", BufferLines: -1},
+	}
+	err := l.Validate()
+	require.Error(t, err)
+
+	mErr := err.(*multierror.Error)
+	require.Len(t, mErr.Errors, 6)
+	require.Contains(t, mErr.Errors[0].Error(), `invalid type "kafka"`)
+	require.Contains(t, mErr.Errors[1].Error(), "must use the tcp, udp or unix scheme")
+	require.Contains(t, mErr.Errors[2].Error(), "missing a host")
+	require.Contains(t, mErr.Errors[3].Error(), "must be a file name")
+	require.Contains(t, mErr.Errors[4].Error(), "may not start with stdout or stderr")
+	require.Contains(t, mErr.Errors[5].Error(), "buffer lines may not be negative")
+}
+
+func TestLogConfig_Validate_Rotation(t *testing.T) {
+	l := DefaultLogConfig()
+	l.Compress = true
+	l.RotateInterval = time.Hour
+	l.MaxAge = 24 * time.Hour
+	require.NoError(t, l.Validate())
+
+	l.RotateInterval = 100 * time.Millisecond
+	l.MaxAge = -time.Hour
+	err := l.Validate()
+	require.Error(t, err)
+
+	mErr := err.(*multierror.Error)
+	require.Len(t, mErr.Errors, 2)
+	require.Contains(t, mErr.Errors[0].Error(), "minimum rotate interval is 1s")
+	require.Contains(t, mErr.Errors[1].Error(), "max age may not be negative")
+}
+
+func TestTask_Validate_LogConfig_JSONSinks(t *testing.T) {
+	task := &Task{
+		LogConfig: DefaultLogConfig(),
+	}
+	task.LogConfig.Sinks = []*LogSink{{Type: LogSinkTypeJSON, Address: "app.json"}}
+
+	// JSON sinks double the log storage of the task
+	err := task.Validate(&EphemeralDisk{SizeMB: 150}, JobTypeService, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "log storage (200 MB)")
+}
+
 func TestTask_Validate_Template(t *testing.T) {
 
 	bad := &Template{}
@@ -2033,6 +2243,75 @@ func TestUpdateStrategy_Validate(t *testing.T) {
 	}
 }
 
+func TestUpdateStrategy_Validate_RolloutSteps(t *testing.T) {
+	u := DefaultUpdateStrategy.Copy()
+	u.Canary = 1
+	u.CanaryPercent = 120
+	u.RolloutSteps = []int{50, 120, 40}
+
+	err := u.Validate()
+	require.Error(t, err)
+	mErr := err.(*multierror.Error)
+	require.Len(t, mErr.Errors, 4)
+	require.Contains(t, mErr.Errors[0].Error(), "Canary percent must be between 0 and 100")
+	require.Contains(t, mErr.Errors[1].Error(), "can not both be set")
+	require.Contains(t, mErr.Errors[2].Error(), "Rollout step 2 must be between 1 and 100")
+	require.Contains(t, mErr.Errors[3].Error(), "Rollout steps must be increasing")
+
+	u.Canary = 0
+	u.CanaryPercent = 10
+	u.RolloutSteps = []int{25, 50, 75}
+	require.NoError(t, u.Validate())
+	require.Equal(t, 1, u.DesiredCanaries(3))
+	require.Equal(t, 2, u.DesiredCanaries(11))
+}
+
+func TestUpdateStrategy_Validate_HealthGate(t *testing.T) {
+	u := DefaultUpdateStrategy.Copy()
+	u.HealthGate = &HealthGate{
+		URL:           "ftp://example.com",
+		Attempts:      0,
+		RetryInterval: -1,
+	}
+
+	err := u.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "URL must use http or https")
+	require.Contains(t, err.Error(), "Timeout must be greater than zero")
+	require.Contains(t, err.Error(), "Attempts must be at least one")
+	require.Contains(t, err.Error(), "Retry interval may not be less than zero")
+
+	u.HealthGate = &HealthGate{
+		URL:      "https://example.com/gate",
+		Timeout:  5 * time.Second,
+		Attempts: 1,
+	}
+	require.NoError(t, u.Validate())
+
+	c := u.Copy()
+	require.Equal(t, u.HealthGate, c.HealthGate)
+	c.HealthGate.URL = "http://other"
+	require.NotEqual(t, u.HealthGate.URL, c.HealthGate.URL)
+}
+
+func TestDeploymentState_StepTarget(t *testing.T) {
+	s := &DeploymentState{
+		DesiredTotal: 10,
+		RolloutSteps: []int{25, 50},
+	}
+
+	require.Equal(t, 3, s.StepTarget())
+	require.True(t, s.HasNextStep())
+
+	s.CurrentStep = 1
+	require.Equal(t, 5, s.StepTarget())
+	require.True(t, s.HasNextStep())
+
+	s.CurrentStep = 2
+	require.Equal(t, 10, s.StepTarget())
+	require.False(t, s.HasNextStep())
+}
+
 func TestResource_NetIndex(t *testing.T) {
 	r := &Resources{
 		Networks: []*NetworkResource{
@@ -4687,6 +4966,14 @@ func TestSpread_Validate(t *testing.T) {
 			err:  fmt.Errorf("Spread stanza must have a positive weight from 0 to 100"),
 			name: "Invalid weight",
 		},
+		{
+			spread: &Spread{
+				Attribute: "${node.host_volumes}",
+				Weight:    50,
+			},
+			err:  fmt.Errorf("Spread attribute ${node.host_volumes} is not supported; use ${node.host_volume.<name>}"),
+			name: "Host volume list",
+		},
 		{
 			spread: &Spread{
 				Attribute: "${node.datacenter}",
@@ -4827,3 +5114,34 @@ func TestNodeReservedNetworkResources_ParseReserved(t *testing.T) {
 		require.Equal(out, tc.Parsed)
 	}
 }
+
+func TestAllocMetric_TraceNode(t *testing.T) {
+	require := require.New(t)
+	node := &Node{ID: "foo", Name: "bar"}
+
+	// Nothing is recorded unless the trace is enabled
+	m := new(AllocMetric)
+	m.TraceNode(node, "constraint", "reason")
+	require.Empty(m.PlacementTrace)
+	require.Empty(m.LastTraceReason())
+
+	m.EnablePlacementTrace()
+	require.True(m.TracingPlacement())
+	for i := 0; i < MaxPlacementTraceEntries+2; i++ {
+		m.TraceNode(node, "constraint", fmt.Sprintf("reason %d", i))
+	}
+	require.Len(m.PlacementTrace, MaxPlacementTraceEntries)
+	require.Equal(2, m.PlacementTraceDropped)
+	require.Equal(fmt.Sprintf("reason %d", MaxPlacementTraceEntries+1), m.LastTraceReason())
+	require.Equal(&NodePlacementTrace{
+		NodeID:   "foo",
+		NodeName: "bar",
+		Checker:  "constraint",
+		Reason:   "reason 0",
+	}, m.PlacementTrace[0])
+
+	// Copies don't share the trace entries
+	c := m.Copy()
+	c.PlacementTrace[0].Reason = "changed"
+	require.Equal("reason 0", m.PlacementTrace[0].Reason)
+}
@@ -80,6 +80,9 @@ type EvalContext struct {
 	logger      log.Logger
 	metrics     *structs.AllocMetric
 	eligibility *EvalEligibility
+
+	// placementTrace enables the placement trace of the metrics
+	placementTrace bool
 }
 
 // NewEvalContext constructs a new EvalContext
@@ -115,6 +118,16 @@ func (e *EvalContext) SetState(s State) {
 
 func (e *EvalContext) Reset() {
 	e.metrics = new(structs.AllocMetric)
+	if e.placementTrace {
+		e.metrics.EnablePlacementTrace()
+	}
+}
+
+// EnablePlacementTrace records why each node was rejected in the metrics of
+// the placements.
+func (e *EvalContext) EnablePlacementTrace() {
+	e.placementTrace = true
+	e.metrics.EnablePlacementTrace()
 }
 
 func (e *EvalContext) ProposedAllocs(nodeID string) ([]*structs.Allocation, error) {
@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// blockedEvalUnmetDependencies is the description used for blocked evals
+	// that are waiting on the dependencies of their job.
+	blockedEvalUnmetDependencies = "created to wait for job dependencies"
+
+	// dependencyReasonMissing is the reason a dependency isn't met when its
+	// job doesn't exist.
+	dependencyReasonMissing = "job not found"
+
+	// dependencyReasonStopped is the reason a dependency isn't met when its
+	// job is stopped.
+	dependencyReasonStopped = "job is stopped"
+
+	// dependencyReasonStatus is the reason a dependency isn't met when its job
+	// hasn't reached the required status yet.
+	dependencyReasonStatus = "job is not %s yet"
+)
+
+// unmetJobDependencies returns the IDs of the jobs the job depends on which
+// haven't reached their required status, mapped to the reason. Dependencies
+// only gate the placement of a job which isn't running yet, so nothing is
+// returned if the job has non terminal allocations.
+func unmetJobDependencies(state State, job *structs.Job) (map[string]string, error) {
+	if job == nil || job.Stopped() || len(job.DependsOn) == 0 {
+		return nil, nil
+	}
+
+	ws := memdb.NewWatchSet()
+	allocs, err := state.AllocsByJob(ws, job.Namespace, job.ID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocs for job %q: %v", job.ID, err)
+	}
+	for _, alloc := range allocs {
+		if !alloc.TerminalStatus() {
+			return nil, nil
+		}
+	}
+
+	var unmet map[string]string
+	for _, dep := range job.DependsOn {
+		met, reason, err := jobDependencyMet(state, job.Namespace, dep)
+		if err != nil {
+			return nil, err
+		}
+		if !met {
+			if unmet == nil {
+				unmet = make(map[string]string, len(job.DependsOn))
+			}
+			unmet[dep.JobID] = reason
+		}
+	}
+	return unmet, nil
+}
+
+// jobDependencyMet returns whether the job of the dependency has reached the
+// required status. If it hasn't, the reason is returned.
+func jobDependencyMet(state State, namespace string, dep *structs.JobDependency) (bool, string, error) {
+	ws := memdb.NewWatchSet()
+	job, err := state.JobByID(ws, namespace, dep.JobID)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get job %q: %v", dep.JobID, err)
+	}
+	if job == nil {
+		return false, dependencyReasonMissing, nil
+	}
+	if job.Stopped() {
+		return false, dependencyReasonStopped, nil
+	}
+
+	allocs, err := state.AllocsByJob(ws, namespace, job.ID, false)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get allocs for job %q: %v", job.ID, err)
+	}
+
+	var met bool
+	switch dep.Status {
+	case structs.JobDependencyStatusComplete:
+		met = job.Status == structs.JobStatusDead &&
+			allGroupsHaveAlloc(job, allocs, structs.AllocClientStatusComplete)
+
+	case structs.JobDependencyStatusHealthy:
+		if !jobUsesDeployments(job) {
+			met = allGroupsHaveAlloc(job, allocs, structs.AllocClientStatusRunning)
+			break
+		}
+
+		d, err := state.LatestDeploymentByJobID(ws, namespace, job.ID)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get deployment for job %q: %v", job.ID, err)
+		}
+		met = d != nil && d.JobCreateIndex == job.CreateIndex &&
+			d.JobVersion == job.Version && d.Status == structs.DeploymentStatusSuccessful
+
+	default:
+		met = allGroupsHaveAlloc(job, allocs, structs.AllocClientStatusRunning)
+	}
+
+	if !met {
+		return false, fmt.Sprintf(dependencyReasonStatus, dep.Status), nil
+	}
+	return true, "", nil
+}
+
+// allGroupsHaveAlloc returns whether every task group of the job which should
+// run allocations has an allocation with the given client status.
+func allGroupsHaveAlloc(job *structs.Job, allocs []*structs.Allocation, clientStatus string) bool {
+	groups := make(map[string]struct{}, len(job.TaskGroups))
+	for _, alloc := range allocs {
+		if alloc.ClientStatus == clientStatus {
+			groups[alloc.TaskGroup] = struct{}{}
+		}
+	}
+
+	for _, tg := range job.TaskGroups {
+		if tg.Count == 0 {
+			continue
+		}
+		if _, ok := groups[tg.Name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// jobUsesDeployments returns whether the job creates deployments when it is
+// updated.
+func jobUsesDeployments(job *structs.Job) bool {
+	if job.Type == structs.JobTypeBatch {
+		return false
+	}
+	for _, tg := range job.TaskGroups {
+		if !tg.Update.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// blockOnUnmetDependencies blocks the evaluation if its job has unmet
+// dependencies and returns whether it was blocked.
+func blockOnUnmetDependencies(logger log.Logger, state State, planner Planner, eval *structs.Evaluation) (bool, error) {
+	job, err := state.JobByID(memdb.NewWatchSet(), eval.Namespace, eval.JobID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get job %q: %v", eval.JobID, err)
+	}
+
+	unmet, err := unmetJobDependencies(state, job)
+	if err != nil || len(unmet) == 0 {
+		return false, err
+	}
+	return true, blockOnDependencies(logger, planner, eval, unmet)
+}
+
+// blockOnDependencies blocks the evaluation until any of the unmet dependencies
+// of its job changes. An evaluation which is already blocked is reblocked,
+// otherwise a blocked evaluation is created and the evaluation is completed.
+func blockOnDependencies(logger log.Logger, planner Planner, eval *structs.Evaluation, unmet map[string]string) error {
+	ids := make([]string, 0, len(unmet))
+	for id := range unmet {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	if eval.Status == structs.EvalStatusBlocked {
+		newEval := eval.Copy()
+		newEval.UnmetDependencies = ids
+		newEval.UnmetDependencyReasons = unmet
+		return planner.ReblockEval(newEval)
+	}
+
+	blocked := eval.CreateBlockedEval(nil, false, "")
+	blocked.UnmetDependencies = ids
+	blocked.UnmetDependencyReasons = unmet
+	blocked.StatusDescription = blockedEvalUnmetDependencies
+	if err := planner.CreateEval(blocked); err != nil {
+		return err
+	}
+	logger.Debug("job dependencies not met, blocked eval created", "blocked_eval_id", blocked.ID,
+		"unmet_dependencies", strings.Join(ids, ","))
+
+	return setStatus(logger, planner, eval, nil, blocked, nil, structs.EvalStatusComplete, "", nil, "")
+}
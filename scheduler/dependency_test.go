@@ -0,0 +1,247 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmetJobDependencies(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		status   string
+		setup    func(h *Harness, dep *structs.Job)
+		expected map[string]string
+	}{
+		{
+			name:     "missing job",
+			status:   structs.JobDependencyStatusRunning,
+			expected: map[string]string{"dep": dependencyReasonMissing},
+		},
+		{
+			name:   "pending",
+			status: structs.JobDependencyStatusRunning,
+			setup: func(h *Harness, dep *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+			},
+			expected: map[string]string{"dep": "job is not running yet"},
+		},
+		{
+			name:   "stopped",
+			status: structs.JobDependencyStatusRunning,
+			setup: func(h *Harness, dep *structs.Job) {
+				dep.Stop = true
+				require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+				alloc := mock.Alloc()
+				alloc.Job = dep
+				alloc.JobID = dep.ID
+				alloc.ClientStatus = structs.AllocClientStatusRunning
+				require.NoError(t, h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+			},
+			expected: map[string]string{"dep": dependencyReasonStopped},
+		},
+		{
+			name:   "running",
+			status: structs.JobDependencyStatusRunning,
+			setup: func(h *Harness, dep *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+				alloc := mock.Alloc()
+				alloc.Job = dep
+				alloc.JobID = dep.ID
+				alloc.ClientStatus = structs.AllocClientStatusRunning
+				require.NoError(t, h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+			},
+		},
+		{
+			name:   "healthy without deployments",
+			status: structs.JobDependencyStatusHealthy,
+			setup: func(h *Harness, dep *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+				alloc := mock.Alloc()
+				alloc.Job = dep
+				alloc.JobID = dep.ID
+				alloc.ClientStatus = structs.AllocClientStatusRunning
+				require.NoError(t, h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+			},
+		},
+		{
+			name:   "healthy with running deployment",
+			status: structs.JobDependencyStatusHealthy,
+			setup: func(h *Harness, dep *structs.Job) {
+				dep.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+				require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+				d := mock.Deployment()
+				d.JobID = dep.ID
+				d.JobCreateIndex = dep.CreateIndex
+				d.JobVersion = dep.Version
+				require.NoError(t, h.State.UpsertDeployment(h.NextIndex(), d))
+			},
+			expected: map[string]string{"dep": "job is not healthy yet"},
+		},
+		{
+			name:   "healthy with successful deployment",
+			status: structs.JobDependencyStatusHealthy,
+			setup: func(h *Harness, dep *structs.Job) {
+				dep.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+				require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+				d := mock.Deployment()
+				d.JobID = dep.ID
+				d.JobCreateIndex = dep.CreateIndex
+				d.JobVersion = dep.Version
+				d.Status = structs.DeploymentStatusSuccessful
+				require.NoError(t, h.State.UpsertDeployment(h.NextIndex(), d))
+			},
+		},
+		{
+			name:   "complete while running",
+			status: structs.JobDependencyStatusComplete,
+			setup: func(h *Harness, dep *structs.Job) {
+				dep.Type = structs.JobTypeBatch
+				require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+				alloc := mock.Alloc()
+				alloc.Job = dep
+				alloc.JobID = dep.ID
+				alloc.ClientStatus = structs.AllocClientStatusRunning
+				require.NoError(t, h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+			},
+			expected: map[string]string{"dep": "job is not complete yet"},
+		},
+		{
+			name:   "complete",
+			status: structs.JobDependencyStatusComplete,
+			setup: func(h *Harness, dep *structs.Job) {
+				dep.Type = structs.JobTypeBatch
+				require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+				alloc := mock.Alloc()
+				alloc.Job = dep
+				alloc.JobID = dep.ID
+				alloc.DesiredStatus = structs.AllocDesiredStatusRun
+				alloc.ClientStatus = structs.AllocClientStatusComplete
+				require.NoError(t, h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			dep := mock.Job()
+			dep.ID = "dep"
+			if tc.setup != nil {
+				tc.setup(h, dep)
+			}
+
+			job := mock.Job()
+			job.DependsOn = []*structs.JobDependency{{JobID: "dep", Status: tc.status}}
+
+			unmet, err := unmetJobDependencies(h.State, job)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, unmet)
+		})
+	}
+}
+
+func TestUnmetJobDependencies_Running(t *testing.T) {
+	t.Parallel()
+	h := NewHarness(t)
+
+	// A job which already has allocations isn't gated by its dependencies
+	job := mock.Job()
+	job.DependsOn = []*structs.JobDependency{{JobID: "dep", Status: structs.JobDependencyStatusRunning}}
+	require.NoError(t, h.State.UpsertJob(h.NextIndex(), job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	require.NoError(t, h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+
+	unmet, err := unmetJobDependencies(h.State, job)
+	require.NoError(t, err)
+	require.Empty(t, unmet)
+}
+
+func TestServiceSched_JobRegister_UnmetDependencies(t *testing.T) {
+	h := NewHarness(t)
+
+	// Create some nodes
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		require.NoError(t, h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Create a job depending on a job which doesn't run
+	dep := mock.Job()
+	require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+
+	job := mock.Job()
+	job.DependsOn = []*structs.JobDependency{{JobID: dep.ID, Status: structs.JobDependencyStatusRunning}}
+	require.NoError(t, h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Ensure no plan
+	require.Empty(t, h.Plans)
+
+	// Ensure a blocked eval waiting on the dependency was created
+	require.Len(t, h.CreateEvals, 1)
+	blocked := h.CreateEvals[0]
+	require.Equal(t, structs.EvalStatusBlocked, blocked.Status)
+	require.Equal(t, []string{dep.ID}, blocked.UnmetDependencies)
+	require.Equal(t, map[string]string{dep.ID: "job is not running yet"}, blocked.UnmetDependencyReasons)
+	require.Equal(t, blockedEvalUnmetDependencies, blocked.StatusDescription)
+
+	require.Len(t, h.Evals, 1)
+	require.Equal(t, blocked.ID, h.Evals[0].BlockedEval)
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+
+	// Reprocessing the blocked eval while the dependency still isn't running
+	// reblocks it
+	require.NoError(t, h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{blocked}))
+	h1 := NewHarnessWithState(t, h.State)
+	require.NoError(t, h1.Process(NewServiceScheduler, blocked))
+	require.Empty(t, h1.Plans)
+	require.Empty(t, h1.CreateEvals)
+	require.Len(t, h1.ReblockEvals, 1)
+	require.Equal(t, []string{dep.ID}, h1.ReblockEvals[0].UnmetDependencies)
+
+	// Stopping the dependency is reported as the reason it isn't met
+	stopped := dep.Copy()
+	stopped.Stop = true
+	require.NoError(t, h.State.UpsertJob(h.NextIndex(), stopped))
+	h3 := NewHarnessWithState(t, h.State)
+	require.NoError(t, h3.Process(NewServiceScheduler, blocked))
+	require.Empty(t, h3.Plans)
+	require.Len(t, h3.ReblockEvals, 1)
+	require.Equal(t, map[string]string{dep.ID: dependencyReasonStopped}, h3.ReblockEvals[0].UnmetDependencyReasons)
+	require.NoError(t, h.State.UpsertJob(h.NextIndex(), dep))
+
+	// Once the dependency is running the job is placed
+	alloc := mock.Alloc()
+	alloc.Job = dep
+	alloc.JobID = dep.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(t, h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+
+	h2 := NewHarnessWithState(t, h.State)
+	require.NoError(t, h2.Process(NewServiceScheduler, blocked))
+	require.Len(t, h2.Plans, 1)
+	require.Empty(t, h2.ReblockEvals)
+}
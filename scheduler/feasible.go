@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -136,6 +137,7 @@ func (h *HostVolumeChecker) Feasible(candidate *structs.Node) bool {
 	}
 
 	h.ctx.Metrics().FilterNode(candidate, "missing compatible host volumes")
+	h.ctx.Metrics().TraceNode(candidate, "host-volumes", "missing compatible host volumes")
 	return false
 }
 
@@ -202,6 +204,7 @@ func (c *DriverChecker) Feasible(option *structs.Node) bool {
 		return true
 	}
 	c.ctx.Metrics().FilterNode(option, "missing drivers")
+	c.ctx.Metrics().TraceNode(option, "drivers", "missing drivers")
 	return false
 }
 
@@ -306,6 +309,8 @@ func (iter *DistinctHostsIterator) Next() *structs.Node {
 		// Check if the host constraints are satisfied
 		if !iter.satisfiesDistinctHosts(option) {
 			iter.ctx.Metrics().FilterNode(option, structs.ConstraintDistinctHosts)
+			iter.ctx.Metrics().TraceNode(option, "distinct",
+				fmt.Sprintf("%s: an allocation of the job is already placed on the node", structs.ConstraintDistinctHosts))
 			continue
 		}
 
@@ -431,6 +436,7 @@ func (iter *DistinctPropertyIterator) satisfiesProperties(option *structs.Node,
 	for _, ps := range set {
 		if satisfies, reason := ps.SatisfiesDistinctProperties(option, iter.tg.Name); !satisfies {
 			iter.ctx.Metrics().FilterNode(option, reason)
+			iter.ctx.Metrics().TraceNode(option, "distinct", reason)
 			return false
 		}
 	}
@@ -477,6 +483,9 @@ func (c *ConstraintChecker) Feasible(option *structs.Node) bool {
 	for _, constraint := range c.constraints {
 		if !c.meetsConstraint(constraint, option) {
 			c.ctx.Metrics().FilterNode(option, constraint.String())
+			if c.ctx.Metrics().TracingPlacement() {
+				c.ctx.Metrics().TraceNode(option, "constraint", constraintTraceReason(constraint, option))
+			}
 			return false
 		}
 	}
@@ -493,6 +502,20 @@ func (c *ConstraintChecker) meetsConstraint(constraint *structs.Constraint, opti
 	return checkConstraint(c.ctx, constraint.Operand, lVal, rVal, lOk, rOk)
 }
 
+// constraintTraceReason describes why the node doesn't meet the constraint, for
+// the placement trace.
+func constraintTraceReason(constraint *structs.Constraint, option *structs.Node) string {
+	if !strings.HasPrefix(constraint.LTarget, "${") {
+		return constraint.String()
+	}
+
+	lVal, ok := resolveTarget(constraint.LTarget, option)
+	if !ok {
+		return fmt.Sprintf("%s: %s is not set on the node", constraint.String(), constraint.LTarget)
+	}
+	return fmt.Sprintf("%s: %s is %q on the node", constraint.String(), constraint.LTarget, fmt.Sprint(lVal))
+}
+
 // resolveTarget is used to resolve the LTarget and RTarget of a Constraint.
 func resolveTarget(target string, node *structs.Node) (interface{}, bool) {
 	// If no prefix, this must be a literal value
@@ -514,6 +537,25 @@ func resolveTarget(target string, node *structs.Node) (interface{}, bool) {
 	case "${node.class}" == target:
 		return node.NodeClass, true
 
+	case "${node.host_volumes}" == target:
+		if len(node.HostVolumes) == 0 {
+			return nil, false
+		}
+		names := make([]string, 0, len(node.HostVolumes))
+		for name := range node.HostVolumes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ","), true
+
+	case strings.HasPrefix(target, "${node.host_volume."):
+		name := strings.TrimSuffix(strings.TrimPrefix(target, "${node.host_volume."), "}")
+		vol, ok := node.HostVolumes[name]
+		if !ok || vol == nil {
+			return nil, false
+		}
+		return vol.Path, true
+
 	case strings.HasPrefix(target, "${attr."):
 		attr := strings.TrimSuffix(strings.TrimPrefix(target, "${attr."), "}")
 		val, ok := node.Attributes[attr]
@@ -781,6 +823,11 @@ type FeasibilityWrapper struct {
 	jobCheckers []FeasibilityChecker
 	tgCheckers  []FeasibilityChecker
 	tg          string
+
+	// ineligibleReasons stores the reason the computed classes were marked
+	// ineligible, for the placement trace. Job level classes are keyed by
+	// class and task group level classes by task group and class.
+	ineligibleReasons map[string]string
 }
 
 // NewFeasibilityWrapper returns a FeasibleIterator based on the passed source
@@ -823,6 +870,7 @@ OUTER:
 		case EvalComputedClassIneligible:
 			// Fast path the ineligible case
 			metrics.FilterNode(option, "computed class ineligible")
+			w.traceIneligible(option, option.ComputedClass)
 			continue
 		case EvalComputedClassEscaped:
 			jobEscaped = true
@@ -838,6 +886,7 @@ OUTER:
 				// failed a job check.
 				if !jobEscaped {
 					evalElig.SetJobEligibility(false, option.ComputedClass)
+					w.storeIneligibleReason(option.ComputedClass)
 				}
 				continue OUTER
 			}
@@ -855,6 +904,7 @@ OUTER:
 		case EvalComputedClassIneligible:
 			// Fast path the ineligible case
 			metrics.FilterNode(option, "computed class ineligible")
+			w.traceIneligible(option, w.tg+"/"+option.ComputedClass)
 			continue
 		case EvalComputedClassEligible:
 			// Fast path the eligible case
@@ -873,6 +923,7 @@ OUTER:
 				// since it failed a check.
 				if !tgEscaped {
 					evalElig.SetTaskGroupEligibility(false, w.tg, option.ComputedClass)
+					w.storeIneligibleReason(w.tg + "/" + option.ComputedClass)
 				}
 				continue OUTER
 			}
@@ -888,6 +939,34 @@ OUTER:
 	}
 }
 
+// storeIneligibleReason stores the reason of the last rejected node as the
+// reason the computed class is ineligible, for the placement trace.
+func (w *FeasibilityWrapper) storeIneligibleReason(key string) {
+	metrics := w.ctx.Metrics()
+	if !metrics.TracingPlacement() {
+		return
+	}
+	if w.ineligibleReasons == nil {
+		w.ineligibleReasons = make(map[string]string)
+	}
+	w.ineligibleReasons[key] = metrics.LastTraceReason()
+}
+
+// traceIneligible records a node skipped because its computed class was
+// marked ineligible in the placement trace.
+func (w *FeasibilityWrapper) traceIneligible(option *structs.Node, key string) {
+	metrics := w.ctx.Metrics()
+	if !metrics.TracingPlacement() {
+		return
+	}
+
+	reason := "computed class ineligible"
+	if r, ok := w.ineligibleReasons[key]; ok && r != "" {
+		reason = fmt.Sprintf("%s: %s", reason, r)
+	}
+	metrics.TraceNode(option, "feasibility", reason)
+}
+
 // DeviceChecker is a FeasibilityChecker which returns whether a node has the
 // devices necessary to scheduler a task group.
 type DeviceChecker struct {
@@ -916,30 +995,34 @@ func (c *DeviceChecker) SetTaskGroup(tg *structs.TaskGroup) {
 }
 
 func (c *DeviceChecker) Feasible(option *structs.Node) bool {
-	if c.hasDevices(option) {
+	ok, reason := c.hasDevices(option)
+	if ok {
 		return true
 	}
 
 	c.ctx.Metrics().FilterNode(option, "missing devices")
+	c.ctx.Metrics().TraceNode(option, "devices", reason)
 	return false
 }
 
-func (c *DeviceChecker) hasDevices(option *structs.Node) bool {
+// hasDevices returns whether the node has the devices required by the task
+// group, and if not the reason why.
+func (c *DeviceChecker) hasDevices(option *structs.Node) (bool, string) {
 	if !c.requiresDevices {
-		return true
+		return true, ""
 	}
 
 	// COMPAT(0.11): Remove in 0.11
 	// The node does not have the new resources object so it can not have any
 	// devices
 	if option.NodeResources == nil {
-		return false
+		return false, "node has no devices"
 	}
 
 	// Check if the node has any devices
 	nodeDevs := option.NodeResources.Devices
 	if len(nodeDevs) == 0 {
-		return false
+		return false, "node has no devices"
 	}
 
 	// Create a mapping of node devices to the remaining count
@@ -986,11 +1069,11 @@ OUTER:
 		}
 
 		// We couldn't match the request for the device
-		return false
+		return false, fmt.Sprintf("no device matching %q with %d healthy instances available", req.Name, desiredCount)
 	}
 
 	// Only satisfied if there are no more devices to place
-	return true
+	return true, ""
 }
 
 // nodeDeviceMatches checks if the device matches the request and its
@@ -455,6 +455,46 @@ func TestConstraintChecker(t *testing.T) {
 	}
 }
 
+func TestConstraintChecker_PlacementTrace(t *testing.T) {
+	_, ctx := testContext(t)
+	ctx.EnablePlacementTrace()
+	require := require.New(t)
+
+	node := mock.Node()
+	node.Attributes["kernel.name"] = "freebsd"
+	constraints := []*structs.Constraint{
+		{
+			Operand: "is",
+			LTarget: "${attr.kernel.name}",
+			RTarget: "linux",
+		},
+		{
+			Operand: "is_set",
+			LTarget: "${meta.rack}",
+		},
+	}
+	checker := NewConstraintChecker(ctx, constraints)
+	require.False(checker.Feasible(node))
+
+	// Meet the first constraint so the second one fails
+	node2 := mock.Node()
+	require.False(checker.Feasible(node2))
+
+	trace := ctx.Metrics().PlacementTrace
+	require.Len(trace, 2)
+	require.Equal(node.ID, trace[0].NodeID)
+	require.Equal("constraint", trace[0].Checker)
+	require.Equal(`${attr.kernel.name} is linux: ${attr.kernel.name} is "freebsd" on the node`, trace[0].Reason)
+	require.Equal(node2.ID, trace[1].NodeID)
+	require.Equal("${meta.rack} is_set : ${meta.rack} is not set on the node", trace[1].Reason)
+
+	// Nothing is recorded once the context is reset without the trace
+	ctx2 := NewEvalContext(ctx.State(), ctx.Plan(), ctx.Logger())
+	checker = NewConstraintChecker(ctx2, constraints)
+	require.False(checker.Feasible(node))
+	require.Empty(ctx2.Metrics().PlacementTrace)
+}
+
 func TestResolveConstraintTarget(t *testing.T) {
 	type tcase struct {
 		target string
@@ -463,6 +503,10 @@ func TestResolveConstraintTarget(t *testing.T) {
 		result bool
 	}
 	node := mock.Node()
+	node.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"fast": {Name: "fast", Path: "/mnt/fast"},
+		"data": {Name: "data", Path: "/mnt/data"},
+	}
 	cases := []tcase{
 		{
 			target: "${node.unique.id}",
@@ -488,6 +532,28 @@ func TestResolveConstraintTarget(t *testing.T) {
 			val:    node.NodeClass,
 			result: true,
 		},
+		{
+			target: "${node.host_volumes}",
+			node:   node,
+			val:    "data,fast",
+			result: true,
+		},
+		{
+			target: "${node.host_volumes}",
+			node:   mock.Node(),
+			result: false,
+		},
+		{
+			target: "${node.host_volume.fast}",
+			node:   node,
+			val:    "/mnt/fast",
+			result: true,
+		},
+		{
+			target: "${node.host_volume.slow}",
+			node:   node,
+			result: false,
+		},
 		{
 			target: "${node.foo}",
 			node:   node,
@@ -1872,6 +1938,36 @@ func TestFeasibilityWrapper_JobIneligible(t *testing.T) {
 	}
 }
 
+func TestFeasibilityWrapper_PlacementTrace(t *testing.T) {
+	_, ctx := testContext(t)
+	ctx.EnablePlacementTrace()
+	require := require.New(t)
+
+	// Both nodes share the same computed class so the second one is skipped
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	for _, node := range nodes {
+		node.Attributes["kernel.name"] = "freebsd"
+		require.NoError(node.ComputeClass())
+	}
+	static := NewStaticIterator(ctx, nodes)
+	checker := NewConstraintChecker(ctx, []*structs.Constraint{
+		{
+			Operand: "=",
+			LTarget: "${attr.kernel.name}",
+			RTarget: "linux",
+		},
+	})
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{checker}, nil)
+
+	require.Nil(collectFeasible(wrapper))
+
+	trace := ctx.Metrics().PlacementTrace
+	require.Len(trace, 2)
+	require.Equal("constraint", trace[0].Checker)
+	require.Equal("feasibility", trace[1].Checker)
+	require.Equal("computed class ineligible: "+trace[0].Reason, trace[1].Reason)
+}
+
 func TestFeasibilityWrapper_JobEscapes(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*structs.Node{mock.Node()}
@@ -2314,6 +2410,58 @@ func TestDeviceChecker(t *testing.T) {
 	}
 }
 
+func TestDeviceChecker_PlacementTrace(t *testing.T) {
+	_, ctx := testContext(t)
+	ctx.EnablePlacementTrace()
+	require := require.New(t)
+
+	tg := &structs.TaskGroup{
+		Name: "example",
+		Tasks: []*structs.Task{
+			{
+				Resources: &structs.Resources{
+					Devices: []*structs.RequestedDevice{
+						{
+							Name:  "nvidia/gpu",
+							Count: 2,
+						},
+					},
+				},
+			},
+		},
+	}
+	checker := NewDeviceChecker(ctx)
+	checker.SetTaskGroup(tg)
+
+	// A node without devices
+	noDevices := mock.Node()
+	require.False(checker.Feasible(noDevices))
+
+	// A node without enough healthy instances
+	oneGPU := mock.Node()
+	oneGPU.NodeResources.Devices = []*structs.NodeDeviceResource{
+		{
+			Vendor: "nvidia",
+			Type:   "gpu",
+			Name:   "1080ti",
+			Instances: []*structs.NodeDevice{
+				{
+					ID:      uuid.Generate(),
+					Healthy: true,
+				},
+			},
+		},
+	}
+	require.False(checker.Feasible(oneGPU))
+
+	trace := ctx.Metrics().PlacementTrace
+	require.Len(trace, 2)
+	require.Equal("devices", trace[0].Checker)
+	require.Equal("node has no devices", trace[0].Reason)
+	require.Equal(oneGPU.ID, trace[1].NodeID)
+	require.Equal(`no device matching "nvidia/gpu" with 2 healthy instances available`, trace[1].Reason)
+}
+
 func TestCheckAttributeConstraint(t *testing.T) {
 	type tcase struct {
 		op         string
@@ -143,6 +143,11 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) error {
 			s.deployment.GetID())
 	}
 
+	// Wait for the dependencies of the job before placing its allocations
+	if blocked, err := blockOnUnmetDependencies(s.logger, s.state, s.planner, s.eval); err != nil || blocked {
+		return err
+	}
+
 	// Retry up to the maxScheduleAttempts and reset if progress is made.
 	progress := func() bool { return progressMade(s.planResult) }
 	limit := maxServiceScheduleAttempts
@@ -175,6 +180,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) error {
 		newEval.EscapedComputedClass = e.HasEscaped()
 		newEval.ClassEligibility = e.GetClasses()
 		newEval.QuotaLimitReached = e.QuotaLimitReached()
+		newEval.UnmetDependencies = nil
+		newEval.UnmetDependencyReasons = nil
 		return s.planner.ReblockEval(newEval)
 	}
 
@@ -242,6 +249,9 @@ func (s *GenericScheduler) process() (bool, error) {
 
 	// Create an evaluation context
 	s.ctx = NewEvalContext(s.state, s.plan, s.logger)
+	if placementTraceEnabled(s.state, s.eval) {
+		s.ctx.EnablePlacementTrace()
+	}
 
 	// Construct the placement stack
 	s.stack = NewGenericStack(s.batch, s.ctx)
@@ -483,6 +493,9 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 
 			// Set fields based on if we found an allocation option
 			if option != nil {
+				// The placement trace only explains failed placements
+				s.ctx.Metrics().ClearPlacementTrace()
+
 				resources := &structs.AllocatedResources{
 					Tasks: option.TaskResources,
 					Shared: structs.AllocatedSharedResources{
@@ -981,6 +981,111 @@ func TestServiceSched_JobRegister_AllocFail(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_PlacementTrace(t *testing.T) {
+	cases := []struct {
+		name      string
+		evalTrace bool
+		config    bool
+	}{
+		{name: "disabled"},
+		{name: "eval", evalTrace: true},
+		{name: "scheduler config", config: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+			h := NewHarness(t)
+
+			if tc.config {
+				require.NoError(h.State.SchedulerSetConfig(h.NextIndex(), &structs.SchedulerConfiguration{
+					PlacementTraceEnabled: true,
+				}))
+			}
+
+			// Create a node that doesn't meet the job constraint
+			node := mock.Node()
+			node.Attributes["kernel.name"] = "freebsd"
+			require.NoError(node.ComputeClass())
+			require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+
+			job := mock.Job()
+			require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+			eval := &structs.Evaluation{
+				Namespace:      structs.DefaultNamespace,
+				ID:             uuid.Generate(),
+				Priority:       job.Priority,
+				TriggeredBy:    structs.EvalTriggerJobRegister,
+				JobID:          job.ID,
+				Status:         structs.EvalStatusPending,
+				PlacementTrace: tc.evalTrace,
+			}
+			require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+			require.NoError(h.Process(NewServiceScheduler, eval))
+			require.Len(h.Evals, 1)
+
+			metrics := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+			require.NotNil(metrics)
+			if !tc.evalTrace && !tc.config {
+				require.Empty(metrics.PlacementTrace)
+				return
+			}
+
+			require.Len(metrics.PlacementTrace, 1)
+			trace := metrics.PlacementTrace[0]
+			require.Equal(node.ID, trace.NodeID)
+			require.Equal("constraint", trace.Checker)
+			require.Contains(trace.Reason, `is "freebsd" on the node`)
+		})
+	}
+}
+
+func TestServiceSched_JobRegister_PlacementTrace_Placed(t *testing.T) {
+	require := require.New(t)
+	h := NewHarness(t)
+
+	require.NoError(h.State.SchedulerSetConfig(h.NextIndex(), &structs.SchedulerConfiguration{
+		PlacementTraceEnabled: true,
+	}))
+
+	// Create nodes that don't meet the job constraint and one that does
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		node.Attributes["kernel.name"] = "freebsd"
+		require.NoError(node.ComputeClass())
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+	require.NoError(h.State.UpsertNode(h.NextIndex(), mock.Node()))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	require.NoError(h.Process(NewServiceScheduler, eval))
+	require.Len(h.Plans, 1)
+
+	// The placed alloc doesn't carry the trace of the rejected node
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	require.Len(planned, 1)
+	require.Empty(planned[0].Metrics.PlacementTrace)
+	require.Zero(planned[0].Metrics.PlacementTraceDropped)
+}
+
 func TestServiceSched_JobRegister_CreateBlockedEval(t *testing.T) {
 	h := NewHarness(t)
 
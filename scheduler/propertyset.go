@@ -2,7 +2,9 @@ package scheduler
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
@@ -236,6 +238,26 @@ func (p *propertySet) UsedCount(option *structs.Node, tg string) (string, string
 
 	// Get the nodes property value
 	nValue, ok := getProperty(option, p.targetAttribute)
+	return p.usedCount(nValue, ok)
+}
+
+// RankedUsedCount is like UsedCount but resolves device targets from the
+// devices assigned to the ranked option. It is used when evaluating spread
+// stanzas, after the devices have been assigned by bin packing.
+func (p *propertySet) RankedUsedCount(option *RankedNode, tg string) (string, string, uint64) {
+	// Check if there was an error building
+	if p.errorBuilding != nil {
+		return "", p.errorBuilding.Error(), 0
+	}
+
+	// Get the options property value
+	nValue, ok := getRankedProperty(option, p.targetAttribute)
+	return p.usedCount(nValue, ok)
+}
+
+// usedCount returns the number of times the resolved property value is used
+// along with an error message if it couldn't be resolved.
+func (p *propertySet) usedCount(nValue string, ok bool) (string, string, uint64) {
 	if !ok {
 		return nValue, fmt.Sprintf("missing property %q", p.targetAttribute), 0
 	}
@@ -327,7 +349,7 @@ func (p *propertySet) populateProperties(allocs []*structs.Allocation, nodes map
 	properties map[string]uint64) {
 
 	for _, alloc := range allocs {
-		nProperty, ok := getProperty(nodes[alloc.NodeID], p.targetAttribute)
+		nProperty, ok := getAllocProperty(alloc, nodes[alloc.NodeID], p.targetAttribute)
 		if !ok {
 			continue
 		}
@@ -353,3 +375,67 @@ func getProperty(n *structs.Node, property string) (string, bool) {
 
 	return nodeValue, true
 }
+
+// getAllocProperty is used to lookup the property value of an allocation.
+// Device targets are resolved from the devices assigned to the allocation and
+// any other target from the node it is placed on.
+func getAllocProperty(alloc *structs.Allocation, n *structs.Node, property string) (string, bool) {
+	if !isDeviceTarget(property) {
+		return getProperty(n, property)
+	}
+
+	if alloc.AllocatedResources == nil {
+		return "", false
+	}
+	return getDeviceProperty(n, alloc.AllocatedResources.Tasks, property)
+}
+
+// getRankedProperty is used to lookup the property value of a ranked option.
+// Device targets are resolved from the devices assigned to the option and any
+// other target from its node.
+func getRankedProperty(option *RankedNode, property string) (string, bool) {
+	if !isDeviceTarget(property) {
+		return getProperty(option.Node, property)
+	}
+	return getDeviceProperty(option.Node, option.TaskResources, property)
+}
+
+// isDeviceTarget returns whether the property is a device target, such as
+// ${device.model}, which is resolved from the devices assigned to placements.
+func isDeviceTarget(property string) bool {
+	return strings.HasPrefix(property, "${device.")
+}
+
+// getDeviceProperty is used to lookup a device property using the first device
+// assigned to the tasks, in task name order. The attributes of the device are
+// looked up on the node.
+func getDeviceProperty(n *structs.Node, tasks map[string]*structs.AllocatedTaskResources, property string) (string, bool) {
+	if n == nil || n.NodeResources == nil {
+		return "", false
+	}
+
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, device := range tasks[name].Devices {
+			id := device.ID()
+			for _, nodeDevice := range n.NodeResources.Devices {
+				if !nodeDevice.ID().Equals(id) {
+					continue
+				}
+
+				val, ok := resolveDeviceTarget(property, nodeDevice)
+				if !ok {
+					return "", false
+				}
+				return val.GoString(), true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -234,6 +234,7 @@ OUTER:
 				if !iter.evict {
 					iter.ctx.Metrics().ExhaustedNode(option.Node,
 						fmt.Sprintf("network: %s", err))
+					iter.ctx.Metrics().TraceNode(option.Node, "binpack", fmt.Sprintf("network: %s", err))
 					netIdx.Release()
 					continue OUTER
 				}
@@ -244,6 +245,7 @@ OUTER:
 				netPreemptions := preemptor.PreemptForNetwork(ask, netIdx)
 				if netPreemptions == nil {
 					iter.ctx.Logger().Named("binpack").Debug("preemption not possible ", "network_resource", ask)
+					iter.ctx.Metrics().TraceNode(option.Node, "binpack", fmt.Sprintf("network: %s and preemption not possible", err))
 					netIdx.Release()
 					continue OUTER
 				}
@@ -298,6 +300,7 @@ OUTER:
 					if !iter.evict {
 						iter.ctx.Metrics().ExhaustedNode(option.Node,
 							fmt.Sprintf("network: %s", err))
+						iter.ctx.Metrics().TraceNode(option.Node, "binpack", fmt.Sprintf("network: %s", err))
 						netIdx.Release()
 						continue OUTER
 					}
@@ -308,6 +311,7 @@ OUTER:
 					netPreemptions := preemptor.PreemptForNetwork(ask, netIdx)
 					if netPreemptions == nil {
 						iter.ctx.Logger().Named("binpack").Debug("preemption not possible ", "network_resource", ask)
+						iter.ctx.Metrics().TraceNode(option.Node, "binpack", fmt.Sprintf("network: %s and preemption not possible", err))
 						netIdx.Release()
 						continue OUTER
 					}
@@ -344,6 +348,7 @@ OUTER:
 					// If eviction is not enabled, mark this node as exhausted and continue
 					if !iter.evict {
 						iter.ctx.Metrics().ExhaustedNode(option.Node, fmt.Sprintf("devices: %s", err))
+						iter.ctx.Metrics().TraceNode(option.Node, "binpack", fmt.Sprintf("devices: %s", err))
 						continue OUTER
 					}
 
@@ -353,6 +358,7 @@ OUTER:
 
 					if devicePreemptions == nil {
 						iter.ctx.Logger().Named("binpack").Debug("preemption not possible", "requested_device", req)
+						iter.ctx.Metrics().TraceNode(option.Node, "binpack", fmt.Sprintf("devices: %s and preemption not possible", err))
 						netIdx.Release()
 						continue OUTER
 					}
@@ -406,6 +412,7 @@ OUTER:
 			// Skip the node if evictions are not enabled
 			if !iter.evict {
 				iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
+				iter.traceExhausted(option.Node, dim, total, util, false)
 				continue
 			}
 
@@ -422,6 +429,7 @@ OUTER:
 			// mark as exhausted and continue
 			if len(preemptedAllocs) == 0 {
 				iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
+				iter.traceExhausted(option.Node, dim, total, util, true)
 				continue
 			}
 		}
@@ -446,6 +454,32 @@ OUTER:
 	}
 }
 
+// traceExhausted records in the placement trace the resources of the node
+// exhausted by the task group.
+func (iter *BinPackIterator) traceExhausted(node *structs.Node, dim string,
+	ask *structs.AllocatedResources, used *structs.ComparableResources, preempt bool) {
+	metrics := iter.ctx.Metrics()
+	if !metrics.TracingPlacement() {
+		return
+	}
+
+	reason := fmt.Sprintf("%s exhausted", dim)
+	if used != nil {
+		requested := ask.Comparable()
+		free := node.ComparableResources()
+		free.Subtract(used)
+		free.Add(requested)
+		reason = fmt.Sprintf("%s: task group asks for cpu %d MHz, memory %d MB, disk %d MB; node has cpu %d MHz, memory %d MB, disk %d MB free",
+			reason,
+			requested.Flattened.Cpu.CpuShares, requested.Flattened.Memory.MemoryMB, requested.Shared.DiskMB,
+			free.Flattened.Cpu.CpuShares, free.Flattened.Memory.MemoryMB, free.Shared.DiskMB)
+	}
+	if preempt {
+		reason += " and preemption not possible"
+	}
+	metrics.TraceNode(node, "binpack", reason)
+}
+
 func (iter *BinPackIterator) Reset() {
 	iter.source.Reset()
 }
@@ -127,6 +127,63 @@ func TestBinPackIterator_NoExistingAlloc(t *testing.T) {
 	}
 }
 
+func TestBinPackIterator_PlacementTrace(t *testing.T) {
+	_, ctx := testContext(t)
+	ctx.EnablePlacementTrace()
+	require := require.New(t)
+
+	nodes := []*RankedNode{
+		{
+			Node: &structs.Node{
+				// Overloaded
+				ID:   uuid.Generate(),
+				Name: "small",
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares: 1024,
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 1024,
+					},
+				},
+				ReservedResources: &structs.NodeReservedResources{
+					Cpu: structs.NodeReservedCpuResources{
+						CpuShares: 512,
+					},
+					Memory: structs.NodeReservedMemoryResources{
+						MemoryMB: 512,
+					},
+				},
+			},
+		},
+	}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 256,
+				},
+			},
+		},
+	}
+	binp := NewBinPackIterator(ctx, static, false, 0)
+	binp.SetTaskGroup(taskGroup)
+
+	require.Empty(collectRanked(binp))
+
+	trace := ctx.Metrics().PlacementTrace
+	require.Len(trace, 1)
+	require.Equal(nodes[0].Node.ID, trace[0].NodeID)
+	require.Equal("small", trace[0].NodeName)
+	require.Equal("binpack", trace[0].Checker)
+	require.Equal("cpu exhausted: task group asks for cpu 1024 MHz, memory 256 MB, disk 0 MB; node has cpu 512 MHz, memory 512 MB, disk 0 MB free", trace[0].Reason)
+}
+
 // Tests bin packing iterator with network resources at task and task group level
 func TestBinPackIterator_Network_Success(t *testing.T) {
 	_, ctx := testContext(t)
@@ -1285,3 +1342,52 @@ func TestNodeAffinityIterator(t *testing.T) {
 	}
 
 }
+
+func TestNodeAffinityIterator_HostVolumes(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+
+	nodes[0].Node.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"fast": {Name: "fast", Path: "/mnt/fast"},
+		"data": {Name: "data", Path: "/mnt/data"},
+	}
+	nodes[1].Node.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"data": {Name: "data", Path: "/mnt/data"},
+	}
+
+	static := NewStaticRankIterator(ctx, nodes)
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Affinities = []*structs.Affinity{
+		{
+			Operand: structs.ConstraintSetContains,
+			LTarget: "${node.host_volumes}",
+			RTarget: "fast",
+			Weight:  100,
+		},
+	}
+
+	nodeAffinity := NewNodeAffinityIterator(ctx, static)
+	nodeAffinity.SetTaskGroup(tg)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, nodeAffinity)
+
+	out := collectRanked(scoreNorm)
+	expectedScores := map[string]float64{
+		// Only node 0 has the fast host volume
+		nodes[0].Node.ID: 1.0,
+		nodes[1].Node.ID: 0.0,
+		nodes[2].Node.ID: 0.0,
+	}
+
+	require := require.New(t)
+	require.Len(out, 3)
+	for _, n := range out {
+		require.Equal(expectedScores[n.Node.ID], n.FinalScore)
+	}
+}
@@ -335,6 +335,7 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 			dstate.AutoRevert = tg.Update.AutoRevert
 			dstate.AutoPromote = tg.Update.AutoPromote
 			dstate.ProgressDeadline = tg.Update.ProgressDeadline
+			dstate.RolloutSteps = helper.CopySliceInt(tg.Update.RolloutSteps)
 		}
 	}
 
@@ -387,13 +388,14 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// desired means we need to create canaries
 	numDestructive := len(destructive)
 	strategy := tg.Update
+	desiredCanaries := strategy.DesiredCanaries(tg.Count)
 	canariesPromoted := dstate != nil && dstate.Promoted
-	requireCanary := numDestructive != 0 && strategy != nil && len(canaries) < strategy.Canary && !canariesPromoted
+	requireCanary := numDestructive != 0 && strategy != nil && len(canaries) < desiredCanaries && !canariesPromoted
 	if requireCanary && !a.deploymentPaused && !a.deploymentFailed {
-		number := strategy.Canary - len(canaries)
+		number := desiredCanaries - len(canaries)
 		desiredChanges.Canary += uint64(number)
 		if !existingDeployment {
-			dstate.DesiredCanaries = strategy.Canary
+			dstate.DesiredCanaries = desiredCanaries
 		}
 
 		for _, name := range nameIndex.NextCanaries(uint(number), canaries, destructive) {
@@ -408,6 +410,7 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// Determine how many we can place
 	canaryState = dstate != nil && dstate.DesiredCanaries != 0 && !dstate.Promoted
 	limit := a.computeLimit(tg, untainted, destructive, migrate, canaryState)
+	limit = a.computeStepLimit(dstate, untainted, limit)
 
 	// Place if:
 	// * The deployment is not paused or failed
@@ -529,7 +532,8 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	if deploymentComplete && a.deployment != nil {
 		if dstate, ok := a.deployment.TaskGroups[group]; ok {
 			if dstate.HealthyAllocs < helper.IntMax(dstate.DesiredTotal, dstate.DesiredCanaries) || // Make sure we have enough healthy allocs
-				(dstate.DesiredCanaries > 0 && !dstate.Promoted) { // Make sure we are promoted if we have canaries
+				(dstate.DesiredCanaries > 0 && !dstate.Promoted) || // Make sure we are promoted if we have canaries
+				(strategy != nil && strategy.HealthGate != nil && !dstate.HealthGatePassed()) { // Make sure the health gate has passed
 				deploymentComplete = false
 			}
 		}
@@ -612,6 +616,29 @@ func (a *allocReconciler) handleGroupCanaries(all allocSet, desiredChanges *stru
 	return canaries, all
 }
 
+// computeStepLimit further restricts the placement limit of a group so that
+// the deployment does not go past the group's current rollout step.
+func (a *allocReconciler) computeStepLimit(dstate *structs.DeploymentState, untainted allocSet, limit int) int {
+	if dstate == nil || len(dstate.RolloutSteps) == 0 {
+		return limit
+	}
+
+	// Count the allocations the deployment has placed. Allocations created
+	// before the deployment were only updated in place and don't count
+	// against the step.
+	placed := 0
+	if a.deployment != nil {
+		partOf, _ := untainted.filterByDeployment(a.deployment.ID)
+		for _, alloc := range partOf {
+			if alloc.CreateIndex >= a.deployment.CreateIndex {
+				placed++
+			}
+		}
+	}
+
+	return helper.IntMax(0, helper.IntMin(limit, dstate.StepTarget()-placed))
+}
+
 // computeLimit returns the placement limit for a particular group. The inputs
 // are the group definition, the untainted, destructive, and migrate allocation
 // set and whether we are in a canary state.
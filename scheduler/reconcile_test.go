@@ -644,6 +644,171 @@ func TestReconciler_DestructiveMaxParallel(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(0, 9), destructiveResultsToNames(r.destructiveUpdate))
 }
 
+// Tests the reconciler limits destructive updates to the first rollout step
+func TestReconciler_Destructive_RolloutSteps(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.RolloutSteps = []int{20, 50}
+
+	// Create 10 existing allocations
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		allocs = append(allocs, alloc)
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnDestructive, false, job.ID, job, nil, allocs, nil, "")
+	r := reconciler.Compute()
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal: 10,
+		RolloutSteps: []int{20, 50},
+	}
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  d,
+		deploymentUpdates: nil,
+		destructive:       2,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				DestructiveUpdate: 2,
+				Ignore:            8,
+			},
+		},
+	})
+
+	assertNamesHaveIndexes(t, intRange(0, 1), destructiveResultsToNames(r.destructiveUpdate))
+}
+
+// Tests the reconciler only updates up to the target of the current rollout
+// step when part of the group has already been updated
+func TestReconciler_Destructive_RolloutSteps_NextStep(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.RolloutSteps = []int{20, 50}
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal:  10,
+		RolloutSteps:  []int{20, 50},
+		CurrentStep:   1,
+		PlacedAllocs:  2,
+		HealthyAllocs: 2,
+	}
+
+	// Create 8 allocations from the old job
+	var allocs []*structs.Allocation
+	for i := 2; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		allocs = append(allocs, alloc)
+	}
+
+	// Create 2 healthy allocations that are part of the deployment
+	handled := make(map[string]allocUpdateType)
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.DeploymentID = d.ID
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{
+			Healthy: helper.BoolToPtr(true),
+		}
+		allocs = append(allocs, alloc)
+		handled[alloc.ID] = allocUpdateFnIgnore
+	}
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), mockUpdateFn, false, job.ID, job, d, allocs, nil, "")
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		destructive:       3,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				DestructiveUpdate: 3,
+				Ignore:            7,
+			},
+		},
+	})
+
+	assertNamesHaveIndexes(t, intRange(2, 4), destructiveResultsToNames(r.destructiveUpdate))
+}
+
+// Tests the reconciler doesn't count allocations updated in place against the
+// target of the current rollout step
+func TestReconciler_Destructive_RolloutSteps_InplaceUpdates(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.RolloutSteps = []int{20, 50}
+
+	d := structs.NewDeployment(job)
+	d.CreateIndex = 100
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal: 10,
+		RolloutSteps: []int{20, 50},
+	}
+
+	// Create 8 allocations from the old job
+	var allocs []*structs.Allocation
+	for i := 2; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.CreateIndex = 50
+		allocs = append(allocs, alloc)
+	}
+
+	// Create 2 allocations created before the deployment and updated in place
+	handled := make(map[string]allocUpdateType)
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.CreateIndex = 50
+		alloc.DeploymentID = d.ID
+		allocs = append(allocs, alloc)
+		handled[alloc.ID] = allocUpdateFnIgnore
+	}
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), mockUpdateFn, false, job.ID, job, d, allocs, nil, "")
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		destructive:       2,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				DestructiveUpdate: 2,
+				Ignore:            8,
+			},
+		},
+	})
+
+	assertNamesHaveIndexes(t, intRange(2, 3), destructiveResultsToNames(r.destructiveUpdate))
+}
+
 // Tests the reconciler properly handles destructive upgrading allocations while
 // scaling up
 func TestReconciler_Destructive_ScaleUp(t *testing.T) {
@@ -4014,6 +4179,70 @@ func TestReconciler_MarkDeploymentComplete_FailedAllocations(t *testing.T) {
 	})
 }
 
+// Tests that the reconciler only marks a deployment as complete once the
+// health gate of its groups has passed
+func TestReconciler_MarkDeploymentComplete_HealthGate(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.HealthGate = &structs.HealthGate{
+		URL:      "http://127.0.0.1/gate",
+		Timeout:  time.Second,
+		Attempts: 1,
+	}
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal:  10,
+		PlacedAllocs:  10,
+		HealthyAllocs: 10,
+	}
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		alloc.DeploymentID = d.ID
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{
+			Healthy: helper.BoolToPtr(true),
+		}
+		allocs = append(allocs, alloc)
+	}
+
+	// The health gate hasn't been checked yet
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, d, allocs, nil, "")
+	r := reconciler.Compute()
+	assertResults(t, r, &resultExpectation{
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 10,
+			},
+		},
+	})
+
+	// The health gate has passed
+	d.TaskGroups[job.TaskGroups[0].Name].HealthGate = &structs.HealthGateResult{Passed: true}
+	reconciler = NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, d, allocs, nil, "")
+	r = reconciler.Compute()
+	assertResults(t, r, &resultExpectation{
+		deploymentUpdates: []*structs.DeploymentStatusUpdate{
+			{
+				DeploymentID:      d.ID,
+				Status:            structs.DeploymentStatusSuccessful,
+				StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+			},
+		},
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 10,
+			},
+		},
+	})
+}
+
 // Test that a failed deployment cancels non-promoted canaries
 func TestReconciler_FailedDeployment_CancelCanaries(t *testing.T) {
 	// Create a job with two task groups
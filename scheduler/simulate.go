@@ -0,0 +1,338 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Simulation replays hypothetical jobs, node additions and node removals
+// against a copy of the cluster state. Plans are only applied to the copy
+// using a Harness, so nothing is ever committed to the cluster.
+type Simulation struct {
+	logger  log.Logger
+	harness *Harness
+
+	// added tracks the IDs of the nodes added by the simulation
+	added map[string]struct{}
+}
+
+// NewSimulation returns a simulation against the given state store, which is
+// modified by the simulation and so should be a snapshot of the cluster state.
+func NewSimulation(logger log.Logger, state *state.StateStore) (*Simulation, error) {
+	index, err := state.LatestIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Simulation{
+		logger: logger.Named("simulation"),
+		harness: &Harness{
+			State:     state,
+			nextIndex: index + 1,
+		},
+		added: make(map[string]struct{}),
+	}, nil
+}
+
+// AddNode adds count clones of the given existing node to the cluster and
+// evaluates the system jobs that may be placed on them.
+func (s *Simulation) AddNode(nodeID string, count int) ([]*structs.SimulatedEval, error) {
+	ws := memdb.NewWatchSet()
+	node, err := s.harness.State.NodeByID(ws, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("node %q not found", nodeID)
+	}
+
+	var results []*structs.SimulatedEval
+	for i := 0; i < count; i++ {
+		clone := node.Copy()
+		clone.ID = uuid.Generate()
+		clone.SecretID = uuid.Generate()
+		clone.Name = fmt.Sprintf("%s-simulated-%d", node.Name, len(s.added)+1)
+		clone.Status = structs.NodeStatusReady
+		clone.Drain = false
+		clone.DrainStrategy = nil
+		clone.SchedulingEligibility = structs.NodeSchedulingEligible
+
+		index := s.harness.NextIndex()
+		if err := s.harness.State.UpsertNode(index, clone); err != nil {
+			return nil, err
+		}
+		s.added[clone.ID] = struct{}{}
+
+		evalResults, err := s.processNodeEvals(clone.ID, index)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, evalResults...)
+	}
+
+	return results, nil
+}
+
+// RemoveNode marks the given node as down and evaluates the jobs with
+// allocations on it, so they are rescheduled onto the remaining nodes.
+func (s *Simulation) RemoveNode(nodeID string) ([]*structs.SimulatedEval, error) {
+	ws := memdb.NewWatchSet()
+	node, err := s.harness.State.NodeByID(ws, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("node %q not found", nodeID)
+	}
+
+	index := s.harness.NextIndex()
+	event := structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemCluster).SetMessage("Node removed by simulation")
+	if err := s.harness.State.UpdateNodeStatus(index, nodeID, structs.NodeStatusDown, time.Now().Unix(), event); err != nil {
+		return nil, err
+	}
+
+	return s.processNodeEvals(nodeID, index)
+}
+
+// RegisterJob registers the given job and evaluates it.
+func (s *Simulation) RegisterJob(job *structs.Job) (*structs.SimulatedEval, error) {
+	index := s.harness.NextIndex()
+	if err := s.harness.State.UpsertJob(index, job.Copy()); err != nil {
+		return nil, err
+	}
+
+	// Use the job as stored, including the version set by the state store
+	ws := memdb.NewWatchSet()
+	stored, err := s.harness.State.JobByID(ws, job.Namespace, job.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().UnixNano()
+	eval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      stored.Namespace,
+		Priority:       stored.Priority,
+		Type:           stored.Type,
+		TriggeredBy:    structs.EvalTriggerJobRegister,
+		JobID:          stored.ID,
+		JobModifyIndex: stored.JobModifyIndex,
+		Status:         structs.EvalStatusPending,
+		CreateTime:     now,
+		ModifyTime:     now,
+	}
+	return s.process(eval)
+}
+
+// Nodes returns the utilization of the nodes, sorted by name.
+func (s *Simulation) Nodes() ([]*structs.SimulatedNode, error) {
+	ws := memdb.NewWatchSet()
+	iter, err := s.harness.State.Nodes(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*structs.SimulatedNode
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+
+		allocs, err := s.harness.State.AllocsByNode(ws, node.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		used := &structs.ComparableResources{}
+		count := 0
+		for _, alloc := range allocs {
+			if alloc.TerminalStatus() {
+				continue
+			}
+			used.Add(alloc.ComparableResources())
+			count++
+		}
+
+		capacity := node.ComparableResources()
+		if reserved := node.ComparableReservedResources(); reserved != nil {
+			capacity.Subtract(reserved)
+		}
+
+		_, simulated := s.added[node.ID]
+		nodes = append(nodes, &structs.SimulatedNode{
+			ID:               node.ID,
+			Name:             node.Name,
+			Datacenter:       node.Datacenter,
+			NodeClass:        node.NodeClass,
+			Status:           node.Status,
+			Simulated:        simulated,
+			Allocs:           count,
+			CPU:              used.Flattened.Cpu.CpuShares,
+			CPUCapacity:      capacity.Flattened.Cpu.CpuShares,
+			MemoryMB:         used.Flattened.Memory.MemoryMB,
+			MemoryCapacityMB: capacity.Flattened.Memory.MemoryMB,
+			DiskMB:           used.Shared.DiskMB,
+			DiskCapacityMB:   capacity.Shared.DiskMB,
+		})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Name != nodes[j].Name {
+			return nodes[i].Name < nodes[j].Name
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes, nil
+}
+
+// processNodeEvals evaluates the jobs affected by a change of the given node,
+// the same way the servers do when a node's status changes.
+func (s *Simulation) processNodeEvals(nodeID string, nodeIndex uint64) ([]*structs.SimulatedEval, error) {
+	ws := memdb.NewWatchSet()
+	allocs, err := s.harness.State.AllocsByNode(ws, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	sysJobsIter, err := s.harness.State.JobsByScheduler(ws, structs.JobTypeSystem)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*structs.Job
+	seen := make(map[structs.NamespacedID]struct{})
+	for _, alloc := range allocs {
+		id := structs.NamespacedID{ID: alloc.JobID, Namespace: alloc.Namespace}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		jobs = append(jobs, alloc.Job)
+	}
+	for raw := sysJobsIter.Next(); raw != nil; raw = sysJobsIter.Next() {
+		job := raw.(*structs.Job)
+		id := structs.NamespacedID{ID: job.ID, Namespace: job.Namespace}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		jobs = append(jobs, job)
+	}
+
+	var results []*structs.SimulatedEval
+	now := time.Now().UTC().UnixNano()
+	for _, job := range jobs {
+		eval := &structs.Evaluation{
+			ID:              uuid.Generate(),
+			Namespace:       job.Namespace,
+			Priority:        job.Priority,
+			Type:            job.Type,
+			TriggeredBy:     structs.EvalTriggerNodeUpdate,
+			JobID:           job.ID,
+			NodeID:          nodeID,
+			NodeModifyIndex: nodeIndex,
+			Status:          structs.EvalStatusPending,
+			CreateTime:      now,
+			ModifyTime:      now,
+		}
+
+		result, err := s.process(eval)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// process runs the scheduler for the given evaluation and collects the
+// changes of the plans it submitted.
+func (s *Simulation) process(eval *structs.Evaluation) (*structs.SimulatedEval, error) {
+	h := s.harness
+	if err := h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}); err != nil {
+		return nil, err
+	}
+
+	// Track the existing allocations of the job so only new ones are
+	// reported as placed
+	ws := memdb.NewWatchSet()
+	allocs, err := h.State.AllocsByJob(ws, eval.Namespace, eval.JobID, true)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]struct{}, len(allocs))
+	for _, alloc := range allocs {
+		existing[alloc.ID] = struct{}{}
+	}
+
+	plans, evals := len(h.Plans), len(h.Evals)
+
+	sched, err := NewScheduler(eval.Type, s.logger, h.Snapshot(), h)
+	if err != nil {
+		return nil, err
+	}
+	if err := sched.Process(eval); err != nil {
+		return nil, fmt.Errorf("failed to process evaluation for job %q: %v", eval.JobID, err)
+	}
+
+	result := &structs.SimulatedEval{
+		Namespace:   eval.Namespace,
+		JobID:       eval.JobID,
+		TriggeredBy: eval.TriggeredBy,
+	}
+	for _, plan := range h.Plans[plans:] {
+		for _, allocs := range plan.NodeAllocation {
+			for _, alloc := range allocs {
+				if _, ok := existing[alloc.ID]; !ok {
+					result.Placed = append(result.Placed, simulatedAlloc(alloc))
+				}
+			}
+		}
+		for _, allocs := range plan.NodeUpdate {
+			for _, alloc := range allocs {
+				result.Stopped = append(result.Stopped, simulatedAlloc(alloc))
+			}
+		}
+		for _, allocs := range plan.NodePreemptions {
+			for _, alloc := range allocs {
+				result.Preempted = append(result.Preempted, simulatedAlloc(alloc))
+			}
+		}
+	}
+	sortSimulatedAllocs(result.Placed)
+	sortSimulatedAllocs(result.Stopped)
+	sortSimulatedAllocs(result.Preempted)
+
+	// The last update of the evaluation has the placement failures
+	if updates := h.Evals[evals:]; len(updates) != 0 {
+		result.FailedTGAllocs = updates[len(updates)-1].FailedTGAllocs
+	}
+
+	return result, nil
+}
+
+func simulatedAlloc(alloc *structs.Allocation) *structs.SimulatedAlloc {
+	return &structs.SimulatedAlloc{
+		ID:        alloc.ID,
+		Name:      alloc.Name,
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+		TaskGroup: alloc.TaskGroup,
+		NodeID:    alloc.NodeID,
+	}
+}
+
+func sortSimulatedAllocs(allocs []*structs.SimulatedAlloc) {
+	sort.Slice(allocs, func(i, j int) bool {
+		if allocs[i].Name != allocs[j].Name {
+			return allocs[i].Name < allocs[j].Name
+		}
+		return allocs[i].ID < allocs[j].ID
+	})
+}
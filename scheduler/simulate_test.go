@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestSimulation_RegisterJob(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	store := state.TestStateStore(t)
+	node := mock.Node()
+	require.NoError(store.UpsertNode(100, node))
+
+	sim, err := NewSimulation(testlog.HCLogger(t), store)
+	require.NoError(err)
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	result, err := sim.RegisterJob(job)
+	require.NoError(err)
+	require.Equal(job.ID, result.JobID)
+	require.Equal(structs.EvalTriggerJobRegister, result.TriggeredBy)
+	require.Len(result.Placed, 3)
+	require.Empty(result.FailedTGAllocs)
+	for _, alloc := range result.Placed {
+		require.Equal(node.ID, alloc.NodeID)
+	}
+
+	nodes, err := sim.Nodes()
+	require.NoError(err)
+	require.Len(nodes, 1)
+	require.Equal(3, nodes[0].Allocs)
+	require.Equal(int64(3*500), nodes[0].CPU)
+	require.Equal(int64(3*256), nodes[0].MemoryMB)
+	require.False(nodes[0].Simulated)
+
+	// Too many allocations fail to place
+	big := mock.Job()
+	big.TaskGroups[0].Count = 100
+	result, err = sim.RegisterJob(big)
+	require.NoError(err)
+	require.Contains(result.FailedTGAllocs, "web")
+}
+
+func TestSimulation_AddRemoveNode(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	store := state.TestStateStore(t)
+	node := mock.Node()
+	require.NoError(store.UpsertNode(100, node))
+
+	sim, err := NewSimulation(testlog.HCLogger(t), store)
+	require.NoError(err)
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	_, err = sim.RegisterJob(job)
+	require.NoError(err)
+
+	sysJob := mock.SystemJob()
+	result, err := sim.RegisterJob(sysJob)
+	require.NoError(err)
+	require.Len(result.Placed, 1)
+
+	// Adding nodes places the system job on them
+	results, err := sim.AddNode(node.ID, 2)
+	require.NoError(err)
+	require.Len(results, 2)
+	for _, result := range results {
+		require.Equal(sysJob.ID, result.JobID)
+		require.Len(result.Placed, 1)
+	}
+
+	// Removing the original node moves the service allocations
+	results, err = sim.RemoveNode(node.ID)
+	require.NoError(err)
+	placed := 0
+	for _, result := range results {
+		if result.JobID == job.ID {
+			placed += len(result.Placed)
+			require.Len(result.Stopped, 2)
+		}
+	}
+	require.Equal(2, placed)
+
+	nodes, err := sim.Nodes()
+	require.NoError(err)
+	require.Len(nodes, 3)
+	simulated := 0
+	for _, n := range nodes {
+		if n.Simulated {
+			simulated++
+			require.NotEqual(node.ID, n.ID)
+		}
+	}
+	require.Equal(2, simulated)
+
+	// The original state store node is only marked down
+	out, err := store.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Equal(structs.NodeStatusDown, out.Status)
+
+	_, err = sim.RemoveNode("unknown")
+	require.Error(err)
+}
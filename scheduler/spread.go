@@ -121,7 +121,7 @@ func (iter *SpreadIterator) Next() *RankedNode {
 		// Iterate over each spread attribute's property set and add a weighted score
 		totalSpreadScore := 0.0
 		for _, pset := range propertySets {
-			nValue, errorMsg, usedCount := pset.UsedCount(option.Node, tgName)
+			nValue, errorMsg, usedCount := pset.RankedUsedCount(option, tgName)
 
 			// Add one to include placement on this node in the scoring calculation
 			usedCount += 1
@@ -137,7 +137,7 @@ func (iter *SpreadIterator) Next() *RankedNode {
 			if len(spreadDetails.desiredCounts) == 0 {
 				// When desired counts map is empty the user didn't specify any targets
 				// Use even spreading scoring algorithm for this scenario
-				scoreBoost := evenSpreadScoreBoost(pset, option.Node)
+				scoreBoost := evenSpreadScoreBoost(pset, option)
 				totalSpreadScore += scoreBoost
 			} else {
 				// Get the desired count
@@ -175,14 +175,14 @@ func (iter *SpreadIterator) Next() *RankedNode {
 
 // evenSpreadScoreBoost is a scoring helper that calculates the score
 // for the option when even spread is desired (all attribute values get equal preference)
-func evenSpreadScoreBoost(pset *propertySet, option *structs.Node) float64 {
+func evenSpreadScoreBoost(pset *propertySet, option *RankedNode) float64 {
 	combinedUseMap := pset.GetCombinedUseMap()
 	if len(combinedUseMap) == 0 {
 		// Nothing placed yet, so return 0 as the score
 		return 0.0
 	}
-	// Get the nodes property value
-	nValue, ok := getProperty(option, pset.targetAttribute)
+	// Get the options property value
+	nValue, ok := getRankedProperty(option, pset.targetAttribute)
 
 	// Maximum possible penalty when the attribute isn't set on the node
 	if !ok {
@@ -561,10 +561,140 @@ func Test_evenSpreadScoreBoost(t *testing.T) {
 		targetAttribute: "${node.datacenter}",
 	}
 
-	opt := &structs.Node{
-		Datacenter: "dc2",
+	opt := &RankedNode{
+		Node: &structs.Node{
+			Datacenter: "dc2",
+		},
 	}
 	boost := evenSpreadScoreBoost(pset, opt)
 	require.False(t, math.IsInf(boost, 1))
 	require.Equal(t, 1.0, boost)
 }
+
+func TestSpreadIterator_Devices(t *testing.T) {
+	state, ctx := testContext(t)
+
+	// Create two nodes with different GPU models
+	models := []string{"1080ti", "2080ti"}
+	var nodes []*RankedNode
+	for i, model := range models {
+		node := mock.NvidiaNode()
+		node.NodeResources.Devices[0].Name = model
+		require.NoError(t, state.UpsertNode(uint64(100+i), node))
+
+		// Assign a device to the option as bin packing does
+		device := node.NodeResources.Devices[0]
+		option := &RankedNode{Node: node}
+		option.SetTaskResources(&structs.Task{Name: "web"}, &structs.AllocatedTaskResources{
+			Devices: []*structs.AllocatedDeviceResource{
+				{
+					Vendor:    device.Vendor,
+					Type:      device.Type,
+					Name:      device.Name,
+					DeviceIDs: []string{device.Instances[0].ID},
+				},
+			},
+		})
+		nodes = append(nodes, option)
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Count = 4
+	tg.Spreads = []*structs.Spread{
+		{
+			Weight:    100,
+			Attribute: "${device.model}",
+		},
+	}
+
+	// Add an alloc using the 1080ti
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.TaskGroup = tg.Name
+	alloc.NodeID = nodes[0].Node.ID
+	alloc.AllocatedResources.Tasks["web"] = nodes[0].TaskResources["web"]
+	require.NoError(t, state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	static := NewStaticRankIterator(ctx, nodes)
+	spreadIter := NewSpreadIterator(ctx, static)
+	spreadIter.SetJob(job)
+	spreadIter.SetTaskGroup(tg)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, spreadIter)
+	out := collectRanked(scoreNorm)
+
+	// The node assigning the unused model gets the maximum boost
+	expectedScores := map[string]float64{
+		"1080ti": -1.0,
+		"2080ti": 1.0,
+	}
+	require.Len(t, out, 2)
+	for _, rn := range out {
+		require.Equal(t, expectedScores[rn.Node.NodeResources.Devices[0].Name], rn.FinalScore)
+	}
+
+	// Device attributes are resolved from the node
+	value, ok := getRankedProperty(nodes[0], "${device.attr.memory}")
+	require.True(t, ok)
+	require.Equal(t, "11GiB", value)
+
+	// Options without devices don't have device properties
+	_, ok = getRankedProperty(&RankedNode{Node: nodes[0].Node}, "${device.vendor}")
+	require.False(t, ok)
+}
+
+func TestSpreadIterator_HostVolume(t *testing.T) {
+	state, ctx := testContext(t)
+
+	// Create nodes with several host volumes, whose data volume is backed by
+	// different disks
+	paths := []string{"/mnt/ssd-a", "/mnt/ssd-b"}
+	var nodes []*RankedNode
+	for i, path := range paths {
+		node := mock.Node()
+		node.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+			"data":    {Name: "data", Path: path},
+			"scratch": {Name: "scratch", Path: "/tmp"},
+		}
+		require.NoError(t, state.UpsertNode(uint64(100+i), node))
+		nodes = append(nodes, &RankedNode{Node: node})
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Count = 4
+	tg.Spreads = []*structs.Spread{
+		{
+			Weight:    100,
+			Attribute: "${node.host_volume.data}",
+		},
+	}
+
+	// Add an alloc on the node backing the data volume with ssd-a
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.TaskGroup = tg.Name
+	alloc.NodeID = nodes[0].Node.ID
+	require.NoError(t, state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	static := NewStaticRankIterator(ctx, nodes)
+	spreadIter := NewSpreadIterator(ctx, static)
+	spreadIter.SetJob(job)
+	spreadIter.SetTaskGroup(tg)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, spreadIter)
+	out := collectRanked(scoreNorm)
+
+	// The node with the unused disk gets the maximum boost
+	expectedScores := map[string]float64{
+		nodes[0].Node.ID: -1.0,
+		nodes[1].Node.ID: 1.0,
+	}
+	require.Len(t, out, 2)
+	for _, rn := range out {
+		require.Equal(t, expectedScores[rn.Node.ID], rn.FinalScore)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -33,6 +34,20 @@ type SystemScheduler struct {
 	nodes      []*structs.Node
 	nodesByDC  map[string]int
 
+	// deployment is the current deployment of the job. It may be created
+	// by this evaluation when the job's task groups define an update
+	// strategy.
+	deployment *structs.Deployment
+
+	// pendingUpdates is the number of destructive updates per task group
+	// that were deferred because of the group's update strategy.
+	pendingUpdates map[string]int
+
+	// addedPlacements is the number of allocations per task group placed
+	// into an existing deployment on nodes it didn't cover before, such as
+	// nodes that joined during the deployment.
+	addedPlacements map[string]int
+
 	limitReached bool
 	nextEval     *structs.Evaluation
 
@@ -68,7 +83,12 @@ func (s *SystemScheduler) Process(eval *structs.Evaluation) error {
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
 		return setStatus(s.logger, s.planner, s.eval, s.nextEval, nil, s.failedTGAllocs, structs.EvalStatusFailed, desc,
-			s.queuedAllocs, "")
+			s.queuedAllocs, s.deployment.GetID())
+	}
+
+	// Wait for the dependencies of the job before placing its allocations
+	if blocked, err := blockOnUnmetDependencies(s.logger, s.state, s.planner, s.eval); err != nil || blocked {
+		return err
 	}
 
 	// Retry up to the maxSystemScheduleAttempts and reset if progress is made.
@@ -76,14 +96,14 @@ func (s *SystemScheduler) Process(eval *structs.Evaluation) error {
 	if err := retryMax(maxSystemScheduleAttempts, s.process, progress); err != nil {
 		if statusErr, ok := err.(*SetStatusError); ok {
 			return setStatus(s.logger, s.planner, s.eval, s.nextEval, nil, s.failedTGAllocs, statusErr.EvalStatus, err.Error(),
-				s.queuedAllocs, "")
+				s.queuedAllocs, s.deployment.GetID())
 		}
 		return err
 	}
 
 	// Update the status to complete
 	return setStatus(s.logger, s.planner, s.eval, s.nextEval, nil, s.failedTGAllocs, structs.EvalStatusComplete, "",
-		s.queuedAllocs, "")
+		s.queuedAllocs, s.deployment.GetID())
 }
 
 // process is wrapped in retryMax to iteratively run the handler until we have no
@@ -103,6 +123,13 @@ func (s *SystemScheduler) process() (bool, error) {
 	}
 	s.queuedAllocs = make(map[string]int, numTaskGroups)
 
+	// Get any existing deployment
+	s.deployment, err = s.state.LatestDeploymentByJobID(ws, s.eval.Namespace, s.eval.JobID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get job deployment %q: %v", s.eval.JobID, err)
+	}
+	s.deployment = s.deployment.Copy()
+
 	// Get the ready nodes in the required datacenters
 	if !s.job.Stopped() {
 		s.nodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters)
@@ -114,11 +141,16 @@ func (s *SystemScheduler) process() (bool, error) {
 	// Create a plan
 	s.plan = s.eval.MakePlan(s.job)
 
-	// Reset the failed allocations
+	// Reset the failed allocations and the placements added to the
+	// deployment
 	s.failedTGAllocs = nil
+	s.addedPlacements = nil
 
 	// Create an evaluation context
 	s.ctx = NewEvalContext(s.state, s.plan, s.logger)
+	if placementTraceEnabled(s.state, s.eval) {
+		s.ctx.EnablePlacementTrace()
+	}
 
 	// Construct the placement stack
 	s.stack = NewSystemStack(s.ctx)
@@ -132,6 +164,9 @@ func (s *SystemScheduler) process() (bool, error) {
 		return false, err
 	}
 
+	// Update the deployment based on the computed placements
+	s.computeDeploymentStatus()
+
 	// If the plan is a no-op, we can bail. If AnnotatePlan is set submit the plan
 	// anyways to get the annotations.
 	if s.plan.IsNoOp() && !s.eval.AnnotatePlan {
@@ -203,6 +238,9 @@ func (s *SystemScheduler) computeJobAllocs() error {
 	// Filter out the allocations in a terminal state
 	allocs, terminalAllocs := structs.FilterTerminalAllocs(allocs)
 
+	// Handle stopping unneeded deployments
+	s.cancelDeployments()
+
 	// Diff the required and existing allocations
 	diff := diffSystemAllocs(s.job, s.nodes, tainted, allocs, terminalAllocs)
 	s.logger.Debug("reconciled current state with desired state",
@@ -236,14 +274,11 @@ func (s *SystemScheduler) computeJobAllocs() error {
 		}
 	}
 
-	// Check if a rolling upgrade strategy is being used
-	limit := len(diff.update)
-	if !s.job.Stopped() && s.job.Update.Rolling() {
-		limit = s.job.Update.MaxParallel
-	}
+	// Create a deployment for the task groups being updated
+	s.computeDeployment(allocs, diff, inplaceUpdates)
 
 	// Treat non in-place updates as an eviction and new placement.
-	s.limitReached = evictAndPlace(s.ctx, diff, diff.update, allocUpdating, &limit)
+	s.limitReached = s.computeUpdates(diff, allocs)
 
 	// Nothing remaining to do if placement is not required
 	if len(diff.place) == 0 {
@@ -271,6 +306,11 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 		nodeByID[node.ID] = node
 	}
 
+	var deploymentID string
+	if s.deployment != nil && s.deployment.Active() {
+		deploymentID = s.deployment.ID
+	}
+
 	nodes := make([]*structs.Node, 1)
 	for _, missing := range place {
 		node, ok := nodeByID[missing.Alloc.NodeID]
@@ -343,6 +383,9 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 		// Compute top K scoring node metadata
 		s.ctx.Metrics().PopulateScoreMetaData()
 
+		// The placement trace only explains failed placements
+		s.ctx.Metrics().ClearPlacementTrace()
+
 		// Set fields based on if we found an allocation option
 		resources := &structs.AllocatedResources{
 			Tasks: option.TaskResources,
@@ -384,6 +427,22 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 			alloc.PreviousAllocation = missing.Alloc.ID
 		}
 
+		// Attach the allocation to the deployment of its task group
+		if deploymentID != "" {
+			if _, ok := s.deployment.TaskGroups[missing.TaskGroup.Name]; ok {
+				alloc.DeploymentID = deploymentID
+
+				// A placement that doesn't replace an allocation grows an
+				// existing deployment
+				if s.plan.Deployment == nil && alloc.PreviousAllocation == "" {
+					if s.addedPlacements == nil {
+						s.addedPlacements = make(map[string]int)
+					}
+					s.addedPlacements[missing.TaskGroup.Name]++
+				}
+			}
+		}
+
 		// If this placement involves preemption, set DesiredState to evict for those allocations
 		if option.PreemptedAllocs != nil {
 			var preemptedAllocIDs []string
@@ -426,3 +485,263 @@ func (s *SystemScheduler) addBlocked(node *structs.Node) error {
 
 	return s.planner.CreateEval(blocked)
 }
+
+// cancelDeployments cancels the deployment of the job if it is no longer
+// needed because the job was stopped or it references an older job version.
+func (s *SystemScheduler) cancelDeployments() {
+	d := s.deployment
+	if d == nil {
+		return
+	}
+
+	// If the job is stopped and there is a non-terminal deployment, cancel it
+	if s.job.Stopped() {
+		if d.Active() {
+			s.plan.DeploymentUpdates = append(s.plan.DeploymentUpdates, &structs.DeploymentStatusUpdate{
+				DeploymentID:      d.ID,
+				Status:            structs.DeploymentStatusCancelled,
+				StatusDescription: structs.DeploymentStatusDescriptionStoppedJob,
+			})
+		}
+		s.deployment = nil
+		return
+	}
+
+	// Check if the deployment is active and referencing an older job and cancel it
+	if d.JobCreateIndex != s.job.CreateIndex || d.JobVersion != s.job.Version {
+		if d.Active() {
+			s.plan.DeploymentUpdates = append(s.plan.DeploymentUpdates, &structs.DeploymentStatusUpdate{
+				DeploymentID:      d.ID,
+				Status:            structs.DeploymentStatusCancelled,
+				StatusDescription: structs.DeploymentStatusDescriptionNewerJob,
+			})
+		}
+		s.deployment = nil
+		return
+	}
+
+	// Clear it as the current deployment if it is successful
+	if d.Status == structs.DeploymentStatusSuccessful {
+		s.deployment = nil
+	}
+}
+
+// computeDeployment creates a deployment for the task groups that have an
+// update strategy and are either being updated or placed for the first time.
+// Allocations updated in-place are moved into the current deployment.
+func (s *SystemScheduler) computeDeployment(allocs []*structs.Allocation, diff *diffResult, inplace []allocTuple) {
+	if s.job.Stopped() {
+		return
+	}
+
+	if s.deployment == nil {
+		// Determine which groups are being updated, placed and which ones
+		// already have allocations running the current job version
+		updating := make(map[string]bool)
+		for _, tuple := range diff.update {
+			updating[tuple.TaskGroup.Name] = true
+		}
+		for _, tuple := range inplace {
+			updating[tuple.TaskGroup.Name] = true
+		}
+		placing := make(map[string]bool)
+		for _, tuple := range diff.place {
+			placing[tuple.TaskGroup.Name] = true
+		}
+		hadRunning := make(map[string]bool)
+		for _, alloc := range allocs {
+			if alloc.Job.Version == s.job.Version && alloc.Job.CreateIndex == s.job.CreateIndex {
+				hadRunning[alloc.TaskGroup] = true
+			}
+		}
+
+		// Create a new deployment if:
+		// 1. Updating a job specification
+		// 2. No running allocations (first time running a job)
+		for _, tg := range s.job.TaskGroups {
+			if tg.Update.IsEmpty() {
+				continue
+			}
+			if !updating[tg.Name] && (hadRunning[tg.Name] || !placing[tg.Name]) {
+				continue
+			}
+
+			// A previous group may have made the deployment already
+			if s.deployment == nil {
+				s.deployment = structs.NewDeployment(s.job)
+				s.plan.Deployment = s.deployment
+			}
+
+			s.deployment.TaskGroups[tg.Name] = &structs.DeploymentState{
+				AutoRevert:       tg.Update.AutoRevert,
+				ProgressDeadline: tg.Update.ProgressDeadline,
+			}
+		}
+	}
+
+	if s.deployment == nil || !s.deployment.Active() {
+		return
+	}
+
+	// Move the allocations updated in-place into the deployment. At this point
+	// the plan only contains in-place updates.
+	for _, planned := range s.plan.NodeAllocation {
+		for _, alloc := range planned {
+			if _, ok := s.deployment.TaskGroups[alloc.TaskGroup]; !ok {
+				continue
+			}
+			if alloc.DeploymentID != s.deployment.ID {
+				alloc.DeploymentID = s.deployment.ID
+				alloc.DeploymentStatus = nil
+			}
+		}
+	}
+}
+
+// computeUpdates evicts the allocations requiring a destructive update and
+// queues their replacement. Task groups that are part of the deployment are
+// limited by their update strategy and the health of the deployment, while
+// the others use the job's rolling update strategy. It returns whether the
+// rolling update limit was reached.
+func (s *SystemScheduler) computeUpdates(diff *diffResult, allocs []*structs.Allocation) bool {
+	s.pendingUpdates = make(map[string]int)
+
+	// Check if a rolling upgrade strategy is being used
+	limit := len(diff.update)
+	if !s.job.Stopped() && s.job.Update.Rolling() {
+		limit = s.job.Update.MaxParallel
+	}
+
+	if s.deployment == nil {
+		return evictAndPlace(s.ctx, diff, diff.update, allocUpdating, &limit)
+	}
+
+	// Split the updates between the groups of the deployment and the others
+	deployed := make(map[string][]allocTuple)
+	var rolling []allocTuple
+	for _, tuple := range diff.update {
+		if _, ok := s.deployment.TaskGroups[tuple.TaskGroup.Name]; ok {
+			deployed[tuple.TaskGroup.Name] = append(deployed[tuple.TaskGroup.Name], tuple)
+		} else {
+			rolling = append(rolling, tuple)
+		}
+	}
+
+	for _, tg := range s.job.TaskGroups {
+		updates, ok := deployed[tg.Name]
+		if !ok {
+			continue
+		}
+
+		groupLimit := s.computeLimit(tg, allocs)
+		s.pendingUpdates[tg.Name] = len(updates) - helper.IntMin(len(updates), groupLimit)
+		evictAndPlace(s.ctx, diff, updates, allocUpdating, &groupLimit)
+	}
+
+	return evictAndPlace(s.ctx, diff, rolling, allocUpdating, &limit)
+}
+
+// computeLimit returns the number of destructive updates that can be made for
+// a task group of the deployment. The limit is the group's MaxParallel minus
+// any outstanding non-healthy allocation of the deployment.
+func (s *SystemScheduler) computeLimit(tg *structs.TaskGroup, allocs []*structs.Allocation) int {
+	// If the deployment is paused or failed, do not create anything else
+	switch s.deployment.Status {
+	case structs.DeploymentStatusPaused, structs.DeploymentStatusFailed:
+		return 0
+	}
+
+	limit := tg.Update.MaxParallel
+	for _, alloc := range allocs {
+		if alloc.DeploymentID != s.deployment.ID || alloc.TaskGroup != tg.Name {
+			continue
+		}
+
+		// An unhealthy allocation means nothing else should be happen.
+		if alloc.DeploymentStatus.IsUnhealthy() {
+			return 0
+		}
+
+		if !alloc.DeploymentStatus.IsHealthy() {
+			limit--
+		}
+	}
+
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// computeDeploymentStatus sets the desired totals of a deployment created by
+// the evaluation, grows the desired totals of the current deployment by the
+// placements added to it, or marks the current deployment as successful once
+// all of its task groups are fully placed and healthy.
+func (s *SystemScheduler) computeDeploymentStatus() {
+	d := s.deployment
+	if d == nil || s.job.Stopped() {
+		return
+	}
+
+	// Count the allocations of the deployment placed or updated by the plan
+	planned := make(map[string]int, len(d.TaskGroups))
+	for _, allocs := range s.plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if alloc.DeploymentID == d.ID {
+				planned[alloc.TaskGroup]++
+			}
+		}
+	}
+
+	if s.plan.Deployment != nil {
+		// Drop the groups that ended up with nothing to deploy, which happens
+		// when no node is feasible for them.
+		for name, dstate := range d.TaskGroups {
+			dstate.DesiredTotal = planned[name] + s.pendingUpdates[name]
+			if dstate.DesiredTotal == 0 {
+				delete(d.TaskGroups, name)
+			}
+		}
+
+		if len(d.TaskGroups) == 0 {
+			s.plan.Deployment = nil
+			s.deployment = nil
+		}
+		return
+	}
+
+	if !d.Active() {
+		return
+	}
+
+	// The placements added to the deployment must be healthy before it can
+	// complete
+	if len(s.addedPlacements) != 0 {
+		for name, added := range s.addedPlacements {
+			d.TaskGroups[name].DesiredTotal += added
+		}
+		s.plan.Deployment = d
+		return
+	}
+
+	for name, dstate := range d.TaskGroups {
+		if planned[name] != 0 || s.pendingUpdates[name] != 0 || dstate.HealthyAllocs < dstate.DesiredTotal {
+			return
+		}
+		if _, ok := s.failedTGAllocs[name]; ok {
+			return
+		}
+
+		// Wait for the health gate of the group to pass
+		if tg := s.job.LookupTaskGroup(name); tg != nil && tg.Update != nil &&
+			tg.Update.HealthGate != nil && !dstate.HealthGatePassed() {
+			return
+		}
+	}
+
+	s.plan.DeploymentUpdates = append(s.plan.DeploymentUpdates, &structs.DeploymentStatusUpdate{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusSuccessful,
+		StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+	})
+}
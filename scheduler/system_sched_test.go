@@ -735,6 +735,371 @@ func TestSystemSched_JobModify_Rolling(t *testing.T) {
 	}
 }
 
+func TestSystemSched_JobRegister_Deployment(t *testing.T) {
+	h := NewHarness(t)
+	require := require.New(t)
+
+	// Create some nodes
+	for i := 0; i < 5; i++ {
+		node := mock.Node()
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Create a job with an update strategy
+	job := mock.SystemJob()
+	job.TaskGroups[0].Update = &structs.UpdateStrategy{
+		MaxParallel:      2,
+		HealthCheck:      structs.UpdateStrategyHealthCheck_TaskStates,
+		MinHealthyTime:   10 * time.Second,
+		HealthyDeadline:  5 * time.Minute,
+		ProgressDeadline: 10 * time.Minute,
+		AutoRevert:       true,
+	}
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(h.Process(NewSystemScheduler, eval))
+
+	// Ensure a single plan creating a deployment
+	require.Len(h.Plans, 1)
+	plan := h.Plans[0]
+	require.NotNil(plan.Deployment)
+
+	dstate, ok := plan.Deployment.TaskGroups[job.TaskGroups[0].Name]
+	require.True(ok)
+	require.Equal(5, dstate.DesiredTotal)
+	require.True(dstate.AutoRevert)
+	require.Equal(10*time.Minute, dstate.ProgressDeadline)
+
+	// Ensure all the placements are part of the deployment
+	var planned []*structs.Allocation
+	for _, allocList := range plan.NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	require.Len(planned, 5)
+	for _, alloc := range planned {
+		require.Equal(plan.Deployment.ID, alloc.DeploymentID)
+	}
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+	require.Equal(plan.Deployment.ID, h.Evals[0].DeploymentID)
+}
+
+func TestSystemSched_JobModify_Deployment(t *testing.T) {
+	h := NewHarness(t)
+	require := require.New(t)
+
+	// Create some nodes
+	var nodes []*structs.Node
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Generate a fake job with allocations
+	job := mock.SystemJob()
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	var allocs []*structs.Allocation
+	for _, node := range nodes {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = "my-job.web[0]"
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(h.State.UpsertAllocs(h.NextIndex(), allocs))
+
+	// Update the job with an update strategy
+	job2 := job.Copy()
+	job2.TaskGroups[0].Update = &structs.UpdateStrategy{
+		MaxParallel:      3,
+		HealthCheck:      structs.UpdateStrategyHealthCheck_TaskStates,
+		MinHealthyTime:   10 * time.Second,
+		HealthyDeadline:  5 * time.Minute,
+		ProgressDeadline: 10 * time.Minute,
+	}
+
+	// Update the task, such that it cannot be done in-place
+	job2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job2))
+
+	// Create a mock evaluation to deal with the update
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(h.Process(NewSystemScheduler, eval))
+
+	// Ensure a single plan creating a deployment for every node
+	require.Len(h.Plans, 1)
+	plan := h.Plans[0]
+	require.NotNil(plan.Deployment)
+	require.Equal(10, plan.Deployment.TaskGroups["web"].DesiredTotal)
+
+	// Ensure the plan only updated MaxParallel allocations
+	var update []*structs.Allocation
+	for _, updateList := range plan.NodeUpdate {
+		update = append(update, updateList...)
+	}
+	require.Len(update, 3)
+
+	var planned []*structs.Allocation
+	for _, allocList := range plan.NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	require.Len(planned, 3)
+	for _, alloc := range planned {
+		require.Equal(plan.Deployment.ID, alloc.DeploymentID)
+	}
+
+	// The deployment watcher drives the rollout so no follow up eval is
+	// created
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+	require.Empty(h.Evals[0].NextEval)
+	require.Empty(h.CreateEvals)
+
+	// Mark one of the new allocations as healthy and ensure that the next
+	// evaluation only replaces one more allocation
+	ws := memdb.NewWatchSet()
+	out, err := h.State.AllocsByDeployment(ws, plan.Deployment.ID)
+	require.NoError(err)
+	require.Len(out, 3)
+
+	healthy := out[0].Copy()
+	healthy.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy: helper.BoolToPtr(true),
+	}
+	require.NoError(h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{healthy}))
+
+	eval2 := &structs.Evaluation{
+		Namespace:    structs.DefaultNamespace,
+		ID:           uuid.Generate(),
+		Priority:     50,
+		TriggeredBy:  structs.EvalTriggerDeploymentWatcher,
+		JobID:        job.ID,
+		DeploymentID: plan.Deployment.ID,
+		Status:       structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval2}))
+	require.NoError(h.Process(NewSystemScheduler, eval2))
+
+	require.Len(h.Plans, 2)
+	plan = h.Plans[1]
+	require.Nil(plan.Deployment)
+
+	planned = nil
+	for _, allocList := range plan.NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	require.Len(planned, 1)
+	require.Equal(eval2.DeploymentID, planned[0].DeploymentID)
+}
+
+func TestSystemSched_Deployment_Complete(t *testing.T) {
+	h := NewHarness(t)
+	require := require.New(t)
+
+	// Create some nodes
+	var nodes []*structs.Node
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Create a job with an update strategy and a running deployment
+	job := mock.SystemJob()
+	job.TaskGroups[0].Update = &structs.UpdateStrategy{
+		MaxParallel:     1,
+		HealthCheck:     structs.UpdateStrategyHealthCheck_TaskStates,
+		MinHealthyTime:  10 * time.Second,
+		HealthyDeadline: 5 * time.Minute,
+	}
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups["web"] = &structs.DeploymentState{
+		DesiredTotal:  3,
+		PlacedAllocs:  3,
+		HealthyAllocs: 3,
+	}
+	require.NoError(h.State.UpsertDeployment(h.NextIndex(), d))
+
+	// Create healthy allocations for the deployment on every node
+	var allocs []*structs.Allocation
+	for _, node := range nodes {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = "my-job.web[0]"
+		alloc.DeploymentID = d.ID
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{
+			Healthy: helper.BoolToPtr(true),
+		}
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(h.State.UpsertAllocs(h.NextIndex(), allocs))
+
+	// Create a mock evaluation from the deployment watcher
+	eval := &structs.Evaluation{
+		Namespace:    structs.DefaultNamespace,
+		ID:           uuid.Generate(),
+		Priority:     50,
+		TriggeredBy:  structs.EvalTriggerDeploymentWatcher,
+		JobID:        job.ID,
+		DeploymentID: d.ID,
+		Status:       structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(h.Process(NewSystemScheduler, eval))
+
+	// Ensure the deployment was marked as successful
+	require.Len(h.Plans, 1)
+	plan := h.Plans[0]
+	require.Len(plan.DeploymentUpdates, 1)
+	update := plan.DeploymentUpdates[0]
+	require.Equal(d.ID, update.DeploymentID)
+	require.Equal(structs.DeploymentStatusSuccessful, update.Status)
+	require.Empty(plan.NodeAllocation)
+}
+
+func TestSystemSched_Deployment_NodeJoin(t *testing.T) {
+	h := NewHarness(t)
+	require := require.New(t)
+
+	// Create some nodes
+	var nodes []*structs.Node
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Create a job with an update strategy and a running deployment
+	job := mock.SystemJob()
+	job.TaskGroups[0].Update = &structs.UpdateStrategy{
+		MaxParallel:     1,
+		HealthCheck:     structs.UpdateStrategyHealthCheck_TaskStates,
+		MinHealthyTime:  10 * time.Second,
+		HealthyDeadline: 5 * time.Minute,
+	}
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups["web"] = &structs.DeploymentState{
+		DesiredTotal:  3,
+		HealthyAllocs: 3,
+	}
+	require.NoError(h.State.UpsertDeployment(h.NextIndex(), d))
+
+	// Create healthy allocations for the deployment on every node
+	var allocs []*structs.Allocation
+	for _, node := range nodes {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = "my-job.web[0]"
+		alloc.DeploymentID = d.ID
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{
+			Healthy: helper.BoolToPtr(true),
+		}
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(h.State.UpsertAllocs(h.NextIndex(), allocs))
+
+	// Add a new node during the deployment
+	node := mock.Node()
+	require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+
+	// Create a mock evaluation to deal with the node joining
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerNodeUpdate,
+		JobID:       job.ID,
+		NodeID:      node.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(h.Process(NewSystemScheduler, eval))
+
+	// Ensure the new allocation joined the deployment without completing it
+	require.Len(h.Plans, 1)
+	plan := h.Plans[0]
+	require.Empty(plan.DeploymentUpdates)
+	require.Len(plan.NodeAllocation[node.ID], 1)
+	require.Equal(d.ID, plan.NodeAllocation[node.ID][0].DeploymentID)
+
+	// Ensure the desired total of the deployment grew
+	ws := memdb.NewWatchSet()
+	out, err := h.State.DeploymentByID(ws, d.ID)
+	require.NoError(err)
+	require.Equal(structs.DeploymentStatusRunning, out.Status)
+	dstate := out.TaskGroups["web"]
+	require.Equal(4, dstate.DesiredTotal)
+	require.Equal(4, dstate.PlacedAllocs)
+	require.Equal(3, dstate.HealthyAllocs)
+}
+
+func TestSystemSched_JobDeregister_CancelDeployment(t *testing.T) {
+	h := NewHarness(t)
+	require := require.New(t)
+
+	// Create a stopped job with a running deployment
+	job := mock.SystemJob()
+	job.Stop = true
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups["web"] = &structs.DeploymentState{
+		DesiredTotal: 1,
+	}
+	require.NoError(h.State.UpsertDeployment(h.NextIndex(), d))
+
+	// Create a mock evaluation to deregister the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerJobDeregister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(h.Process(NewSystemScheduler, eval))
+
+	// Ensure the deployment was cancelled
+	require.Len(h.Plans, 1)
+	plan := h.Plans[0]
+	require.Len(plan.DeploymentUpdates, 1)
+	update := plan.DeploymentUpdates[0]
+	require.Equal(d.ID, update.DeploymentID)
+	require.Equal(structs.DeploymentStatusCancelled, update.Status)
+	require.Equal(structs.DeploymentStatusDescriptionStoppedJob, update.StatusDescription)
+}
+
 func TestSystemSched_JobModify_InPlace(t *testing.T) {
 	h := NewHarness(t)
 
@@ -289,6 +289,18 @@ func retryMax(max int, cb func() (bool, error), reset func() bool) error {
 	}
 }
 
+// placementTraceEnabled returns whether the placements of the evaluation
+// should record why each node was rejected, either because the evaluation
+// asked for it or because it is enabled for the cluster.
+func placementTraceEnabled(state State, eval *structs.Evaluation) bool {
+	if eval.PlacementTrace {
+		return true
+	}
+
+	_, schedConfig, _ := state.SchedulerConfig()
+	return schedConfig != nil && schedConfig.PlacementTraceEnabled
+}
+
 // progressMade checks to see if the plan result made allocations or updates.
 // If the result is nil, false is returned.
 func progressMade(result *structs.PlanResult) bool {